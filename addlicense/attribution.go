@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultPreserveAdjacent are regular expressions for attribution lines that
+// are kept attached to the header directly above them, such as the
+// "Original author: Jane Doe <jane@example.com>" lines carried by migrated
+// files. Options.PreserveAdjacent adds to them.
+var DefaultPreserveAdjacent = []string{
+	`(?i)\boriginal[ _-]?authors?\s*:`,
+}
+
+// ValidatePreserveAdjacent returns an error if any of the given patterns is not
+// a valid regular expression
+func ValidatePreserveAdjacent(patterns []string) error {
+	invalid := []string{}
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%q", p))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid preserve_adjacent patterns: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// preserveAdjacent compiles DefaultPreserveAdjacent along with the patterns in
+// opts. Invalid patterns, which Run rejects up front, are ignored.
+func preserveAdjacent(opts Options) []*regexp.Regexp {
+	patterns := append(append([]string{}, DefaultPreserveAdjacent...), opts.PreserveAdjacent...)
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		if re, err := regexp.Compile(p); err == nil {
+			res = append(res, re)
+		}
+	}
+	return res
+}
+
+// attributionLength returns the length of the run of attribution lines at the
+// start of b, each of which matches one of patterns, including the newline
+// that ends the last of them. Blank lines end the run.
+func attributionLength(b []byte, patterns []*regexp.Regexp) int {
+	n := 0
+	for n < len(b) {
+		end := bytes.IndexByte(b[n:], '\n')
+		if end == -1 {
+			end = len(b)
+		} else {
+			end += n + 1
+		}
+
+		line := bytes.TrimRight(b[n:end], "\r\n")
+		if len(bytes.TrimSpace(line)) == 0 || !matchesAny(line, patterns) {
+			break
+		}
+		n = end
+	}
+	return n
+}
+
+func matchesAny(line []byte, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.Match(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+)
+
+// Test that attribution lines directly below an existing header stay attached
+// to it when the spacing after the header is normalized.
+func TestRespaceHeaderPreservesAttribution(t *testing.T) {
+	lic := []byte("// Copyright H\n\n")
+	zero, two := 0, 2
+
+	tests := []struct {
+		contents     string
+		spacing      *int
+		preserve     []string
+		wantContents string
+		wantUpdated  bool
+	}{
+		{
+			"// Copyright H\n// Original author: Jane Doe <jane@example.com>\ncontent",
+			&two, nil,
+			"// Copyright H\n// Original author: Jane Doe <jane@example.com>\n\n\ncontent", true,
+		},
+		{
+			"// Copyright H\n// Original Authors: Jane Doe, John Doe\n\n\n\ncontent",
+			&zero, nil,
+			"// Copyright H\n// Original Authors: Jane Doe, John Doe\ncontent", true,
+		},
+		{
+			"// Copyright H\n// Original author: Jane Doe\n\n\ncontent",
+			&two, nil,
+			"// Copyright H\n// Original author: Jane Doe\n\n\ncontent", false,
+		},
+		{
+			"// Copyright H\n// Original author: Jane Doe\n// Ported-From: example.com/lib\ncontent",
+			&two, []string{`Ported-From:`},
+			"// Copyright H\n// Original author: Jane Doe\n// Ported-From: example.com/lib\n\n\ncontent", true,
+		},
+		{
+			// Attribution lines separated from the header are ordinary content
+			"// Copyright H\n\n// Original author: Jane Doe\ncontent",
+			&zero, nil,
+			"// Copyright H\n// Original author: Jane Doe\ncontent", true,
+		},
+		{
+			// Only an attribution line follows the header
+			"// Copyright H\n// Original author: Jane Doe\n",
+			&two, nil,
+			"// Copyright H\n// Original author: Jane Doe\n", false,
+		},
+	}
+
+	for _, tt := range tests {
+		got, updated := prependLicense("f.go", []byte(tt.contents), lic, Options{HeaderSpacing: tt.spacing, PreserveAdjacent: tt.preserve})
+		if updated != tt.wantUpdated {
+			t.Errorf("prependLicense with contents %q returned updated: %t, want %t", tt.contents, updated, tt.wantUpdated)
+		}
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
+		}
+	}
+}
+
+func TestValidatePreserveAdjacent(t *testing.T) {
+	if err := ValidatePreserveAdjacent([]string{`(?i)^\W*ported from:`}); err != nil {
+		t.Errorf("ValidatePreserveAdjacent returned error: %v", err)
+	}
+
+	err := ValidatePreserveAdjacent([]string{`ok`, `(unclosed`})
+	want := `invalid preserve_adjacent patterns: "(unclosed"`
+	if err == nil || err.Error() != want {
+		t.Errorf("ValidatePreserveAdjacent returned error: %v, want %q", err, want)
+	}
+}
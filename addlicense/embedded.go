@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// licenseTextSignature is a phrase that shows that a file embeds the text of a
+// license rather than a short header. Phrases are short enough that they're
+// usually written on a single line, which is all that hasLicense can match.
+type licenseTextSignature struct {
+	// phrase is lowercase, and matched against lowercase text
+	phrase string
+
+	// spdxID identifies the license, or is empty if the phrase is shared by
+	// several licenses or versions of one
+	spdxID string
+}
+
+// licenseTextSignatures lists the embedded license text that is recognized
+var licenseTextSignatures = []licenseTextSignature{
+	{"version 2.0, january 2004", "Apache-2.0"},
+	{"licensed under the apache license, version 2.0", "Apache-2.0"},
+	{"mozilla public license, v. 2.0", "MPL-2.0"},
+	{"permission is hereby granted, free of charge", "MIT"},
+	{"permission to use, copy, modify, and/or distribute this software", "ISC"},
+	{"redistribution and use in source and binary forms", ""},
+	{"this program is free software", ""},
+}
+
+// maxCompactedHeaderLines is the number of lines beyond which a comment at the
+// top of a file is no longer considered a header that can be compacted. The
+// full text of the Apache License, the longest recognized, is around 200.
+const maxCompactedHeaderLines = 400
+
+// copyrightStatementStart matches lines that begin a copyright statement, as
+// opposed to license text that happens to start with the word, such as
+// "copyright notice, this list of conditions..."
+var copyrightStatementStart = regexp.MustCompile(`^Copyright(?:\s*(?:\([cC]\)|©)|\s+(?:19|20)\d{2}|\s+\p{Lu})`)
+
+// embeddedLicense returns the first signature of license text found in the
+// lowercase text b
+func embeddedLicense(b []byte) (licenseTextSignature, bool) {
+	for _, sig := range licenseTextSignatures {
+		if bytes.Contains(b, []byte(sig.phrase)) {
+			return sig, true
+		}
+	}
+	return licenseTextSignature{}, false
+}
+
+// Compaction describes embedded license text that Compact replaced
+type Compaction struct {
+	// License is the SPDX identifier of the license text that was replaced
+	License string
+
+	// Original is the comment that was replaced, exactly as it was written
+	Original string
+}
+
+// Compact returns b, the contents of the file at path, with license text
+// embedded in its header, such as the full text of the Apache License,
+// replaced by the canonical short header that Normalize would write. The
+// Compaction is nil if b was left unchanged.
+//
+// Like Normalize, only a comment at the top of a file (below any preamble) in
+// the file's own comment style is replaced, and only if the license text
+// identifies a single license that matches license.SPDXID. Copyright
+// statements in the comment must be for the holder, and their years are kept,
+// as are attribution lines. A comment with any other copyright statement is
+// left exactly as it is, so that third-party notices are never removed.
+func (n *Normalizer) Compact(path string, b []byte) ([]byte, *Compaction, error) {
+	if n.license.SPDXID == "" || HasConflictMarkers(b) || HasIgnoreFileMarker(b) || SkipReason(path, b) != "" {
+		return b, nil, nil
+	}
+	style, ok := CommentStyleFor(path)
+	if !ok {
+		return b, nil, nil
+	}
+
+	pre := preamble(path, b, n.opts)
+	rest := bytes.TrimLeft(b[len(pre):], "\n")
+	texts, size := headerComment(rest, style, maxCompactedHeaderLines)
+	if texts == nil {
+		return b, nil, nil
+	}
+
+	sig, ok := embeddedLicense([]byte(strings.ToLower(strings.Join(texts, " "))))
+	if !ok || sig.spdxID == "" || !SPDXExpressionsMatch(sig.spdxID, n.license.SPDXID) {
+		return b, nil, nil
+	}
+
+	data, attribution, ok := n.parseLicenseText(texts)
+	if !ok {
+		return b, nil, nil
+	}
+	out, changed, err := n.rewrite(path, b, pre, rest[size:], style, data, attribution)
+	if err != nil || !changed {
+		return b, nil, err
+	}
+	return out, &Compaction{License: sig.spdxID, Original: string(rest[:size])}, nil
+}
+
+// parseLicenseText is like parse, for a header comment made up of license
+// text. Every line other than copyright statements, SPDX identifiers, and
+// attribution lines is taken to be license text, and dropped.
+func (n *Normalizer) parseLicenseText(texts []string) (LicenseData, []string, bool) {
+	data := LicenseData{SPDXID: n.license.SPDXID}
+	attribution := []string{}
+	for _, text := range texts {
+		switch {
+		case text == "":
+		case matchesAny([]byte(text), n.preserve):
+			attribution = append(attribution, text)
+		case spdxHeaderText.MatchString(text):
+			if !SPDXExpressionsMatch(spdxHeaderText.FindStringSubmatch(text)[1], n.license.SPDXID) {
+				return data, nil, false
+			}
+		default:
+			years, ok := n.statementYears(text)
+			if ok {
+				if data.Holder != "" && data.Year != years {
+					return data, nil, false
+				}
+				data.Holder = n.license.Holder
+				data.Year = years
+			} else if copyrightStatementStart.MatchString(text) {
+				return data, nil, false
+			}
+		}
+	}
+
+	if data.Holder == "" && !n.opts.OmitCopyright {
+		data.Holder = n.license.Holder
+	}
+	return data, attribution, true
+}
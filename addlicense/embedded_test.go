@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+)
+
+const apacheNotice = `// Copyright 2019 HashiCorp, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+`
+
+const apacheText = `/*
+                                 Apache License
+                           Version 2.0, January 2004
+                        http://www.apache.org/licenses/
+
+   TERMS AND CONDITIONS FOR USE, REPRODUCTION, AND DISTRIBUTION
+
+   1. Definitions.
+
+      "License" shall mean the terms and conditions for use, reproduction,
+      and distribution as defined by Sections 1 through 9 of this document.
+
+      "Licensor" shall mean the copyright owner or entity authorized by
+      the copyright owner that is granting the License.
+
+   APPENDIX: How to apply the Apache License to your work.
+
+   Copyright [yyyy] [name of copyright owner]
+*/
+`
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		description  string
+		path         string
+		contents     string
+		license      LicenseData
+		wantContents string
+		wantLicense  string
+	}{
+		{
+			"license notice",
+			"f.go",
+			apacheNotice + "\npackage a\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "Apache-2.0"},
+			"// Copyright (c) 2019 HashiCorp, Inc.\n// SPDX-License-Identifier: Apache-2.0\n\npackage a\n",
+			"Apache-2.0",
+		},
+		{
+			"full license text",
+			"f.c",
+			apacheText + "int x;\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "Apache-2.0"},
+			"/*\n * Copyright (c) HashiCorp, Inc.\n * SPDX-License-Identifier: Apache-2.0\n */\n\nint x;\n",
+			"Apache-2.0",
+		},
+		{
+			"mpl notice",
+			"f.sh",
+			"#!/bin/bash\n# This Source Code Form is subject to the terms of the Mozilla Public\n# License, v. 2.0. If a copy of the MPL was not distributed with this\n# file, You can obtain one at https://mozilla.org/MPL/2.0/.\necho hi\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "MPL-2.0"},
+			"#!/bin/bash\n# Copyright (c) HashiCorp, Inc.\n# SPDX-License-Identifier: MPL-2.0\n\necho hi\n",
+			"MPL-2.0",
+		},
+		{
+			"attribution lines are kept",
+			"f.go",
+			"// Original author: Jane Doe <jane@example.com>\n" + apacheNotice + "package a\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "Apache-2.0"},
+			"// Copyright (c) 2019 HashiCorp, Inc.\n// SPDX-License-Identifier: Apache-2.0\n// Original author: Jane Doe <jane@example.com>\n\npackage a\n",
+			"Apache-2.0",
+		},
+		{
+			"other license",
+			"f.go",
+			apacheNotice + "package a\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "MPL-2.0"},
+			apacheNotice + "package a\n",
+			"",
+		},
+		{
+			"third-party copyright",
+			"f.go",
+			"// Copyright 2015 Acme Inc.\n" + apacheNotice + "package a\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "Apache-2.0"},
+			"// Copyright 2015 Acme Inc.\n" + apacheNotice + "package a\n",
+			"",
+		},
+		{
+			"ambiguous license",
+			"f.go",
+			"// Copyright 2019 HashiCorp, Inc.\n// Redistribution and use in source and binary forms, with or without\n// modification, are permitted provided that the following conditions are met.\npackage a\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "BSD-3-Clause"},
+			"// Copyright 2019 HashiCorp, Inc.\n// Redistribution and use in source and binary forms, with or without\n// modification, are permitted provided that the following conditions are met.\npackage a\n",
+			"",
+		},
+		{
+			"short header",
+			"f.go",
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: Apache-2.0\n\npackage a\n",
+			LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "Apache-2.0"},
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: Apache-2.0\n\npackage a\n",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			n, err := NewNormalizer(tt.license, Options{})
+			if err != nil {
+				t.Fatalf("NewNormalizer returned error: %v", err)
+			}
+			got, compaction, err := n.Compact(tt.path, []byte(tt.contents))
+			if err != nil {
+				t.Fatalf("Compact returned error: %v", err)
+			}
+			if string(got) != tt.wantContents {
+				t.Errorf("Compact returned contents: %q, want %q", got, tt.wantContents)
+			}
+			if compaction == nil {
+				if got := string(got); got != tt.contents {
+					t.Errorf("Compact changed contents without returning a Compaction")
+				}
+				return
+			}
+			if compaction.License != tt.wantLicense {
+				t.Errorf("Compact returned license %q, want %q", compaction.License, tt.wantLicense)
+			}
+			if !hasLicense([]byte(compaction.Original), nil) {
+				t.Errorf("Compact returned original %q, which isn't a license header", compaction.Original)
+			}
+
+			// Compacted headers are already in canonical form
+			if _, updated, _ := n.Normalize(tt.path, got); updated {
+				t.Errorf("Compact returned a header that isn't in canonical form: %q", got)
+			}
+		})
+	}
+}
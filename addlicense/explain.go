@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"fmt"
+)
+
+// Step is one of the checks made in deciding whether a file needs a header
+type Step struct {
+	// Check names what was checked, such as "header_ignore patterns"
+	Check string
+
+	// Result describes what the check found, such as the pattern that matched
+	Result string
+
+	// Decisive reports whether the check decided the file's outcome, in which
+	// case it is the last step
+	Decisive bool
+}
+
+// Explanation traces how Run would treat a single file
+type Explanation struct {
+	Status Status
+	Steps  []Step
+}
+
+// Explain traces each decision that Run makes about b, the contents of the
+// file at path, when given the ignore patterns and opts: which patterns or
+// Options.Skip exclude it, whether its type is supported, and what its
+// contents show. The checks are made in the same order as Run, stopping at the
+// first that decides the file's outcome. b may be the result of ReadHead.
+func Explain(path string, b []byte, ignore []string, opts Options) (Explanation, error) {
+	for _, patterns := range [][]string{ignore, opts.NeverTouch, opts.Include} {
+		if err := ValidatePatterns(patterns); err != nil {
+			return Explanation{}, err
+		}
+	}
+	if err := ValidateNeverTouchExceptions(opts.NeverTouchExceptions); err != nil {
+		return Explanation{}, err
+	}
+
+	e := Explanation{}
+	step := func(check string, result string) {
+		e.Steps = append(e.Steps, Step{Check: check, Result: result})
+	}
+	decide := func(check string, result string, status Status) (Explanation, error) {
+		e.Steps = append(e.Steps, Step{Check: check, Result: result, Decisive: true})
+		e.Status = status
+		return e, nil
+	}
+
+	if p := firstMatch(path, defaultNeverTouch(opts)); p != "" {
+		return decide("built-in never-touch patterns", fmt.Sprintf("matched %q", p), Status{State: HeaderIgnored, Reason: "never touched"})
+	}
+	step("built-in never-touch patterns", "no match")
+	if p := firstMatch(path, opts.NeverTouch); p != "" {
+		return decide("never_touch patterns", fmt.Sprintf("matched %q", p), Status{State: HeaderIgnored, Reason: "never touched"})
+	}
+	step("never_touch patterns", "no match")
+
+	if opts.SkipHidden {
+		if isHidden(path) {
+			return decide("hidden files", "hidden, and hidden files are skipped", Status{State: HeaderIgnored, Reason: "hidden"})
+		}
+		step("hidden files", "not hidden")
+	}
+
+	if len(opts.Include) > 0 {
+		p := firstMatch(path, opts.Include)
+		if p == "" {
+			return decide("header_include patterns", "no match", Status{State: HeaderIgnored, Reason: "not included"})
+		}
+		step("header_include patterns", fmt.Sprintf("matched %q", p))
+	}
+
+	if p := firstMatch(path, ignore); p != "" {
+		return decide("header_ignore patterns", fmt.Sprintf("matched %q", p), Status{State: HeaderIgnored})
+	}
+	step("header_ignore patterns", "no match")
+
+	if opts.Skip != nil {
+		if skip, reason := opts.Skip(path); skip {
+			return decide("other filters", reason, Status{State: HeaderIgnored, Reason: reason})
+		}
+		step("other filters", "not skipped")
+	}
+
+	style, ok := CommentStyleFor(path)
+	if !ok {
+		return decide("file type", "unknown extension, so there's no comment style for a header", Status{State: HeaderUnsupported})
+	}
+	step("file type", fmt.Sprintf("comment style %q", style.String()))
+
+	if HasConflictMarkers(b) {
+		return decide("merge conflict markers", "found", Status{State: HeaderExempt, Reason: ConflictMarkersReason})
+	}
+	step("merge conflict markers", "none found")
+
+	if _, _, m := publicDomainComment(path, b, opts); m != "" && opts.ConvertPublicDomain {
+		step("existing header", fmt.Sprintf("found public domain dedication %q, which is to be replaced", m))
+	} else if k := opts.headerKeyword(path, b); k != "" {
+		return decide("existing header", fmt.Sprintf("found %q near the top of the file", k), Status{State: HeaderPresent})
+	} else {
+		step("existing header", "none found")
+	}
+
+	if HasIgnoreFileMarker(b) {
+		return decide(IgnoreFileMarker+" marker", "found", Status{State: HeaderExempt, Reason: IgnoreFileMarker + " marker"})
+	}
+	step(IgnoreFileMarker+" marker", "none found")
+
+	if isGenerated(b) {
+		return decide("generated code markers", "found", Status{State: HeaderExempt, Reason: "generated file"})
+	}
+	step("generated code markers", "none found")
+
+	if reason := buildArtifactReason(path, b); reason != "" {
+		return decide("build artifacts", reason, Status{State: HeaderExempt, Reason: reason})
+	}
+	step("build artifacts", "not minified or bundled")
+
+	e.Status = Status{State: HeaderMissing}
+	return e, nil
+}
+
+// firstMatch returns the first of patterns that matches path, or an empty
+// string if none do. Patterns are assumed to be valid.
+func firstMatch(path string, patterns []string) string {
+	for _, p := range patterns {
+		if fileMatches(path, []string{p}) {
+			return p
+		}
+	}
+	return ""
+}
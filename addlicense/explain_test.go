@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		path      string
+		contents  string
+		ignore    []string
+		opts      Options
+		wantState HeaderState
+		wantLast  string
+		wantSteps int
+	}{
+		{"a.go", "package a\n", nil, Options{}, HeaderMissing, "build artifacts", 9},
+		{"a.pb.go", "package a\n", nil, Options{}, HeaderIgnored, "built-in never-touch patterns", 1},
+		{"a.go", "package a\n", nil, Options{NeverTouch: []string{"*.go"}}, HeaderIgnored, "never_touch patterns", 2},
+		{"a.go", "package a\n", nil, Options{Include: []string{"*.tf"}}, HeaderIgnored, "header_include patterns", 3},
+		{"vendor/a.go", "package a\n", []string{"vendor/**"}, Options{}, HeaderIgnored, "header_ignore patterns", 3},
+		{"a.go", "package a\n", nil, Options{Skip: func(string) (bool, string) { return true, "because" }}, HeaderIgnored, "other filters", 4},
+		{"a.md", "# A\n", nil, Options{}, HeaderUnsupported, "file type", 4},
+		{"a.go", "<<<<<<< HEAD\n", nil, Options{}, HeaderExempt, "merge conflict markers", 5},
+		{"a.go", "// Copyright Example Corp\npackage a\n", nil, Options{}, HeaderPresent, "existing header", 6},
+		{"a.go", "// Urheberrecht Example GmbH\npackage a\n", nil, Options{}, HeaderPresent, "existing header", 6},
+		{"a.go", "// copywrite:ignore-file\npackage a\n", nil, Options{}, HeaderExempt, "copywrite:ignore-file marker", 7},
+		{"a.go", "// Code generated by foo. DO NOT EDIT.\npackage a\n", nil, Options{}, HeaderExempt, "generated code markers", 8},
+		{"a.js", "/*! For license information please see a.js.LICENSE.txt */\n", nil, Options{}, HeaderExempt, "build artifacts", 9},
+	}
+
+	for _, tt := range tests {
+		e, err := Explain(tt.path, []byte(tt.contents), tt.ignore, tt.opts)
+		if err != nil {
+			t.Errorf("Explain(%q) returned error: %v", tt.path, err)
+			continue
+		}
+		if e.Status.State != tt.wantState {
+			t.Errorf("Explain(%q) with contents %q returned state %s, want %s", tt.path, tt.contents, e.Status.State, tt.wantState)
+		}
+		last := e.Steps[len(e.Steps)-1]
+		if last.Check != tt.wantLast || len(e.Steps) != tt.wantSteps {
+			t.Errorf("Explain(%q) with contents %q returned %d steps ending with %q, want %d ending with %q", tt.path, tt.contents, len(e.Steps), last.Check, tt.wantSteps, tt.wantLast)
+		}
+		if last.Decisive != (tt.wantState != HeaderMissing) {
+			t.Errorf("Explain(%q) with contents %q returned a last step with Decisive %t", tt.path, tt.contents, last.Decisive)
+		}
+	}
+
+	// Explain agrees with CheckBytes
+	for _, contents := range []string{"package a\n", "// Copyright H\n", "// copywrite:ignore-file\n"} {
+		e, _ := Explain("a.go", []byte(contents), nil, Options{})
+		s, _ := CheckBytes("a.go", []byte(contents), Options{})
+		if e.Status != s {
+			t.Errorf("Explain with contents %q returned %+v, but CheckBytes returned %+v", contents, e.Status, s)
+		}
+	}
+
+	if _, err := Explain("a.go", nil, []string{"["}, Options{}); err == nil {
+		t.Errorf("Explain with an invalid ignore pattern didn't return an error")
+	}
+}
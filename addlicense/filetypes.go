@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"sort"
+	"strings"
+)
+
+// FileType describes the files with one extension or name that headers can be
+// added to, and how
+type FileType struct {
+	// Name is the extension (e.g., ".go") or lowercase file name (e.g.,
+	// "dockerfile") of the files, or a pattern such as "templates/*.yaml" for
+	// files that depend on where they are
+	Name     string
+	Language string
+	Style    CommentStyle
+
+	// Enabled reports whether headers are added to the files, and if not,
+	// Reason explains why
+	Enabled bool
+	Reason  string
+
+	// Setting is the project config setting that changes how the files are
+	// handled, if any
+	Setting string
+}
+
+// String renders a comment style as its delimiters, such as "//" or "/* * */"
+func (s CommentStyle) String() string {
+	return strings.Join(strings.Fields(s.Top+" "+s.Mid+" "+s.Bottom), " ")
+}
+
+// FileTypes returns every type of file that headers can be added to, sorted by
+// language and then name, with their comment styles under the current
+// configuration (JSONComments, MATLABFiles, and AssemblyComment). Types whose
+// files would all be excluded by the ignore or include patterns given are
+// reported as disabled.
+func FileTypes(ignore []string, include []string) []FileType {
+	types := []FileType{}
+	add := func(t FileType, example string) {
+		switch {
+		case !t.Enabled:
+		case fileMatches(example, ignore):
+			t.Enabled, t.Reason = false, "ignored by header_ignore"
+		case len(include) > 0 && !fileMatches(example, include):
+			t.Enabled, t.Reason = false, "not matched by header_include"
+		}
+		types = append(types, t)
+	}
+
+	for lang, names := range Languages {
+		for _, name := range names {
+			example := name
+			if strings.HasPrefix(name, ".") {
+				example = "file" + name
+			}
+			style, ok := CommentStyleFor(example)
+			t := FileType{Name: name, Language: lang, Style: style, Enabled: ok}
+			switch name {
+			case ".m":
+				t.Setting = "matlab_m_files"
+				if MATLABFiles {
+					t.Language = "matlab"
+				}
+			case ".s", ".asm":
+				t.Setting = "asm_comment"
+			}
+			add(t, example)
+		}
+	}
+
+	for _, name := range append([]string{".jsonc", ".json5"}, commentedJSONNames...) {
+		t := FileType{Name: name, Language: "json", Style: jsonCommentStyle, Enabled: JSONComments, Setting: "json_comments"}
+		if !JSONComments {
+			t.Reason = "json_comments is not enabled"
+		}
+		example := name
+		if strings.HasPrefix(name, ".") {
+			example = "file" + name
+		}
+		add(t, example)
+	}
+
+	for ext := range helmTemplateExtensions {
+		name := "templates/*" + ext
+		add(FileType{Name: name, Language: "helm", Style: helmCommentStyle, Enabled: true}, "chart/templates/file"+ext)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Language != types[j].Language {
+			return types[i].Language < types[j].Language
+		}
+		return types[i].Name < types[j].Name
+	})
+	return types
+}
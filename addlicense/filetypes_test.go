@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+)
+
+func TestFileTypes(t *testing.T) {
+	defer func() { JSONComments = false }()
+
+	find := func(types []FileType, name string) FileType {
+		for _, ft := range types {
+			if ft.Name == name {
+				return ft
+			}
+		}
+		t.Fatalf("FileTypes didn't return %q", name)
+		return FileType{}
+	}
+
+	types := FileTypes(nil, nil)
+	if ft := find(types, ".go"); !ft.Enabled || ft.Language != "go" || ft.Style.String() != "//" {
+		t.Errorf("FileTypes returned %+v for .go files", ft)
+	}
+	if ft := find(types, ".jsonc"); ft.Enabled || ft.Reason != "json_comments is not enabled" {
+		t.Errorf("FileTypes without JSONComments returned %+v for .jsonc files", ft)
+	}
+	if ft := find(types, "templates/*.tpl"); !ft.Enabled || ft.Language != "helm" {
+		t.Errorf("FileTypes returned %+v for Helm templates", ft)
+	}
+
+	JSONComments = true
+	types = FileTypes([]string{"**/*.py"}, nil)
+	if ft := find(types, ".jsonc"); !ft.Enabled {
+		t.Errorf("FileTypes with JSONComments returned %+v for .jsonc files", ft)
+	}
+	if ft := find(types, ".py"); ft.Enabled || ft.Reason != "ignored by header_ignore" {
+		t.Errorf("FileTypes with .py files ignored returned %+v for them", ft)
+	}
+
+	types = FileTypes(nil, []string{"**/*.go", "**/*.tf"})
+	if ft := find(types, ".tf"); !ft.Enabled {
+		t.Errorf("FileTypes with .tf files included returned %+v for them", ft)
+	}
+	if ft := find(types, ".py"); ft.Enabled || ft.Reason != "not matched by header_include" {
+		t.Errorf("FileTypes with only .go and .tf files included returned %+v for .py files", ft)
+	}
+}
+
+func TestCommentStyleString(t *testing.T) {
+	tests := []struct {
+		style CommentStyle
+		want  string
+	}{
+		{CommentStyle{Mid: "// "}, "//"},
+		{CommentStyle{Top: "/*", Mid: " * ", Bottom: " */"}, "/* * */"},
+		{CommentStyle{Top: "<!--", Mid: " ", Bottom: "-->"}, "<!-- -->"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.style.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.style, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HeaderStyle controls how headers are framed within their comments
+type HeaderStyle string
+
+const (
+	// HeaderStylePlain leaves headers unframed
+	HeaderStylePlain HeaderStyle = "plain"
+
+	// HeaderStyleBoxed encloses headers in a box, with a border above, below,
+	// and to the right of them
+	HeaderStyleBoxed HeaderStyle = "boxed"
+
+	// HeaderStyleBanner places a border above and below headers
+	HeaderStyleBanner HeaderStyle = "banner"
+)
+
+// DefaultHeaderBorder is the character used to draw the borders of framed
+// headers if none is configured
+const DefaultHeaderBorder = "="
+
+// headerBorders are the characters that may be used to draw borders. Others,
+// such as "/" or "-", could combine with comment delimiters to end a comment
+// early or make it invalid.
+const headerBorders = "=*#~+"
+
+// ParseHeaderStyle validates a header style from config or flags. An empty
+// string results in the default style, HeaderStylePlain.
+func ParseHeaderStyle(s string) (HeaderStyle, error) {
+	switch HeaderStyle(s) {
+	case "", HeaderStylePlain:
+		return HeaderStylePlain, nil
+	case HeaderStyleBoxed, HeaderStyleBanner:
+		return HeaderStyle(s), nil
+	}
+	return "", fmt.Errorf("invalid header style %q: must be one of %q, %q, or %q", s, HeaderStylePlain, HeaderStyleBoxed, HeaderStyleBanner)
+}
+
+// ValidateHeaderBorder returns an error if border can't be used to draw the
+// borders of framed headers. An empty string results in DefaultHeaderBorder.
+func ValidateHeaderBorder(border string) error {
+	if border == "" || (len(border) == 1 && strings.Contains(headerBorders, border)) {
+		return nil
+	}
+	return fmt.Errorf("invalid header border %q: must be a single one of %q", border, strings.Split(headerBorders, ""))
+}
+
+// executeFramedTemplate is like executeTemplate, but frames the header in the
+// given style with borders drawn using border. For example, a boxed header in
+// a C file looks like:
+//
+//	/*=================================
+//	 * Copyright (c) HashiCorp, Inc.   =
+//	 * SPDX-License-Identifier: MPL-2.0 =
+//	 *===============================*/
+func executeFramedTemplate(t *template.Template, d LicenseData, style CommentStyle, hs HeaderStyle, border string) ([]byte, error) {
+	if hs == "" || hs == HeaderStylePlain {
+		return executeTemplate(t, d, style.Top, style.Mid, style.Bottom)
+	}
+	if border == "" {
+		border = DefaultHeaderBorder
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	lines := []string{}
+	width := 0
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		line := strings.TrimRightFunc(s.Text(), unicode.IsSpace)
+		lines = append(lines, line)
+		width = max(width, utf8.RuneCountInString(line))
+	}
+
+	// Lines of a boxed header are padded so that the right border lines up,
+	// one space past the longest line
+	inner := len(style.Mid) + width + 2
+	if hs == HeaderStyleBanner {
+		inner = len(style.Mid) + width
+	}
+
+	// Line comment styles (e.g., "# ") draw their borders after the comment
+	// characters, and block comment styles (e.g., "/*") within the opening and
+	// closing delimiters
+	open, close := strings.TrimRight(style.Mid, " "), ""
+	if style.Top != "" {
+		open = style.Top
+		close = strings.TrimLeft(style.Bottom, " ")
+	}
+	closeLead := strings.TrimRight(style.Mid, " ")
+	if style.Top == "" {
+		closeLead = open
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintln(&out, open+strings.Repeat(border, max(inner-len(open), 1)))
+	for _, line := range lines {
+		if hs == HeaderStyleBoxed {
+			padding := width - utf8.RuneCountInString(line)
+			fmt.Fprintln(&out, style.Mid+line+strings.Repeat(" ", padding+1)+border)
+		} else {
+			fmt.Fprintln(&out, strings.TrimRightFunc(style.Mid+line, unicode.IsSpace))
+		}
+	}
+	fmt.Fprintln(&out, closeLead+strings.Repeat(border, max(inner-len(closeLead)-len(close), 1))+close)
+	fmt.Fprintln(&out)
+	return out.Bytes(), nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+	"text/template"
+)
+
+// Test that headers are framed in each of the header styles.
+func TestFramedHeader(t *testing.T) {
+	tpl := template.Must(template.New("").Parse(tmplSPDX))
+	data := LicenseData{Holder: "H", SPDXID: "MIT"}
+
+	tests := []struct {
+		path   string
+		style  HeaderStyle
+		border string
+		want   string
+	}{
+		{"f.go", "", "", "// Copyright (c) H\n// SPDX-License-Identifier: MIT\n\n"},
+		{"f.go", HeaderStylePlain, "", "// Copyright (c) H\n// SPDX-License-Identifier: MIT\n\n"},
+		{"f.c", HeaderStyleBoxed, "", "/*===============================\n * Copyright (c) H              =\n * SPDX-License-Identifier: MIT =\n *=============================*/\n\n"},
+		{"f.c", HeaderStyleBanner, "*", "/******************************\n * Copyright (c) H\n * SPDX-License-Identifier: MIT\n *****************************/\n\n"},
+		{"f.py", HeaderStyleBoxed, "#", "################################\n# Copyright (c) H              #\n# SPDX-License-Identifier: MIT #\n################################\n\n"},
+		{"f.py", HeaderStyleBanner, "~", "#~~~~~~~~~~~~~~~~~~~~~~~~~~~~~\n# Copyright (c) H\n# SPDX-License-Identifier: MIT\n#~~~~~~~~~~~~~~~~~~~~~~~~~~~~~\n\n"},
+		{"f.html", HeaderStyleBoxed, "", "<!--===========================\n Copyright (c) H              =\n SPDX-License-Identifier: MIT =\n============================-->\n\n"},
+	}
+
+	for _, tt := range tests {
+		got, err := licenseHeader(tt.path, tpl, data, Options{HeaderStyle: tt.style, HeaderBorder: tt.border})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("licenseHeader(%q) in style %q returned:\n%s\nwant:\n%s", tt.path, tt.style, got, tt.want)
+		}
+	}
+}
+
+// Test that framed headers are recognized once added, so they aren't added
+// again.
+func TestFramedHeaderIdempotent(t *testing.T) {
+	tpl := template.Must(template.New("").Parse(tmplMPL))
+	data := LicenseData{Holder: "H", Year: "2025"}
+
+	for _, style := range []HeaderStyle{HeaderStyleBoxed, HeaderStyleBanner} {
+		opts := Options{HeaderStyle: style}
+		lic, err := licenseHeader("f.go", tpl, data, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, updated := prependLicense("f.go", []byte("package a\n"), lic, opts)
+		if !updated {
+			t.Errorf("prependLicense in style %q returned updated: false, want true", style)
+		}
+		if _, updated := prependLicense("f.go", b, lic, opts); updated {
+			t.Errorf("prependLicense in style %q would add a second header", style)
+		}
+	}
+}
+
+func TestParseHeaderStyle(t *testing.T) {
+	for _, s := range []string{"", "plain", "boxed", "banner"} {
+		if _, err := ParseHeaderStyle(s); err != nil {
+			t.Errorf("ParseHeaderStyle(%q) returned error: %v", s, err)
+		}
+	}
+	if _, err := ParseHeaderStyle("fancy"); err == nil {
+		t.Errorf("ParseHeaderStyle(%q) returned no error", "fancy")
+	}
+
+	for _, b := range []string{"", "=", "*", "#", "~", "+"} {
+		if err := ValidateHeaderBorder(b); err != nil {
+			t.Errorf("ValidateHeaderBorder(%q) returned error: %v", b, err)
+		}
+	}
+	for _, b := range []string{"/", "-", "==", "é"} {
+		if err := ValidateHeaderBorder(b); err == nil {
+			t.Errorf("ValidateHeaderBorder(%q) returned no error", b)
+		}
+	}
+}
@@ -3,62 +3,18 @@
 
 set -euo pipefail
 
-# This is the officially updated list of SPDX license identifiers in JSON form
-licenses=$(curl --silent https://raw.githubusercontent.com/spdx/license-list-data/master/json/licenses.json)
+# Refreshes the SPDX license and exception lists that are embedded in the
+# copywrite binary. Users can refresh their local copy without a new release by
+# running `copywrite spdx update`, which fetches these same files.
 
-OUTPUT_FILE_PATH="./spdx.go"
+OUTPUT_DIR="./spdx"
+BASE_URL="https://raw.githubusercontent.com/spdx/license-list-data/master/json"
 
+mkdir -p "$OUTPUT_DIR"
 
-###############
-# File Header #
-###############
-cat <<EOF | tee "$OUTPUT_FILE_PATH"
-// Copyright (c) HashiCorp, Inc.
-
-package addlicense
-
-// This file is autogenerated by running ./generate-spdx-list.sh
-
-var spdxIds = []string{
-EOF
-
-###############
-# Contents    #
-###############
-
-# Helper function to avoid needing to handle redirects
-outfile() {
-  content="$@"
-  echo "$content" >> $OUTPUT_FILE_PATH
-}
-
-echo $licenses | jq '.licenses[] | select(.isDeprecatedLicenseId == false)' -c | sort | while read -r l ;do
-  # this is super slow... but this script is basically never reran, sooo... meh?
-  id=$(jq '.licenseId' -r <<< "$l")
-  name=$(jq '.name' -r <<< "$l")
-  link=$(jq '.reference' -r <<< "$l")
-  outfile ""
-  outfile "	// $name"
-  outfile "	// $link" 
-  outfile "	\"$id\","
+for list in licenses exceptions; do
+  curl --silent --fail "$BASE_URL/$list.json" | jq '.' > "$OUTPUT_DIR/$list.json"
+  echo "Updated $OUTPUT_DIR/$list.json"
 done
 
-
-###############
-# File footer #
-###############
-cat <<EOF | tee -a "$OUTPUT_FILE_PATH"
-}
-
-// ValidSPDX takes in a string and returns true if it represents a valid SPDX ID
-func ValidSPDX(id string) bool {
-	for _, v := range spdxIds {
-		if v == id {
-			return true
-		}
-	}
-	return false
-}
-EOF
-
-go fmt $OUTPUT_FILE_PATH
+jq -r '"SPDX license list version: \(.licenseListVersion)"' "$OUTPUT_DIR/licenses.json"
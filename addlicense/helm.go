@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// helmCommentStyle comments out headers in Helm chart templates with a
+// template comment, which is removed when the chart is rendered. The trailing
+// trim marker also removes the blank lines that follow the header, so that
+// rendered output is unchanged, even when a template starts with an action
+// such as {{- define }}.
+var helmCommentStyle = CommentStyle{Top: "{{/*", Mid: "  ", Bottom: "*/ -}}"}
+
+// helmTemplateExtensions are the types of files in a chart's templates
+// directory that are rendered as templates
+var helmTemplateExtensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".tpl":  true,
+	".txt":  true,
+}
+
+// chartDirs caches whether each directory checked by isChartDir is the root
+// of a Helm chart
+var chartDirs sync.Map
+
+// isChartDir reports whether dir is the root of a Helm chart
+func isChartDir(dir string) bool {
+	if v, ok := chartDirs.Load(dir); ok {
+		return v.(bool)
+	}
+	_, err := os.Stat(filepath.Join(dir, "Chart.yaml"))
+	chartDirs.Store(dir, err == nil)
+	return err == nil
+}
+
+// isHelmTemplate reports whether the file at path is a template in a Helm
+// chart: a file within a templates directory beside a Chart.yaml
+func isHelmTemplate(path string) bool {
+	if !helmTemplateExtensions[fileExtension(filepath.Base(path))] {
+		return false
+	}
+
+	dir := filepath.Dir(path)
+	for {
+		parent := filepath.Dir(dir)
+		if filepath.Base(dir) == "templates" && isChartDir(parent) {
+			return true
+		}
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+// Test that templates in Helm charts get headers which don't change their
+// rendered output.
+func TestHelmTemplates(t *testing.T) {
+	tmp := t.TempDir()
+	chart := filepath.Join(tmp, "charts", "app")
+	for _, dir := range []string{filepath.Join(chart, "templates", "tests"), filepath.Join(tmp, "other", "templates")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(chart, "Chart.yaml"), []byte("name: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(chart, "templates", "deployment.yaml"), true},
+		{filepath.Join(chart, "templates", "_helpers.tpl"), true},
+		{filepath.Join(chart, "templates", "NOTES.txt"), true},
+		{filepath.Join(chart, "templates", "tests", "test.yaml"), true},
+		{filepath.Join(chart, "values.yaml"), false},
+		{filepath.Join(tmp, "other", "templates", "a.yaml"), false},
+	}
+	for _, tt := range tests {
+		if got := isHelmTemplate(tt.path); got != tt.want {
+			t.Errorf("isHelmTemplate(%q) returned %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	tpl := template.Must(template.New("").Parse("{{.Holder}}"))
+	path := filepath.Join(chart, "templates", "deployment.yaml")
+	lic, err := licenseHeader(path, tpl, LicenseData{Holder: "H"}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{{/*\n  H\n*/ -}}\n\n"; string(lic) != want {
+		t.Errorf("licenseHeader(%q) returned: %q, want: %q", path, lic, want)
+	}
+
+	contents := "{{- define \"app.name\" -}}\napp\n{{- end }}\nname: {{ template \"app.name\" . }}\n"
+	got, _ := prependLicense(path, []byte(contents), lic, Options{})
+	if want := string(lic) + contents; string(got) != want {
+		t.Errorf("prependLicense returned contents: %q, want %q", got, want)
+	}
+
+	render := func(s string) string {
+		var out bytes.Buffer
+		if err := template.Must(template.New("").Parse(s)).Execute(&out, nil); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	if before, after := render(contents), render(string(got)); before != after {
+		t.Errorf("header changed rendered output from %q to %q", before, after)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// JSONComments enables headers in JSON files that tolerate comments, such as
+// .jsonc and .json5 files and tsconfig.json. It may be set (e.g., from a
+// project's config) before any files are processed. Plain JSON files never get
+// headers, as comments would make them invalid.
+var JSONComments bool
+
+// jsonCommentStyle is the comment style of headers in JSON files that
+// tolerate comments
+var jsonCommentStyle = CommentStyle{Mid: "// "}
+
+// commentedJSONNames are the names of .json files that are read by parsers
+// which tolerate comments, matched without case sensitivity
+var commentedJSONNames = []string{
+	"tsconfig.json",
+	"jsconfig.json",
+	".eslintrc.json",
+	"devcontainer.json",
+	".devcontainer.json",
+}
+
+// isCommentedJSON reports whether the file at path is a JSON file in which
+// comments are allowed
+func isCommentedJSON(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	switch filepath.Ext(base) {
+	case ".jsonc", ".json5":
+		return true
+	case ".json":
+	default:
+		return false
+	}
+
+	for _, name := range commentedJSONNames {
+		if base == name {
+			return true
+		}
+	}
+
+	// Variants such as tsconfig.build.json extend a base config, and VS Code
+	// reads every file in .vscode as JSON with comments
+	if strings.HasPrefix(base, "tsconfig.") || strings.HasPrefix(base, "jsconfig.") {
+		return true
+	}
+	return filepath.Base(filepath.Dir(path)) == ".vscode"
+}
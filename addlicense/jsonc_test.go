@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import "testing"
+
+// Test that only JSON files which tolerate comments get headers, and only when
+// enabled.
+func TestJSONComments(t *testing.T) {
+	defer func(v bool) { JSONComments = v }(JSONComments)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"f.jsonc", true},
+		{"F.JSON5", true},
+		{"tsconfig.json", true},
+		{"web/tsconfig.build.json", true},
+		{"jsconfig.json", true},
+		{".eslintrc.json", true},
+		{".devcontainer/devcontainer.json", true},
+		{".vscode/settings.json", true},
+		{"package.json", false},
+		{"testdata/fixture.json", false},
+		{"settings.json", false},
+		{"tsconfig.json.txt", false},
+	}
+
+	for _, tt := range tests {
+		JSONComments = true
+		if _, got := CommentStyleFor(tt.path); got != tt.want {
+			t.Errorf("CommentStyleFor(%q) with JSONComments returned %v, want %v", tt.path, got, tt.want)
+		}
+
+		JSONComments = false
+		if _, got := CommentStyleFor(tt.path); got {
+			t.Errorf("CommentStyleFor(%q) without JSONComments returned true, want false", tt.path)
+		}
+	}
+}
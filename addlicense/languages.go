@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Languages maps the name of each language that files can be filtered by to
+// the file extensions (or, for files without one, the lowercase file names)
+// that belong to it. Every extension belongs to at most one language.
+var Languages = map[string][]string{
+	"assembly":   {".s", ".asm"},
+	"asciidoc":   {".adoc", ".asciidoc"},
+	"astro":      {".astro"},
+	"bazel":      {".bzl", ".bazel", ".bazelrc", ".bzlmod", "build", "workspace"},
+	"c":          {".c", ".h"},
+	"cairo":      {".cairo"},
+	"cmake":      {".cmake", "cmakelists.txt"},
+	"cpp":        {".cc", ".cpp", ".hh", ".hpp", ".cu", ".cuh"},
+	"csharp":     {".cs"},
+	"css":        {".css", ".scss", ".sass"},
+	"dart":       {".dart"},
+	"devicetree": {".dts", ".dtsi"},
+	"dockerfile": {".dockerfile", "dockerfile"},
+	"ejs":        {".ejs"},
+	"erlang":     {".erl"},
+	"fortran":    {".f", ".for", ".f90", ".f95", ".f03", ".f08"},
+	"go":         {".go"},
+	"graphql":    {".graphql", ".gql", ".sdl"},
+	"graphviz":   {".gv"},
+	"groovy":     {".groovy"},
+	"handlebars": {".hbs"},
+	"haskell":    {".hs"},
+	"hcl":        {".hcl", ".hcl2", ".nomad"},
+	"html":       {".html", ".htm"},
+	"java":       {".java"},
+	"javascript": {".js", ".mjs", ".cjs", ".jsx"},
+	"julia":      {".jl"},
+	"kotlin":     {".kt", ".kts"},
+	"linker":     {".ld", ".lds"},
+	"lisp":       {".el", ".lisp"},
+	"lr":         {".lr"},
+	"objc":       {".m", ".mm"},
+	"ocaml":      {".ml", ".mli", ".mll", ".mly"},
+	"org":        {".org"},
+	"perl":       {".pl"},
+	"php":        {".php"},
+	"powershell": {".ps1", ".psd1", ".psm1"},
+	"prisma":     {".prisma"},
+	"protobuf":   {".proto", ".textproto", ".txtpb"},
+	"puppet":     {".pp"},
+	"python":     {".py"},
+	"r":          {".r"},
+	"ruby":       {".rb", ".ru", "gemfile"},
+	"rust":       {".rs"},
+	"scala":      {".scala"},
+	"shell":      {".sh", ".bash", ".zsh"},
+	"solidity":   {".sol"},
+	"sql":        {".sql"},
+	"svelte":     {".svelte"},
+	"swift":      {".swift"},
+	"tcl":        {".tcl"},
+	"terraform":  {".tf", ".tfvars"},
+	"tex":        {".tex", ".sty"},
+	"txtar":      {".txtar"},
+	"typescript": {".ts", ".tsx"},
+	"verilog":    {".v", ".sv"},
+	"vue":        {".vue"},
+	"xml":        {".xml", ".wxi", ".wxl", ".wxs"},
+	"yaml":       {".yaml", ".yml"},
+}
+
+// languageByExtension is the inverse of Languages
+var languageByExtension = func() map[string]string {
+	m := map[string]string{}
+	for lang, exts := range Languages {
+		for _, ext := range exts {
+			m[ext] = lang
+		}
+	}
+	return m
+}()
+
+// LanguageNames returns the names of every language in Languages, sorted
+func LanguageNames() []string {
+	names := make([]string, 0, len(Languages))
+	for name := range Languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LanguageOf returns the name of the language of the file at path, according
+// to Languages, or "" if it isn't one of them. Like CommentStyleFor, only the
+// file's name is used.
+func LanguageOf(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	if base == "cmakelists.txt" || strings.HasSuffix(base, ".cmake.in") {
+		return "cmake"
+	}
+	return languageByExtension[fileExtension(base)]
+}
+
+// ValidateLanguages returns an error if any of names is not a language in
+// Languages
+func ValidateLanguages(names []string) error {
+	invalid := []string{}
+	for _, name := range names {
+		if _, ok := Languages[strings.ToLower(name)]; !ok {
+			invalid = append(invalid, name)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("unknown languages: %s (must be one of %s)", strings.Join(invalid, ", "), strings.Join(LanguageNames(), ", "))
+	}
+	return nil
+}
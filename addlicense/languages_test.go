@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+)
+
+// Test that every file in a language can have a header added, and that no
+// extension belongs to more than one language.
+func TestLanguages(t *testing.T) {
+	seen := map[string]string{}
+	for lang, exts := range Languages {
+		for _, ext := range exts {
+			if other, ok := seen[ext]; ok {
+				t.Errorf("extension %q belongs to both %q and %q", ext, lang, other)
+			}
+			seen[ext] = lang
+
+			path := "file" + ext
+			if ext[0] != '.' {
+				path = ext
+			}
+			if _, ok := CommentStyleFor(path); !ok {
+				t.Errorf("%s files (%q) have no comment style", lang, path)
+			}
+		}
+	}
+}
+
+func TestLanguageOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", "go"},
+		{"a/b/setup.py", "python"},
+		{"SCRIPT.SH", "shell"},
+		{"main.tf", "terraform"},
+		{"config.hcl", "hcl"},
+		{"Dockerfile", "dockerfile"},
+		{"dir/CMakeLists.txt", "cmake"},
+		{"config.cmake.in", "cmake"},
+		{"README.md", ""},
+		{"LICENSE", ""},
+	}
+
+	for _, tt := range tests {
+		if got := LanguageOf(tt.path); got != tt.want {
+			t.Errorf("LanguageOf(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestValidateLanguages(t *testing.T) {
+	if err := ValidateLanguages([]string{"go", "Python"}); err != nil {
+		t.Errorf("ValidateLanguages returned unexpected error: %v", err)
+	}
+	if err := ValidateLanguages([]string{"go", "golang"}); err == nil {
+		t.Errorf("ValidateLanguages didn't return an error for an unknown language")
+	}
+}
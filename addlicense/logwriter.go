@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"log"
+)
+
+// logWriter serializes the log messages of files processed concurrently onto a
+// single logger from one goroutine, so that each file's messages are written
+// together and never interleave with another file's, whatever the logger
+// writes to. Each message is written with logger.Print, so that it gains the
+// logger's prefix and flags.
+type logWriter struct {
+	ch   chan []string
+	done chan struct{}
+}
+
+func newLogWriter(logger *log.Logger) *logWriter {
+	w := &logWriter{
+		ch:   make(chan []string, 100),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		for messages := range w.ch {
+			for _, msg := range messages {
+				logger.Print(msg)
+			}
+		}
+	}()
+	return w
+}
+
+// Write makes logWriter an io.Writer for a log.Logger, writing each message
+// on its own
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.emit([]string{string(p)})
+	return len(p), nil
+}
+
+// emit writes messages together, in order
+func (w *logWriter) emit(messages []string) {
+	if len(messages) > 0 {
+		w.ch <- messages
+	}
+}
+
+// Close waits for every message to be written. The logWriter must not be used
+// afterwards.
+func (w *logWriter) Close() error {
+	close(w.ch)
+	<-w.done
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Test that messages emitted concurrently are written whole, with each batch
+// kept together, even to a writer that isn't safe for concurrent use.
+func TestLogWriter(t *testing.T) {
+	var buf strings.Builder
+	w := newLogWriter(log.New(&buf, "prefix: ", 0))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w.emit([]string{fmt.Sprintf("file %d first", i), fmt.Sprintf("file %d second", i)})
+		}(i)
+	}
+	wg.Wait()
+	if _, err := fmt.Fprint(w, "last"); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 101 {
+		t.Fatalf("logWriter wrote %d lines, want 101", len(lines))
+	}
+	for i := 0; i < 100; i += 2 {
+		var n int
+		if _, err := fmt.Sscanf(lines[i], "prefix: file %d first", &n); err != nil {
+			t.Fatalf("logWriter wrote %q, want the first message of a file", lines[i])
+		}
+		if want := fmt.Sprintf("prefix: file %d second", n); lines[i+1] != want {
+			t.Errorf("logWriter wrote %q after %q, want %q", lines[i+1], lines[i], want)
+		}
+	}
+	if lines[100] != "prefix: last" {
+		t.Errorf("logWriter wrote %q last, want %q", lines[100], "prefix: last")
+	}
+}
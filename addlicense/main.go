@@ -22,12 +22,17 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
@@ -63,6 +68,10 @@ var (
 	checkonly = flag.Bool("check", false, "check only mode: verify presence of license headers and exit with non-zero code if missing")
 )
 
+// ErrMissingHeader is returned by Run and CheckFiles in check-only mode when
+// any file is missing a license header
+var ErrMissingHeader = errors.New("missing license header")
+
 func init() {
 	flag.Usage = func() {
 		fmt.Fprint(os.Stderr, helpText)
@@ -92,6 +101,11 @@ const (
 	spdxOff  spdxFlag = ""
 	spdxOn   spdxFlag = "true" // value set by flag package on bool flag
 	spdxOnly spdxFlag = "only"
+
+	// spdxIdentifierOnly omits the copyright statement, leaving only the SPDX
+	// identifier. It is selected with Options.OmitCopyright rather than the -s
+	// flag.
+	spdxIdentifierOnly spdxFlag = "identifier-only"
 )
 
 // IsBoolFlag causes a bare '-s' flag to be set as the string 'true'.  This
@@ -147,10 +161,11 @@ func main() {
 		*checkonly,
 		patterns,
 		logger,
+		Options{},
 	)
 
 	if err != nil {
-		if err.Error() == "missing license header" {
+		if errors.Is(err, ErrMissingHeader) {
 			// this retains the historical behavior of addLicense, which is to give a
 			// non-zero exit code when the -check flag is used and headers are needed
 			os.Exit(1)
@@ -160,7 +175,9 @@ func main() {
 	}
 }
 
-func validatePatterns(patterns []string) error {
+// ValidatePatterns returns an error listing any of the given patterns that are
+// not valid doublestar glob patterns
+func ValidatePatterns(patterns []string) error {
 	invalidPatterns := []string{}
 	for _, p := range patterns {
 		if !doublestar.ValidatePattern(p) {
@@ -177,6 +194,200 @@ func validatePatterns(patterns []string) error {
 	return nil
 }
 
+// Options holds optional behaviors layered on top of the upstream addlicense
+// feature set. The zero value preserves upstream behavior.
+type Options struct {
+	// Skip, if set, is consulted for every file that is not already excluded by
+	// an ignore pattern. Returning true excludes the file from processing, and
+	// the reason is included in debug logs. It may be called concurrently.
+	Skip func(path string) (skip bool, reason string)
+
+	// Parallelism is the number of directories read concurrently while
+	// discovering files. Defaults to the number of CPUs.
+	Parallelism int
+
+	// NoSort streams per-file log messages as soon as each file is processed,
+	// rather than buffering them to be emitted sorted by path. Either way, the
+	// messages for each file are written together.
+	NoSort bool
+
+	// CopyrightKeywords are additional words or phrases that indicate a file
+	// already carries a copyright statement, such as translations of
+	// "copyright" not already covered by localizedCopyrightKeywords
+	CopyrightKeywords []string
+
+	// ConvertPublicDomain, if set, replaces a comment at the top of a file
+	// that dedicates it to the public domain (such as with the Unlicense or
+	// CC0) with the configured header. By default, a public domain dedication
+	// counts as a license header, and the file is left alone.
+	ConvertPublicDomain bool
+
+	// NeverTouch is a list of additional doublestar patterns that are always
+	// skipped, on top of DefaultNeverTouch
+	NeverTouch []string
+
+	// NeverTouchExceptions opts back in to files covered by DefaultNeverTouch.
+	// Each must be one of its patterns, written exactly as it appears there
+	// (e.g., "**/*.pb.go"), other than those in requiredNeverTouch.
+	NeverTouchExceptions []string
+
+	// Include, if set, is a list of doublestar patterns that files must match
+	// one of to be processed. It is evaluated before the ignore patterns.
+	Include []string
+
+	// SkipHidden, if set, skips hidden files and directories, whose names
+	// start with a dot (e.g., .github or .eslintrc.js), other than the
+	// directory being processed itself
+	SkipHidden bool
+
+	// SkippedHidden, if set, is called for every hidden directory that is
+	// skipped because of SkipHidden, without descending into it
+	SkippedHidden func(dir string)
+
+	// HeaderSpacing, if set, is the number of blank lines placed between a
+	// header and the content that follows it. Blank lines already following a
+	// previously added header are normalized to match. If nil, a single blank
+	// line follows new headers and existing headers are left as they are.
+	HeaderSpacing *int
+
+	// PreserveAdjacent are regular expressions for attribution lines to keep
+	// attached to the header directly above them when it is updated, on top of
+	// DefaultPreserveAdjacent
+	PreserveAdjacent []string
+
+	// SyntaxAware, if set, places new headers using a scanner for the syntax
+	// of a file's language where one is available (e.g., below the opening
+	// tag of PHP files that begin with HTML, or below JSX pragma comments),
+	// rather than only by matching lines at the top of the file. Detection of
+	// existing headers is unaffected.
+	SyntaxAware bool
+
+	// HeaderStyle controls how new headers are framed within their comments,
+	// with borders drawn using HeaderBorder (DefaultHeaderBorder if empty). The
+	// zero value adds plain headers.
+	HeaderStyle  HeaderStyle
+	HeaderBorder string
+
+	// CopyrightFormat is the template for the copyright statements in new
+	// headers (see FormatCopyright). If empty, DefaultCopyrightFormat is used.
+	// It doesn't apply to custom template files.
+	CopyrightFormat string
+
+	// OmitCopyright, if set, leaves the copyright statement out of new headers,
+	// so that they only include an SPDX identifier
+	OmitCopyright bool
+
+	// HeaderTemplate, if set, is the template for new headers in place of the
+	// copyright statement and SPDX identifier, such as one returned by
+	// LicenseTemplate. The SPDX identifier is added below it, unless the
+	// license has none or the template already includes one.
+	HeaderTemplate string
+
+	// TemplateEnv lists the environment variables that header templates may
+	// read with the env function (see TemplateFuncs)
+	TemplateEnv []string
+
+	// YAMLHeaderAtTop, if set, places headers in YAML files at the very top,
+	// rather than below any directives and the marker that starts the first
+	// document
+	YAMLHeaderAtTop bool
+
+	// FailFast, if set, stops checking files in check-only mode as soon as one
+	// is found to be missing a header. Files already being checked are
+	// finished, but no more are started, so only the first few violations are
+	// reported.
+	FailFast bool
+
+	// Skipped, if set, is called for every file that is exempted from needing
+	// a header because of its contents (e.g., generated or minified files),
+	// along with a human-readable reason. It may be called concurrently.
+	Skipped func(path string, reason string)
+
+	// Changed, if set, is called for every file that had a header added or
+	// updated or, in check-only mode, that is missing one. existing reports
+	// whether the file already had a header. It may be called concurrently.
+	Changed func(path string, existing bool)
+
+	// Stats, if set, is populated with a summary of what Run did once it
+	// completes
+	Stats *Stats
+
+	// tally counts files for Stats while Run is in progress
+	tally *tally
+}
+
+// DefaultNeverTouch lists files and folders that are never processed by any
+// walker, regardless of configuration
+var DefaultNeverTouch = []string{
+	// Version control and copywrite's own config
+	"**/.git/**",
+	"**/.hg/**",
+	"**/.svn/**",
+	"**/.copywrite.hcl",
+
+	// Editor settings
+	"**/.idea/**",
+	"**/.vscode/**",
+
+	// CI configuration that does not benefit from headers
+	".github/workflows/**",
+	".github/dependabot.yml",
+
+	// Vendored dependencies and their lockfiles, which are managed by tools
+	"**/node_modules/**",
+	"**/package-lock.json",
+	"**/npm-shrinkwrap.json",
+	"**/yarn.lock",
+	"**/pnpm-lock.yaml",
+	"**/go.sum",
+	"**/Cargo.lock",
+	"**/Gemfile.lock",
+	"**/poetry.lock",
+	"**/composer.lock",
+	"**/.terraform.lock.hcl",
+	"**/MODULE.bazel.lock",
+
+	// Generated code and data
+	"**/*.pb.go",
+
+	// Minified assets and source maps
+	"**/*.min.*",
+	"**/*.map",
+}
+
+// requiredNeverTouch are the patterns in DefaultNeverTouch that can't be made
+// exceptions with Options.NeverTouchExceptions
+var requiredNeverTouch = []string{"**/.git/**", "**/.hg/**", "**/.svn/**", "**/.copywrite.hcl"}
+
+// ValidateNeverTouchExceptions returns an error if any of the given exceptions
+// is not a pattern in DefaultNeverTouch that may be opted back in to
+func ValidateNeverTouchExceptions(exceptions []string) error {
+	invalid := []string{}
+	for _, e := range exceptions {
+		if !slices.Contains(DefaultNeverTouch, e) || slices.Contains(requiredNeverTouch, e) {
+			invalid = append(invalid, e)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid never_touch_exceptions: %s (each must be a built-in never-touch pattern other than %s)", strings.Join(invalid, ", "), strings.Join(requiredNeverTouch, ", "))
+	}
+	return nil
+}
+
+// defaultNeverTouch returns DefaultNeverTouch, less any exceptions in opts
+func defaultNeverTouch(opts Options) []string {
+	if len(opts.NeverTouchExceptions) == 0 {
+		return DefaultNeverTouch
+	}
+	patterns := make([]string, 0, len(DefaultNeverTouch))
+	for _, p := range DefaultNeverTouch {
+		if !slices.Contains(opts.NeverTouchExceptions, p) {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
 // Run executes addLicense with supplied variables
 func Run(
 	ignorePatternList []string,
@@ -187,33 +398,90 @@ func Run(
 	checkonly bool,
 	patterns []string,
 	logger *log.Logger,
+	opts Options,
 ) error {
 	// verify that all ignorePatterns are valid
-	err := validatePatterns(ignorePatternList)
+	err := ValidatePatterns(ignorePatternList)
+	if err != nil {
+		return err
+	}
+	err = ValidatePatterns(opts.NeverTouch)
+	if err != nil {
+		return err
+	}
+	err = ValidatePatterns(opts.Include)
+	if err != nil {
+		return err
+	}
+	err = ValidateNeverTouchExceptions(opts.NeverTouchExceptions)
+	if err != nil {
+		return err
+	}
+	err = ValidatePreserveAdjacent(opts.PreserveAdjacent)
 	if err != nil {
 		return err
 	}
 	ignorePatterns = ignorePatternList
 
+	defer trackStats(&opts)()
+
+	if opts.OmitCopyright {
+		spdx = spdxIdentifierOnly
+	}
 	tpl, err := fetchTemplate(license.SPDXID, licenseFileOverride, spdx)
 	if err != nil {
 		return err
 	}
-	t, err := template.New("").Parse(tpl)
+	if opts.HeaderTemplate != "" {
+		tpl = opts.HeaderTemplate
+		if license.SPDXID != "" && !strings.Contains(tpl, "SPDX-License-Identifier") {
+			tpl += spdxSuffix
+		}
+	}
+	t, err := template.New("").Funcs(TemplateFuncs(opts.TemplateEnv)).Parse(withCopyrightFormat(tpl, opts.CopyrightFormat))
 	if err != nil {
 		return err
 	}
+	if opts.HeaderTemplate != "" || licenseFileOverride != "" {
+		if err := ValidateHeaderTemplate(t, license, ignorePatternList, opts); err != nil {
+			return err
+		}
+	}
+
+	// Every log message is written by a single goroutine, with each file's
+	// messages buffered and written together once it has been processed. Unless
+	// streaming output was requested, files are written in path order once
+	// every file has been processed.
+	output := newLogWriter(logger)
+	var recordedMu sync.Mutex
+	recorded := map[string]*messageRecorder{}
 
 	// process at most 1000 files in parallel
 	ch := make(chan *file, 1000)
 	done := make(chan struct{})
 	var out error
+	var failed atomic.Bool
 	go func() {
 		var wg errgroup.Group
 		for f := range ch {
 			f := f // https://golang.org/doc/faq#closures_and_goroutines
+			if checkonly && opts.FailFast && failed.Load() {
+				// Keep draining ch so that walk isn't blocked
+				continue
+			}
 			wg.Go(func() error {
-				err := processFile(f, t, license, checkonly, verbose, logger)
+				r := &messageRecorder{}
+				err := processFile(f, t, license, checkonly, verbose, opts, log.New(r, "", 0))
+				if err != nil {
+					failed.Store(true)
+				}
+				if opts.NoSort {
+					output.emit(r.messages)
+				} else {
+					recordedMu.Lock()
+					recorded[f.path] = r
+					recordedMu.Unlock()
+				}
 				return err
 			})
 		}
@@ -221,21 +489,230 @@ func Run(
 		close(done)
 	}()
 
+	walkLogger := log.New(output, "", 0)
 	for _, d := range patterns {
-		if err := walk(ch, d, logger); err != nil {
+		if err := walk(ch, d, opts, walkLogger); err != nil {
+			close(ch)
+			<-done
+			output.Close()
 			return err
 		}
 	}
 	close(ch)
 	<-done
 
+	paths := make([]string, 0, len(recorded))
+	for p := range recorded {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		output.emit(recorded[p].messages)
+	}
+	output.Close()
+
 	return out
 }
 
-func processFile(f *file, t *template.Template, license LicenseData, checkonly bool, verbose bool, logger *log.Logger) error {
+// messageRecorder is an io.Writer for a log.Logger that keeps each log message
+// separately, so they can later be replayed through another logger
+type messageRecorder struct {
+	messages []string
+}
+
+func (r *messageRecorder) Write(p []byte) (int, error) {
+	r.messages = append(r.messages, string(p))
+	return len(p), nil
+}
+
+// Walk calls fn for every file under the given patterns that would be
+// processed by Run, honoring the same ignore patterns and Options.Skip. fn may
+// be called concurrently from multiple goroutines.
+func Walk(ignorePatternList []string, patterns []string, logger *log.Logger, opts Options, fn func(path string) error) error {
+	err := ValidatePatterns(ignorePatternList)
+	if err != nil {
+		return err
+	}
+	err = ValidatePatterns(opts.NeverTouch)
+	if err != nil {
+		return err
+	}
+	err = ValidatePatterns(opts.Include)
+	if err != nil {
+		return err
+	}
+	err = ValidateNeverTouchExceptions(opts.NeverTouchExceptions)
+	if err != nil {
+		return err
+	}
+	err = ValidatePreserveAdjacent(opts.PreserveAdjacent)
+	if err != nil {
+		return err
+	}
+	ignorePatterns = ignorePatternList
+
+	ch := make(chan *file, 1000)
+	done := make(chan struct{})
+	var out error
+	go func() {
+		var wg errgroup.Group
+		for f := range ch {
+			f := f // https://golang.org/doc/faq#closures_and_goroutines
+			wg.Go(func() error {
+				return fn(f.path)
+			})
+		}
+		out = wg.Wait()
+		close(done)
+	}()
+
+	for _, d := range patterns {
+		if err := walk(ch, d, opts, logger); err != nil {
+			close(ch)
+			<-done
+			return err
+		}
+	}
+	close(ch)
+	<-done
+
+	return out
+}
+
+// CheckFiles is the equivalent of Run in check-only mode for files that aren't
+// in the working tree, such as those in a git commit. The given paths are
+// filtered using the same ignore patterns and Options as Run, and the contents
+// of the rest are retrieved with read. Every file missing a license header is
+// logged, and an error is returned if there are any.
+func CheckFiles(ignorePatternList []string, paths []string, read func(path string) ([]byte, error), logger *log.Logger, opts Options) error {
+	err := ValidatePatterns(ignorePatternList)
+	if err != nil {
+		return err
+	}
+	err = ValidatePatterns(opts.NeverTouch)
+	if err != nil {
+		return err
+	}
+	err = ValidatePatterns(opts.Include)
+	if err != nil {
+		return err
+	}
+	err = ValidateNeverTouchExceptions(opts.NeverTouchExceptions)
+	if err != nil {
+		return err
+	}
+	err = ValidatePreserveAdjacent(opts.PreserveAdjacent)
+	if err != nil {
+		return err
+	}
+	ignorePatterns = ignorePatternList
+
+	defer trackStats(&opts)()
+
+	paths = slices.Clone(paths)
+	sort.Strings(paths)
+
+	missing := false
+	for _, path := range paths {
+		if category, reason := skipFile(path, opts); category != "" {
+			if reason == "" {
+				logger.Printf("[DEBUG] skipping: %s", path)
+			} else {
+				logger.Printf("[DEBUG] skipping: %s (%s)", path, reason)
+			}
+			opts.tally.ignore(category)
+			continue
+		}
+		if _, ok := CommentStyleFor(path); !ok {
+			opts.tally.skip(SkipUnknownExtension)
+			continue
+		}
+		opts.tally.scan()
+
+		b, err := read(path)
+		if err != nil {
+			logger.Printf("%s: %v", path, err)
+			return err
+		}
+		if !contentHasLicense(path, b, opts) {
+			opts.change(path, false)
+			logger.Printf("%s\n", path)
+			missing = true
+			if opts.FailFast {
+				break
+			}
+		}
+	}
+
+	if missing {
+		return ErrMissingHeader
+	}
+	return nil
+}
+
+// trackStats sets up opts to count files for opts.Stats, if it is set, and
+// returns a function that fills in opts.Stats once a run completes
+func trackStats(opts *Options) func() {
+	if opts.Stats == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	t := &tally{}
+	opts.tally = t
+	skipped := opts.Skipped
+	opts.Skipped = func(path string, reason string) {
+		t.exempt(exemptCategory(reason))
+		if skipped != nil {
+			skipped(path, reason)
+		}
+	}
+	return func() {
+		*opts.Stats = t.stats(time.Since(start))
+	}
+}
+
+// skipFile reports whether the file at path is excluded from processing by the
+// never-touch lists, Options.Include, ignore patterns, or Options.Skip,
+// returning the category of the rule that excluded it, or an empty string if
+// none did, along with the reason given by Options.Skip, or for the
+// never-touch lists and Options.Include
+func skipFile(path string, opts Options) (SkipCategory, string) {
+	return skipFileWith(path, opts, ignorePatterns)
+}
+
+// skipFileWith is like skipFile, but uses the given ignore patterns rather than
+// those passed to the last call to Run or CheckFiles
+func skipFileWith(path string, opts Options, ignore []string) (SkipCategory, string) {
+	if fileMatches(path, defaultNeverTouch(opts)) || fileMatches(path, opts.NeverTouch) {
+		return SkipNeverTouched, "never touched"
+	}
+	if opts.SkipHidden && isHidden(path) {
+		return SkipHidden, "hidden"
+	}
+	if len(opts.Include) > 0 && !fileMatches(path, opts.Include) {
+		return SkipNotIncluded, "not included"
+	}
+	if fileMatches(path, ignore) {
+		return SkipIgnorePattern, ""
+	}
+	if opts.Skip != nil {
+		if skip, reason := opts.Skip(path); skip {
+			return SkipFiltered, reason
+		}
+	}
+	return "", ""
+}
+
+func processFile(f *file, t *template.Template, license LicenseData, checkonly bool, verbose bool, opts Options, logger *log.Logger) error {
+	if _, ok := CommentStyleFor(f.path); ok {
+		opts.tally.scan()
+	} else {
+		opts.tally.skip(SkipUnknownExtension)
+	}
 	if checkonly {
 		// Check if file extension is known
-		lic, err := licenseHeader(f.path, t, license)
+		lic, err := licenseHeader(f.path, t, license, opts)
 		if err != nil {
 			logger.Printf("%s: %v", f.path, err)
 			return err
@@ -244,17 +721,18 @@ func processFile(f *file, t *template.Template, license LicenseData, checkonly b
 			return nil
 		}
 		// Check if file has a license
-		hasLicense, err := fileHasLicense(f.path)
+		hasLicense, err := fileHasLicense(f.path, opts)
 		if err != nil {
 			logger.Printf("%s: %v", f.path, err)
 			return err
 		}
 		if !hasLicense {
+			opts.change(f.path, false)
 			logger.Printf("%s\n", f.path)
-			return errors.New("missing license header")
+			return ErrMissingHeader
 		}
 	} else {
-		modified, err := addLicense(f.path, f.mode, t, license)
+		modified, err := addLicense(f.path, f.mode, t, license, opts)
 		if err != nil {
 			logger.Printf("%s: %v", f.path, err)
 			return err
@@ -262,6 +740,12 @@ func processFile(f *file, t *template.Template, license LicenseData, checkonly b
 		if verbose && modified {
 			logger.Printf("%s modified", f.path)
 		}
+		if modified {
+			if err := checkIdempotent(f.path, t, license, opts); err != nil {
+				logger.Printf("%s: %v", f.path, err)
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -271,23 +755,145 @@ type file struct {
 	mode os.FileMode
 }
 
-func walk(ch chan<- *file, start string, logger *log.Logger) error {
-	return filepath.Walk(start, func(path string, fi os.FileInfo, err error) error {
-		if err != nil {
-			logger.Printf("%s error: %v", path, err)
-			return nil
-		}
+// walk discovers files beneath start and sends the ones that should be
+// processed to ch. Directory listing dominates runtime on network filesystems,
+// so directories are read by a pool of workers. Files and log messages are
+// emitted in lexical order once discovery completes, keeping output
+// deterministic regardless of scheduling.
+func walk(ch chan<- *file, start string, opts Options, logger *log.Logger) error {
+	workers := opts.Parallelism
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	type logLine struct {
+		path string
+		msg  string
+	}
+
+	var (
+		mu    sync.Mutex
+		files []*file
+		logs  []logLine
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, workers)
+	)
+
+	logf := func(path string, format string, v ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, logLine{path, fmt.Sprintf(format, v...)})
+	}
+
+	// visit applies skip rules to a single file or folder, and returns whether
+	// the walk should descend into it
+	visit := func(path string, fi os.FileInfo) bool {
 		if fi.IsDir() {
-			return nil
+			// Avoid descending into folders that can only contain skipped files
+			if dirMatches(path, defaultNeverTouch(opts)) || dirMatches(path, opts.NeverTouch) {
+				logf(path, "[DEBUG] skipping: %s (never touched)", path)
+				return false
+			}
+			if opts.SkipHidden && path != start && isHidden(filepath.Base(path)) {
+				logf(path, "[DEBUG] skipping: %s (hidden)", path)
+				if opts.SkippedHidden != nil {
+					opts.SkippedHidden(path)
+				}
+				return false
+			}
+			return true
 		}
-		if fileMatches(path, ignorePatterns) {
+		if category, reason := skipFile(path, opts); category != "" {
 			// The [DEBUG] level is inferred by go-hclog as a debug statement
-			logger.Printf("[DEBUG] skipping: %s", path)
-			return nil
+			if reason == "" {
+				logf(path, "[DEBUG] skipping: %s", path)
+			} else {
+				logf(path, "[DEBUG] skipping: %s (%s)", path, reason)
+			}
+			opts.tally.ignore(category)
+			return false
+		}
+
+		mu.Lock()
+		files = append(files, &file{path, fi.Mode()})
+		mu.Unlock()
+		return false
+	}
+
+	var readDir func(dir string)
+	readDir = func(dir string) {
+		defer wg.Done()
+
+		sem <- struct{}{}
+		entries, err := os.ReadDir(dir)
+		<-sem
+		if err != nil {
+			logf(dir, "%s error: %v", dir, err)
+			return
+		}
+
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			fi, err := e.Info()
+			if err != nil {
+				logf(path, "%s error: %v", path, err)
+				continue
+			}
+			if visit(path, fi) {
+				wg.Add(1)
+				go readDir(path)
+			}
 		}
-		ch <- &file{path, fi.Mode()}
-		return nil
-	})
+	}
+
+	fi, err := os.Lstat(start)
+	if err != nil {
+		logf(start, "%s error: %v", start, err)
+	} else if visit(start, fi) {
+		wg.Add(1)
+		go readDir(start)
+	}
+	wg.Wait()
+
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].path < logs[j].path })
+	for _, l := range logs {
+		logger.Print(l.msg)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	for _, f := range files {
+		ch <- f
+	}
+	return nil
+}
+
+// isHidden reports whether any element of path, other than "." and "..", is
+// hidden, as its name starts with a dot
+func isHidden(path string) bool {
+	for _, name := range strings.Split(filepath.ToSlash(path), "/") {
+		if strings.HasPrefix(name, ".") && name != "." && name != ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// dirMatches reports whether a directory is wholly covered by a pattern ending
+// in "/**", such as "**/.git/**" for ".git" or "vendor/**" for "vendor"
+func dirMatches(path string, patterns []string) bool {
+	for _, p := range patterns {
+		if dir, ok := strings.CutSuffix(p, "/**"); ok && fileMatches(path, []string{dir}) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileMatches reports whether path, relative to the directory being processed,
+// matches any of patterns in the same way as the ignore patterns given to Run.
+// Patterns are assumed to be valid (see ValidatePatterns).
+func FileMatches(path string, patterns []string) bool {
+	return fileMatches(path, patterns)
 }
 
 // fileMatches determines if path matches one of the provided file patterns.
@@ -312,23 +918,123 @@ func fileMatches(path string, patterns []string) bool {
 // addLicense add a license to the file if missing.
 //
 // It returns true if the file was updated.
-func addLicense(path string, fmode os.FileMode, tmpl *template.Template, data LicenseData) (bool, error) {
+func addLicense(path string, fmode os.FileMode, tmpl *template.Template, data LicenseData, opts Options) (bool, error) {
 	var lic []byte
 	var err error
-	lic, err = licenseHeader(path, tmpl, data)
+	lic, err = licenseHeader(path, tmpl, data, opts)
 	if err != nil || lic == nil {
 		return false, err
 	}
 
-	b, err := os.ReadFile(path)
+	// Headers only ever change the top of a file, so only the top is read into
+	// memory and the remainder is streamed into place after it. The exception is
+	// a file whose top is nothing but a preamble and blank lines, which may be
+	// rearranged, in which case the whole file is read.
+	b, err := ReadHead(path)
 	if err != nil {
 		return false, err
 	}
-	if hasLicense(b) || isGenerated(b) {
-		return false, err
+	if len(bytes.TrimLeft(b[len(preamble(path, b, opts)):], "\r\n")) == 0 {
+		b, err = os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	line := hashBang(b)
+	out, modified := prependLicense(path, b, lic, opts)
+	if !modified {
+		return false, nil
+	}
+	if err := replaceHead(path, len(b), out); err != nil {
+		return true, err
+	}
+	opts.change(path, opts.headerKeyword(path, b) != "")
+	return true, nil
+}
+
+// replaceHead replaces the first n bytes of the file at path with head. The
+// remainder of the file is set aside in a temporary file, rather than read into
+// memory, and then copied back after the new head. The file is rewritten in
+// place, rather than replaced, so that symlinks, hard links, permissions and
+// ownership are all left as they were.
+func replaceHead(path string, n int, head []byte) (err error) {
+	// Opening for writing ensures read-only files are left alone, just as if
+	// they were written to directly
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	if _, err := f.Seek(int64(n), io.SeekStart); err != nil {
+		return err
+	}
+
+	rest, err := os.CreateTemp("", "copywrite-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(rest.Name())
+	defer rest.Close()
+
+	size, err := io.Copy(rest, f)
+	if err != nil {
+		return err
+	}
+	if _, err := rest.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := f.Write(head); err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rest); err != nil {
+		return err
+	}
+	return f.Truncate(int64(len(head)) + size)
+}
+
+// prependLicense returns the contents of a file with the license header lic
+// added, unless it already has a license or is exempt from needing one. With
+// Options.ConvertPublicDomain, the header replaces a public domain dedication
+// at the top of the file. The second return value reports whether the header
+// was added.
+func prependLicense(path string, b []byte, lic []byte, opts Options) ([]byte, bool) {
+	// Files that are mid-merge are never modified, even to respace a header
+	if HasConflictMarkers(b) {
+		if opts.Skipped != nil {
+			opts.Skipped(path, ConflictMarkersReason)
+		}
+		return b, false
+	}
+	if opts.headerKeyword(path, b) != "" {
+		if opts.HeaderSpacing != nil {
+			return respaceHeader(b, preamble(path, b, opts), lic, *opts.HeaderSpacing, preserveAdjacent(opts))
+		}
+		return b, false
+	}
+	if reason := SkipReason(path, b); reason != "" {
+		if opts.Skipped != nil {
+			opts.Skipped(path, reason)
+		}
+		return b, false
+	}
+	if opts.ConvertPublicDomain {
+		if start, end, m := publicDomainComment(path, b, opts); m != "" {
+			b = append(b[:start:start], b[end:]...)
+		}
+	}
+
+	if opts.HeaderSpacing != nil {
+		lic = spacedHeader(lic, *opts.HeaderSpacing)
+	}
+
+	line := preamble(path, b, opts)
 	if len(line) > 0 {
 		b = b[len(line):]
 		if line[len(line)-1] != '\n' {
@@ -336,60 +1042,260 @@ func addLicense(path string, fmode os.FileMode, tmpl *template.Template, data Li
 		}
 		lic = append(line, lic...)
 	}
-	b = append(lic, b...)
-	return true, os.WriteFile(path, b, fmode)
+	if opts.HeaderSpacing != nil {
+		b = bytes.TrimLeft(b, "\r\n")
+	}
+	return append(lic, b...), true
+}
+
+// spacedHeader returns the header lic followed by exactly n blank lines, in
+// place of the single blank line added by executeTemplate
+func spacedHeader(lic []byte, n int) []byte {
+	trimmed := bytes.TrimRight(lic, "\r\n")
+	out := make([]byte, 0, len(trimmed)+1+n)
+	out = append(out, trimmed...)
+	return append(out, bytes.Repeat([]byte("\n"), n+1)...)
+}
+
+// respaceHeader normalizes the number of blank lines following a header that
+// was previously added to b below line, its preamble, so that existing files
+// converge on the configured spacing. Only headers exactly matching lic are
+// considered; files with any other header are left alone. Attribution lines
+// directly below the header (see DefaultPreserveAdjacent) are kept attached to
+// it, with the blank lines placed after them instead. The second return value
+// reports whether b was changed.
+func respaceHeader(b []byte, line []byte, lic []byte, n int, preserve []*regexp.Regexp) ([]byte, bool) {
+	rest := b[len(line):]
+
+	header := spacedHeader(lic, 0)
+	if !bytes.HasPrefix(rest, header) {
+		return b, false
+	}
+	rest = rest[len(header):]
+	attribution := rest[:attributionLength(rest, preserve)]
+	content := bytes.TrimLeft(rest[len(attribution):], "\r\n")
+	if len(content) == 0 {
+		return b, false
+	}
+
+	out := make([]byte, 0, len(b))
+	out = append(out, line...)
+	if len(attribution) > 0 {
+		out = append(out, header...)
+		out = append(out, spacedHeader(attribution, n)...)
+	} else {
+		out = append(out, spacedHeader(lic, n)...)
+	}
+	out = append(out, content...)
+	if bytes.Equal(out, b) {
+		return b, false
+	}
+	return out, true
+}
+
+// checkIdempotent re-runs the logic of addLicense in memory against a file it
+// just updated, returning an error if a second pass would modify it again. This
+// catches headers that aren't recognized as licenses once written (e.g., from a
+// custom template), which would otherwise be stacked again on every run.
+func checkIdempotent(path string, tmpl *template.Template, data LicenseData, opts Options) error {
+	lic, err := licenseHeader(path, tmpl, data, opts)
+	if err != nil || lic == nil {
+		return err
+	}
+
+	b, err := ReadHead(path)
+	if err != nil {
+		return err
+	}
+
+	opts.Skipped = nil
+	if _, modified := prependLicense(path, b, lic, opts); modified {
+		return errors.New("header is not idempotent: a second pass would add it again")
+	}
+	return nil
 }
 
 // fileHasLicense reports whether the file at path contains a license header.
-func fileHasLicense(path string) (bool, error) {
-	b, err := os.ReadFile(path)
+func fileHasLicense(path string, opts Options) (bool, error) {
+	b, err := ReadHead(path)
 	if err != nil {
 		return false, err
 	}
-	// If generated, we count it as if it has a license.
-	return hasLicense(b) || isGenerated(b), nil
+	return contentHasLicense(path, b, opts), nil
+}
+
+// contentHasLicense reports whether b, the contents of the file at path,
+// contains a license header or is exempt from needing one
+func contentHasLicense(path string, b []byte, opts Options) bool {
+	s := contentStatus(path, b, opts)
+	// If generated or a build artifact, we count it as if it has a license.
+	if s.State == HeaderExempt && opts.Skipped != nil {
+		opts.Skipped(path, s.Reason)
+	}
+	return s.OK()
 }
 
 // licenseHeader populates the provided license template with data, and returns
 // it with the proper prefix for the file type specified by path. The file does
 // not need to actually exist, only its name is used to determine the prefix.
-func licenseHeader(path string, tmpl *template.Template, data LicenseData) ([]byte, error) {
-	var lic []byte
-	var err error
+func licenseHeader(path string, tmpl *template.Template, data LicenseData, opts Options) ([]byte, error) {
+	style, ok := CommentStyleFor(path)
+	if !ok {
+		return nil, nil
+	}
+	data.Filename = filepath.Base(path)
+	data.RelPath = relPath(path)
+	header, err := executeFramedTemplate(tmpl, data, style, opts.HeaderStyle, opts.HeaderBorder)
+	if err != nil {
+		return nil, err
+	}
+	// By convention, and to satisfy the compiler, the license identifier of a
+	// Solidity file comes first
+	if fileExtension(strings.ToLower(filepath.Base(path))) == ".sol" {
+		header = spdxFirst(header, style.Mid)
+	}
+	return header, nil
+}
+
+// relPath returns path relative to the working directory, with forward
+// slashes, or path itself if it can't be made relative
+func relPath(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// spdxFirst moves the SPDX-License-Identifier line of header, if any, to the
+// top, along with any blank comment line (i.e., just mid) separating it from
+// the rest of the header
+func spdxFirst(header []byte, mid string) []byte {
+	lines := strings.SplitAfter(string(header), "\n")
+	for i, line := range lines {
+		if i == 0 || !strings.Contains(line, "SPDX-License-Identifier:") {
+			continue
+		}
+		rest := append(slices.Clone(lines[:i]), lines[i+1:]...)
+		if strings.TrimSpace(rest[i-1]) == strings.TrimSpace(mid) {
+			rest = slices.Delete(rest, i-1, i)
+		}
+		return []byte(line + strings.Join(rest, ""))
+	}
+	return header
+}
+
+// CommentStyle describes how a header is commented out for a type of file
+type CommentStyle struct {
+	// Top and Bottom are the lines that open and close a block comment, which
+	// are empty for line comments
+	Top    string `json:"top,omitempty"`
+	Bottom string `json:"bottom,omitempty"`
+
+	// Mid is the prefix of each line of the header
+	Mid string `json:"mid"`
+}
+
+// MATLABFiles treats .m files as MATLAB rather than Objective-C, which share
+// the extension. It may be set before any files are processed.
+var MATLABFiles bool
+
+// AssemblyComment, if set, is the comment syntax used for headers in every
+// assembly file, as it varies by assembler and architecture: ";", "#", "//",
+// or "/*". Otherwise, .asm files use ";", .S files (which are run through the
+// C preprocessor) use "/*", and .s files use "#". It may be set before any
+// files are processed.
+var AssemblyComment string
+
+// assemblyCommentStyles are the comment styles selectable with AssemblyComment
+var assemblyCommentStyles = map[string]CommentStyle{
+	";":  {Mid: "; "},
+	"#":  {Mid: "# "},
+	"//": {Mid: "// "},
+	"/*": {Top: "/*", Mid: " * ", Bottom: " */"},
+}
+
+// ValidAssemblyComment reports whether s is a valid value for AssemblyComment
+func ValidAssemblyComment(s string) bool {
+	_, ok := assemblyCommentStyles[s]
+	return s == "" || ok
+}
+
+// assemblyCommentStyle returns the comment style for the assembly file with the
+// given extension, which is case sensitive
+func assemblyCommentStyle(ext string) CommentStyle {
+	if style, ok := assemblyCommentStyles[AssemblyComment]; ok {
+		return style
+	}
+	switch {
+	case strings.EqualFold(ext, ".asm"):
+		return assemblyCommentStyles[";"]
+	case ext == ".S":
+		return assemblyCommentStyles["/*"]
+	}
+	return assemblyCommentStyles["#"]
+}
+
+// CommentStyleFor returns the comment style used for headers in the type of
+// file specified by path, or false if headers are not supported for it. The
+// file does not need to actually exist, only its name is used, except that
+// templates in Helm charts are recognized by the chart's Chart.yaml. JSON files
+// that allow comments are only supported if JSONComments is set, .m files are
+// Objective-C unless MATLABFiles is set, and assembly files follow
+// AssemblyComment.
+func CommentStyleFor(path string) (CommentStyle, bool) {
 	base := strings.ToLower(filepath.Base(path))
 
+	if JSONComments && isCommentedJSON(path) {
+		return jsonCommentStyle, true
+	}
+	if isHelmTemplate(path) {
+		return helmCommentStyle, true
+	}
+	if MATLABFiles && fileExtension(base) == ".m" {
+		return CommentStyle{Mid: "% "}, true
+	}
+	if ext := filepath.Ext(path); strings.EqualFold(ext, ".s") || strings.EqualFold(ext, ".asm") {
+		return assemblyCommentStyle(ext), true
+	}
+
 	switch fileExtension(base) {
-	case ".c", ".h", ".gv", ".java", ".scala", ".kt", ".kts":
-		lic, err = executeTemplate(tmpl, data, "/*", " * ", " */")
+	case ".c", ".h", ".gv", ".java", ".scala", ".kt", ".kts", ".ld", ".lds":
+		return CommentStyle{Top: "/*", Mid: " * ", Bottom: " */"}, true
 	case ".js", ".mjs", ".cjs", ".jsx", ".tsx", ".css", ".scss", ".sass", ".ts":
-		lic, err = executeTemplate(tmpl, data, "/**", " * ", " */")
-	case ".cc", ".cpp", ".cs", ".go", ".hh", ".hpp", ".m", ".mm", ".proto", ".rs", ".swift", ".dart", ".groovy", ".v", ".sv", ".lr":
-		lic, err = executeTemplate(tmpl, data, "", "// ", "")
-	case ".py", ".sh", ".bash", ".zsh", ".yaml", ".yml", ".dockerfile", "dockerfile", ".rb", "gemfile", ".ru", ".tcl", ".hcl", ".tf", ".tfvars", ".nomad", ".bzl", ".pl", ".pp", ".ps1", ".psd1", ".psm1", ".txtar":
-		lic, err = executeTemplate(tmpl, data, "", "# ", "")
+		return CommentStyle{Top: "/**", Mid: " * ", Bottom: " */"}, true
+	case ".cc", ".cpp", ".cs", ".go", ".hh", ".hpp", ".m", ".mm", ".proto", ".rs", ".swift", ".dart", ".groovy", ".v", ".sv", ".lr", ".prisma", ".sol", ".cairo", ".adoc", ".asciidoc", ".cu", ".cuh", ".dts", ".dtsi":
+		return CommentStyle{Mid: "// "}, true
+	case ".py", ".sh", ".graphql", ".gql", ".r", ".jl", ".org", ".bash", ".zsh", ".yaml", ".yml", ".dockerfile", "dockerfile", ".rb", "gemfile", ".ru", ".tcl", ".hcl", ".hcl2", ".tf", ".tfvars", ".nomad", ".bzl", ".bazel", ".bazelrc", ".bzlmod", "build", "workspace", ".textproto", ".txtpb", ".pl", ".pp", ".ps1", ".psd1", ".psm1", ".txtar":
+		return CommentStyle{Mid: "# "}, true
 	case ".el", ".lisp":
-		lic, err = executeTemplate(tmpl, data, "", ";; ", "")
-	case ".erl":
-		lic, err = executeTemplate(tmpl, data, "", "% ", "")
+		return CommentStyle{Mid: ";; "}, true
+	case ".erl", ".tex", ".sty":
+		return CommentStyle{Mid: "% "}, true
+	case ".f", ".for", ".f90", ".f95", ".f03", ".f08":
+		return CommentStyle{Mid: "! "}, true
 	case ".hs", ".sql", ".sdl":
-		lic, err = executeTemplate(tmpl, data, "", "-- ", "")
+		return CommentStyle{Mid: "-- "}, true
 	case ".hbs":
-		lic, err = executeTemplate(tmpl, data, "{{!", "  ", "}}")
-	case ".html", ".htm", ".xml", ".vue", ".wxi", ".wxl", ".wxs":
-		lic, err = executeTemplate(tmpl, data, "<!--", " ", "-->")
+		return CommentStyle{Top: "{{!", Mid: "  ", Bottom: "}}"}, true
+	case ".html", ".htm", ".xml", ".vue", ".svelte", ".astro", ".wxi", ".wxl", ".wxs":
+		return CommentStyle{Top: "<!--", Mid: " ", Bottom: "-->"}, true
 	case ".php":
-		lic, err = executeTemplate(tmpl, data, "", "// ", "")
+		return CommentStyle{Mid: "// "}, true
 	case ".ml", ".mli", ".mll", ".mly":
-		lic, err = executeTemplate(tmpl, data, "(**", "   ", "*)")
+		return CommentStyle{Top: "(**", Mid: "   ", Bottom: "*)"}, true
 	case ".ejs":
-		lic, err = executeTemplate(tmpl, data, "<%/*", "  ", "*/%>")
+		return CommentStyle{Top: "<%/*", Mid: "  ", Bottom: "*/%>"}, true
 	default:
 		// handle various cmake files
 		if base == "cmakelists.txt" || strings.HasSuffix(base, ".cmake.in") || strings.HasSuffix(base, ".cmake") {
-			lic, err = executeTemplate(tmpl, data, "", "# ", "")
+			return CommentStyle{Mid: "# "}, true
 		}
 	}
-	return lic, err
+	return CommentStyle{}, false
 }
 
 // fileExtension returns the file extension of name, or the full name if there
@@ -414,21 +1320,39 @@ var head = []string{
 	"/** @jest-environment",    // Jest Environment string https://jestjs.io/docs/configuration#testenvironment-string
 }
 
+// pythonEncoding matches a PEP 263 source encoding declaration, such as
+// "# -*- coding: utf-8 -*-" or "# vim: set fileencoding=utf-8 :"
+var pythonEncoding = regexp.MustCompile(`^[ \t\f]*#.*?coding[:=][ \t]*[-_.a-zA-Z0-9]+`)
+
+// hashBang returns the preamble of b that must remain at the very top of the
+// file, above any header. A preamble may span several lines, such as a shebang
+// followed by an encoding declaration or multiple Dockerfile directives, and is
+// treated as a unit so that a header is never inserted in the middle of it.
 func hashBang(b []byte) []byte {
-	var line []byte
-	for _, c := range b {
-		line = append(line, c)
-		if c == '\n' {
+	var preamble []byte
+	for len(b) > 0 {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line = b[:i+1]
+		}
+		if !isPreambleLine(line) {
 			break
 		}
+		preamble = append(preamble, line...)
+		b = b[len(line):]
 	}
+	return preamble
+}
+
+// isPreambleLine reports whether line is part of a file's preamble
+func isPreambleLine(line []byte) bool {
 	first := strings.ToLower(string(line))
 	for _, h := range head {
 		if strings.HasPrefix(first, h) {
-			return line
+			return true
 		}
 	}
-	return nil
+	return pythonEncoding.Match(line)
 }
 
 // go generate: ^// Code generated .* DO NOT EDIT\.$
@@ -440,18 +1364,129 @@ var cargoRazeGenerated = regexp.MustCompile(`(?m)^DO NOT EDIT! Replaced on runs
 // terraform init: ^# This file is maintained automatically by "terraform init"\.$
 var terraformGenerated = regexp.MustCompile(`(?m)^# This file is maintained automatically by "terraform init"\.$`)
 
-// isGenerated returns true if it contains a string that implies the file was
-// generated.
+// protoc: ^// Generated by the protocol buffer compiler.  DO NOT EDIT!$
+var protocGenerated = regexp.MustCompile(`(?m)^.{1,2} Generated by the protocol buffer compiler\.\s+DO NOT EDIT!`)
+
+// tfplugindocs: ^\W*generated by https://github.com/hashicorp/terraform-plugin-docs
+// after any comment marker, e.g. "<!-- generated by ..." or "# generated by ..."
+var tfplugindocsGenerated = regexp.MustCompile(`(?m)^\W*generated by https://github\.com/hashicorp/terraform-plugin-docs`)
+
+// isGenerated returns true if the top of b (first 64k bytes) contains a string
+// that implies the file was generated.
 func isGenerated(b []byte) bool {
-	return goGenerated.Match(b) || cargoRazeGenerated.Match(b) || terraformGenerated.Match(b)
+	b = b[:min(len(b), sniffBytes)]
+	return goGenerated.Match(b) || cargoRazeGenerated.Match(b) || terraformGenerated.Match(b) || protocGenerated.Match(b) || tfplugindocsGenerated.Match(b)
 }
 
-func hasLicense(b []byte) bool {
-	n := 1000
-	if len(b) < 1000 {
+// bundlerOutput matches the runtime boilerplate and banners that JavaScript
+// bundlers emit at the top of their output
+var bundlerOutput = regexp.MustCompile(`webpackBootstrap|__webpack_require__|parcelRequire|/\*! For license information please see`)
+
+// buildArtifactReason returns a description of why a file appears to be a
+// build artifact (e.g., minified or bundled JavaScript) that accidentally
+// lives in the source tree, or an empty string if it does not
+func buildArtifactReason(path string, b []byte) string {
+	if strings.Contains(filepath.Base(path), ".min.") {
+		return "minified asset"
+	}
+
+	// Bundler banners and bootstrapping code live at the very top of a file
+	n := 4096
+	if len(b) < n {
 		n = len(b)
 	}
-	return bytes.Contains(bytes.ToLower(b[:n]), []byte("copyright")) ||
-		bytes.Contains(bytes.ToLower(b[:n]), []byte("mozilla public")) ||
-		bytes.Contains(bytes.ToLower(b[:n]), []byte("spdx-license-identifier"))
+	if bundlerOutput.Match(b[:n]) {
+		return "bundler output"
+	}
+
+	// Minifiers strip newlines, leaving one (or a few) very long lines. Only the
+	// top of the file is sampled, so that the whole file needn't be read.
+	b = b[:min(len(b), sniffBytes)]
+	switch fileExtension(path) {
+	case ".js", ".mjs", ".cjs", ".css":
+		lines := bytes.Count(b, []byte("\n")) + 1
+		if len(b) >= 1000 && len(b)/lines > 500 {
+			return fmt.Sprintf("minified asset (average line length of %d characters)", len(b)/lines)
+		}
+	}
+
+	return ""
+}
+
+// generatedReason is the reason given by SkipReason for generated files
+const generatedReason = "generated file"
+
+// SkipReason returns why the contents of a file exempt it from needing a
+// header, or an empty string if they do not. Only the top of the file is
+// inspected, so b may be the result of ReadHead.
+func SkipReason(path string, b []byte) string {
+	if HasConflictMarkers(b) {
+		return ConflictMarkersReason
+	}
+	if HasIgnoreFileMarker(b) {
+		return IgnoreFileMarker + " marker"
+	}
+	if isGenerated(b) {
+		return generatedReason
+	}
+	return buildArtifactReason(path, b)
+}
+
+// localizedCopyrightKeywords are translations of "copyright" commonly found in
+// headers of acquired or third-party code. Files containing any of them are
+// treated as already having a header, so that a second (English) header isn't
+// stacked on top of the existing one.
+var localizedCopyrightKeywords = []string{
+	"著作権",               // Japanese
+	"版权",                // Chinese (Simplified)
+	"版權",                // Chinese (Traditional)
+	"저작권",               // Korean
+	"urheberrecht",      // German
+	"droit d'auteur",    // French
+	"derechos de autor", // Spanish
+	"diritto d'autore",  // Italian
+	"direitos autorais", // Portuguese
+	"auteursrecht",      // Dutch
+	"авторское право",   // Russian
+	"prawa autorskie",   // Polish
+	"upphovsrätt",       // Swedish
+}
+
+// hasLicense reports whether the header region of b (see HeaderScanWindow)
+// contains a license header, license text embedded in place of one (see
+// licenseTextSignatures), or a public domain dedication. In addition to the
+// built-in keywords, any of the supplied extra keywords (matched without case
+// sensitivity) also count as a license header.
+func hasLicense(b []byte, keywords []string) bool {
+	return licenseKeyword(b, keywords) != ""
+}
+
+// licenseKeyword returns the keyword that makes hasLicense consider b to have
+// a license header, or an empty string if there is none
+func licenseKeyword(b []byte, keywords []string) string {
+	header := bytes.ToLower(HeaderRegion(b))
+
+	for _, k := range []string{"copyright", "mozilla public", "spdx-license-identifier"} {
+		if bytes.Contains(header, []byte(k)) {
+			return k
+		}
+	}
+
+	if m := publicDomainDedication.Find(header); m != nil {
+		return string(m)
+	}
+
+	// License text embedded in place of a short header
+	if sig, ok := embeddedLicense(header); ok {
+		return sig.phrase
+	}
+
+	for _, list := range [][]string{localizedCopyrightKeywords, keywords} {
+		for _, k := range list {
+			if k != "" && bytes.Contains(header, bytes.ToLower([]byte(k))) {
+				return k
+			}
+		}
+	}
+	return ""
 }
@@ -15,10 +15,17 @@
 package addlicense
 
 import (
+	"fmt"
+	"io"
+	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"text/template"
 )
@@ -246,6 +253,12 @@ func TestAddLicense(t *testing.T) {
 		{"# syntax: docker/dockerfile:1.3\ncontent", "# syntax: docker/dockerfile:1.3\n// HYS\n\ncontent", true},
 		{"/** @jest-environment jsdom */\ncontent", "/** @jest-environment jsdom */\n// HYS\n\ncontent", true},
 
+		// multi-line preambles are kept together
+		{"#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\ncontent", "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\n// HYS\n\ncontent", true},
+		{"# vim: set fileencoding=latin-1 :\ncontent", "# vim: set fileencoding=latin-1 :\n// HYS\n\ncontent", true},
+		{"# syntax=docker/dockerfile:1\n# escape=`\ncontent", "# syntax=docker/dockerfile:1\n# escape=`\n// HYS\n\ncontent", true},
+		{"#!/bin/bash\n# a regular comment\ncontent", "#!/bin/bash\n// HYS\n\n# a regular comment\ncontent", true},
+
 		// ensure files with existing license or generated files are
 		// skipped. No need to test all permutations of these, since
 		// there are specific tests below.
@@ -265,7 +278,7 @@ func TestAddLicense(t *testing.T) {
 		}
 
 		// run addlicense
-		updated, err := addLicense(f.Name(), fi.Mode(), tmpl, data)
+		updated, err := addLicense(f.Name(), fi.Mode(), tmpl, data, Options{})
 		if err != nil {
 			t.Error(err)
 		}
@@ -289,6 +302,82 @@ func TestAddLicense(t *testing.T) {
 	}
 }
 
+// Test that the blank lines following new and existing headers are normalized
+// when a header spacing is configured.
+func TestHeaderSpacing(t *testing.T) {
+	lic := []byte("// Copyright H\n\n")
+	zero, two := 0, 2
+
+	tests := []struct {
+		contents     string
+		spacing      *int
+		wantContents string
+		wantUpdated  bool
+	}{
+		// default behavior is left alone
+		{"\n\ncontent", nil, "// Copyright H\n\n\n\ncontent", true},
+		{"// Copyright H\ncontent", nil, "// Copyright H\ncontent", false},
+
+		// new headers
+		{"\n\ncontent", &zero, "// Copyright H\ncontent", true},
+		{"content", &two, "// Copyright H\n\n\ncontent", true},
+		{"#!/bin/bash\n\ncontent", &two, "#!/bin/bash\n// Copyright H\n\n\ncontent", true},
+
+		// existing headers
+		{"// Copyright H\ncontent", &two, "// Copyright H\n\n\ncontent", true},
+		{"// Copyright H\n\n\n\ncontent", &zero, "// Copyright H\ncontent", true},
+		{"// Copyright H\n\n\ncontent", &two, "// Copyright H\n\n\ncontent", false},
+		{"// Copyright Someone Else\ncontent", &two, "// Copyright Someone Else\ncontent", false},
+	}
+
+	for _, tt := range tests {
+		got, updated := prependLicense("f.go", []byte(tt.contents), lic, Options{HeaderSpacing: tt.spacing})
+		if updated != tt.wantUpdated {
+			t.Errorf("prependLicense with contents %q returned updated: %t, want %t", tt.contents, updated, tt.wantUpdated)
+		}
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
+		}
+	}
+}
+
+// Test that headers which would be added again on a second pass are caught.
+func TestCheckIdempotent(t *testing.T) {
+	data := LicenseData{Holder: "H", Year: "Y", SPDXID: "S"}
+
+	tests := []struct {
+		tmpl    string
+		wantErr bool
+	}{
+		{"Copyright {{.Year}} {{.Holder}}", false},
+		{"SPDX-License-Identifier: {{.SPDXID}}", false},
+		{"{{.Holder}}{{.Year}}{{.SPDXID}}", true},
+	}
+
+	for _, tt := range tests {
+		tmpl := template.Must(template.New("").Parse(tt.tmpl))
+
+		f, err := createTempFile("content", "*.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := addLicense(f.Name(), fi.Mode(), tmpl, data, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		err = checkIdempotent(f.Name(), tmpl, data, Options{})
+		if gotErr := err != nil; gotErr != tt.wantErr {
+			t.Errorf("checkIdempotent with template %q returned error: %v, want error: %t", tt.tmpl, err, tt.wantErr)
+		}
+
+		_ = os.Remove(f.Name())
+	}
+}
+
 // Test that license headers are added using the appropriate prefix for
 // different filenames and extensions.
 func TestLicenseHeader(t *testing.T) {
@@ -304,7 +393,7 @@ func TestLicenseHeader(t *testing.T) {
 			"",
 		},
 		{
-			[]string{"f.c", "f.h", "f.gv", "f.java", "f.scala", "f.kt", "f.kts"},
+			[]string{"f.c", "f.h", "f.gv", "f.java", "f.scala", "f.kt", "f.kts", "f.ld", "f.lds", "f.S"},
 			"/*\n * HYS\n */\n\n",
 		},
 		{
@@ -313,11 +402,11 @@ func TestLicenseHeader(t *testing.T) {
 		},
 		{
 			[]string{"f.cc", "f.cpp", "f.cs", "f.go", "f.hh", "f.hpp", "f.m", "f.mm", "f.proto",
-				"f.rs", "f.swift", "f.dart", "f.groovy", "f.v", "f.sv", "f.php", "f.lr"},
+				"f.rs", "f.swift", "f.dart", "f.groovy", "f.v", "f.sv", "f.php", "f.lr", "f.prisma", "f.sol", "f.cairo", "f.adoc", "f.asciidoc", "f.cu", "f.cuh", "f.dts", "f.dtsi"},
 			"// HYS\n\n",
 		},
 		{
-			[]string{"f.py", "f.sh", ".bash", ".zsh", "f.yaml", "f.yml", "f.dockerfile", "dockerfile", "f.rb", "gemfile", ".ru", "f.tcl", "f.bzl", "f.pl", "f.pp", "f.ps1", "f.psd1", "f.psm1", "f.hcl", "f.tf", "f.nomad", "f.tfvars", "f.txtar"},
+			[]string{"f.py", "f.sh", "f.s", "f.graphql", "f.gql", "f.r", "F.R", "f.jl", "f.org", ".bash", ".zsh", "f.yaml", "f.yml", "f.dockerfile", "dockerfile", "f.rb", "gemfile", ".ru", "f.tcl", "f.bzl", "BUILD", "BUILD.bazel", "WORKSPACE", "MODULE.bazel", ".bazelrc", "f.bzlmod", "f.textproto", "f.txtpb", "f.pl", "f.pp", "f.ps1", "f.psd1", "f.psm1", "f.hcl", "f.hcl2", "f.tftest.hcl", "f.tfstack.hcl", "f.tfdeploy.hcl", "f.tf", "f.nomad", "f.tfvars", "f.txtar"},
 			"# HYS\n\n",
 		},
 		{
@@ -325,9 +414,22 @@ func TestLicenseHeader(t *testing.T) {
 			";; HYS\n\n",
 		},
 		{
-			[]string{"f.erl"},
+			[]string{"f.asm", "F.ASM"},
+			"; HYS\n\n",
+		},
+		{
+			[]string{"f.erl", "f.tex", "f.sty"},
 			"% HYS\n\n",
 		},
+		{
+			// .cls is also used by Apex and VBA classes, so it's left alone
+			[]string{"f.cls"},
+			"",
+		},
+		{
+			[]string{"f.f", "f.for", "f.f90", "f.f95", "f.f03", "f.f08"},
+			"! HYS\n\n",
+		},
 		{
 			[]string{"f.hs", "f.sql", "f.sdl"},
 			"-- HYS\n\n",
@@ -337,7 +439,7 @@ func TestLicenseHeader(t *testing.T) {
 			"{{!\n  HYS\n}}\n\n",
 		},
 		{
-			[]string{"f.html", "f.htm", "f.xml", "f.vue", "f.wxi", "f.wxl", "f.wxs"},
+			[]string{"f.html", "f.htm", "f.xml", "f.vue", "f.svelte", "f.astro", "f.wxi", "f.wxl", "f.wxs"},
 			"<!--\n HYS\n-->\n\n",
 		},
 		{
@@ -362,7 +464,7 @@ func TestLicenseHeader(t *testing.T) {
 
 	for _, tt := range tests {
 		for _, path := range tt.paths {
-			header, _ := licenseHeader(path, tpl, data)
+			header, _ := licenseHeader(path, tpl, data, Options{})
 			if got := string(header); got != tt.want {
 				t.Errorf("licenseHeader(%q) returned: %q, want: %q", path, got, tt.want)
 			}
@@ -370,6 +472,71 @@ func TestLicenseHeader(t *testing.T) {
 	}
 }
 
+// Test that .m files can be treated as MATLAB rather than Objective-C.
+func TestMATLABFiles(t *testing.T) {
+	defer func(v bool) { MATLABFiles = v }(MATLABFiles)
+
+	MATLABFiles = false
+	if style, _ := CommentStyleFor("f.m"); style.Mid != "// " {
+		t.Errorf("CommentStyleFor(\"f.m\") returned prefix %q, want %q", style.Mid, "// ")
+	}
+
+	MATLABFiles = true
+	if style, _ := CommentStyleFor("f.m"); style.Mid != "% " {
+		t.Errorf("CommentStyleFor(\"f.m\") with MATLABFiles returned prefix %q, want %q", style.Mid, "% ")
+	}
+	if style, _ := CommentStyleFor("f.mm"); style.Mid != "// " {
+		t.Errorf("CommentStyleFor(\"f.mm\") with MATLABFiles returned prefix %q, want %q", style.Mid, "// ")
+	}
+}
+
+// Test that the comment syntax of every assembly file can be configured.
+func TestAssemblyComment(t *testing.T) {
+	defer func(v string) { AssemblyComment = v }(AssemblyComment)
+
+	AssemblyComment = "//"
+	for _, path := range []string{"f.s", "f.S", "f.asm"} {
+		if style, _ := CommentStyleFor(path); style.Mid != "// " || style.Top != "" {
+			t.Errorf("CommentStyleFor(%q) with AssemblyComment %q returned %+v", path, AssemblyComment, style)
+		}
+	}
+
+	for _, s := range []string{"", ";", "#", "//", "/*"} {
+		if !ValidAssemblyComment(s) {
+			t.Errorf("ValidAssemblyComment(%q) returned false, want true", s)
+		}
+	}
+	if ValidAssemblyComment("@") {
+		t.Errorf("ValidAssemblyComment(%q) returned true, want false", "@")
+	}
+}
+
+// Test that the license identifier of Solidity files comes first.
+func TestSolidityHeader(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{tmplSPDX, "// SPDX-License-Identifier: MIT\n// Copyright (c) H\n\n"},
+		{tmplMIT + spdxSuffix, "// SPDX-License-Identifier: MIT\n// Copyright (c) H\n//\n// Permission is hereby granted"},
+		{tmplCopyrightOnly, "// Copyright (c) H\n\n"},
+	}
+
+	for _, tt := range tests {
+		tpl := template.Must(template.New("").Parse(tt.tmpl))
+		header, err := licenseHeader("f.sol", tpl, LicenseData{Holder: "H", SPDXID: "MIT"}, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(header), tt.want) {
+			t.Errorf("licenseHeader(\"f.sol\") returned: %q, want prefix: %q", header, tt.want)
+		}
+		if n := strings.Count(string(header), "SPDX-License-Identifier"); tt.tmpl != tmplCopyrightOnly && n != 1 {
+			t.Errorf("licenseHeader(\"f.sol\") returned %d license identifiers, want 1", n)
+		}
+	}
+}
+
 // Test that generated files are properly recognized.
 func TestIsGenerated(t *testing.T) {
 	tests := []struct {
@@ -381,6 +548,12 @@ func TestIsGenerated(t *testing.T) {
 		{"// Code generated by go generate; DO NOT EDIT.", true},
 		{"/*\n* Code generated by go generate; DO NOT EDIT.\n*/\n", true},
 		{"DO NOT EDIT! Replaced on runs of cargo-raze", true},
+		{"// Generated by the protocol buffer compiler.  DO NOT EDIT!\n// source: foo.proto\n", true},
+		{"# Generated by the protocol buffer compiler.  DO NOT EDIT!\n", true},
+		{"# Code generated by terraform-plugin-docs\n", false},
+		{"# This file is maintained automatically by \"terraform init\".\n# Manual edits may be lost in future updates.\n", true},
+		{"---\n# generated by https://github.com/hashicorp/terraform-plugin-docs\npage_title: \"example Provider\"\n", true},
+		{"<!-- generated by https://github.com/hashicorp/terraform-plugin-docs -->\n", true},
 	}
 
 	for _, tt := range tests {
@@ -391,27 +564,159 @@ func TestIsGenerated(t *testing.T) {
 	}
 }
 
-// Test that existing license headers are identified.
-func TestHasLicense(t *testing.T) {
+func TestHasIgnoreFileMarker(t *testing.T) {
 	tests := []struct {
 		content string
 		want    bool
 	}{
 		{"", false},
-		{"This is my license", false},
-		{"This code is released into the public domain.", false},
-		{"SPDX: MIT", false},
+		{"package main", false},
+		{"// copywrite:ignore-file\npackage main", true},
+		{"#!/bin/bash\n# copywrite:ignore-file -- vendored from upstream\n", true},
+		{strings.Repeat("\n", 10) + "// copywrite:ignore-file", false},
+	}
 
-		{"Copyright 2000", true},
-		{"CoPyRiGhT 2000", true},
-		{"Subject to the terms of the Mozilla Public License", true},
-		{"SPDX-License-Identifier: MIT", true},
-		{"spdx-license-identifier: MIT", true},
+	for _, tt := range tests {
+		if got := HasIgnoreFileMarker([]byte(tt.content)); got != tt.want {
+			t.Errorf("HasIgnoreFileMarker(%q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"", false},
+		{"package main", false},
+		{"<<<<<<< HEAD\npackage main\n=======\npackage other\n>>>>>>> feature\n", true},
+		{"package main\r\n>>>>>>>\r\n", true},
+		{"// <<<<<<< quoted in a comment\npackage main", false},
+		{"<<<<<<<<<< not a marker\n", false},
+		{"Title\n=======\n", false},
+		{strings.Repeat("\n", 100) + "<<<<<<< HEAD\n", false},
+	}
+
+	for _, tt := range tests {
+		if got := HasConflictMarkers([]byte(tt.content)); got != tt.want {
+			t.Errorf("HasConflictMarkers(%q) returned %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+// Test that files with merge conflict markers are never modified, even if they
+// already have a header that would otherwise be respaced.
+func TestPrependLicenseConflictMarkers(t *testing.T) {
+	lic := []byte("// Copyright 2020 Google LLC\n")
+	spacing := 2
+	for _, content := range []string{
+		"<<<<<<< HEAD\npackage main\n=======\npackage other\n>>>>>>> feature\n",
+		"// Copyright 2020 Google LLC\n<<<<<<< HEAD\npackage main\n>>>>>>> feature\n",
+	} {
+		var reason string
+		opts := Options{
+			HeaderSpacing: &spacing,
+			Skipped:       func(_ string, r string) { reason = r },
+		}
+		got, modified := prependLicense("main.go", []byte(content), lic, opts)
+		if modified || string(got) != content {
+			t.Errorf("prependLicense(%q) modified the file: %q", content, got)
+		}
+		if reason != ConflictMarkersReason {
+			t.Errorf("prependLicense(%q) skipped the file with reason %q, want %q", content, reason, ConflictMarkersReason)
+		}
+	}
+}
+
+// Test that public domain dedications are left alone by default, and replaced
+// by the header with ConvertPublicDomain.
+func TestPrependLicensePublicDomain(t *testing.T) {
+	lic := []byte("// Copyright (c) HashiCorp, Inc.\n\n")
+	tests := []struct {
+		contents     string
+		convert      bool
+		wantContents string
+	}{
+		{
+			"// This is free and unencumbered software released into the public domain.\n\npackage main\n",
+			false,
+			"// This is free and unencumbered software released into the public domain.\n\npackage main\n",
+		},
+		{
+			"// This is free and unencumbered software released into the public domain.\n//\n// For more information, please refer to <https://unlicense.org>\n\npackage main\n",
+			true,
+			"// Copyright (c) HashiCorp, Inc.\n\npackage main\n",
+		},
+		{
+			"// SPDX-License-Identifier: CC0-1.0\n\n\npackage main\n",
+			true,
+			"// Copyright (c) HashiCorp, Inc.\n\npackage main\n",
+		},
+		{
+			// Dedications in comments attached to code, such as package
+			// docs, can't be replaced, so they still count as a header
+			"// Package main does things.\n// It is released into the public domain.\npackage main\n",
+			true,
+			"// Package main does things.\n// It is released into the public domain.\npackage main\n",
+		},
+	}
+
+	for _, tt := range tests {
+		opts := Options{ConvertPublicDomain: tt.convert}
+		got, _ := prependLicense("main.go", []byte(tt.contents), lic, opts)
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense(%q) with ConvertPublicDomain %t returned %q, want %q", tt.contents, tt.convert, got, tt.wantContents)
+		}
+		if tt.convert && opts.headerKeyword("main.go", got) == "" {
+			t.Errorf("prependLicense(%q) returned contents without a header: %q", tt.contents, got)
+		}
+	}
+}
+
+// Test that existing license headers are identified.
+func TestHasLicense(t *testing.T) {
+	tests := []struct {
+		content  string
+		keywords []string
+		want     bool
+	}{
+		{"", nil, false},
+		{"This is my license", nil, false},
+		{"SPDX: MIT", nil, false},
+
+		{"Copyright 2000", nil, true},
+		{"CoPyRiGhT 2000", nil, true},
+		{"Subject to the terms of the Mozilla Public License", nil, true},
+		{"SPDX-License-Identifier: MIT", nil, true},
+		{"spdx-license-identifier: MIT", nil, true},
+
+		// embedded license text
+		{"Licensed under the Apache License, Version 2.0 (the \"License\");", nil, true},
+		{"                                 Apache License\n                           Version 2.0, January 2004", nil, true},
+		{"Permission is hereby granted, free of charge, to any person obtaining a copy", nil, true},
+		{"Redistribution and use in source and binary forms, with or without", nil, true},
+
+		// public domain dedications
+		{"This code is released into the public domain.", nil, true},
+		{"SPDX-License-Identifier: CC0-1.0", nil, true},
+		{"To the extent possible under law, the author has dedicated all rights. See <https://creativecommons.org/publicdomain/zero/1.0/>", nil, true},
+		{"For more information, please refer to <https://unlicense.org>", nil, true},
+
+		// localized copyright statements
+		{"著作権 2000 Acme株式会社", nil, true},
+		{"Urheberrecht (c) 2000 Acme GmbH", nil, true},
+		{"URHEBERRECHT 2000 Acme GmbH", nil, true},
+
+		// additional keywords
+		{"Tekijänoikeus 2000 Acme Oy", nil, false},
+		{"Tekijänoikeus 2000 Acme Oy", []string{"tekijänoikeus"}, true},
+		{"This is my license", []string{""}, false},
 	}
 
 	for _, tt := range tests {
 		b := []byte(tt.content)
-		if got := hasLicense(b); got != tt.want {
+		if got := hasLicense(b, tt.keywords); got != tt.want {
 			t.Errorf("hasLicense(%q) returned %v, want %v", tt.content, got, tt.want)
 		}
 	}
@@ -476,3 +781,518 @@ func TestFileMatches(t *testing.T) {
 		}
 	}
 }
+
+func TestWalkNeverTouch(t *testing.T) {
+	tmp := tempDir(t)
+	files := []string{
+		"main.go",
+		"web/app.js",
+		"web/app.min.js",
+		"web/app.min.mjs",
+		"web/app.js.map",
+		"web/package-lock.json",
+		"web/node_modules/dep/index.js",
+		".git/hooks/pre-commit.sh",
+		".copywrite.hcl",
+		"generated/types.go",
+		"api/api.pb.go",
+	}
+	for _, f := range files {
+		path := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		opts Options
+		want []string
+	}{
+		{
+			opts: Options{NeverTouch: []string{"**/generated/**"}},
+			want: []string{"main.go", "web/app.js"},
+		},
+		{
+			opts: Options{NeverTouch: []string{"**/generated/**"}, NeverTouchExceptions: []string{"**/*.pb.go", "**/*.map"}},
+			want: []string{"api/api.pb.go", "main.go", "web/app.js", "web/app.js.map"},
+		},
+	}
+
+	for _, tt := range tests {
+		var mu sync.Mutex
+		got := []string{}
+		err := Walk(nil, []string{tmp}, log.New(io.Discard, "", 0), tt.opts, func(path string) error {
+			rel, _ := filepath.Rel(tmp, path)
+			mu.Lock()
+			got = append(got, filepath.ToSlash(rel))
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Walk() with exceptions %v visited %v, want %v", tt.opts.NeverTouchExceptions, got, tt.want)
+		}
+	}
+}
+
+func TestValidateNeverTouchExceptions(t *testing.T) {
+	tests := []struct {
+		exceptions []string
+		wantErr    bool
+	}{
+		{nil, false},
+		{[]string{"**/*.pb.go", "**/go.sum"}, false},
+		{[]string{"**/*.pb.gw.go"}, true},
+		{[]string{"**/.git/**"}, true},
+		{[]string{"**/.copywrite.hcl"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateNeverTouchExceptions(tt.exceptions); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateNeverTouchExceptions(%v) returned %v, want error: %v", tt.exceptions, err, tt.wantErr)
+		}
+	}
+
+	if err := Walk(nil, []string{"."}, log.New(io.Discard, "", 0), Options{NeverTouchExceptions: []string{"*.go"}}, func(string) error { return nil }); err == nil {
+		t.Error("Walk() should reject invalid never-touch exceptions")
+	}
+}
+
+func TestBuildArtifactReason(t *testing.T) {
+	tests := []struct {
+		path    string
+		content string
+		want    string
+	}{
+		{"app.js", "function main() {\n  return 1;\n}\n", ""},
+		{"app.min.mjs", "function main(){return 1}", "minified asset"},
+		{"dist/main.js", "/******/ (() => { // webpackBootstrap\n", "bundler output"},
+		{"dist/main.js", "/*! For license information please see main.js.LICENSE.txt */\n", "bundler output"},
+		{"style.css", strings.Repeat("a{color:red}", 100), "minified asset (average line length of 1200 characters)"},
+		{"main.go", strings.Repeat("a", 1200), ""},
+	}
+
+	for _, tt := range tests {
+		if got := buildArtifactReason(tt.path, []byte(tt.content)); got != tt.want {
+			t.Errorf("buildArtifactReason(%q) returned %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWalkOrder(t *testing.T) {
+	tmp := tempDir(t)
+	files := []string{"b/2.go", "a/1.go", "c.go", "a/b/3.go", "b/1.go"}
+	for _, f := range files {
+		path := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ch := make(chan *file, len(files))
+	if err := walk(ch, tmp, Options{Parallelism: 4}, log.New(io.Discard, "", 0)); err != nil {
+		t.Fatal(err)
+	}
+	close(ch)
+
+	var got []string
+	for f := range ch {
+		rel, _ := filepath.Rel(tmp, f.path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := []string{"a/1.go", "a/b/3.go", "b/1.go", "b/2.go", "c.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("walk() sent %v, want %v", got, want)
+	}
+}
+
+func TestRunSortedOutput(t *testing.T) {
+	tmp := tempDir(t)
+	files := []string{"d.go", "b/c.go", "a.go", "b/a.go"}
+	for _, f := range files {
+		path := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf strings.Builder
+	logger := log.New(&buf, "", 0)
+	data := LicenseData{Holder: "Google LLC", SPDXID: "Apache-2.0"}
+	err := Run(nil, spdxOnly, data, "", false, true, []string{tmp}, logger, Options{})
+	if err == nil {
+		t.Fatal("Run() should report missing license headers")
+	}
+
+	want := ""
+	for _, f := range []string{"a.go", "b/a.go", "b/c.go", "d.go"} {
+		want += filepath.Join(tmp, f) + "\n"
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("Run() logged %q, want %q", got, want)
+	}
+}
+
+func TestRunHeaderTemplate(t *testing.T) {
+	tmp := tempDir(t)
+	path := filepath.Join(tmp, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := log.New(io.Discard, "", 0)
+	data := LicenseData{Holder: "Acme Inc.", SPDXID: "LicenseRef-Acme"}
+	opts := Options{HeaderTemplate: "Copyright {{.Holder}}\nAll rights reserved."}
+	for i := 0; i < 2; i++ {
+		if err := Run(nil, spdxOnly, data, "", false, false, []string{tmp}, logger, opts); err != nil {
+			t.Fatalf("Run() returned error: %v", err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Copyright Acme Inc.\n// All rights reserved.\n//\n// SPDX-License-Identifier: LicenseRef-Acme\n\npackage main\n"
+	if string(got) != want {
+		t.Errorf("Run() with a header template wrote %q, want %q", got, want)
+	}
+}
+
+// Test that a header template that would break comments fails before any file
+// is changed.
+func TestRunInvalidHeaderTemplate(t *testing.T) {
+	tmp := tempDir(t)
+	path := filepath.Join(tmp, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := log.New(io.Discard, "", 0)
+	data := LicenseData{Holder: "Acme Inc.", SPDXID: "LicenseRef-Acme"}
+	opts := Options{HeaderTemplate: "Copyright {{.Holder}} */"}
+	if err := Run(nil, spdxOnly, data, "", false, false, []string{tmp}, logger, opts); err == nil {
+		t.Errorf("Run() with a header template that ends C comments returned no error")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "package main\n" {
+		t.Errorf("Run() with an invalid header template wrote %q", got)
+	}
+}
+
+// Test that streamed output includes every file's messages whole, in whatever
+// order the files were processed.
+func TestRunNoSortOutput(t *testing.T) {
+	tmp := tempDir(t)
+	want := []string{}
+	for i := 0; i < 100; i++ {
+		path := filepath.Join(tmp, fmt.Sprintf("%d.go", i))
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, path)
+	}
+
+	var buf strings.Builder
+	logger := log.New(&buf, "", 0)
+	data := LicenseData{Holder: "Google LLC", SPDXID: "Apache-2.0"}
+	err := Run(nil, spdxOnly, data, "", false, true, []string{tmp}, logger, Options{NoSort: true})
+	if err == nil {
+		t.Fatal("Run() should report missing license headers")
+	}
+
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Run() logged %q, want %q in any order", got, want)
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	tmp := tempDir(t)
+	files := map[string]string{
+		"main.go":          "package main\n",
+		"licensed.go":      "// Copyright 2020 Google LLC\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+		"generated.go":     "// Code generated by stringer. DO NOT EDIT.\n\npackage main\n",
+		"vendor/dep/a.go":  "package dep\n",
+		"notes.unknownext": "notes\n",
+	}
+	for f, content := range files {
+		path := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stats Stats
+	data := LicenseData{Holder: "Google LLC", SPDXID: "Apache-2.0"}
+	err := Run([]string{"**/vendor/**"}, spdxOnly, data, "", false, false, []string{tmp}, log.New(io.Discard, "", 0), Options{Stats: &stats})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats.Elapsed = 0
+	want := Stats{Scanned: 3, Added: 1, Ignored: 1, Exempted: 1, Skipped: map[SkipCategory]int{
+		SkipIgnorePattern:    1,
+		SkipGenerated:        1,
+		SkipUnknownExtension: 1,
+	}}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("Run() reported %+v, want %+v", stats, want)
+	}
+}
+
+// Test that hidden files and directories are skipped with SkipHidden, and that
+// each hidden directory is reported without descending into it.
+func TestRunSkipHidden(t *testing.T) {
+	tmp := tempDir(t)
+	for _, f := range []string{"main.go", ".hidden.go", ".github/a.go", ".github/b/c.go", "d/.e/f.go", ".hg/g.go", ".vscode/h.go"} {
+		path := filepath.Join(tmp, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf strings.Builder
+	data := LicenseData{Holder: "Google LLC", SPDXID: "Apache-2.0"}
+	err := Run(nil, spdxOnly, data, "", false, true, []string{tmp}, log.New(&buf, "", 0), Options{})
+	if err == nil {
+		t.Fatal("Run() should report missing license headers")
+	}
+	// Version control and editor settings are skipped regardless
+	want := fmt.Sprintf("[DEBUG] skipping: %s (never touched)\n[DEBUG] skipping: %s (never touched)\n", filepath.Join(tmp, ".hg"), filepath.Join(tmp, ".vscode"))
+	for _, f := range []string{".github/a.go", ".github/b/c.go", ".hidden.go", "d/.e/f.go", "main.go"} {
+		want += filepath.Join(tmp, f) + "\n"
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("Run() without SkipHidden logged %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	hidden := []string{}
+	opts := Options{SkipHidden: true, SkippedHidden: func(dir string) { hidden = append(hidden, dir) }}
+	err = Run(nil, spdxOnly, data, "", false, true, []string{tmp}, log.New(&buf, "", 0), opts)
+	if err == nil {
+		t.Fatal("Run() should report missing license headers")
+	}
+	want = ""
+	for _, f := range []string{".github (hidden)", ".hg (never touched)", ".hidden.go (hidden)", ".vscode (never touched)", "d/.e (hidden)"} {
+		want += "[DEBUG] skipping: " + filepath.Join(tmp, f) + "\n"
+	}
+	want += filepath.Join(tmp, "main.go") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Run() with SkipHidden logged %q, want %q", got, want)
+	}
+	sort.Strings(hidden)
+	if want := []string{filepath.Join(tmp, ".github"), filepath.Join(tmp, "d/.e")}; !reflect.DeepEqual(hidden, want) {
+		t.Errorf("Run() with SkipHidden reported hidden directories %q, want %q", hidden, want)
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	files := map[string]string{
+		"main.go":         "package main\n",
+		"licensed.go":     "// Copyright 2020 Google LLC\n\npackage main\n",
+		"generated.go":    "// Code generated by stringer. DO NOT EDIT.\n\npackage main\n",
+		"vendor/dep/a.go": "package dep\n",
+		"README.md":       "# Readme\n",
+	}
+	read := func(path string) ([]byte, error) {
+		return []byte(files[path]), nil
+	}
+	paths := []string{"vendor/dep/a.go", "main.go", "licensed.go", "generated.go", "README.md"}
+
+	var buf strings.Builder
+	var stats Stats
+	err := CheckFiles([]string{"vendor/**"}, paths, read, log.New(&buf, "", 0), Options{Stats: &stats})
+	if err == nil {
+		t.Fatal("CheckFiles() should report missing license headers")
+	}
+
+	if got, want := buf.String(), "main.go\n[DEBUG] skipping: vendor/dep/a.go\n"; got != want {
+		t.Errorf("CheckFiles() logged %q, want %q", got, want)
+	}
+	stats.Elapsed = 0
+	want := Stats{Scanned: 3, Added: 1, Ignored: 1, Exempted: 1, Skipped: map[SkipCategory]int{
+		SkipIgnorePattern:    1,
+		SkipGenerated:        1,
+		SkipUnknownExtension: 1,
+	}}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("CheckFiles() reported %+v, want %+v", stats, want)
+	}
+
+	files["main.go"] = "// Copyright 2020 Google LLC\n\npackage main\n"
+	if err := CheckFiles([]string{"vendor/**"}, paths, read, log.New(io.Discard, "", 0), Options{}); err != nil {
+		t.Errorf("CheckFiles() returned %v once every file has a header", err)
+	}
+}
+
+// Test that only files matching Options.Include are checked, and that the
+// ignore patterns still apply to them.
+func TestCheckFilesInclude(t *testing.T) {
+	paths := []string{"main.go", "web/app.js", "api/a.proto", "gen/b.proto"}
+	read := func(path string) ([]byte, error) {
+		return []byte("package main\n"), nil
+	}
+
+	var buf strings.Builder
+	opts := Options{Include: []string{"**/*.go", "**/*.proto"}}
+	err := CheckFiles([]string{"gen/**"}, paths, read, log.New(&buf, "", 0), opts)
+	if err == nil {
+		t.Fatal("CheckFiles() should report missing license headers")
+	}
+	want := "api/a.proto\n[DEBUG] skipping: gen/b.proto\nmain.go\n[DEBUG] skipping: web/app.js (not included)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CheckFiles() logged %q, want %q", got, want)
+	}
+
+	opts.Include = []string{"["}
+	if err := CheckFiles(nil, paths, read, log.New(io.Discard, "", 0), opts); err == nil {
+		t.Error("CheckFiles() should reject invalid include patterns")
+	}
+}
+
+// Test that checking stops at the first file missing a header with FailFast.
+func TestCheckFilesFailFast(t *testing.T) {
+	paths := []string{"c.go", "a.go", "b.go"}
+	read := func(path string) ([]byte, error) {
+		return []byte("package main\n"), nil
+	}
+
+	var buf strings.Builder
+	err := CheckFiles(nil, paths, read, log.New(&buf, "", 0), Options{FailFast: true})
+	if err == nil {
+		t.Fatal("CheckFiles() should report missing license headers")
+	}
+	if got, want := buf.String(), "a.go\n"; got != want {
+		t.Errorf("CheckFiles() logged %q, want %q", got, want)
+	}
+}
+
+// Test that the contents of files larger than the portion read into memory are
+// preserved when a header is added.
+func TestAddLicenseLargeFile(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse("{{.Holder}}{{.Year}}{{.SPDXID}}"))
+	data := LicenseData{Holder: "H", Year: "Y", SPDXID: "S"}
+
+	contents := "#!/bin/bash\n" + strings.Repeat("echo \"hello world\"\n", 10000)
+	path := filepath.Join(t.TempDir(), "large.sh")
+	if err := os.WriteFile(path, []byte(contents), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := addLicense(path, 0755, tmpl, data, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated {
+		t.Errorf("addLicense returned updated: false, want true")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "#!/bin/bash\n# HYS\n\n" + strings.TrimPrefix(contents, "#!/bin/bash\n"); string(got) != want {
+		t.Errorf("addLicense returned %d bytes, want %d", len(got), len(want))
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0755 {
+		t.Errorf("addLicense changed mode to %v, want %v", fi.Mode().Perm(), os.FileMode(0755))
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("addLicense left %d files behind, want 1", len(entries))
+	}
+}
+
+// Test that headers are written through symlinks and to every name of a hard
+// linked file, rather than replacing the link with a copy.
+func TestAddLicenseLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on Windows")
+	}
+	tmpl := template.Must(template.New("").Parse("{{.Holder}}{{.Year}}{{.SPDXID}}"))
+	data := LicenseData{Holder: "H", Year: "Y", SPDXID: "S"}
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target.sh")
+	if err := os.WriteFile(target, []byte("echo hello\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	symlink := filepath.Join(dir, "symlink.sh")
+	if err := os.Symlink("target.sh", symlink); err != nil {
+		t.Fatal(err)
+	}
+	hardlink := filepath.Join(dir, "hardlink.sh")
+	if err := os.Link(target, hardlink); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := addLicense(symlink, 0777, tmpl, data, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Lstat(symlink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("addLicense replaced the symlink with a %v file", fi.Mode().Type())
+	}
+	want := "# HYS\n\necho hello\n"
+	for _, path := range []string{target, hardlink} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s contains %q, want %q", filepath.Base(path), got, want)
+		}
+	}
+
+	target2, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hardlink2, err := os.Stat(hardlink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(target2, hardlink2) {
+		t.Errorf("addLicense broke the hard link between target.sh and hardlink.sh")
+	}
+	if target2.Mode().Perm() != 0640 {
+		t.Errorf("addLicense changed mode to %v, want %v", target2.Mode().Perm(), os.FileMode(0640))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bytes"
+)
+
+const (
+	// IgnoreFileMarker exempts a file from all processing when it appears in
+	// one of the first few lines of the file
+	IgnoreFileMarker = "copywrite:ignore-file"
+
+	// IgnoreNextLineMarker protects the line following it from being modified,
+	// such as a third-party copyright statement that must be kept verbatim
+	IgnoreNextLineMarker = "copywrite:ignore-next-line"
+)
+
+// ignoreFileMarkerLines is the number of lines at the top of a file searched
+// for IgnoreFileMarker
+const ignoreFileMarkerLines = 10
+
+// HasIgnoreFileMarker reports whether IgnoreFileMarker appears in the first
+// few lines of b
+func HasIgnoreFileMarker(b []byte) bool {
+	for i := 0; i < ignoreFileMarkerLines && len(b) > 0; i++ {
+		line := b
+		if j := bytes.IndexByte(b, '\n'); j >= 0 {
+			line, b = b[:j], b[j+1:]
+		} else {
+			b = nil
+		}
+		if bytes.Contains(line, []byte(IgnoreFileMarker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConflictMarkersReason is the reason given by SkipReason for files with merge
+// conflict markers near the top
+const ConflictMarkersReason = "merge conflict markers"
+
+// conflictMarkerLines is the number of lines at the top of a file searched for
+// merge conflict markers
+const conflictMarkerLines = 100
+
+// HasConflictMarkers reports whether a merge conflict marker (a line starting
+// with "<<<<<<<" or ">>>>>>>") appears in the first few lines of b. Adding a
+// header to a file in a half-merged state would only make the conflict harder
+// to resolve.
+func HasConflictMarkers(b []byte) bool {
+	for i := 0; i < conflictMarkerLines && len(b) > 0; i++ {
+		line := b
+		if j := bytes.IndexByte(b, '\n'); j >= 0 {
+			line, b = b[:j], b[j+1:]
+		} else {
+			b = nil
+		}
+		line = bytes.TrimRight(line, "\r")
+		for _, marker := range [][]byte{[]byte("<<<<<<<"), []byte(">>>>>>>")} {
+			if rest, ok := bytes.CutPrefix(line, marker); ok && (len(rest) == 0 || rest[0] == ' ') {
+				return true
+			}
+		}
+	}
+	return false
+}
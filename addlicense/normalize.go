@@ -0,0 +1,302 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// yearList matches the years of a copyright statement, such as "2019",
+// "2019-2023", or "2019, 2021"
+var yearList = regexp.MustCompile(`\b(?:19|20)\d{2}(?:\s*(?:[-–]|,)\s*(?:19|20)\d{2})*\b`)
+
+// yearRange and yearSeparator match the separators between years written
+// by canonicalYears
+var (
+	yearRange     = regexp.MustCompile(`\s*[-–]\s*`)
+	yearSeparator = regexp.MustCompile(`\s*,\s*`)
+)
+
+// spdxHeaderText matches the text of an SPDX-License-Identifier header line
+var spdxHeaderText = regexp.MustCompile(`^SPDX-License-Identifier:\s*(.+)$`)
+
+// maxNormalizedHeaderLines is the number of lines beyond which a comment at the
+// top of a file is no longer considered a header that can be normalized
+const maxNormalizedHeaderLines = 20
+
+// Normalizer rewrites headers that are already compliant into the canonical
+// form that Run would write for them
+type Normalizer struct {
+	license  LicenseData
+	opts     Options
+	full     *template.Template
+	spdxOnly *template.Template
+	preserve []*regexp.Regexp
+
+	// statement matches copyright statements naming the holder in any casing,
+	// with or without "(c)", capturing the years before or after the holder
+	statement *regexp.Regexp
+}
+
+// NewNormalizer returns a Normalizer for headers that name license.Holder and
+// license.SPDXID. license.Year is ignored: the years of each existing copyright
+// statement are kept as they are.
+func NewNormalizer(license LicenseData, opts Options) (*Normalizer, error) {
+	if license.Holder == "" {
+		return nil, fmt.Errorf("a copyright holder is required to normalize headers")
+	}
+	if opts.HeaderStyle != "" && opts.HeaderStyle != HeaderStylePlain {
+		return nil, fmt.Errorf("only plain headers can be normalized, not %q", opts.HeaderStyle)
+	}
+	if opts.HeaderTemplate != "" {
+		return nil, fmt.Errorf("headers rendered from a license template can't be normalized")
+	}
+	if err := ValidatePreserveAdjacent(opts.PreserveAdjacent); err != nil {
+		return nil, err
+	}
+
+	full, err := template.New("").Parse(withCopyrightFormat(tmplSPDX, opts.CopyrightFormat))
+	if err != nil {
+		return nil, err
+	}
+	spdxOnly, err := template.New("").Parse(tmplSPDXIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	years := yearList.String()
+	statement := regexp.MustCompile(`^(?i:copyright)(?:\s*(?:\(c\)|©))?\s*(` + years + `)?[\s,]*(?i:` + regexp.QuoteMeta(license.Holder) + `)\.?[\s,]*(` + years + `)?\.?$`)
+
+	return &Normalizer{
+		license:   license,
+		opts:      opts,
+		full:      full,
+		spdxOnly:  spdxOnly,
+		preserve:  preserveAdjacent(opts),
+		statement: statement,
+	}, nil
+}
+
+// Normalize returns b, the contents of the file at path, with its header
+// rewritten into canonical form: the copyright statement followed by the SPDX
+// identifier, with the configured holder casing, copyright format, and
+// spacing, and years written as "2019-2023" or "2019, 2021". Attribution lines
+// (see DefaultPreserveAdjacent) are kept directly below it.
+//
+// Only headers at the top of a file (below any preamble) in the file's own
+// comment style are rewritten, and only if they consist of nothing but a
+// copyright statement for the holder and, if license.SPDXID is set, a
+// matching SPDX identifier, along with blank comment lines and attribution
+// lines. Anything else, such as license text or a third-party notice, means
+// the header is left exactly as it is. The second return value reports
+// whether b was changed.
+func (n *Normalizer) Normalize(path string, b []byte) ([]byte, bool, error) {
+	if HasConflictMarkers(b) || HasIgnoreFileMarker(b) || SkipReason(path, b) != "" {
+		return b, false, nil
+	}
+	style, ok := CommentStyleFor(path)
+	if !ok {
+		return b, false, nil
+	}
+
+	pre := preamble(path, b, n.opts)
+	rest := bytes.TrimLeft(b[len(pre):], "\n")
+	texts, size := headerComment(rest, style, maxNormalizedHeaderLines)
+	if texts == nil {
+		return b, false, nil
+	}
+
+	data, attribution, ok := n.parse(texts)
+	if !ok {
+		return b, false, nil
+	}
+	return n.rewrite(path, b, pre, rest[size:], style, data, attribution)
+}
+
+// rewrite returns b with the header between pre and rest replaced by the
+// canonical header for data, with attribution lines below it. The second
+// return value reports whether b was changed.
+func (n *Normalizer) rewrite(path string, b, pre, rest []byte, style CommentStyle, data LicenseData, attribution []string) ([]byte, bool, error) {
+	tmpl := n.full
+	if data.Holder == "" {
+		tmpl = n.spdxOnly
+	}
+	header, err := licenseHeader(path, tmpl, data, Options{})
+	if err != nil || header == nil {
+		return b, false, err
+	}
+	// Years are never dropped, even if the copyright format doesn't use them
+	if !bytes.Contains(header, []byte(data.Year)) {
+		return b, false, nil
+	}
+	header = withAttribution(header, attribution, style)
+
+	spacing := 1
+	if n.opts.HeaderSpacing != nil {
+		spacing = *n.opts.HeaderSpacing
+	}
+	content := bytes.TrimLeft(rest, "\n")
+
+	out := make([]byte, 0, len(b))
+	out = append(out, pre...)
+	if len(pre) > 0 && pre[len(pre)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	if len(content) == 0 {
+		out = append(out, spacedHeader(header, 0)...)
+	} else {
+		out = append(out, spacedHeader(header, spacing)...)
+		out = append(out, content...)
+	}
+
+	if bytes.Equal(out, b) {
+		return b, false, nil
+	}
+	return out, true, nil
+}
+
+// parse classifies the text of each line of a header comment, returning the
+// data that renders its canonical form and the attribution lines to keep,
+// or false if the header isn't one that can be normalized. data.Holder is
+// empty if the header has no copyright statement, which is only allowed if
+// Options.OmitCopyright is set.
+func (n *Normalizer) parse(texts []string) (LicenseData, []string, bool) {
+	data := LicenseData{}
+	attribution := []string{}
+	hasSPDX := false
+	for _, text := range texts {
+		switch {
+		case text == "":
+		case matchesAny([]byte(text), n.preserve):
+			attribution = append(attribution, text)
+		case spdxHeaderText.MatchString(text):
+			expr := spdxHeaderText.FindStringSubmatch(text)[1]
+			if hasSPDX || n.license.SPDXID == "" || !SPDXExpressionsMatch(expr, n.license.SPDXID) {
+				return data, nil, false
+			}
+			hasSPDX = true
+			data.SPDXID = n.license.SPDXID
+		default:
+			years, ok := n.statementYears(text)
+			if !ok || data.Holder != "" {
+				return data, nil, false
+			}
+			data.Holder = n.license.Holder
+			data.Year = years
+		}
+	}
+
+	if n.license.SPDXID != "" && !hasSPDX {
+		return data, nil, false
+	}
+	if data.Holder == "" && !n.opts.OmitCopyright {
+		return data, nil, false
+	}
+	return data, attribution, true
+}
+
+// statementYears reports whether text is a copyright statement for the holder,
+// either in the configured copyright format or the default one in any casing,
+// and returns its years in canonical form
+func (n *Normalizer) statementYears(text string) (string, bool) {
+	years := canonicalYears(yearList.FindString(text))
+
+	if m := n.statement.FindStringSubmatch(text); m != nil {
+		if m[1] != "" && m[2] != "" {
+			return "", false
+		}
+		return years, true
+	}
+
+	formatted, err := FormatCopyright(n.opts.CopyrightFormat, LicenseData{Year: years, Holder: n.license.Holder})
+	if err != nil {
+		return "", false
+	}
+	return years, strings.EqualFold(strings.Join(strings.Fields(text), " "), formatted)
+}
+
+// canonicalYears writes years found by yearList with ranges as "2019-2023" and
+// lists as "2019, 2021"
+func canonicalYears(years string) string {
+	years = yearRange.ReplaceAllString(years, "-")
+	return yearSeparator.ReplaceAllString(years, ", ")
+}
+
+// headerComment returns the text of each line of the comment at the start of b
+// written in style, without its comment delimiters and surrounding
+// whitespace, along with the length of the comment including its final
+// newline. If b doesn't start with such a comment of at most maxLines lines,
+// nil is returned.
+func headerComment(b []byte, style CommentStyle, maxLines int) ([]string, int) {
+	top := strings.TrimSpace(style.Top)
+	mid := strings.TrimSpace(style.Mid)
+	bottom := strings.TrimSpace(style.Bottom)
+
+	texts := []string{}
+	size := 0
+	inBlock := false
+	for i := 0; size < len(b) && i < maxLines; i++ {
+		end := lineEnd(b, size)
+		line := string(b[size:end])
+		if strings.Contains(line, "\r") {
+			return nil, 0
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case top == "":
+			// Line comments end at the first line that isn't one
+			if mid == "" || !strings.HasPrefix(trimmed, mid) {
+				if len(texts) == 0 {
+					return nil, 0
+				}
+				return texts, size
+			}
+			texts = append(texts, strings.TrimSpace(strings.TrimPrefix(trimmed, mid)))
+		case !inBlock:
+			if trimmed != top {
+				return nil, 0
+			}
+			inBlock = true
+		case trimmed == bottom:
+			return texts, end
+		default:
+			if mid != "" {
+				trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, mid))
+			}
+			texts = append(texts, trimmed)
+		}
+		size = end
+	}
+
+	if top == "" && size == len(b) && len(texts) > 0 {
+		return texts, size
+	}
+	return nil, 0
+}
+
+// withAttribution adds attribution lines to the end of header, inside its
+// comment
+func withAttribution(header []byte, attribution []string, style CommentStyle) []byte {
+	if len(attribution) == 0 {
+		return header
+	}
+	lines := strings.SplitAfter(strings.TrimRight(string(header), "\n")+"\n", "\n")
+	lines = lines[:len(lines)-1]
+
+	added := make([]string, 0, len(attribution))
+	for _, a := range attribution {
+		added = append(added, strings.TrimRight(style.Mid+a, " ")+"\n")
+	}
+
+	at := len(lines)
+	if style.Bottom != "" {
+		at--
+	}
+	lines = append(lines[:at], append(added, lines[at:]...)...)
+	return []byte(strings.Join(lines, "") + "\n")
+}
@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	license := LicenseData{Holder: "HashiCorp, Inc.", SPDXID: "MPL-2.0"}
+	two := 2
+
+	tests := []struct {
+		description  string
+		path         string
+		contents     string
+		opts         Options
+		wantContents string
+		wantUpdated  bool
+	}{
+		{
+			"canonical header",
+			"f.go",
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			Options{},
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			false,
+		},
+		{
+			"order, casing, and spacing",
+			"f.go",
+			"//SPDX-License-Identifier:   MPL-2.0\n//\n//   copyright   hashicorp, inc.\npackage a\n",
+			Options{},
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			true,
+		},
+		{
+			"years are kept",
+			"f.go",
+			"// Copyright 2019 - 2023 HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			Options{},
+			"// Copyright (c) 2019-2023 HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			true,
+		},
+		{
+			"equivalent SPDX expression",
+			"f.go",
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: mpl-2.0\n\npackage a\n",
+			Options{},
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			true,
+		},
+		{
+			"header spacing",
+			"f.go",
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			Options{HeaderSpacing: &two},
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\n\npackage a\n",
+			true,
+		},
+		{
+			"attribution lines are kept",
+			"f.go",
+			"// Copyright (c) HashiCorp, Inc.\n//\n// Original author: Jane Doe <jane@example.com>\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			Options{},
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n// Original author: Jane Doe <jane@example.com>\n\npackage a\n",
+			true,
+		},
+		{
+			"preamble",
+			"f.sh",
+			"#!/bin/bash\n\n# SPDX-License-Identifier: MPL-2.0\n# Copyright HashiCorp, Inc.\necho hi\n",
+			Options{},
+			"#!/bin/bash\n# Copyright (c) HashiCorp, Inc.\n# SPDX-License-Identifier: MPL-2.0\n\necho hi\n",
+			true,
+		},
+		{
+			"block comments",
+			"f.css",
+			"/**\n * SPDX-License-Identifier: MPL-2.0\n * Copyright HashiCorp, Inc.\n */\nbody {}\n",
+			Options{},
+			"/**\n * Copyright (c) HashiCorp, Inc.\n * SPDX-License-Identifier: MPL-2.0\n */\n\nbody {}\n",
+			true,
+		},
+		{
+			"copyright format",
+			"f.go",
+			"// Copyright (c) 2020 HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			Options{CopyrightFormat: "Copyright {{.Years}} {{.Holder}} All rights reserved."},
+			"// Copyright 2020 HashiCorp, Inc. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			true,
+		},
+		{
+			"other holder",
+			"f.go",
+			"// Copyright (c) Acme Inc.\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			Options{},
+			"// Copyright (c) Acme Inc.\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			false,
+		},
+		{
+			"other license",
+			"f.go",
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MIT\npackage a\n",
+			Options{},
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MIT\npackage a\n",
+			false,
+		},
+		{
+			"missing SPDX identifier",
+			"f.go",
+			"// Copyright HashiCorp, Inc.\npackage a\n",
+			Options{},
+			"// Copyright HashiCorp, Inc.\npackage a\n",
+			false,
+		},
+		{
+			"additional text",
+			"f.go",
+			"// Copyright HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n// Portions copyright Acme Inc.\npackage a\n",
+			Options{},
+			"// Copyright HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n// Portions copyright Acme Inc.\npackage a\n",
+			false,
+		},
+		{
+			"trailing text",
+			"f.go",
+			"// Copyright HashiCorp, Inc. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			Options{},
+			"// Copyright HashiCorp, Inc. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			false,
+		},
+		{
+			"comment in another style",
+			"f.go",
+			"/* Copyright HashiCorp, Inc.\n SPDX-License-Identifier: MPL-2.0 */\npackage a\n",
+			Options{},
+			"/* Copyright HashiCorp, Inc.\n SPDX-License-Identifier: MPL-2.0 */\npackage a\n",
+			false,
+		},
+		{
+			"ignore marker",
+			"f.go",
+			"// copywrite:ignore-next-line\n// Copyright HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			Options{},
+			"// copywrite:ignore-next-line\n// Copyright HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			false,
+		},
+		{
+			"spdx only",
+			"f.go",
+			"//SPDX-License-Identifier: MPL-2.0\npackage a\n",
+			Options{OmitCopyright: true},
+			"// SPDX-License-Identifier: MPL-2.0\n\npackage a\n",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			n, err := NewNormalizer(license, tt.opts)
+			if err != nil {
+				t.Fatalf("NewNormalizer returned error: %v", err)
+			}
+			got, updated, err := n.Normalize(tt.path, []byte(tt.contents))
+			if err != nil {
+				t.Fatalf("Normalize returned error: %v", err)
+			}
+			if updated != tt.wantUpdated {
+				t.Errorf("Normalize returned updated: %t, want %t", updated, tt.wantUpdated)
+			}
+			if string(got) != tt.wantContents {
+				t.Errorf("Normalize returned contents: %q, want %q", got, tt.wantContents)
+			}
+
+			// Normalized headers are stable, and still recognized as headers
+			again, updated, _ := n.Normalize(tt.path, got)
+			if updated {
+				t.Errorf("Normalize is not idempotent: %q became %q", got, again)
+			}
+			if !hasLicense(got, nil) {
+				t.Errorf("Normalize returned contents without a header: %q", got)
+			}
+		})
+	}
+}
+
+func TestNewNormalizerErrors(t *testing.T) {
+	if _, err := NewNormalizer(LicenseData{SPDXID: "MPL-2.0"}, Options{}); err == nil {
+		t.Errorf("NewNormalizer without a holder returned no error")
+	}
+	if _, err := NewNormalizer(LicenseData{Holder: "H"}, Options{HeaderStyle: HeaderStyleBoxed}); err == nil {
+		t.Errorf("NewNormalizer with boxed headers returned no error")
+	}
+}
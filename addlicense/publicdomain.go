@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// publicDomainDedication matches lowercase text that dedicates a file to the
+// public domain, such as the Unlicense or CC0, rather than licensing it
+var publicDomainDedication = regexp.MustCompile(`released (?:in)?to the public domain|dedicated to the public domain|creativecommons\.org/publicdomain/zero|cc0 1\.0 universal|unlicense\.org|no copyright (?:is )?(?:claimed|intended)|spdx-license-identifier:\s*(?:unlicense|cc0-1\.0)\b`)
+
+// publicDomainComment returns the start and end of the comment at the top of
+// b, the contents of the file at path, that dedicates it to the public domain,
+// along with the text that does. The end includes any blank lines after the
+// comment. Like Normalize, only a comment below any preamble, written in the
+// file's own comment style, is found, and it must be separated from whatever
+// follows it by a blank line, so that doc comments are never removed.
+func publicDomainComment(path string, b []byte, opts Options) (int, int, string) {
+	style, ok := CommentStyleFor(path)
+	if !ok {
+		return 0, 0, ""
+	}
+	start := len(preamble(path, b, opts))
+	rest := bytes.TrimLeft(b[start:], "\n")
+	texts, size := headerComment(rest, style, maxCompactedHeaderLines)
+	if texts == nil || (size < len(rest) && rest[size] != '\n') {
+		return 0, 0, ""
+	}
+	m := publicDomainDedication.FindString(strings.ToLower(strings.Join(texts, " ")))
+	if m == "" {
+		return 0, 0, ""
+	}
+	end := len(b) - len(bytes.TrimLeft(rest[size:], "\n"))
+	return start, end, m
+}
+
+// headerKeyword returns the keyword that makes b, the contents of the file at
+// path, count as having a license header under opts, or an empty string if
+// there is none. It's like licenseKeyword, except that with
+// ConvertPublicDomain set, a comment at the top of the file that dedicates it
+// to the public domain doesn't count, as prependLicense replaces it.
+func (opts Options) headerKeyword(path string, b []byte) string {
+	if opts.ConvertPublicDomain {
+		if _, _, m := publicDomainComment(path, b, opts); m != "" {
+			return ""
+		}
+	}
+	return licenseKeyword(b, opts.CopyrightKeywords)
+}
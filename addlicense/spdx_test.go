@@ -43,3 +43,71 @@ func TestValidSPDX(t *testing.T) {
 		})
 	}
 }
+
+func TestSuggestSPDX(t *testing.T) {
+	tests := []struct {
+		description    string // test case description
+		spdxID         string // invalid SPDX ID passed to SuggestSPDX()
+		expectedOutput string // best suggestion, or empty if none are expected
+	}{
+		{
+			"Missing punctuation is suggested",
+			"MPL2",
+			"MPL-2.0",
+		},
+		{
+			"Wrong casing and punctuation is suggested",
+			"apache 2",
+			"Apache-2.0",
+		},
+		{
+			"Missing -only suffix is suggested",
+			"GPL-2.0",
+			"GPL-2.0-only",
+		},
+		{
+			"Nonsense has no suggestions",
+			"asdf323dd7g23f9h38rf978f3h938hf98asdf279hf85gh65323f",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			suggestions := SuggestSPDX(tt.spdxID)
+			actualOutput := ""
+			if len(suggestions) > 0 {
+				actualOutput = suggestions[0]
+			}
+			if tt.expectedOutput != actualOutput {
+				t.Fatalf("SuggestSPDX(%q) returned %v, want %q first", tt.spdxID, suggestions, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestParseSPDXList(t *testing.T) {
+	input := `{
+  "licenseListVersion": "3.21",
+  "licenses": [
+    {"licenseId": "MIT", "name": "MIT License", "isDeprecatedLicenseId": false},
+    {"licenseId": "GPL-2.0", "name": "GNU General Public License v2.0 only", "isDeprecatedLicenseId": true},
+    {"licenseId": "0BSD", "name": "BSD Zero Clause License", "isDeprecatedLicenseId": false}
+  ]
+}`
+
+	version, list, err := parseSPDXList([]byte(input))
+	if err != nil {
+		t.Fatalf("parseSPDXList() returned error: %v", err)
+	}
+	if version != "3.21" {
+		t.Fatalf("parseSPDXList() returned version %q, want %q", version, "3.21")
+	}
+	if len(list) != 2 || list[0].ID != "0BSD" || list[1].ID != "MIT" {
+		t.Fatalf("parseSPDXList() returned %v, want sorted non-deprecated licenses", list)
+	}
+
+	if _, _, err := parseSPDXList([]byte(`{"licenses": []}`)); err == nil {
+		t.Fatalf("parseSPDXList() of an empty list should return an error")
+	}
+}
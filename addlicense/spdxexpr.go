@@ -0,0 +1,311 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// spdxExpression is a node in a parsed SPDX license expression. Leaf nodes hold
+// a license (and an optional exception), while compound nodes hold an operator
+// and the expressions it joins.
+//
+// https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+type spdxExpression struct {
+	license   string
+	exception string
+
+	operator string
+	operands []*spdxExpression
+}
+
+// String renders the expression with consistent spacing and casing, keeping
+// the original operand order. Parentheses are only added where required.
+func (e *spdxExpression) String() string {
+	if e.operator == "" {
+		if e.exception != "" {
+			return e.license + " WITH " + e.exception
+		}
+		return e.license
+	}
+
+	parts := make([]string, len(e.operands))
+	for i, o := range e.operands {
+		s := o.String()
+		// AND binds more tightly than OR, so an OR nested in an AND must be grouped
+		if e.operator == "AND" && o.operator == "OR" {
+			s = "(" + s + ")"
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, " "+e.operator+" ")
+}
+
+// canonical renders the expression such that two equivalent expressions
+// always produce the same string, regardless of operand order
+func (e *spdxExpression) canonical() string {
+	if e.operator == "" {
+		return e.String()
+	}
+
+	parts := make([]string, len(e.operands))
+	for i, o := range e.operands {
+		parts[i] = o.canonical()
+		if o.operator != "" {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " "+e.operator+" ")
+}
+
+// spdxParser is a recursive descent parser for SPDX license expressions.
+// Operator precedence, from tightest to loosest, is: WITH, AND, OR
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *spdxParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *spdxParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *spdxParser) parseOr() (*spdxExpression, error) {
+	return p.parseCompound("OR", p.parseAnd)
+}
+
+func (p *spdxParser) parseAnd() (*spdxExpression, error) {
+	return p.parseCompound("AND", p.parseAtom)
+}
+
+// parseCompound parses one or more operands joined by the given operator.
+// Nested expressions using the same operator are flattened, as both AND and
+// OR are associative.
+func (p *spdxParser) parseCompound(operator string, operand func() (*spdxExpression, error)) (*spdxExpression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*spdxExpression{first}
+	for strings.EqualFold(p.peek(), operator) {
+		p.next()
+		o, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, o)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	flattened := []*spdxExpression{}
+	for _, o := range operands {
+		if o.operator == operator {
+			flattened = append(flattened, o.operands...)
+		} else {
+			flattened = append(flattened, o)
+		}
+	}
+	return &spdxExpression{operator: operator, operands: flattened}, nil
+}
+
+func (p *spdxParser) parseAtom() (*spdxExpression, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return nil, fmt.Errorf("expected a license identifier, but the expression ended")
+	case t == "(":
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return e, nil
+	case t == ")" || isSPDXOperator(t):
+		return nil, fmt.Errorf("expected a license identifier, but found %q", t)
+	}
+
+	license, err := canonicalSPDXLicense(t)
+	if err != nil {
+		return nil, err
+	}
+	e := &spdxExpression{license: license}
+
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.next()
+		t := p.next()
+		exception, ok := canonicalSPDXID(SPDXExceptions(), t)
+		if !ok {
+			return nil, fmt.Errorf("unknown SPDX license exception %q", t)
+		}
+		e.exception = exception
+	}
+
+	return e, nil
+}
+
+func isSPDXOperator(t string) bool {
+	return strings.EqualFold(t, "AND") || strings.EqualFold(t, "OR") || strings.EqualFold(t, "WITH")
+}
+
+// spdxLicenseRef matches user-defined license references, such as
+// "LicenseRef-Proprietary" or "DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2"
+var spdxLicenseRef = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.-]+:)?LicenseRef-[A-Za-z0-9.-]+$`)
+
+// canonicalSPDXLicense validates a single license token (an SPDX ID with an
+// optional "+" suffix, or a LicenseRef) and returns it in its canonical casing
+func canonicalSPDXLicense(t string) (string, error) {
+	if spdxLicenseRef.MatchString(t) {
+		return t, nil
+	}
+
+	id, orLater := strings.CutSuffix(t, "+")
+	canonical, ok := canonicalSPDXID(SPDXLicenses(), id)
+	if !ok {
+		if suggestions := SuggestSPDX(id); len(suggestions) > 0 {
+			return "", fmt.Errorf("unknown SPDX license identifier %q (did you mean %q?)", t, suggestions[0])
+		}
+		return "", fmt.Errorf("unknown SPDX license identifier %q", t)
+	}
+	if orLater {
+		canonical += "+"
+	}
+	return canonical, nil
+}
+
+// canonicalSPDXID looks up an ID without case sensitivity, as required by the
+// SPDX specification, and returns the officially cased version
+func canonicalSPDXID(list []SPDXLicense, id string) (string, bool) {
+	for _, v := range list {
+		if strings.EqualFold(v.ID, id) {
+			return v.ID, true
+		}
+	}
+	return "", false
+}
+
+func tokenizeSPDX(expr string) []string {
+	tokens := []string{}
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expr {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func parseSPDXExpression(expr string) (*spdxExpression, error) {
+	p := &spdxParser{tokens: tokenizeSPDX(expr)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("SPDX license expression is empty")
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPDX license expression %q: %w", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid SPDX license expression %q: unexpected %q", expr, p.peek())
+	}
+
+	return e, nil
+}
+
+// NormalizeSPDXExpression validates an SPDX license expression, such as
+// "MIT OR Apache-2.0" or "GPL-2.0-only WITH Classpath-exception-2.0", and
+// returns it with consistent spacing and casing. A single SPDX ID is also a
+// valid expression.
+func NormalizeSPDXExpression(expr string) (string, error) {
+	e, err := parseSPDXExpression(expr)
+	if err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+// ValidSPDXExpression returns true if expr is a valid SPDX ID or a well-formed
+// SPDX license expression composed of valid IDs
+func ValidSPDXExpression(expr string) bool {
+	_, err := parseSPDXExpression(expr)
+	return err == nil
+}
+
+// SPDXExpressionsMatch reports whether two SPDX license expressions are
+// equivalent, ignoring differences in spacing, casing, and operand order.
+// Invalid expressions never match.
+func SPDXExpressionsMatch(a, b string) bool {
+	ea, err := parseSPDXExpression(a)
+	if err != nil {
+		return false
+	}
+	eb, err := parseSPDXExpression(b)
+	if err != nil {
+		return false
+	}
+	return ea.canonical() == eb.canonical()
+}
+
+// spdxHeaderLine matches the SPDX-License-Identifier line of a header,
+// capturing the expression that follows it
+var spdxHeaderLine = regexp.MustCompile(`(?im)SPDX-License-Identifier:[ \t]*(.*)$`)
+
+// SPDXExpressions returns the expressions of every SPDX-License-Identifier
+// line found in the header region of b (see HeaderScanWindow)
+func SPDXExpressions(b []byte) []string {
+	exprs := []string{}
+	for _, m := range spdxHeaderLine.FindAllSubmatch(HeaderRegion(b), -1) {
+		found := strings.TrimSpace(string(m[1]))
+		// Drop the closing delimiter of single-line block comments
+		for _, closer := range []string{"*/", "-->", "*)", "}}", "%>"} {
+			found = strings.TrimSpace(strings.TrimSuffix(found, closer))
+		}
+		exprs = append(exprs, found)
+	}
+	return exprs
+}
+
+// HasSPDXExpression reports whether the header region of b contains
+// an SPDX-License-Identifier line whose expression is equivalent to expr
+func HasSPDXExpression(b []byte, expr string) bool {
+	for _, found := range SPDXExpressions(b) {
+		if SPDXExpressionsMatch(found, expr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"testing"
+)
+
+func TestNormalizeSPDXExpression(t *testing.T) {
+	tests := []struct {
+		description    string // test case description
+		expr           string // expression passed to NormalizeSPDXExpression()
+		expectedOutput string // normalized expression, if valid
+		expectErr      bool   // whether the expression should be rejected
+	}{
+		{
+			"Single SPDX ID is a valid expression",
+			"MPL-2.0",
+			"MPL-2.0",
+			false,
+		},
+		{
+			"IDs and operators are canonically cased",
+			"mit or apache-2.0",
+			"MIT OR Apache-2.0",
+			false,
+		},
+		{
+			"Extra whitespace is collapsed",
+			"  MIT   AND\tBSD-3-Clause ",
+			"MIT AND BSD-3-Clause",
+			false,
+		},
+		{
+			"License exceptions are supported",
+			"gpl-2.0-only with classpath-exception-2.0",
+			"GPL-2.0-only WITH Classpath-exception-2.0",
+			false,
+		},
+		{
+			"Or-later suffix is supported",
+			"LGPL-2.1-only+ OR MIT",
+			"LGPL-2.1-only+ OR MIT",
+			false,
+		},
+		{
+			"LicenseRefs are supported",
+			"LicenseRef-Proprietary OR DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2",
+			"LicenseRef-Proprietary OR DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2",
+			false,
+		},
+		{
+			"Redundant parentheses are dropped",
+			"((MIT)) AND (Apache-2.0 AND BSD-2-Clause)",
+			"MIT AND Apache-2.0 AND BSD-2-Clause",
+			false,
+		},
+		{
+			"Required parentheses are kept",
+			"(MIT OR Apache-2.0) AND BSD-2-Clause",
+			"(MIT OR Apache-2.0) AND BSD-2-Clause",
+			false,
+		},
+		{
+			"AND binds more tightly than OR",
+			"MIT OR Apache-2.0 AND BSD-2-Clause",
+			"MIT OR Apache-2.0 AND BSD-2-Clause",
+			false,
+		},
+		{
+			"Unknown license ID is invalid",
+			"MIT OR NotARealLicense",
+			"",
+			true,
+		},
+		{
+			"Unknown exception is invalid",
+			"GPL-2.0-only WITH NotARealException",
+			"",
+			true,
+		},
+		{
+			"Dangling operator is invalid",
+			"MIT OR",
+			"",
+			true,
+		},
+		{
+			"Unbalanced parentheses are invalid",
+			"(MIT OR Apache-2.0",
+			"",
+			true,
+		},
+		{
+			"Missing operator is invalid",
+			"MIT Apache-2.0",
+			"",
+			true,
+		},
+		{
+			"Empty expression is invalid",
+			"",
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, err := NormalizeSPDXExpression(tt.expr)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("NormalizeSPDXExpression(%q) returned error %v, want error: %v", tt.expr, err, tt.expectErr)
+			}
+			if tt.expectedOutput != actualOutput {
+				t.Fatalf("NormalizeSPDXExpression(%q) returned %q, want %q", tt.expr, actualOutput, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestSPDXExpressionsMatch(t *testing.T) {
+	tests := []struct {
+		description    string // test case description
+		a              string // first expression
+		b              string // second expression
+		expectedOutput bool   // whether the expressions should be considered equivalent
+	}{
+		{
+			"Identical expressions match",
+			"MIT OR Apache-2.0",
+			"MIT OR Apache-2.0",
+			true,
+		},
+		{
+			"Operand order is ignored",
+			"MIT OR Apache-2.0",
+			"Apache-2.0 OR MIT",
+			true,
+		},
+		{
+			"Casing and grouping are ignored",
+			"(mit and bsd-2-clause) or apache-2.0",
+			"Apache-2.0 OR BSD-2-Clause AND MIT",
+			true,
+		},
+		{
+			"Different operators do not match",
+			"MIT OR Apache-2.0",
+			"MIT AND Apache-2.0",
+			false,
+		},
+		{
+			"Missing exception does not match",
+			"GPL-2.0-only WITH Classpath-exception-2.0",
+			"GPL-2.0-only",
+			false,
+		},
+		{
+			"Invalid expressions never match",
+			"MIT OR",
+			"MIT OR",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput := SPDXExpressionsMatch(tt.a, tt.b)
+			if tt.expectedOutput != actualOutput {
+				t.Fatalf("SPDXExpressionsMatch(%q, %q) returned %v, want %v", tt.a, tt.b, actualOutput, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestHasSPDXExpression(t *testing.T) {
+	tests := []struct {
+		description    string // test case description
+		content        string // file content
+		expr           string // expression to search for
+		expectedOutput bool   // whether the header should be considered a match
+	}{
+		{
+			"Line comment header matches",
+			"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: Apache-2.0 OR MIT\n\npackage main\n",
+			"MIT OR Apache-2.0",
+			true,
+		},
+		{
+			"Single-line block comment header matches",
+			"/* SPDX-License-Identifier: MIT */\n",
+			"MIT",
+			true,
+		},
+		{
+			"Different expression does not match",
+			"# SPDX-License-Identifier: MIT AND Apache-2.0\n",
+			"MIT OR Apache-2.0",
+			false,
+		},
+		{
+			"No SPDX line does not match",
+			"// Copyright (c) HashiCorp, Inc.\n",
+			"MIT",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput := HasSPDXExpression([]byte(tt.content), tt.expr)
+			if tt.expectedOutput != actualOutput {
+				t.Fatalf("HasSPDXExpression(%q, %q) returned %v, want %v", tt.content, tt.expr, actualOutput, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestValidSPDXException(t *testing.T) {
+	if !ValidSPDXException("Classpath-exception-2.0") {
+		t.Fatalf("ValidSPDXException(%q) returned false, want true", "Classpath-exception-2.0")
+	}
+	if ValidSPDXException("MIT") {
+		t.Fatalf("ValidSPDXException(%q) returned true, want false", "MIT")
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SkipCategory is a kind of reason for a file being skipped, for counting
+// skipped files in Stats
+type SkipCategory string
+
+const (
+	// SkipNeverTouched files match DefaultNeverTouch or Options.NeverTouch
+	SkipNeverTouched SkipCategory = "never_touched"
+
+	// SkipHidden files are hidden, and Options.SkipHidden is set
+	SkipHidden SkipCategory = "hidden"
+
+	// SkipNotIncluded files don't match any of Options.Include
+	SkipNotIncluded SkipCategory = "not_included"
+
+	// SkipIgnorePattern files match an ignore pattern
+	SkipIgnorePattern SkipCategory = "ignore_pattern"
+
+	// SkipFiltered files were excluded by Options.Skip, such as files
+	// authored by someone else or unchanged from an upstream repo
+	SkipFiltered SkipCategory = "filtered"
+
+	// SkipUnknownExtension files are of a type that headers can't be added to
+	SkipUnknownExtension SkipCategory = "unknown_extension"
+
+	// SkipConflictMarkers files have merge conflict markers
+	SkipConflictMarkers SkipCategory = "conflict_markers"
+
+	// SkipIgnoreMarker files have the IgnoreFileMarker
+	SkipIgnoreMarker SkipCategory = "ignore_marker"
+
+	// SkipGenerated files were generated by a tool
+	SkipGenerated SkipCategory = "generated"
+
+	// SkipBuildArtifact files are minified or bundled
+	SkipBuildArtifact SkipCategory = "build_artifact"
+)
+
+// SkipCategories lists every SkipCategory, in the order that files are checked
+// for them
+var SkipCategories = []SkipCategory{
+	SkipNeverTouched,
+	SkipHidden,
+	SkipNotIncluded,
+	SkipIgnorePattern,
+	SkipFiltered,
+	SkipUnknownExtension,
+	SkipConflictMarkers,
+	SkipIgnoreMarker,
+	SkipGenerated,
+	SkipBuildArtifact,
+}
+
+// exemptCategory returns the category of a reason given by SkipReason
+func exemptCategory(reason string) SkipCategory {
+	switch reason {
+	case ConflictMarkersReason:
+		return SkipConflictMarkers
+	case IgnoreFileMarker + " marker":
+		return SkipIgnoreMarker
+	case generatedReason:
+		return SkipGenerated
+	}
+	return SkipBuildArtifact
+}
+
+// Stats summarizes what a call to Run did. Every count is a number of files.
+type Stats struct {
+	// Scanned files passed every ignore rule and were checked for a header
+	Scanned int
+
+	// Added files had a header added or, in check-only mode, are missing one
+	Added int
+
+	// Updated files had an existing header reformatted (e.g., respaced to match
+	// Options.HeaderSpacing)
+	Updated int
+
+	// Ignored files were excluded by ignore patterns, never-touch lists, or
+	// Options.Skip, such as vendored third-party code
+	Ignored int
+
+	// Exempted files did not need a header because of their contents, such as
+	// generated or minified files
+	Exempted int
+
+	// Skipped counts the files that were ignored, exempted, or of an unknown
+	// type by category. Categories with no files are omitted.
+	Skipped map[SkipCategory]int
+
+	// Elapsed is how long the run took
+	Elapsed time.Duration
+}
+
+// tally counts files as they are processed concurrently, and is summarized
+// into Stats once a run completes. All methods are safe to call on a nil tally.
+type tally struct {
+	scanned, added, updated, ignored, exempted atomic.Int64
+
+	mu      sync.Mutex
+	skipped map[SkipCategory]int
+}
+
+func (t *tally) scan() {
+	if t != nil {
+		t.scanned.Add(1)
+	}
+}
+
+func (t *tally) ignore(category SkipCategory) {
+	if t != nil {
+		t.ignored.Add(1)
+		t.skip(category)
+	}
+}
+
+func (t *tally) exempt(category SkipCategory) {
+	if t != nil {
+		t.exempted.Add(1)
+		t.skip(category)
+	}
+}
+
+// skip counts a file skipped for a reason in category
+func (t *tally) skip(category SkipCategory) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.skipped == nil {
+		t.skipped = map[SkipCategory]int{}
+	}
+	t.skipped[category]++
+}
+
+// change counts a file that needed a header, or whose existing header was
+// updated if existing is true
+func (t *tally) change(existing bool) {
+	switch {
+	case t == nil:
+	case existing:
+		t.updated.Add(1)
+	default:
+		t.added.Add(1)
+	}
+}
+
+// change records that the file at path needed a header, or had its existing
+// header updated if existing is true
+func (opts Options) change(path string, existing bool) {
+	opts.tally.change(existing)
+	if opts.Changed != nil {
+		opts.Changed(path, existing)
+	}
+}
+
+func (t *tally) stats(elapsed time.Duration) Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	skipped := make(map[SkipCategory]int, len(t.skipped))
+	for category, n := range t.skipped {
+		skipped[category] = n
+	}
+
+	return Stats{
+		Scanned:  int(t.scanned.Load()),
+		Added:    int(t.added.Load()),
+		Updated:  int(t.updated.Load()),
+		Ignored:  int(t.ignored.Load()),
+		Exempted: int(t.exempted.Load()),
+		Skipped:  skipped,
+		Elapsed:  elapsed,
+	}
+}
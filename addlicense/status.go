@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+// HeaderState is the outcome of checking a single file for a license header
+type HeaderState int
+
+const (
+	// HeaderPresent files already have a license header
+	HeaderPresent HeaderState = iota
+
+	// HeaderMissing files need a license header but don't have one
+	HeaderMissing
+
+	// HeaderExempt files don't need a header because of their contents, such
+	// as generated or minified files
+	HeaderExempt
+
+	// HeaderIgnored files are excluded by the never-touch lists,
+	// Options.Include, ignore patterns, or Options.Skip
+	HeaderIgnored
+
+	// HeaderUnsupported files are of a type that headers can't be added to,
+	// as its comment syntax is unknown
+	HeaderUnsupported
+)
+
+func (s HeaderState) String() string {
+	switch s {
+	case HeaderPresent:
+		return "present"
+	case HeaderMissing:
+		return "missing"
+	case HeaderExempt:
+		return "exempt"
+	case HeaderIgnored:
+		return "ignored"
+	case HeaderUnsupported:
+		return "unsupported"
+	}
+	return "unknown"
+}
+
+// Status describes whether a file has the license header it needs
+type Status struct {
+	State HeaderState
+
+	// Reason explains why a file is exempt or ignored, if known
+	Reason string
+}
+
+// OK reports whether the file passes a check-only run, either because it has
+// a header or because it doesn't need one
+func (s Status) OK() bool {
+	return s.State != HeaderMissing
+}
+
+// CheckBytes checks content, the contents of a file named name, for a license
+// header without touching disk, such as for a file piped to stdin or an unsaved
+// editor buffer. The same rules are applied as by CheckFiles, except that ignore
+// patterns, which are a parameter of Run and CheckFiles, must be given with
+// opts.NeverTouch instead. opts.Skipped, opts.Changed, and opts.Stats are not
+// used, as the outcome is returned directly.
+func CheckBytes(name string, content []byte, opts Options) (Status, error) {
+	if err := ValidatePatterns(opts.NeverTouch); err != nil {
+		return Status{}, err
+	}
+	if err := ValidatePatterns(opts.Include); err != nil {
+		return Status{}, err
+	}
+	if err := ValidateNeverTouchExceptions(opts.NeverTouchExceptions); err != nil {
+		return Status{}, err
+	}
+
+	if category, reason := skipFileWith(name, opts, nil); category != "" {
+		return Status{State: HeaderIgnored, Reason: reason}, nil
+	}
+	if _, ok := CommentStyleFor(name); !ok {
+		return Status{State: HeaderUnsupported}, nil
+	}
+	return contentStatus(name, content, opts), nil
+}
+
+// contentStatus checks b, the contents of the file at path, for a license
+// header. The file is assumed to be of a supported type and not ignored.
+func contentStatus(path string, b []byte, opts Options) Status {
+	if HasConflictMarkers(b) {
+		return Status{State: HeaderExempt, Reason: ConflictMarkersReason}
+	}
+	if opts.headerKeyword(path, b) != "" {
+		return Status{State: HeaderPresent}
+	}
+	if reason := SkipReason(path, b); reason != "" {
+		return Status{State: HeaderExempt, Reason: reason}
+	}
+	return Status{State: HeaderMissing}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import "testing"
+
+func TestCheckBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    Options
+		want    Status
+	}{
+		{
+			name:    "main.go",
+			content: "// Copyright 2020 Google LLC\n\npackage main\n",
+			want:    Status{State: HeaderPresent},
+		},
+		{
+			name:    "main.go",
+			content: "package main\n",
+			want:    Status{State: HeaderMissing},
+		},
+		{
+			name:    "stringer.go",
+			content: "// Code generated by stringer. DO NOT EDIT.\n\npackage main\n",
+			want:    Status{State: HeaderExempt, Reason: "generated file"},
+		},
+		{
+			name:    "vendor/dep/a.go",
+			content: "package dep\n",
+			opts:    Options{NeverTouch: []string{"vendor/**"}},
+			want:    Status{State: HeaderIgnored, Reason: "never touched"},
+		},
+		{
+			name:    "lib/a.go",
+			content: "package lib\n",
+			opts:    Options{Include: []string{"cmd/**"}},
+			want:    Status{State: HeaderIgnored, Reason: "not included"},
+		},
+		{
+			name:    "main.go",
+			content: "// Copyright 2020 Google LLC\n<<<<<<< HEAD\npackage main\n>>>>>>> feature\n",
+			want:    Status{State: HeaderExempt, Reason: ConflictMarkersReason},
+		},
+		{
+			name:    "data.unknownext",
+			content: "hello\n",
+			want:    Status{State: HeaderUnsupported},
+		},
+		{
+			name:    "main.go",
+			content: "// Derechos de autor 2020 Ejemplo\n\npackage main\n",
+			opts:    Options{CopyrightKeywords: []string{"Derechos de autor"}},
+			want:    Status{State: HeaderPresent},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := CheckBytes(tt.name, []byte(tt.content), tt.opts)
+		if err != nil {
+			t.Fatalf("CheckBytes(%q) returned error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("CheckBytes(%q, %q) = %+v, want %+v", tt.name, tt.content, got, tt.want)
+		}
+		if got.OK() != (tt.want.State != HeaderMissing) {
+			t.Errorf("CheckBytes(%q, %q).OK() = %v", tt.name, tt.content, got.OK())
+		}
+	}
+
+	if _, err := CheckBytes("main.go", nil, Options{NeverTouch: []string{"["}}); err == nil {
+		t.Error("CheckBytes() should reject invalid patterns")
+	}
+}
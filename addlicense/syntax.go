@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// A preambleParser returns the length of the leading portion of b that must
+// remain above a header, using knowledge of the syntax of a language. If the
+// parser can't make sense of b, it returns false and the line-based rules of
+// hashBang are used instead. Parsers are hand-written scanners rather than
+// tree-sitter grammars, as those need cgo, which release builds disable.
+type preambleParser func(b []byte) (int, bool)
+
+// preambleParsers are the syntax-aware parsers used when
+// Options.SyntaxAware is set, by file extension. Languages without one always
+// use the line-based rules.
+var preambleParsers = map[string]preambleParser{
+	".php": phpPreamble,
+	".js":  jsPreamble,
+	".mjs": jsPreamble,
+	".cjs": jsPreamble,
+	".jsx": jsPreamble,
+	".ts":  jsPreamble,
+	".tsx": jsPreamble,
+	".vue": vuePreamble,
+}
+
+// requiredPreambleParsers are used regardless of Options.SyntaxAware, for
+// languages in which a file would be invalid, or misread by tools, with a
+// header above its preamble
+var requiredPreambleParsers = map[string]preambleParser{
+	".astro":     astroPreamble,
+	".proto":     protoPreamble,
+	".textproto": textprotoPreamble,
+	".txtpb":     textprotoPreamble,
+	".sql":       sqlPreamble,
+	".tex":       texPreamble,
+	".sty":       texPreamble,
+	".org":       orgPreamble,
+	".adoc":      asciidocPreamble,
+	".asciidoc":  asciidocPreamble,
+}
+
+// preamble returns the portion of b, the contents of the file at path, that
+// must remain above a header. If a parser is available for the file's language
+// (see Options.SyntaxAware and Options.YAMLHeaderAtTop), it decides;
+// otherwise, the line-based rules of hashBang apply.
+func preamble(path string, b []byte, opts Options) []byte {
+	ext := fileExtension(path)
+	parse, ok := requiredPreambleParsers[ext]
+	if !ok && opts.SyntaxAware {
+		parse, ok = preambleParsers[ext]
+	}
+	if !ok && !opts.YAMLHeaderAtTop && (ext == ".yaml" || ext == ".yml") && !isHelmTemplate(path) {
+		parse, ok = yamlPreamble, true
+	}
+	if ok {
+		if n, ok := parse(b); ok {
+			// Capped so that appending to it never overwrites the rest of b
+			return b[:n:n]
+		}
+	}
+	return hashBang(b)
+}
+
+// lineEnd returns the index just past the end of the line containing b[i],
+// including its newline
+func lineEnd(b []byte, i int) int {
+	if j := bytes.IndexByte(b[i:], '\n'); j >= 0 {
+		return i + j + 1
+	}
+	return len(b)
+}
+
+// phpOpenTag matches the tag that starts a block of PHP code
+var phpOpenTag = regexp.MustCompile(`(?i)<\?php`)
+
+// phpPreamble places headers within the first block of PHP code, so that they
+// are never output as part of any HTML that precedes it. The opening tag must
+// be on a line of its own, as the header is a series of line comments.
+func phpPreamble(b []byte) (int, bool) {
+	loc := phpOpenTag.FindIndex(b)
+	if loc == nil {
+		return 0, false
+	}
+	end := lineEnd(b, loc[1])
+	if len(bytes.TrimSpace(b[loc[1]:end])) != 0 {
+		return 0, false
+	}
+	return end, true
+}
+
+// jsPragma matches the pragmas that tools such as Babel, Flow, TypeScript,
+// and Jest read from the leading comments of JavaScript and TypeScript files
+var jsPragma = regexp.MustCompile(`@(?:jsx|jsxFrag|jsxImportSource|jsxRuntime|flow|noflow|ts-check|ts-nocheck|jest-environment|vitest-environment)\b|\beslint-env\b`)
+
+// jsPreamble keeps comments containing pragmas above headers, including
+// multi-line block comments that the line-based rules would split
+func jsPreamble(b []byte) (int, bool) {
+	n := len(hashBang(b))
+	for {
+		i := n + len(b[n:]) - len(bytes.TrimLeft(b[n:], " \t\r\n"))
+
+		var end int
+		switch {
+		case bytes.HasPrefix(b[i:], []byte("//")):
+			end = lineEnd(b, i)
+		case bytes.HasPrefix(b[i:], []byte("/*")):
+			close := bytes.Index(b[i+2:], []byte("*/"))
+			if close == -1 {
+				return 0, false
+			}
+			close += i + 4
+			end = lineEnd(b, close)
+			// Code following a comment on the same line can't be separated from it
+			if len(bytes.TrimSpace(b[close:end])) != 0 {
+				return 0, false
+			}
+		default:
+			return n, true
+		}
+
+		if !jsPragma.Match(b[i:end]) {
+			return n, true
+		}
+		n = end
+	}
+}
+
+// vuePragma matches the pragmas that Prettier reads from the first comment of
+// a Vue single-file component, e.g. "<!-- @format -->"
+var vuePragma = regexp.MustCompile(`@(?:format|prettier)\b`)
+
+// vuePreamble keeps a leading comment containing a pragma above headers, as it
+// is only read from the very top of a single-file component. Headers otherwise
+// go above every block, where comments are allowed.
+func vuePreamble(b []byte) (int, bool) {
+	i := len(b) - len(bytes.TrimLeft(b, " \t\r\n"))
+	if !bytes.HasPrefix(b[i:], []byte("<!--")) {
+		return 0, false
+	}
+	close := bytes.Index(b[i+4:], []byte("-->"))
+	if close == -1 {
+		return 0, false
+	}
+	close += i + 7
+	end := lineEnd(b, close)
+	if len(bytes.TrimSpace(b[close:end])) != 0 || !vuePragma.Match(b[i:close]) {
+		return 0, false
+	}
+	return end, true
+}
+
+// astroPreamble keeps the component script of an Astro component, fenced by
+// "---" lines, above headers, as it must come first in the file
+func astroPreamble(b []byte) (int, bool) {
+	end := lineEnd(b, 0)
+	if string(bytes.TrimSpace(b[:end])) != "---" {
+		return 0, false
+	}
+	for i := end; i < len(b); i = end {
+		end = lineEnd(b, i)
+		if string(bytes.TrimSpace(b[i:end])) == "---" {
+			return end, true
+		}
+	}
+	return 0, false
+}
+
+// commentLinesPreamble keeps the leading lines of b for which keep returns
+// true above headers, following any preamble found by hashBang
+func commentLinesPreamble(b []byte, keep func(line []byte) bool) (int, bool) {
+	n := len(hashBang(b))
+	for n < len(b) {
+		end := lineEnd(b, n)
+		if !keep(bytes.TrimSpace(b[n:end])) {
+			break
+		}
+		n = end
+	}
+	return n, true
+}
+
+// protoPreamble keeps "// @generated" markers, which tools expect to find at
+// the very top of a file, above headers. Headers always precede the syntax
+// statement and any options that follow it.
+func protoPreamble(b []byte) (int, bool) {
+	return commentLinesPreamble(b, func(line []byte) bool {
+		return bytes.HasPrefix(line, []byte("//")) && bytes.Contains(line, []byte("@generated"))
+	})
+}
+
+// textprotoPreamble keeps the "# proto-file:" and "# proto-message:"
+// directives that identify the schema of a text format file above headers
+func textprotoPreamble(b []byte) (int, bool) {
+	return commentLinesPreamble(b, func(line []byte) bool {
+		directive := bytes.TrimSpace(bytes.TrimPrefix(line, []byte("#")))
+		return bytes.HasPrefix(line, []byte("#")) &&
+			(bytes.HasPrefix(directive, []byte("proto-file:")) ||
+				bytes.HasPrefix(directive, []byte("proto-message:")) ||
+				bytes.HasPrefix(directive, []byte("proto-import:")))
+	})
+}
+
+// yamlPreamble keeps any directives (e.g., "%YAML 1.2") and the marker that
+// starts the first document ("---") above headers, as some strict parsers
+// reject comments before them
+func yamlPreamble(b []byte) (int, bool) {
+	n := len(hashBang(b))
+	for n < len(b) {
+		end := lineEnd(b, n)
+		if !bytes.HasPrefix(b[n:end], []byte("%")) {
+			break
+		}
+		n = end
+	}
+
+	end := lineEnd(b, n)
+	line := bytes.TrimRight(b[n:end], " \t\r\n")
+	if bytes.Equal(line, []byte("---")) || bytes.HasPrefix(line, []byte("--- ")) {
+		n = end
+	}
+	return n, true
+}
+
+// sqlMigrationMarker matches the first line of migrations written for tools
+// such as goose, sql-migrate, dbmate, and Liquibase, which must stay at the
+// very top of the file
+var sqlMigrationMarker = regexp.MustCompile(`(?i)^--\s*(?:\+goose\b|\+migrate\b|migrate:|liquibase formatted sql)`)
+
+// sqlPreamble keeps a migration tool's marker above headers. Only the first
+// line is kept, as later markers (e.g., "-- +goose StatementBegin") delimit the
+// statements that follow it.
+func sqlPreamble(b []byte) (int, bool) {
+	n := len(hashBang(b))
+	end := lineEnd(b, n)
+	if sqlMigrationMarker.Match(b[n:end]) {
+		n = end
+	}
+	return n, true
+}
+
+// texMagicComment matches the "% !TEX" magic comments that editors read from
+// the top of LaTeX files, e.g. "% !TEX program = xelatex"
+var texMagicComment = regexp.MustCompile(`(?i)^%\s*!tex\s`)
+
+// texPreamble keeps magic comments above headers. Headers always precede
+// \documentclass and the rest of the LaTeX preamble.
+func texPreamble(b []byte) (int, bool) {
+	return commentLinesPreamble(b, texMagicComment.Match)
+}
+
+// orgPreamble keeps an Emacs file variables line (e.g., "# -*- mode: org -*-"),
+// which must be the first line of a file, above headers
+func orgPreamble(b []byte) (int, bool) {
+	n := len(hashBang(b))
+	end := lineEnd(b, n)
+	if bytes.Contains(b[n:end], []byte("-*-")) {
+		n = end
+	}
+	return n, true
+}
+
+// asciidocPreamble keeps the header of an AsciiDoc document, i.e. its title
+// along with the author, revision, and attribute entry lines that follow it,
+// or attribute entries at the top of a document without a title, above
+// headers. The document header ends at the first blank line.
+func asciidocPreamble(b []byte) (int, bool) {
+	n := len(hashBang(b))
+	first := b[n:lineEnd(b, n)]
+	if !bytes.HasPrefix(first, []byte("= ")) && !bytes.HasPrefix(first, []byte(":")) {
+		return n, true
+	}
+
+	for n < len(b) {
+		end := lineEnd(b, n)
+		if len(bytes.TrimSpace(b[n:end])) == 0 {
+			break
+		}
+		n = end
+	}
+	return n, true
+}
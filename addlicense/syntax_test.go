@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import "testing"
+
+// Test that syntax-aware placement puts headers where the language requires,
+// and falls back to the line-based rules where it can't.
+func TestSyntaxAwarePlacement(t *testing.T) {
+	lic := []byte("// HYS\n\n")
+
+	tests := []struct {
+		path         string
+		contents     string
+		wantContents string
+	}{
+		// PHP
+		{"f.php", "<?php\necho 1;\n", "<?php\n// HYS\n\necho 1;\n"},
+		{"f.php", "<html>\n<?php\necho 1;\n", "<html>\n<?php\n// HYS\n\necho 1;\n"},
+		{"f.php", "<html>\n<?php echo 1; ?>\n", "// HYS\n\n<html>\n<?php echo 1; ?>\n"},
+		{"f.php", "<html></html>\n", "// HYS\n\n<html></html>\n"},
+
+		// JavaScript and TypeScript
+		{"f.jsx", "/** @jsx h */\nimport h from 'h';\n", "/** @jsx h */\n// HYS\n\nimport h from 'h';\n"},
+		{"f.js", "/**\n * @flow\n */\nconst a = 1;\n", "/**\n * @flow\n */\n// HYS\n\nconst a = 1;\n"},
+		{"f.ts", "#!/usr/bin/env node\n// @ts-check\n\n/* @jsxImportSource preact */\nx();\n", "#!/usr/bin/env node\n// @ts-check\n\n/* @jsxImportSource preact */\n// HYS\n\nx();\n"},
+		{"f.tsx", "/* A regular comment */\nx();\n", "// HYS\n\n/* A regular comment */\nx();\n"},
+		{"f.js", "/* @flow */ x();\n", "// HYS\n\n/* @flow */ x();\n"},
+		{"f.js", "/* @flow\n", "// HYS\n\n/* @flow\n"},
+
+		// Vue single-file components
+		{"f.vue", "<!-- @format -->\n<template></template>\n", "<!-- @format -->\n// HYS\n\n<template></template>\n"},
+		{"f.vue", "<!--\n  @prettier\n-->\n<template></template>\n", "<!--\n  @prettier\n-->\n// HYS\n\n<template></template>\n"},
+		{"f.vue", "<!-- A regular comment -->\n<template></template>\n", "// HYS\n\n<!-- A regular comment -->\n<template></template>\n"},
+		{"f.vue", "<!-- @format --> <template></template>\n", "// HYS\n\n<!-- @format --> <template></template>\n"},
+		{"f.vue", "<script>\n/** @jsx h */\n</script>\n", "// HYS\n\n<script>\n/** @jsx h */\n</script>\n"},
+
+		// languages without a parser
+		{"f.go", "/** @jsx h */\npackage a\n", "// HYS\n\n/** @jsx h */\npackage a\n"},
+	}
+
+	for _, tt := range tests {
+		got, updated := prependLicense(tt.path, []byte(tt.contents), lic, Options{SyntaxAware: true})
+		if !updated {
+			t.Errorf("prependLicense with contents %q returned updated: false, want true", tt.contents)
+		}
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
+		}
+	}
+
+	// without the option, only the line-based rules apply
+	got, _ := prependLicense("f.php", []byte("<html>\n<?php\necho 1;\n"), lic, Options{})
+	if want := "// HYS\n\n<html>\n<?php\necho 1;\n"; string(got) != want {
+		t.Errorf("prependLicense without SyntaxAware returned contents: %q, want %q", got, want)
+	}
+
+	// headers within a component's script block are found
+	vue := "<template></template>\n<script>\n// Copyright (c) HashiCorp, Inc.\n</script>\n"
+	if _, updated := prependLicense("f.vue", []byte(vue), lic, Options{SyntaxAware: true}); updated {
+		t.Errorf("prependLicense with contents %q returned updated: true, want false", vue)
+	}
+}
+
+// Test that the component script of an Astro component stays above headers,
+// whether or not syntax-aware placement is enabled.
+func TestAstroPlacement(t *testing.T) {
+	lic := []byte("<!--\n HYS\n-->\n\n")
+
+	tests := []struct {
+		contents     string
+		wantContents string
+	}{
+		{"---\nconst a = 1;\n---\n<p>{a}</p>\n", "---\nconst a = 1;\n---\n<!--\n HYS\n-->\n\n<p>{a}</p>\n"},
+		{"<p>hi</p>\n", "<!--\n HYS\n-->\n\n<p>hi</p>\n"},
+		{"---\nconst a = 1;\n", "<!--\n HYS\n-->\n\n---\nconst a = 1;\n"},
+	}
+
+	for _, tt := range tests {
+		got, _ := prependLicense("f.astro", []byte(tt.contents), lic, Options{})
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
+		}
+	}
+}
+
+// Test that markers and directives at the top of protobuf files stay above
+// headers.
+func TestProtoPlacement(t *testing.T) {
+	tests := []struct {
+		path         string
+		lic          string
+		contents     string
+		wantContents string
+	}{
+		{"f.proto", "// HYS\n\n", "syntax = \"proto3\";\n", "// HYS\n\nsyntax = \"proto3\";\n"},
+		{"f.proto", "// HYS\n\n", "// @generated by buf\nsyntax = \"proto3\";\noption go_package = \"a\";\n", "// @generated by buf\n// HYS\n\nsyntax = \"proto3\";\noption go_package = \"a\";\n"},
+		{"f.proto", "// HYS\n\n", "// Comment\n// @generated\nsyntax = \"proto3\";\n", "// HYS\n\n// Comment\n// @generated\nsyntax = \"proto3\";\n"},
+		{"f.textproto", "# HYS\n\n", "# proto-file: a.proto\n# proto-message: A\nname: \"a\"\n", "# proto-file: a.proto\n# proto-message: A\n# HYS\n\nname: \"a\"\n"},
+		{"f.txtpb", "# HYS\n\n", "name: \"a\"\n", "# HYS\n\nname: \"a\"\n"},
+	}
+
+	for _, tt := range tests {
+		got, _ := prependLicense(tt.path, []byte(tt.contents), []byte(tt.lic), Options{})
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense(%q) with contents %q returned contents: %q, want %q", tt.path, tt.contents, got, tt.wantContents)
+		}
+	}
+}
+
+// Test that headers in YAML files are placed below any directives and the
+// start of the first document, unless configured to go at the top.
+func TestYAMLPlacement(t *testing.T) {
+	lic := []byte("# HYS\n\n")
+
+	tests := []struct {
+		contents     string
+		atTop        bool
+		wantContents string
+	}{
+		{"a: 1\n", false, "# HYS\n\na: 1\n"},
+		{"---\na: 1\n---\nb: 2\n", false, "---\n# HYS\n\na: 1\n---\nb: 2\n"},
+		{"%YAML 1.2\n%TAG ! tag:example.com,2000:\n---\na: 1\n", false, "%YAML 1.2\n%TAG ! tag:example.com,2000:\n---\n# HYS\n\na: 1\n"},
+		{"--- !!map\na: 1\n", false, "--- !!map\n# HYS\n\na: 1\n"},
+		{"---a: 1\n", false, "# HYS\n\n---a: 1\n"},
+		{"---\na: 1\n---\nb: 2\n", true, "# HYS\n\n---\na: 1\n---\nb: 2\n"},
+	}
+
+	for _, tt := range tests {
+		got, _ := prependLicense("f.yaml", []byte(tt.contents), lic, Options{YAMLHeaderAtTop: tt.atTop})
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
+		}
+	}
+}
+
+// Test that the markers at the top of SQL migrations stay above headers.
+func TestSQLMigrationPlacement(t *testing.T) {
+	lic := []byte("-- HYS\n\n")
+
+	tests := []struct {
+		contents     string
+		wantContents string
+	}{
+		{"CREATE TABLE a (id int);\n", "-- HYS\n\nCREATE TABLE a (id int);\n"},
+		{"-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n", "-- +goose Up\n-- HYS\n\n-- +goose StatementBegin\nSELECT 1;\n"},
+		{"--liquibase formatted sql\n--changeset jane:1\nSELECT 1;\n", "--liquibase formatted sql\n-- HYS\n\n--changeset jane:1\nSELECT 1;\n"},
+		{"-- +migrate Up\nSELECT 1;\n", "-- +migrate Up\n-- HYS\n\nSELECT 1;\n"},
+		{"-- migrate:up\nSELECT 1;\n", "-- migrate:up\n-- HYS\n\nSELECT 1;\n"},
+		{"-- A regular comment\nSELECT 1;\n", "-- HYS\n\n-- A regular comment\nSELECT 1;\n"},
+	}
+
+	for _, tt := range tests {
+		got, _ := prependLicense("f.sql", []byte(tt.contents), lic, Options{})
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense with contents %q returned contents: %q, want %q", tt.contents, got, tt.wantContents)
+		}
+	}
+}
+
+// Test that the preambles of documents stay above headers.
+func TestDocumentPlacement(t *testing.T) {
+	tests := []struct {
+		path         string
+		lic          string
+		contents     string
+		wantContents string
+	}{
+		{"f.tex", "% HYS\n\n", "\\documentclass{article}\n", "% HYS\n\n\\documentclass{article}\n"},
+		{"f.tex", "% HYS\n\n", "% !TEX program = xelatex\n%!TEX root = main.tex\n\\documentclass{article}\n", "% !TEX program = xelatex\n%!TEX root = main.tex\n% HYS\n\n\\documentclass{article}\n"},
+		{"f.org", "# HYS\n\n", "# -*- mode: org -*-\n#+TITLE: Notes\n", "# -*- mode: org -*-\n# HYS\n\n#+TITLE: Notes\n"},
+		{"f.org", "# HYS\n\n", "#+TITLE: Notes\n", "# HYS\n\n#+TITLE: Notes\n"},
+		{"f.adoc", "// HYS\n\n", "= Title\nJane Doe\n:toc:\n\nBody\n", "= Title\nJane Doe\n:toc:\n// HYS\n\n\nBody\n"},
+		{"f.adoc", "// HYS\n\n", ":page-layout: docs\n\nBody\n", ":page-layout: docs\n// HYS\n\n\nBody\n"},
+		{"f.adoc", "// HYS\n\n", "Body\n", "// HYS\n\nBody\n"},
+	}
+
+	for _, tt := range tests {
+		got, _ := prependLicense(tt.path, []byte(tt.contents), []byte(tt.lic), Options{})
+		if string(got) != tt.wantContents {
+			t.Errorf("prependLicense(%q) with contents %q returned contents: %q, want %q", tt.path, tt.contents, got, tt.wantContents)
+		}
+	}
+}
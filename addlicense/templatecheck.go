@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// commentTerminators are the sequences that end each block comment style,
+// keyed by the line that opens it, which must not appear within a header.
+// XML forbids "--" anywhere within a comment, not just "-->".
+var commentTerminators = map[string][]string{
+	"/*":   {"*/"},
+	"/**":  {"*/"},
+	"{{/*": {"*/"},
+	"<%/*": {"*/"},
+	"{{!":  {"}}"},
+	"<!--": {"--"},
+	"(**":  {"*)"},
+}
+
+// terminators returns the sequences that end comments in style, which are
+// none for line comment styles
+func (s CommentStyle) terminators() []string {
+	if s.Top == "" {
+		return nil
+	}
+	if t, ok := commentTerminators[s.Top]; ok {
+		return t
+	}
+	return []string{strings.TrimSpace(s.Bottom)}
+}
+
+// ValidateHeaderTemplate returns an error if the header template t, rendered
+// with data for each type of file that headers are added to (see FileTypes,
+// which ignore and include are passed to), would fail to render, would end
+// the file's block comment early (e.g., with "*/" in a C file), or wouldn't be
+// recognized as a header, and so would be added again on every run. Run calls
+// it before touching any files when a custom template is used.
+func ValidateHeaderTemplate(t *template.Template, data LicenseData, ignore []string, opts Options) error {
+	checked := map[string]bool{}
+	for _, ft := range FileTypes(ignore, opts.Include) {
+		if !ft.Enabled || checked[ft.Style.String()] {
+			continue
+		}
+		checked[ft.Style.String()] = true
+
+		// Render the header for a file of the type, at a path with the
+		// same shape as a real one, so that the file variables are realistic
+		name := strings.ReplaceAll(ft.Name, "*", "file")
+		if strings.HasPrefix(name, ".") {
+			name = "file" + name
+		}
+		d := data
+		d.Filename = path.Base(name)
+		d.RelPath = path.Join("dir", name)
+
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, d); err != nil {
+			return fmt.Errorf("invalid header template: %w", err)
+		}
+		s := bufio.NewScanner(&buf)
+		for line := 1; s.Scan(); line++ {
+			for _, term := range ft.Style.terminators() {
+				if strings.Contains(s.Text(), term) {
+					return fmt.Errorf("invalid header template: line %d (%q) contains %q, which would break the comments around headers in %s files (%s)", line, s.Text(), term, ft.Name, ft.Style)
+				}
+			}
+		}
+
+		header, err := executeFramedTemplate(t, d, ft.Style, opts.HeaderStyle, opts.HeaderBorder)
+		if err != nil {
+			return fmt.Errorf("invalid header template: %w", err)
+		}
+		if !hasLicense(header, opts.CopyrightKeywords) {
+			return fmt.Errorf("invalid header template: headers in %s files wouldn't be recognized, and so would be added again on every run; include a copyright statement or SPDX identifier", ft.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestValidateHeaderTemplate(t *testing.T) {
+	tests := []struct {
+		description string
+		tmpl        string
+		ignore      []string
+		wantErr     string
+	}{
+		{"valid", "Copyright {{.Holder}}\nFile: {{.RelPath}}", nil, ""},
+		{"license text", tmplMPL, nil, ""},
+		{"ends a C comment", "Copyright {{.Holder}}\nSee */ below", nil, `contains "*/"`},
+		{"ends an XML comment", "Copyright {{.Holder}} -- all rights reserved", nil, `contains "--"`},
+		{"ends an OCaml comment", "Copyright {{.Holder}} (*)", nil, `contains "*)"`},
+		{"ignored file types", "Copyright {{.Holder}} -- all rights reserved", []string{"**/*.html", "**/*.htm", "**/*.xml", "**/*.vue", "**/*.svelte", "**/*.astro", "**/*.wxi", "**/*.wxl", "**/*.wxs"}, ""},
+		{"not a header", "Owned by {{.Holder}}", nil, "wouldn't be recognized"},
+		{"render error", `Copyright {{ env "HOME" }}`, nil, "isn't allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			tmpl := template.Must(template.New("").Funcs(TemplateFuncs(nil)).Parse(tt.tmpl))
+			err := ValidateHeaderTemplate(tmpl, LicenseData{Holder: "Acme Inc."}, tt.ignore, Options{})
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("ValidateHeaderTemplate returned error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("ValidateHeaderTemplate returned error: %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Test that every block comment style has known terminators, so that templates
+// can't break any of them
+func TestCommentTerminators(t *testing.T) {
+	for _, ft := range FileTypes(nil, nil) {
+		if _, ok := commentTerminators[ft.Style.Top]; ft.Style.Top != "" && !ok {
+			t.Errorf("%s files (%s) have no entry in commentTerminators", ft.Name, ft.Style)
+		}
+	}
+}
@@ -17,19 +17,53 @@ package addlicense
 import (
 	"bufio"
 	"bytes"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"text/template"
+	"time"
 	"unicode"
 )
 
-var licenseTemplate = map[string]string{
-	"Apache-2.0": tmplApache,
-	"MIT":        tmplMIT,
-	"bsd":        tmplBSD,
-	"MPL-2.0":    tmplMPL,
-}
+// templates holds the built-in license header templates, each in a file named
+// after the license it's for with a .tmpl extension
+//
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// licenseTemplate maps the name of each license in templates to its template
+var licenseTemplate = func() map[string]string {
+	m := map[string]string{}
+	entries, err := templates.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		b, err := templates.ReadFile(path.Join("templates", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		m[strings.TrimSuffix(e.Name(), templateExt)] = strings.TrimSuffix(string(b), "\n")
+	}
+	return m
+}()
+
+// templateExt is the extension of license header template files
+const templateExt = ".tmpl"
+
+var (
+	tmplApache = licenseTemplate["Apache-2.0"]
+	tmplBSD    = licenseTemplate["bsd"]
+	tmplMIT    = licenseTemplate["MIT"]
+	tmplMPL    = licenseTemplate["MPL-2.0"]
+)
 
 // maintain backwards compatibility by mapping legacy license types to their
 // SPDX equivalents.
@@ -44,6 +78,68 @@ type LicenseData struct {
 	Year   string // Copyright year(s).
 	Holder string // Name of the copyright holder.
 	SPDXID string // SPDX Identifier
+
+	// Filename and RelPath are the base name of the file a header is for, and
+	// its path relative to the working directory (with forward slashes). They
+	// are set when headers are rendered.
+	Filename string
+	RelPath  string
+}
+
+// Years returns the copyright year(s), so that copyright formats (see
+// FormatCopyright) can refer to them as {{.Years}}.
+func (d LicenseData) Years() string {
+	return d.Year
+}
+
+// DefaultCopyrightFormat is the template for copyright statements used when
+// no other format is configured.
+const DefaultCopyrightFormat = `Copyright (c){{ if .Year }} {{.Year}}{{ end }}{{ if .Holder }} {{.Holder}}{{ end }}`
+
+// FormatCopyright renders the copyright statement template format, e.g.
+// "Copyright {{.Years}} {{.Holder}}. All rights reserved.", with data d. An
+// empty format results in DefaultCopyrightFormat.
+func FormatCopyright(format string, d LicenseData) (string, error) {
+	if format == "" {
+		format = DefaultCopyrightFormat
+	}
+	t, err := template.New("").Parse(format)
+	if err != nil {
+		return "", fmt.Errorf("invalid copyright format: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("invalid copyright format: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// ValidateCopyrightFormat returns an error if format can't be rendered, or if
+// the statements it renders would span multiple lines or not be recognized as
+// copyright statements (see Options.CopyrightKeywords), which would cause
+// headers to be added to files again and again.
+func ValidateCopyrightFormat(format string, keywords []string) error {
+	s, err := FormatCopyright(format, LicenseData{Year: "2006", Holder: "Example, Inc."})
+	if err != nil {
+		return err
+	}
+	if strings.ContainsAny(s, "\r\n") {
+		return fmt.Errorf("invalid copyright format %q: must render a single line", format)
+	}
+	if !hasLicense([]byte(s), keywords) {
+		return fmt.Errorf("invalid copyright format %q: must contain the word \"copyright\" or one of the copyright keywords", format)
+	}
+	return nil
+}
+
+// withCopyrightFormat replaces the default copyright statement at the start
+// of the built-in template t, if any, with format. Other templates, including
+// custom template files, are returned unchanged.
+func withCopyrightFormat(t string, format string) string {
+	if format == "" || !strings.HasPrefix(t, DefaultCopyrightFormat) {
+		return t
+	}
+	return format + strings.TrimPrefix(t, DefaultCopyrightFormat)
 }
 
 // fetchTemplate returns the license template for the specified license and
@@ -54,6 +150,8 @@ func fetchTemplate(license string, templateFile string, spdx spdxFlag) (string,
 	var t string
 	if spdx == spdxOnly {
 		t = tmplSPDX
+	} else if spdx == spdxIdentifierOnly {
+		t = tmplSPDXIdentifier
 	} else if templateFile != "" {
 		d, err := os.ReadFile(templateFile)
 		if err != nil {
@@ -81,6 +179,84 @@ func fetchTemplate(license string, templateFile string, spdx spdxFlag) (string,
 	return t, nil
 }
 
+// LicenseTemplate returns the header template for license, such as the
+// standard notice of the Apache License or the full text of the MIT License.
+// If dir is set and contains a file named after the license with a .tmpl
+// extension (e.g., "LicenseRef-Acme.tmpl"), it is used in place of any
+// built-in template, so that custom licenses work and built-in templates can
+// be overridden without a network connection. Templates are Go templates that
+// may refer to the fields of LicenseData and call TemplateFuncs, and should
+// end without a newline.
+func LicenseTemplate(license string, dir string) (string, error) {
+	if license == "" || strings.ContainsAny(license, `/\`) || license == "." || license == ".." {
+		return "", fmt.Errorf("invalid license %q for a header template", license)
+	}
+	if dir != "" {
+		b, err := os.ReadFile(filepath.Join(dir, license+templateExt))
+		if err == nil {
+			return strings.TrimSuffix(string(b), "\n"), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("license template: %w", err)
+		}
+	}
+	if t, ok := licenseTemplate[license]; ok {
+		return t, nil
+	}
+	if t, ok := licenseTemplate[legacyLicenseTypes[license]]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("no header template for license %q: add a %s%s file to the license templates directory", license, license, templateExt)
+}
+
+// LicenseTemplateNames returns the name of every license that LicenseTemplate
+// has a template for, both built in and in dir, if set, sorted
+func LicenseTemplateNames(dir string) ([]string, error) {
+	names := map[string]bool{}
+	for name := range licenseTemplate {
+		names[name] = true
+	}
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("license templates: %w", err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), templateExt) {
+				names[strings.TrimSuffix(e.Name(), templateExt)] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// TemplateFuncs returns the functions available to header templates:
+//
+//   - upper returns a string in upper case, e.g. {{ upper .Holder }}
+//   - trim removes leading and trailing white space from a string
+//   - now returns the current time, e.g. {{ now.Year }}
+//   - env returns the value of an environment variable, which must be one of
+//     allowedEnv so that templates can't leak secrets into headers
+func TemplateFuncs(allowedEnv []string) template.FuncMap {
+	return template.FuncMap{
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+		"now":   time.Now,
+		"env": func(name string) (string, error) {
+			if !slices.Contains(allowedEnv, name) {
+				return "", fmt.Errorf("environment variable %s isn't allowed in header templates", name)
+			}
+			return os.Getenv(name), nil
+		},
+	}
+}
+
 // executeTemplate will execute a license template t with data d
 // and prefix the result with top, middle and bottom.
 func executeTemplate(t *template.Template, d LicenseData, top, mid, bot string) ([]byte, error) {
@@ -103,50 +279,11 @@ func executeTemplate(t *template.Template, d LicenseData, top, mid, bot string)
 	return out.Bytes(), nil
 }
 
-const tmplApache = `Copyright {{.Year}} {{.Holder}}
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-     http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.`
-
-const tmplBSD = `Copyright (c) {{.Year}} {{.Holder}} All rights reserved.
-Use of this source code is governed by a BSD-style
-license that can be found in the LICENSE file.`
-
-const tmplMIT = `Copyright (c) {{.Year}} {{.Holder}}
-
-Permission is hereby granted, free of charge, to any person obtaining a copy of
-this software and associated documentation files (the "Software"), to deal in
-the Software without restriction, including without limitation the rights to
-use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
-the Software, and to permit persons to whom the Software is furnished to do so,
-subject to the following conditions:
-
-The above copyright notice and this permission notice shall be included in all
-copies or substantial portions of the Software.
-
-THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
-IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
-FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
-COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
-IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
-CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.`
-
-const tmplMPL = `This Source Code Form is subject to the terms of the Mozilla Public
-License, v. 2.0. If a copy of the MPL was not distributed with this
-file, You can obtain one at https://mozilla.org/MPL/2.0/.`
-
-const tmplSPDX = `Copyright (c){{ if .Year }} {{.Year}}{{ end }}{{ if .Holder }} {{.Holder}}{{ end }}
+const tmplSPDX = DefaultCopyrightFormat + `
 {{ if .SPDXID }}SPDX-License-Identifier: {{.SPDXID}}{{ end }}`
 
-const tmplCopyrightOnly = `Copyright (c){{ if .Year }} {{.Year}}{{ end }}{{ if .Holder }} {{.Holder}}{{ end }}`
+const tmplSPDXIdentifier = `{{ if .SPDXID }}SPDX-License-Identifier: {{.SPDXID}}{{ end }}`
+
+const tmplCopyrightOnly = DefaultCopyrightFormat
 
 const spdxSuffix = "\n\nSPDX-License-Identifier: {{.SPDXID}}"
@@ -17,6 +17,9 @@ package addlicense
 import (
 	"errors"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"text/template"
 )
@@ -124,6 +127,14 @@ func TestFetchTemplate(t *testing.T) {
 			tmplSPDX,
 			nil,
 		},
+		{
+			"apache license template with only the SPDX identifier",
+			"Apache-2.0",
+			"",
+			spdxIdentifierOnly,
+			tmplSPDXIdentifier,
+			nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -194,3 +205,175 @@ func TestExecuteTemplate(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatCopyright(t *testing.T) {
+	tests := []struct {
+		format string
+		data   LicenseData
+		want   string
+	}{
+		{"", LicenseData{Holder: "H", Year: "Y"}, "Copyright (c) Y H"},
+		{"", LicenseData{Holder: "H"}, "Copyright (c) H"},
+		{"Copyright {{.Years}} {{.Holder}}. All rights reserved.", LicenseData{Holder: "H", Year: "Y"}, "Copyright Y H. All rights reserved."},
+	}
+
+	for _, tt := range tests {
+		got, err := FormatCopyright(tt.format, tt.data)
+		if err != nil {
+			t.Errorf("FormatCopyright(%q, %v) returned error: %v", tt.format, tt.data, err)
+		}
+		if got != tt.want {
+			t.Errorf("FormatCopyright(%q, %v) returned %q, want: %q", tt.format, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCopyrightFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		keywords []string
+		wantErr  bool
+	}{
+		{"", nil, false},
+		{"Copyright {{.Years}} {{.Holder}}. All rights reserved.", nil, false},
+		{"(C) {{.Holder}}", []string{"(C)"}, false},
+		{"(C) {{.Holder}}", nil, true},
+		{"Copyright {{.Holder}}\nAll rights reserved.", nil, true},
+		{"Copyright {{.Holder", nil, true},
+		{"Copyright {{.Owner}}", nil, true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateCopyrightFormat(tt.format, tt.keywords)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateCopyrightFormat(%q, %v) returned error: %v, want error: %v", tt.format, tt.keywords, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWithCopyrightFormat(t *testing.T) {
+	format := "Copyright {{.Years}} {{.Holder}}. All rights reserved."
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{tmplCopyrightOnly, format},
+		{tmplSPDX, format + "\n{{ if .SPDXID }}SPDX-License-Identifier: {{.SPDXID}}{{ end }}"},
+		{tmplMPL, tmplMPL},
+		{tmplApache, tmplApache},
+	}
+
+	for _, tt := range tests {
+		if got := withCopyrightFormat(tt.template, format); got != tt.want {
+			t.Errorf("withCopyrightFormat(%q) returned %q, want: %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestBuiltInLicenseTemplates(t *testing.T) {
+	for name, tpl := range licenseTemplate {
+		tmpl, err := template.New("").Parse(tpl)
+		if err != nil {
+			t.Errorf("template for %s doesn't parse: %v", name, err)
+			continue
+		}
+
+		// Every header must be recognized as one, or it would be added again
+		// on the next run
+		header, err := licenseHeader("f.go", tmpl, LicenseData{Holder: "Acme Inc."}, Options{})
+		if err != nil {
+			t.Errorf("template for %s doesn't render: %v", name, err)
+		}
+		if !hasLicense(header, nil) {
+			t.Errorf("header rendered from the template for %s isn't recognized as a header: %q", name, header)
+		}
+		if err := ValidateHeaderTemplate(tmpl, LicenseData{Holder: "Acme Inc.", SPDXID: name}, nil, Options{}); err != nil {
+			t.Errorf("template for %s is invalid: %v", name, err)
+		}
+		// copywrite leaves the year out of headers by default
+		if strings.Contains(string(header), "  Acme Inc.") {
+			t.Errorf("header rendered from the template for %s without a year has a gap: %q", name, header)
+		}
+	}
+}
+
+func TestLicenseTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "MIT.tmpl"), []byte("Copyright {{.Holder}}\nOverridden\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "LicenseRef-Acme.tmpl"), []byte("Copyright {{.Holder}}\nProprietary\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		license string
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{"MPL-2.0", "", tmplMPL, false},
+		{"mit", "", tmplMIT, false},
+		{"MIT", dir, "Copyright {{.Holder}}\nOverridden", false},
+		{"Apache-2.0", dir, tmplApache, false},
+		{"LicenseRef-Acme", dir, "Copyright {{.Holder}}\nProprietary", false},
+		{"LicenseRef-Acme", "", "", true},
+		{"../MIT", dir, "", true},
+		{"", dir, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := LicenseTemplate(tt.license, tt.dir)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LicenseTemplate(%q, %q) returned error %v, want error: %t", tt.license, tt.dir, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("LicenseTemplate(%q, %q) returned %q, want %q", tt.license, tt.dir, got, tt.want)
+		}
+	}
+
+	names, err := LicenseTemplateNames(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(names, "LicenseRef-Acme") || !slices.Contains(names, "GPL-3.0-or-later") || !slices.IsSorted(names) {
+		t.Errorf("LicenseTemplateNames(%q) returned %v", dir, names)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	t.Setenv("COPYWRITE_TEST_TEAM", " platform ")
+	t.Setenv("COPYWRITE_TEST_SECRET", "hunter2")
+
+	tests := []struct {
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{"File: {{.Filename}}", "// File: main.go\n\n", false},
+		{"File: {{.RelPath}}", "// File: cmd/app/main.go\n\n", false},
+		{"Copyright {{ upper .Holder }}", "// Copyright ACME INC.\n\n", false},
+		{`Team: {{ env "COPYWRITE_TEST_TEAM" | trim | upper }}`, "// Team: PLATFORM\n\n", false},
+		{`{{ if gt now.Year 2000 }}Recent{{ end }}`, "// Recent\n\n", false},
+		{`{{ env "COPYWRITE_TEST_SECRET" }}`, "", true},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := template.New("").Funcs(TemplateFuncs([]string{"COPYWRITE_TEST_TEAM"})).Parse(tt.tmpl)
+		if err != nil {
+			t.Fatalf("template %q doesn't parse: %v", tt.tmpl, err)
+		}
+		got, err := licenseHeader(filepath.Join("cmd", "app", "main.go"), tmpl, LicenseData{Holder: "Acme Inc."}, Options{})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("template %q rendered %q, want error", tt.tmpl, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("template %q returned error: %v", tt.tmpl, err)
+		} else if string(got) != tt.want {
+			t.Errorf("template %q rendered %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
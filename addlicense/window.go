@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+)
+
+// defaultScanBytes is the number of bytes searched for headers when no scan
+// window is configured
+const defaultScanBytes = 1000
+
+// sniffBytes is the number of bytes at the top of a file that are inspected for
+// markers of generated files and build artifacts (see SkipReason)
+const sniffBytes = 64 * 1024
+
+// ScanWindow limits how much of the top of a file is searched for headers. If
+// both limits are set, whichever is reached first applies. The zero value
+// searches the first 1k bytes.
+type ScanWindow struct {
+	// Lines is the maximum number of lines to search, or 0 for no line limit
+	Lines int
+
+	// Bytes is the maximum number of bytes to search, or 0 for no byte limit
+	// (unless Lines is also 0)
+	Bytes int
+}
+
+// HeaderScanWindow is the window used by every header detector in addlicense
+// and licensecheck. It may be changed (e.g., to the values in a project's
+// config) before any files are processed, such as when long ASCII-art banners
+// push headers further down than usual.
+var HeaderScanWindow ScanWindow
+
+// Head returns the portion of b that falls within the window
+func (w ScanWindow) Head(b []byte) []byte {
+	if w.Lines <= 0 && w.Bytes <= 0 {
+		w.Bytes = defaultScanBytes
+	}
+
+	n := len(b)
+	if w.Bytes > 0 {
+		n = min(n, w.Bytes)
+	}
+	if w.Lines > 0 {
+		end := 0
+		for i := 0; i < w.Lines && end < n; i++ {
+			j := bytes.IndexByte(b[end:n], '\n')
+			if j == -1 {
+				end = n
+				break
+			}
+			end += j + 1
+		}
+		n = min(n, end)
+	}
+	return b[:n]
+}
+
+// HeaderRegion returns the portion of b searched for headers, according to
+// HeaderScanWindow
+func HeaderRegion(b []byte) []byte {
+	return HeaderScanWindow.Head(b)
+}
+
+// ReadHead reads only as much of the top of the file at path as is needed to
+// decide whether it needs a header: the header region (see HeaderScanWindow)
+// and the portion inspected by SkipReason. Detectors should use it in place of
+// os.ReadFile, so that very large files aren't loaded fully into memory unless
+// they actually need to be modified.
+func ReadHead(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	w := HeaderScanWindow
+	r := bufio.NewReader(f)
+	b, err := io.ReadAll(io.LimitReader(r, int64(max(sniffBytes, w.Bytes))))
+	if err != nil {
+		return nil, err
+	}
+
+	// A window limited only by lines may extend past the bytes read so far
+	if w.Bytes <= 0 && w.Lines > 0 {
+		for n := bytes.Count(b, []byte("\n")); n < w.Lines; n++ {
+			line, err := r.ReadBytes('\n')
+			b = append(b, line...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
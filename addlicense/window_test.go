@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+
+package addlicense
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanWindowHead(t *testing.T) {
+	long := strings.Repeat("x", 1500)
+	lines := "one\ntwo\nthree\nfour\n"
+
+	tests := []struct {
+		window  ScanWindow
+		content string
+		want    string
+	}{
+		{ScanWindow{}, "", ""},
+		{ScanWindow{}, lines, lines},
+		{ScanWindow{}, long, long[:1000]},
+		{ScanWindow{Bytes: 5}, lines, "one\nt"},
+		{ScanWindow{Lines: 2}, lines, "one\ntwo\n"},
+		{ScanWindow{Lines: 10}, lines, lines},
+		{ScanWindow{Lines: 2}, long, long},
+		{ScanWindow{Lines: 2, Bytes: 6}, lines, "one\ntw"},
+		{ScanWindow{Lines: 1, Bytes: 6}, lines, "one\n"},
+	}
+
+	for _, tt := range tests {
+		if got := string(tt.window.Head([]byte(tt.content))); got != tt.want {
+			t.Errorf("%+v.Head(%q) returned %q, want %q", tt.window, tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestReadHead(t *testing.T) {
+	defer func(w ScanWindow) { HeaderScanWindow = w }(HeaderScanWindow)
+
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.go")
+	large := filepath.Join(dir, "large.go")
+	if err := os.WriteFile(small, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Repeat(strings.Repeat("x", 1023)+"\n", 100)
+	if err := os.WriteFile(large, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		window ScanWindow
+		path   string
+		want   int
+	}{
+		{ScanWindow{}, small, len("package main\n")},
+		{ScanWindow{}, large, sniffBytes},
+		{ScanWindow{Bytes: 80 * 1024}, large, 80 * 1024},
+		{ScanWindow{Lines: 90}, large, 90 * 1024},
+		{ScanWindow{Lines: 200}, large, len(content)},
+		{ScanWindow{Lines: 10}, large, sniffBytes},
+	}
+
+	for _, tt := range tests {
+		HeaderScanWindow = tt.window
+		got, err := ReadHead(tt.path)
+		if err != nil {
+			t.Fatalf("ReadHead(%q) returned error: %v", tt.path, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("ReadHead(%q) with %+v read %d bytes, want %d", tt.path, tt.window, len(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeZip creates a zip archive containing the given files in a temp dir
+func writeZip(t *testing.T, files map[string]string) string {
+	path := filepath.Join(t.TempDir(), "release.zip")
+	f, err := os.Create(path)
+	assert.Nil(t, err)
+
+	zw := zip.NewWriter(f)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(contents))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zw.Close())
+	assert.Nil(t, f.Close())
+
+	return path
+}
+
+// writeTarGz creates a gzipped tarball containing the given files in a temp dir
+func writeTarGz(t *testing.T, files map[string]string) string {
+	path := filepath.Join(t.TempDir(), "release.tar.gz")
+	f, err := os.Create(path)
+	assert.Nil(t, err)
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, contents := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})
+		assert.Nil(t, err)
+		_, err = tw.Write([]byte(contents))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gw.Close())
+	assert.Nil(t, f.Close())
+
+	return path
+}
+
+func Test_Extract(t *testing.T) {
+	tests := []struct {
+		description  string
+		archivePath  func(t *testing.T) string
+		expectedRoot string
+		expectedFile string
+		expectErr    bool
+	}{
+		{
+			description: "Flat zip extracts to destination",
+			archivePath: func(t *testing.T) string {
+				return writeZip(t, map[string]string{"LICENSE": "MPL", "main.go": "package main"})
+			},
+			expectedRoot: "",
+			expectedFile: "main.go",
+		},
+		{
+			description: "Single top-level folder becomes the root",
+			archivePath: func(t *testing.T) string {
+				return writeTarGz(t, map[string]string{"app-1.2.3/LICENSE": "MPL", "app-1.2.3/src/main.go": "package main"})
+			},
+			expectedRoot: "app-1.2.3",
+			expectedFile: "src/main.go",
+		},
+		{
+			description: "Entries escaping the destination are rejected",
+			archivePath: func(t *testing.T) string {
+				return writeZip(t, map[string]string{"../../evil.sh": "rm -rf /"})
+			},
+			expectErr: true,
+		},
+		{
+			description: "Unknown formats are rejected",
+			archivePath: func(t *testing.T) string {
+				return filepath.Join(t.TempDir(), "release.rar")
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			dest := t.TempDir()
+			root, err := Extract(tt.archivePath(t), dest)
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, filepath.Join(dest, tt.expectedRoot), root)
+			_, err = os.Stat(filepath.Join(root, tt.expectedFile))
+			assert.Nil(t, err, "Expected file should be extracted")
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VerifyChecksum checks the SHA-256 digest of the file at path against its
+// entry in sums, which uses the format of `sha256sum` (and goreleaser's
+// SHA256SUMS files): one "<hex digest>  <file name>" line per file. Files are
+// looked up by their base name.
+func VerifyChecksum(path string, sums []byte) error {
+	name := filepath.Base(path)
+
+	expected := ""
+	s := bufio.NewScanner(bytes.NewReader(sums))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			expected = strings.ToLower(fields[0])
+			break
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if expected == "" {
+		return fmt.Errorf("no checksum found for %s", name)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+	return nil
+}
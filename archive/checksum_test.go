@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_VerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "copywrite_1.0.0_linux_x86_64.tar.gz")
+	assert.Nil(t, os.WriteFile(path, []byte("hello\n"), 0644))
+
+	// Digest of "hello\n"
+	digest := "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+
+	tests := []struct {
+		description string
+		sums        string
+		expectErr   bool
+	}{
+		{
+			description: "Matching checksum",
+			sums:        "0000000000000000000000000000000000000000000000000000000000000000  copywrite_1.0.0_darwin_arm64.tar.gz\n" + digest + "  copywrite_1.0.0_linux_x86_64.tar.gz\n",
+		},
+		{
+			description: "Binary mode marker",
+			sums:        digest + " *copywrite_1.0.0_linux_x86_64.tar.gz\n",
+		},
+		{
+			description: "Mismatched checksum",
+			sums:        "0000000000000000000000000000000000000000000000000000000000000000  copywrite_1.0.0_linux_x86_64.tar.gz\n",
+			expectErr:   true,
+		},
+		{
+			description: "Missing entry",
+			sums:        digest + "  copywrite_1.0.0_darwin_arm64.tar.gz\n",
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			err := VerifyChecksum(path, []byte(tt.sums))
+			if tt.expectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
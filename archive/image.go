@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractImage flattens the filesystem of a container image into dest. The
+// image must be a tarball produced by `docker save` or an OCI image layout
+// packed into a tarball (e.g., by `skopeo copy ... oci-archive:image.tar`).
+//
+// Layers are applied in order, honoring whiteout files so that files deleted
+// by a later layer do not show up in the result. Only regular files and
+// directories are extracted.
+func ExtractImage(imagePath string, dest string) error {
+	layout, err := os.MkdirTemp("", "copywrite-image")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(layout)
+
+	gzipped := strings.HasSuffix(imagePath, ".gz") || strings.HasSuffix(imagePath, ".tgz")
+	if err := extractTar(imagePath, layout, gzipped); err != nil {
+		return err
+	}
+
+	layers, err := imageLayers(layout)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range layers {
+		layerPath, err := safeJoin(layout, l)
+		if err != nil {
+			return err
+		}
+		if err := applyLayer(layerPath, dest); err != nil {
+			return fmt.Errorf("unable to apply image layer %s: %w", l, err)
+		}
+	}
+
+	return nil
+}
+
+// imageLayers returns the paths (relative to the layout directory) of the
+// image's layer tarballs, from the bottom of the image to the top
+func imageLayers(layout string) ([]string, error) {
+	// `docker save` writes a manifest.json listing layer paths directly
+	b, err := os.ReadFile(filepath.Join(layout, "manifest.json"))
+	if err == nil {
+		var manifests []struct {
+			Layers []string `json:"Layers"`
+		}
+		if err := json.Unmarshal(b, &manifests); err != nil {
+			return nil, fmt.Errorf("unable to parse manifest.json: %w", err)
+		}
+		if len(manifests) == 0 {
+			return nil, errors.New("manifest.json does not contain any images")
+		}
+		return manifests[0].Layers, nil
+	}
+
+	// Otherwise, follow an OCI image layout from index.json to the layers
+	b, err = os.ReadFile(filepath.Join(layout, "index.json"))
+	if err != nil {
+		return nil, errors.New("not a container image: expected a manifest.json or index.json")
+	}
+	return ociLayers(layout, b)
+}
+
+type ociDescriptor struct {
+	Digest string `json:"digest"`
+}
+
+// ociLayers walks an OCI index or manifest, descending into the first image
+// of any index, until it finds a manifest listing layers
+func ociLayers(layout string, b []byte) ([]string, error) {
+	// Guard against malformed layouts with cyclic indexes
+	for depth := 0; depth < 8; depth++ {
+		var doc struct {
+			Manifests []ociDescriptor `json:"manifests"`
+			Layers    []ociDescriptor `json:"layers"`
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("unable to parse OCI image layout: %w", err)
+		}
+
+		if len(doc.Manifests) == 0 {
+			layers := []string{}
+			for _, l := range doc.Layers {
+				layers = append(layers, blobPath(l.Digest))
+			}
+			return layers, nil
+		}
+
+		var err error
+		b, err = os.ReadFile(filepath.Join(layout, filepath.FromSlash(blobPath(doc.Manifests[0].Digest))))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, errors.New("OCI image index is nested too deeply")
+}
+
+// blobPath converts a digest such as "sha256:abc" to its location within an
+// OCI image layout, "blobs/sha256/abc"
+func blobPath(digest string) string {
+	return path.Join("blobs", strings.Replace(digest, ":", "/", 1))
+}
+
+// applyLayer extracts a single (optionally gzipped) layer tarball on top of
+// dest, processing whiteout entries as deletions
+func applyLayer(layerPath string, dest string) error {
+	f, err := os.Open(layerPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Layers may or may not be compressed, so sniff the gzip magic number
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dir, base := path.Split(hdr.Name)
+
+		// An opaque whiteout hides everything from lower layers in its directory
+		if base == ".wh..wh..opq" {
+			target, err := safeJoin(dest, dir)
+			if err != nil {
+				return err
+			}
+			entries, _ := os.ReadDir(target)
+			for _, e := range entries {
+				if err := os.RemoveAll(filepath.Join(target, e.Name())); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// A regular whiteout deletes a single file or folder from lower layers
+		if name, ok := strings.CutPrefix(base, ".wh."); ok {
+			target, err := safeJoin(dest, dir+name)
+			if err != nil {
+				return err
+			}
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+			continue
+		}
+
+		target, err := safeJoin(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			// A file from a lower layer may be read-only, so replace it outright
+			_ = os.Remove(target)
+			if err := writeFile(target, tr, hdr.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tarBytes builds an in-memory tarball of regular files, in the order given
+func tarBytes(t *testing.T, files [][2]string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		err := tw.WriteHeader(&tar.Header{Name: f[0], Mode: 0644, Size: int64(len(f[1])), Typeflag: tar.TypeReg})
+		assert.Nil(t, err)
+		_, err = tw.Write([]byte(f[1]))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write(b)
+	assert.Nil(t, err)
+	assert.Nil(t, gw.Close())
+	return buf.Bytes()
+}
+
+func Test_ExtractImage(t *testing.T) {
+	bottom := tarBytes(t, [][2]string{
+		{"app/main.go", "package main"},
+		{"app/old.go", "package main"},
+		{"cache/tmp.txt", "temporary"},
+	})
+	top := gzipBytes(t, tarBytes(t, [][2]string{
+		{"app/.wh.old.go", ""},
+		{"cache/.wh..wh..opq", ""},
+		{"LICENSE", "MPL"},
+	}))
+
+	tests := []struct {
+		description string
+		files       [][2]string
+	}{
+		{
+			description: "docker save format",
+			files: [][2]string{
+				{"manifest.json", `[{"Config": "config.json", "Layers": ["bottom/layer.tar", "top/layer.tar"]}]`},
+				{"bottom/layer.tar", string(bottom)},
+				{"top/layer.tar", string(top)},
+			},
+		},
+		{
+			description: "OCI image layout",
+			files: [][2]string{
+				{"oci-layout", `{"imageLayoutVersion": "1.0.0"}`},
+				{"index.json", `{"manifests": [{"digest": "sha256:manifest"}]}`},
+				{"blobs/sha256/manifest", `{"layers": [{"digest": "sha256:bottom"}, {"digest": "sha256:top"}]}`},
+				{"blobs/sha256/bottom", string(bottom)},
+				{"blobs/sha256/top", string(top)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			imagePath := filepath.Join(t.TempDir(), "image.tar")
+			assert.Nil(t, os.WriteFile(imagePath, tarBytes(t, tt.files), 0644))
+
+			dest := t.TempDir()
+			err := ExtractImage(imagePath, dest)
+			assert.Nil(t, err)
+
+			_, err = os.Stat(filepath.Join(dest, "app", "main.go"))
+			assert.Nil(t, err, "Files from lower layers are kept")
+			_, err = os.Stat(filepath.Join(dest, "LICENSE"))
+			assert.Nil(t, err, "Files from upper layers are added")
+			_, err = os.Stat(filepath.Join(dest, "app", "old.go"))
+			assert.True(t, os.IsNotExist(err), "Whiteouts delete files")
+			_, err = os.Stat(filepath.Join(dest, "cache", "tmp.txt"))
+			assert.True(t, os.IsNotExist(err), "Opaque whiteouts clear directories")
+		})
+	}
+}
+
+func Test_ExtractImage_NotAnImage(t *testing.T) {
+	imagePath := filepath.Join(t.TempDir(), "image.tar")
+	assert.Nil(t, os.WriteFile(imagePath, tarBytes(t, [][2]string{{"main.go", "package main"}}), 0644))
+
+	err := ExtractImage(imagePath, t.TempDir())
+	assert.NotNil(t, err)
+}
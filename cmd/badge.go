@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	badgeOut   string
+	badgeLabel string
+)
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge",
+	Short: "Generates an SVG badge showing header compliance",
+	Long: `Checks every file in the project for a copyright header, the same way as
+"copywrite headers --plan", and writes a shields.io-style SVG badge showing the
+percentage of files that have one. The badge can be committed or published as a
+CI artifact and displayed in the project's README.
+
+Unlike "copywrite headers --plan", missing headers don't cause the command to
+fail.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		stats, err := headerCompliance()
+		if err != nil {
+			cliLogger.Error("Error checking headers", err)
+		}
+		cobra.CheckErr(err)
+
+		percent := compliancePercent(stats)
+		svg := buildMetadataComment("<!-- ", " -->") + renderBadge(badgeLabel, fmt.Sprintf("%d%%", percent), complianceColor(percent))
+
+		if badgeOut == "-" {
+			_, err = io.WriteString(cmd.OutOrStdout(), svg)
+		} else {
+			err = os.WriteFile(badgeOut, []byte(svg), 0644)
+		}
+		if err != nil {
+			cliLogger.Error("Error writing badge", err)
+		}
+		cobra.CheckErr(err)
+
+		if badgeOut != "-" {
+			cmd.Printf("%d of %d files have headers (%d%%). Badge written to %s\n", stats.Scanned-stats.Added, stats.Scanned, percent, badgeOut)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(badgeCmd)
+
+	badgeCmd.Flags().StringVarP(&badgeOut, "out", "o", "badge.svg", "Path to write the badge to, or \"-\" for stdout")
+	badgeCmd.Flags().StringVar(&badgeLabel, "label", "headers", "Text on the left side of the badge")
+}
+
+// headerCompliance checks the files in the current directory for headers,
+// without changing anything, and returns a summary
+func headerCompliance() (addlicense.Stats, error) {
+	licenseData := addlicense.LicenseData{
+		Holder: conf.Project.CopyrightHolder,
+		SPDXID: conf.Project.License,
+	}
+	stats := addlicense.Stats{}
+	opts := headerOptions()
+	opts.Stats = &stats
+
+	err := addlicense.Run(conf.Project.HeaderIgnore, "only", licenseData, "", false, true, []string{"."}, log.New(io.Discard, "", 0), opts)
+	if err != nil && !errors.Is(err, addlicense.ErrMissingHeader) {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// badgeColors are the hex values of the shields.io colors returned by
+// complianceColor
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+}
+
+// badgeTextWidth approximates the width in pixels of s when rendered in the
+// 11px Verdana used by badges, with padding on either side
+func badgeTextWidth(s string) int {
+	return len([]rune(s))*7 + 10
+}
+
+// renderBadge returns an SVG badge in the "flat" style used by shields.io, with
+// label on a gray background and message on the given color
+func renderBadge(label string, message string, color string) string {
+	labelWidth := badgeTextWidth(label)
+	messageWidth := badgeTextWidth(message)
+	width := labelWidth + messageWidth
+	fill := badgeColors[color]
+	if fill == "" {
+		fill = color
+	}
+	title := html.EscapeString(label + ": " + message)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s">
+  <title>%[2]s</title>
+  <linearGradient id="s" x2="0" y2="100%%">
+    <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
+    <stop offset="1" stop-opacity=".1"/>
+  </linearGradient>
+  <clipPath id="r">
+    <rect width="%[1]d" height="20" rx="3" fill="#fff"/>
+  </clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%[3]d" height="20" fill="#555"/>
+    <rect x="%[3]d" width="%[4]d" height="20" fill="%[5]s"/>
+    <rect width="%[1]d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%[6]d" y="14">%[7]s</text>
+    <text x="%[8]d" y="14">%[9]s</text>
+  </g>
+</svg>
+`, width, title, labelWidth, messageWidth, fill, labelWidth/2, html.EscapeString(label), labelWidth+messageWidth/2, html.EscapeString(message))
+}
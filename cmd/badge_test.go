@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_renderBadge(t *testing.T) {
+	svg := renderBadge("headers", "97%", complianceColor(97))
+
+	// The badge must be well-formed XML for browsers to render it
+	var parsed struct {
+		Width string   `xml:"width,attr"`
+		Title string   `xml:"title"`
+		Text  []string `xml:"g>text"`
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(svg), &parsed))
+	assert.Equal(t, "90", parsed.Width)
+	assert.Equal(t, "headers: 97%", parsed.Title)
+	assert.Equal(t, []string{"headers", "97%"}, parsed.Text)
+	assert.Contains(t, svg, `fill="#97ca00"`)
+
+	assert.Contains(t, renderBadge("a&b", "<1%", "red"), "a&amp;b: &lt;1%")
+}
@@ -0,0 +1,266 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/git"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	showDiff   bool
+	bumpToYear int
+	sinceTag   string
+	sinceDate  string
+)
+
+var bumpYearsCmd = &cobra.Command{
+	Use:   "bump-years",
+	Short: "Refreshes the years in existing copyright headers",
+	Long: `Recursively checks for all files in the given directory and subdirectories,
+refreshing the end year of copyright statements that already name the configured
+copyright holder.
+
+Only the years are modified: holders and license statements are never rewritten,
+and files missing a header are left alone (see "copywrite headers"). If a license
+is configured, files whose SPDX-License-Identifier does not match it are skipped.
+
+The project.year_strategy config key controls how years are refreshed:
+  range   (default) "2019" or "2019-2023" becomes "2019-<year>"
+  current           "2019-2023" becomes "<year>"
+
+Years should generally only advance when a file has substantively changed. Use
+--since-tag or --since to limit bumps to files modified by commits made after a
+release tag or date, respectively.
+
+Files and lines can be exempted using ignore markers (see "copywrite markers").`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`spdx`:             `project.license`,
+			`copyright-holder`: `project.copyright_holder`,
+			`year-strategy`:    `project.year_strategy`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+
+		// Input Validation
+		_, err = licensecheck.ParseYearStrategy(conf.Project.YearStrategy)
+		if err != nil {
+			cliLogger.Error("Error validating year strategy", err)
+		}
+		cobra.CheckErr(err)
+
+		if sinceDate != "" {
+			if _, err := time.Parse("2006-01-02", sinceDate); err != nil {
+				err := fmt.Errorf("invalid --since date %q: must be formatted as YYYY-MM-DD", sinceDate)
+				cliLogger.Error("Error validating flags", err)
+				cobra.CheckErr(err)
+			}
+		}
+
+		if conf.Project.License != "" && !addlicense.ValidSPDXExpression(conf.Project.License) {
+			err := fmt.Errorf("invalid SPDX license identifier: %s", conf.Project.License)
+			cliLogger.Error("Error validating SPDX license", err)
+			cobra.CheckErr(err)
+		}
+
+		if !plan {
+			err := checkSafePath(".")
+			if err != nil {
+				cliLogger.Error("Error validating target directory", err)
+			}
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if plan {
+			infof(cmd, "%s", text.FgYellow.Sprint("Executing in dry-run mode. Rerun without the `--plan` flag to apply changes.\n\n"))
+		}
+
+		strategy, _ := licensecheck.ParseYearStrategy(conf.Project.YearStrategy)
+		infof(cmd, "Bumping copyright years to %d using the %q strategy\n", bumpToYear, strategy)
+		infof(cmd, "Using copyright holder: %v\n\n", conf.Project.CopyrightHolder)
+
+		stdcliLogger := resultLogger(cmd)
+
+		opts := headerOptions()
+		err := skipSubmodules(cmd, &opts)
+		if err != nil {
+			cliLogger.Error("Error applying the submodules policy", err)
+		}
+		cobra.CheckErr(err)
+
+		if sinceTag != "" || sinceDate != "" {
+			skip, err := sinceFilter(sinceTag, sinceDate)
+			if err != nil {
+				cliLogger.Error("Error reading git history", err)
+			}
+			cobra.CheckErr(err)
+			opts.Skip = skip
+		}
+		if conf.Project.Upstream != "" {
+			skip, err := upstreamFilter(conf.Project.Upstream)
+			if err != nil {
+				cliLogger.Error("Error reading upstream file list", err)
+			}
+			cobra.CheckErr(err)
+			opts.Skip = combineSkips(opts.Skip, skip)
+		}
+
+		var mu sync.Mutex
+		changed := map[string][2][]byte{}
+
+		err = addlicense.Walk(conf.Project.HeaderIgnore, []string{"."}, stdcliLogger, opts, func(path string) error {
+			b, err := addlicense.ReadHead(path)
+			if err != nil {
+				return err
+			}
+
+			if addlicense.HasIgnoreFileMarker(b) {
+				stdcliLogger.Printf("[DEBUG] skipping: %s (%s marker)", path, addlicense.IgnoreFileMarker)
+				return nil
+			}
+
+			if conf.Project.License != "" && !addlicense.HasSPDXExpression(b, conf.Project.License) {
+				stdcliLogger.Printf("[DEBUG] skipping: %s (license does not match %s)", path, conf.Project.License)
+				return nil
+			}
+
+			if _, ok := licensecheck.BumpCopyrightYear(b, conf.Project.CopyrightHolder, bumpToYear, strategy); !ok {
+				return nil
+			}
+
+			// Only read the full file once it's known to need changes
+			b, err = os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			updated, _ := licensecheck.BumpCopyrightYear(b, conf.Project.CopyrightHolder, bumpToYear, strategy)
+
+			mu.Lock()
+			changed[path] = [2][]byte{b, updated}
+			mu.Unlock()
+
+			if plan {
+				return nil
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(path, updated, fi.Mode())
+		})
+		if err != nil {
+			cliLogger.Error("Error bumping copyright years", err)
+		}
+		cobra.CheckErr(err)
+
+		paths := lo.Keys(changed)
+		sort.Strings(paths)
+
+		gha.StartGroup("The following files have outdated copyright years:")
+		for _, path := range paths {
+			cmd.Println(path)
+			if showDiff {
+				cmd.Print(lineDiff(path, changed[path][0], changed[path][1]))
+			}
+		}
+		gha.EndGroup()
+
+		if plan && len(paths) > 0 {
+			cobra.CheckErr(fmt.Sprintf("%d files have outdated copyright years. Run without the --plan flag to fix this", len(paths)))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bumpYearsCmd)
+
+	// These flags are only locally relevant
+	bumpYearsCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to bump copyright years")
+	bumpYearsCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run, printing the names of all files with outdated years")
+	bumpYearsCmd.Flags().BoolVar(&showDiff, "diff", false, "Prints the changed line of each file")
+	bumpYearsCmd.Flags().IntVar(&bumpToYear, "year", time.Now().Year(), "Year to bump copyright statements to")
+	bumpYearsCmd.Flags().StringVar(&sinceTag, "since-tag", "", "Only bump files modified by commits made after the given git tag or ref (e.g., \"v1.2.3\")")
+	bumpYearsCmd.Flags().StringVar(&sinceDate, "since", "", "Only bump files modified by commits made on or after the given date (YYYY-MM-DD)")
+	bumpYearsCmd.MarkFlagsMutuallyExclusive("since-tag", "since")
+	bumpYearsCmd.Flags().IntVar(&parallelism, "parallelism", 0, "Number of directories to read concurrently while discovering files (default is the number of CPUs)")
+	addAllowUnsafePathsFlag(bumpYearsCmd)
+
+	// These flags will get mapped to keys in the the global Config
+	bumpYearsCmd.Flags().StringP("spdx", "s", "", "Only bump years in files whose SPDX license identifier matches (e.g., 'MPL-2.0')")
+	cobra.CheckErr(bumpYearsCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
+	bumpYearsCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder whose statements should be bumped (default \"HashiCorp, Inc.\")")
+	bumpYearsCmd.Flags().String("year-strategy", "", "How years are refreshed: \"range\" or \"current\" (default \"range\")")
+}
+
+// sinceFilter builds a skip function for addlicense that excludes files not
+// modified by a commit made after the given ref or date. Only one of sinceRef
+// or sinceDate is expected to be set.
+func sinceFilter(sinceRef string, sinceDate string) (func(path string) (bool, string), error) {
+	var modified map[string]bool
+	var reason string
+	var err error
+
+	if sinceRef != "" {
+		modified, err = git.ChangedSinceRef(".", sinceRef)
+		reason = fmt.Sprintf("not modified since %s", sinceRef)
+	} else {
+		date, _ := time.Parse("2006-01-02", sinceDate)
+		modified, err = git.ChangedSinceDate(".", date)
+		reason = fmt.Sprintf("not modified since %s", sinceDate)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func(path string) (bool, string) {
+		if modified[filepath.ToSlash(path)] {
+			return false, ""
+		}
+		return true, reason
+	}, nil
+}
+
+// lineDiff renders the lines that differ between two versions of a file in a
+// minimal unified diff format
+func lineDiff(path string, before []byte, after []byte) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+
+	beforeLines := bytes.Split(before, []byte("\n"))
+	afterLines := bytes.Split(after, []byte("\n"))
+	for i := 0; i < len(beforeLines) && i < len(afterLines); i++ {
+		if !bytes.Equal(beforeLines[i], afterLines[i]) {
+			fmt.Fprintf(&b, "%s\n%s\n", text.FgRed.Sprintf("-%s", beforeLines[i]), text.FgGreen.Sprintf("+%s", afterLines[i]))
+		}
+	}
+	return b.String()
+}
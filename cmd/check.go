@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var checkPolicy bool
+
+// check is one of the checks run by `copywrite check`
+type check struct {
+	Name string
+	Run  func() error
+}
+
+// checkResult is the outcome of a check
+type checkResult struct {
+	Name string
+	Err  error
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Runs every compliance check in a single step",
+	Long: `Runs the same checks as the following commands, without changing any files,
+and prints a consolidated report:
+- copywrite headers --plan
+- copywrite license --plan
+- copywrite report years (with --policy)
+
+Every check runs even if an earlier one fails, and the command fails if any of
+them do, so CI only needs a single step to enforce compliance.`,
+	GroupID: "common", // Let's put this command in the common section of the help
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`spdx`:             `project.license`,
+			`year`:             `project.copyright_year`,
+			`copyright-holder`: `project.copyright_holder`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+
+		cobra.CheckErr(validateHeaderConfig())
+		cobra.CheckErr(validateMinCoverage())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := []check{
+			{"headers", func() error {
+				return addHeaders(cmd, true)
+			}},
+			{"license", func() error {
+				if err := inferCopyrightYear(); err != nil {
+					return err
+				}
+				copyright, err := licenseCopyright()
+				if err != nil {
+					return err
+				}
+				if err := validateLicenseFile(".", copyright); err != nil {
+					return err
+				}
+				cmd.Println("License file is present, named properly, and has a valid copyright statement!")
+				return nil
+			}},
+		}
+
+		if checkPolicy {
+			checks = append(checks, check{"copyright years", func() error {
+				found, err := scanYearAnomalies(cmd, time.Now().Year(), false)
+				if err != nil {
+					return err
+				}
+				if len(found) == 0 {
+					cmd.Println("No implausible copyright years found")
+					return nil
+				}
+				printYearAnomalies(cmd, found)
+				return fmt.Errorf("%d files have implausible copyright years. Run `copywrite report years --fix` to apply the suggested corrections", len(found))
+			}})
+		}
+
+		cobra.CheckErr(runChecks(cmd, checks))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	// These flags are only locally relevant
+	checkCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to run checks")
+	checkCmd.Flags().BoolVar(&checkPolicy, "policy", false, "Also run policy checks, such as for implausible copyright years")
+	checkCmd.Flags().IntVar(&minCoverage, "min-coverage", 0, "Only fail the headers check if fewer than this percentage of files have headers (e.g., 95)")
+	checkCmd.Flags().IntVar(&parallelism, "parallelism", 0, "Number of directories to read concurrently while discovering files (default is the number of CPUs)")
+
+	// These flags will get mapped to keys in the the global Config
+	checkCmd.Flags().IntP("year", "y", 0, "Year that the LICENSE file's copyright statement should include")
+	checkCmd.Flags().StringP("spdx", "s", "", "SPDX-compliant license identifier or expression (e.g., 'MPL-2.0' or 'MIT OR Apache-2.0')")
+	cobra.CheckErr(checkCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
+	checkCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+	addGitHubRepoFlag(checkCmd)
+}
+
+// runChecks runs every check, even if an earlier one fails, then prints a
+// consolidated report of the results. It returns an error if any check failed.
+func runChecks(cmd *cobra.Command, checks []check) error {
+	results := []checkResult{}
+	for _, c := range checks {
+		cmd.Printf("%s\n\n", text.Bold.Sprintf("Checking %s...", c.Name))
+		err := c.Run()
+		if err != nil {
+			cmd.Println(text.FgRed.Sprint(err))
+		}
+		cmd.Println("")
+		results = append(results, checkResult{Name: c.Name, Err: err})
+	}
+
+	failed := 0
+	gha.StartGroup("Results:")
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			cmd.Printf("❌ %s: %s\n", r.Name, r.Err)
+		} else {
+			cmd.Printf("✔️ %s\n", r.Name)
+		}
+	}
+	gha.EndGroup()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_runChecks(t *testing.T) {
+	ran := []string{}
+	fake := func(name string, err error) check {
+		return check{name, func() error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+
+	tests := []struct {
+		description    string
+		checks         []check
+		expectedRan    []string
+		expectedOutput []string
+		expectedErr    string
+	}{
+		{
+			description:    "All checks pass",
+			checks:         []check{fake("headers", nil), fake("license", nil)},
+			expectedRan:    []string{"headers", "license"},
+			expectedOutput: []string{"✔️ headers\n", "✔️ license\n"},
+		},
+		{
+			description:    "Every check runs after an earlier one fails",
+			checks:         []check{fake("headers", errors.New("2 files are missing headers")), fake("license", nil), fake("copyright years", errors.New("1 file has implausible years"))},
+			expectedRan:    []string{"headers", "license", "copyright years"},
+			expectedOutput: []string{"❌ headers: 2 files are missing headers\n", "✔️ license\n", "❌ copyright years: 1 file has implausible years\n"},
+			expectedErr:    "2 of 3 checks failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ran = []string{}
+			out := &bytes.Buffer{}
+			cmd := &cobra.Command{}
+			cmd.SetOut(out)
+
+			err := runChecks(cmd, tt.checks)
+			if tt.expectedErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+			assert.Equal(t, tt.expectedRan, ran)
+			for _, s := range tt.expectedOutput {
+				assert.Contains(t, out.String(), s)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/config"
+	"github.com/spf13/cobra"
+)
+
+// completeSPDX provides shell completion for SPDX license identifiers. When
+// completing an expression, only the last identifier is completed, and
+// identifiers following the WITH operator are completed from the exception list.
+func completeSPDX(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix, partial := "", toComplete
+	if i := strings.LastIndexAny(toComplete, " ("); i >= 0 {
+		prefix, partial = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	list := addlicense.SPDXLicenses()
+	if fields := strings.Fields(prefix); len(fields) > 0 && strings.EqualFold(fields[len(fields)-1], "WITH") {
+		list = addlicense.SPDXExceptions()
+	}
+
+	completions := []string{}
+	for _, l := range list {
+		if strings.HasPrefix(strings.ToLower(l.ID), strings.ToLower(partial)) {
+			completions = append(completions, prefix+l.ID+"\t"+l.Name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigKeys provides shell completion for the keys that may be set in
+// a .copywrite.hcl config file
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Only the first argument is a key
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := []string{}
+	for _, k := range config.Keys() {
+		if strings.HasPrefix(k, toComplete) {
+			completions = append(completions, k)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
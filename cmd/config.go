@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/copywrite/config"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Reads and writes individual keys of the .copywrite.hcl config",
+	Long: `Reads and writes individual keys of the .copywrite.hcl config, which is useful
+for scripting changes to configs across many repos. Keys are delimited by a
+period, e.g. "project.license" or "dispatch.ignored_repos".`,
+	// Run function is omitted, as this command exists only to house subcommands
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Prints the running value of a config key",
+	Long: `Prints the running value of a config key, which accounts for default values as
+well as those set in the config file. List values are printed one per line, and
+nothing is printed for unset keys.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		key := args[0]
+		if !lo.Contains(config.Keys(), key) {
+			cobra.CheckErr(fmt.Errorf("unknown config key %q", key))
+		}
+
+		switch val := conf.Get(key).(type) {
+		case nil:
+		case []interface{}:
+			for _, v := range val {
+				cmd.Println(v)
+			}
+		case []string:
+			for _, v := range val {
+				cmd.Println(v)
+			}
+		default:
+			cmd.Println(val)
+		}
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>...",
+	Short: "Sets a config key in the .copywrite.hcl config",
+	Long: `Sets a config key in the .copywrite.hcl config, creating the file if needed.
+Comments and formatting elsewhere in the file are left untouched.
+
+List keys (e.g., "project.header_ignore") take any number of values, which
+replace the existing list. All other keys take exactly one value.`,
+	Example: `  copywrite config set project.license MPL-2.0
+  copywrite config set project.header_ignore "vendor/**" "**/*.pb.go"`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := config.SetFileValue(cfgPath, args[0], args[1:]...)
+		if err != nil {
+			cliLogger.Error("Error updating config", err)
+		}
+		cobra.CheckErr(err)
+		cmd.Printf("Set %s in %s\n", args[0], cfgPath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
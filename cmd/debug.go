@@ -5,9 +5,16 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"time"
 
+	"github.com/hashicorp/copywrite/git"
 	"github.com/hashicorp/copywrite/github"
 	"github.com/hashicorp/go-hclog"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -15,15 +22,51 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Flag variables
+var debugFormat string
+
+// debugInfo is everything reported by the debug command. It must never contain
+// secrets: credentials are only reported as present or not.
+type debugInfo struct {
+	reportMetadata
+	Version          string                 `json:"version"`
+	OS               string                 `json:"os"`
+	Arch             string                 `json:"arch"`
+	GoVersion        string                 `json:"go_version"`
+	GitVersion       string                 `json:"git_version,omitempty"`
+	WorkingDirectory string                 `json:"working_directory"`
+	ConfigPath       string                 `json:"config_path"`
+	ConfigExists     bool                   `json:"config_exists"`
+	ConfigHash       string                 `json:"config_hash,omitempty"`
+	RunningConfig    map[string]interface{} `json:"running_config"`
+	GitHubActions    bool                   `json:"github_actions"`
+	GitHubRepo       string                 `json:"github_repo,omitempty"`
+	GitHubAuthMethod github.AuthMethod      `json:"github_auth_method"`
+	Credentials      github.Credentials     `json:"credentials"`
+	GitHubUser       string                 `json:"github_user,omitempty"`
+	RateLimit        *debugRateLimit        `json:"rate_limit,omitempty"`
+}
+
+// debugRateLimit is the state of the GitHub API core rate limit
+type debugRateLimit struct {
+	Remaining int       `json:"remaining"`
+	Limit     int       `json:"limit"`
+	Reset     time.Time `json:"reset"`
+}
+
 // debugCmd represents the debug command
 var debugCmd = &cobra.Command{
 	Use:   "debug",
 	Short: "Prints env-specific debug information about copywrite",
 	Long: `Prints information to help debug issues, including:
 - Copywrite Version
-- Running configuration
+- OS, architecture, and Go and git versions
+- Running configuration, and a hash of the config file
 - Current GitHub repo (if one is detected)
-- GitHub authentication status`,
+- GitHub authentication status
+
+Tokens and keys are never printed; only whether each is present. Use
+--format=json to produce output that can be attached to bug reports.`,
 	PreRun: func(cmd *cobra.Command, args []string) {
 		// Change directory if needed
 		if dirPath != "." {
@@ -31,10 +74,26 @@ var debugCmd = &cobra.Command{
 			cobra.CheckErr(err)
 		}
 
-		// Let's forcibly enable trace-level logging
-		cliLogger.SetLevel(hclog.Trace)
+		if debugFormat != "text" && debugFormat != "json" {
+			cobra.CheckErr(fmt.Sprintf("invalid --format %q: must be \"text\" or \"json\"", debugFormat))
+		}
+
+		// Let's forcibly enable trace-level logging, unless the output is meant
+		// to be machine-readable
+		if debugFormat == "text" {
+			cliLogger.SetLevel(hclog.Trace)
+		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		info := gatherDebugInfo()
+
+		if debugFormat == "json" {
+			b, err := json.MarshalIndent(info, "", "  ")
+			cobra.CheckErr(err)
+			cmd.Println(string(b))
+			return
+		}
+
 		title := func(t string) {
 			escaped := colorize(t, text.FgCyan, text.Bold)
 			cmd.Println(escaped)
@@ -44,8 +103,19 @@ var debugCmd = &cobra.Command{
 		// Print version info
 		//
 		title("Copywrite Version:")
-		version := GetVersion()
-		cmd.Printf("%v\n\n", version)
+		cmd.Printf("%v\n\n", info.Version)
+
+		//
+		// Print environment info
+		//
+		title("Environment:")
+		cmd.Printf("OS/Arch:\t%s/%s\n", info.OS, info.Arch)
+		cmd.Printf("Go Version:\t%s\n", info.GoVersion)
+		if info.GitVersion != "" {
+			cmd.Printf("Git Version:\t%s\n\n", info.GitVersion)
+		} else {
+			cmd.Print("Git Version:\tgit not found\n\n")
+		}
 
 		//
 		// Print working directory info
@@ -54,17 +124,16 @@ var debugCmd = &cobra.Command{
 		if dirPath != "." {
 			cmd.Print("The working directory was overwritten with the --dirPath flag\n")
 		}
-		absDirPath, _ := filepath.Abs(dirPath)
-		cmd.Printf("Directory path: %v\n\n", absDirPath)
+		cmd.Printf("Directory path: %v\n\n", info.WorkingDirectory)
 
 		//
 		// Print info relating to any configuration file found
 		//
 		title("Copywrite Configuration File:")
-		path := conf.GetConfigPath()
-		cmd.Printf("Configuration file path: %s\n", path)
-		if _, err := os.Stat(path); err == nil {
-			cmd.Print("✔️ Config file exists\n\n")
+		cmd.Printf("Configuration file path: %s\n", info.ConfigPath)
+		if info.ConfigExists {
+			cmd.Print("✔️ Config file exists\n")
+			cmd.Printf("Config file hash: %s\n\n", info.ConfigHash)
 		} else {
 			cmd.Print("❌ File does not exist\n\n")
 		}
@@ -80,7 +149,7 @@ var debugCmd = &cobra.Command{
 		// Print GitHub Actions/CI Information
 		//
 		title("GitHub Actions:")
-		if gha.IsGHA() {
+		if info.GitHubActions {
 			cmd.Print("Current execution environment is GitHub Actions\n\n")
 		} else {
 			cmd.Print("Current execution environment is NOT GitHub Actions\n\n")
@@ -90,27 +159,43 @@ var debugCmd = &cobra.Command{
 		// Print any GitHub repo that is discovered
 		//
 		title("Current GitHub Repo:")
-		repo, err := github.DiscoverRepo()
-		if err != nil {
-			cmd.Println(err)
+		if info.GitHubRepo == "" {
+			cmd.Println("No GitHub repo detected")
 		} else {
-			cmd.Printf("GitHub Org:\t%v\n", repo.Owner)
-			cmd.Printf("GitHub Repo:\t%v\n", repo.Name)
+			cmd.Printf("GitHub Repo:\t%v\n", info.GitHubRepo)
 		}
 		cmd.Println()
 
 		//
-		// Attempt to auth to GitHub and print any relevant info
+		// Print which credentials are present (but never their values)
 		//
-		title("Attempting GitHub Authentication:")
-		ghc := github.NewGHClient().Raw()
-
-		user, _, _ := ghc.Users.Get(context.Background(), "")
-		cmd.Printf("Running as authenticated user: %v (@%v)\n", user.GetName(), user.GetLogin())
+		title("GitHub Credentials:")
+		present := func(b bool) string {
+			if b {
+				return "present (redacted)"
+			}
+			return "not set"
+		}
+		cmd.Printf("APP_ID:\t\t\t%s\n", present(info.Credentials.AppID))
+		cmd.Printf("INSTALLATION_ID:\t%s\n", present(info.Credentials.InstallationID))
+		cmd.Printf("APP_PEM:\t\t%s\n", present(info.Credentials.AppPEM))
+		cmd.Printf("GITHUB_TOKEN:\t\t%s\n", present(info.Credentials.GitHubToken))
+		cmd.Printf("gh CLI token:\t\t%s\n", present(info.Credentials.GitHubCLIToken))
+		cmd.Printf("Auth method in use:\t%s\n\n", info.GitHubAuthMethod)
 
-		rateLimits, _, _ := ghc.RateLimits(context.Background())
-		cmd.Printf("GitHub API rate limits: %v/%v remaining\n", rateLimits.Core.Remaining, rateLimits.Core.Limit)
-		cmd.Printf("GitHub API rate limits will reset at: %v (%v)\n", rateLimits.Core.Reset, timediff.TimeDiff(rateLimits.Core.Reset.Time))
+		//
+		// Print any relevant info from authenticating to GitHub
+		//
+		title("Attempting GitHub Authentication:")
+		if info.GitHubUser != "" {
+			cmd.Printf("Running as authenticated user: @%v\n", info.GitHubUser)
+		}
+		if info.RateLimit != nil {
+			cmd.Printf("GitHub API rate limits: %v/%v remaining\n", info.RateLimit.Remaining, info.RateLimit.Limit)
+			cmd.Printf("GitHub API rate limits will reset at: %v (%v)\n", info.RateLimit.Reset, timediff.TimeDiff(info.RateLimit.Reset))
+		} else {
+			cmd.Println("Unable to retrieve GitHub API rate limits")
+		}
 	},
 }
 
@@ -119,4 +204,46 @@ func init() {
 
 	// These flags are only locally relevant
 	debugCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to introspect")
+	debugCmd.Flags().StringVar(&debugFormat, "format", "text", "Output format: \"text\" or \"json\"")
+	addGitHubRepoFlag(debugCmd)
+}
+
+// gatherDebugInfo collects the information printed by the debug command
+func gatherDebugInfo() debugInfo {
+	info := debugInfo{
+		reportMetadata:   newReportMetadata(),
+		Version:          GetVersion(),
+		OS:               runtime.GOOS,
+		Arch:             runtime.GOARCH,
+		GoVersion:        runtime.Version(),
+		ConfigPath:       conf.GetConfigPath(),
+		RunningConfig:    conf.Map(),
+		GitHubActions:    gha.IsGHA(),
+		GitHubAuthMethod: github.DetectAuthMethod(),
+		Credentials:      github.DetectCredentials(),
+	}
+
+	info.WorkingDirectory, _ = filepath.Abs(".")
+	info.GitVersion, _ = git.Version()
+
+	if b, err := os.ReadFile(info.ConfigPath); err == nil {
+		sum := sha256.Sum256(b)
+		info.ConfigExists = true
+		info.ConfigHash = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	if repo, err := discoverRepo(); err == nil {
+		info.GitHubRepo = repo.Owner + "/" + repo.Name
+	}
+
+	ghc := github.NewGHClient().Raw()
+	if user, _, err := ghc.Users.Get(context.Background(), ""); err == nil {
+		info.GitHubUser = user.GetLogin()
+	}
+	if limits, _, err := ghc.RateLimits(context.Background()); err == nil {
+		core := limits.GetCore()
+		info.RateLimit = &debugRateLimit{Remaining: core.Remaining, Limit: core.Limit, Reset: core.Reset.Time}
+	}
+
+	return info
 }
@@ -4,87 +4,77 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
 
 	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/config"
 	"github.com/hashicorp/copywrite/dispatch"
 	gh "github.com/hashicorp/copywrite/github"
 	"github.com/hashicorp/copywrite/repodata"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/thanhpk/randstr"
 )
 
+// Flag variables
+var (
+	dispatchDryRun bool
+	dispatchBundle string
+)
+
 var dispatchCmd = &cobra.Command{
 	Use:   "dispatch",
 	Short: "Dispatches audit jobs for a list of repos",
 	Long:  `Dispatches audit jobs for all public and non-archived repos`,
 	PreRun: func(cmd *cobra.Command, args []string) {
-		// Map command flags to config keys
-		mapping := map[string]string{
-			`batch-id`:     `dispatch.batch_id`,
-			`branch`:       `dispatch.branch`,
-			`max-attempts`: `dispatch.max_attempts`,
-			`sleep`:        `dispatch.sleep`,
-			`workers`:      `dispatch.workers`,
-			`workflow`:     `dispatch.workflow_file_name`,
-			`github-org`:   `dispatch.github_org_to_audit`,
-		}
-
-		// update the running config with any command-line flags
-		clobberWithDefaults := false
-		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
-		if err != nil {
-			cliLogger.Error("Error merging configuration", err)
-		}
-		cobra.CheckErr(err)
+		loadDispatchFlags(cmd)
 
-		// Dynamically generate a batchID if none is supplied
-		if conf.Dispatch.BatchID == "" {
-			conf.Dispatch.BatchID = randstr.Hex(8) // 8-digit random string
-			cliLogger.Debug(fmt.Sprintf("Using auto-generated batchID: %s", conf.Dispatch.BatchID))
+		if err := validateDispatchWorkflows(); err != nil {
+			cliLogger.Error("Error validating config", err)
+			cobra.CheckErr(err)
 		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
 
 		client := gh.NewGHClient().Raw()
 
-		// Retrieve all public, non-archived GitHub repos for auditing
-		allRepos, err := repodata.GetRepos(conf.Dispatch.GitHubOrgToAudit)
-		cobra.CheckErr(err)
-
-		targetRepos := repodata.FilterRepos(allRepos)
-
-		if len(conf.Dispatch.IgnoredRepos) > 0 {
-			gha.StartGroup("Exempting the following repos:")
-			for _, v := range conf.Dispatch.IgnoredRepos {
-				cliLogger.Info(text.FgCyan.Sprint(v))
-			}
-			gha.EndGroup()
-
-			// Filter out any repos that are on the ignore list
-			targetRepos = lo.Filter(targetRepos, func(r *github.Repository, i int) bool {
-				fqn := fmt.Sprintf("%v/%v", conf.Dispatch.GitHubOrgToAudit, r.GetName())
-				return !lo.Contains(conf.Dispatch.IgnoredRepos, fqn)
-			})
+		orgs := auditOrgs()
+		targetRepos := map[string][]*github.Repository{}
+		total := 0
+		for _, org := range orgs {
+			repos, err := auditTargets(ctx, client, org)
+			checkTimeout(err)
+			targetRepos[org.Name] = repos
+			total += len(repos)
 		}
 
-		cliLogger.Info(fmt.Sprintf("Repositories will be audited with the \"%v\" GitHub Actions workflow", conf.Dispatch.WorkflowFileName))
-		cliLogger.Info(fmt.Sprintf("Set to process %v GitHub repositories with %v concurrent workers", len(targetRepos), conf.Dispatch.Workers))
+		for _, org := range orgs {
+			cliLogger.Info(fmt.Sprintf("Repositories in %v will be audited with the \"%v\" GitHub Actions workflow", org.Name, org.WorkflowFileName))
+		}
+		cliLogger.Info(fmt.Sprintf("Set to process %v GitHub repositories with %v concurrent workers", total, conf.Dispatch.Workers))
 
 		if plan {
 			cliLogger.Info(text.Bold.Sprint("The following repos would be audited:"))
-			for _, v := range targetRepos {
-				cliLogger.Info(fmt.Sprintf("%v/%v", conf.Dispatch.GitHubOrgToAudit, *v.Name))
+			for _, org := range orgs {
+				for _, v := range targetRepos[org.Name] {
+					cliLogger.Info(fmt.Sprintf("%v/%v", org.Name, *v.Name))
+				}
 			}
-			cliLogger.Info(text.FgYellow.Sprintf("Executing in dry-run mode. Rerun without the `--plan` flag to trigger audits on all %v repos.", len(targetRepos)))
+			cliLogger.Info(text.FgYellow.Sprintf("Executing in dry-run mode. Rerun without the `--plan` flag to trigger audits on all %v repos.", total))
 			return
 		}
 
 		// The actual stuff
 
-		repo, err := gh.DiscoverRepo()
+		repo, err := discoverRepo()
 		cobra.CheckErr(err)
 
 		opts := dispatch.Options{
@@ -93,36 +83,42 @@ var dispatchCmd = &cobra.Command{
 			Logger:              cliLogger.Named("dispatch"),
 			BranchRef:           conf.Dispatch.Branch,
 			BatchID:             conf.Dispatch.BatchID,
-			WorkflowFileName:    conf.Dispatch.WorkflowFileName,
 			GitHubOwner:         repo.Owner,
 			GitHubRepo:          repo.Name,
+			DryRun:              dispatchDryRun,
 		}
 
-		numJobs := len(targetRepos)
-		jobs := make(chan string, numJobs)
-		results := make(chan dispatch.Result, numJobs)
-
-		// Create a worker pool
-		for w := 1; w <= conf.Dispatch.Workers; w++ {
-			go dispatch.Worker(client, opts, w, jobs, results)
+		// Each org is audited in turn with its own workflow, with results
+		// named after the repo's full name so that they can be reported together
+		failures := []dispatch.Result{}
+		succeeded := []dispatch.Result{}
+		summary := newTableWriter(cmd.OutOrStdout())
+		summary.AppendHeader(table.Row{"Org", "Repos", "Succeeded", "Failed"})
+		for _, org := range orgs {
+			opts.WorkflowFileName = org.WorkflowFileName
+			orgFailures := 0
+			for _, result := range dispatchRepos(ctx, client, opts, targetRepos[org.Name]) {
+				result.Name = fmt.Sprintf("%v/%v", org.Name, result.Name)
+				if !result.Success {
+					failures = append(failures, result)
+					orgFailures++
+				} else {
+					succeeded = append(succeeded, result)
+				}
+			}
+			summary.AppendRow(table.Row{org.Name, len(targetRepos[org.Name]), len(targetRepos[org.Name]) - orgFailures, orgFailures})
 		}
-
-		// Queue up all of the repos to be processed by the worker pool
-		for _, v := range targetRepos {
-			jobs <- *v.Name
+		if len(orgs) > 1 {
+			summary.AppendFooter(table.Row{"Total", total, len(succeeded), len(failures)})
+			summary.Render()
 		}
 
-		// TODO: the 'jobs' channel will need to remain open if we decide to requeue
-		// failed jobs in the future.
-		close(jobs)
-
-		// Let's print out any failure cases
-		failures := []dispatch.Result{}
-		for a := 1; a <= numJobs; a++ {
-			result := <-results
-			if !result.Success {
-				failures = append(failures, result)
+		if dispatchDryRun {
+			err := writeDryRunBundle(ctx, client, opts, succeeded)
+			if err != nil {
+				cliLogger.Error("Error collecting dry-run diffs", err)
 			}
+			checkTimeout(err)
 		}
 
 		if len(failures) > 0 {
@@ -132,9 +128,41 @@ var dispatchCmd = &cobra.Command{
 			}
 		}
 
+		// Jobs that were cut short by the --timeout are reported above, but the
+		// command should still fail so that CI doesn't treat them as done
+		checkTimeout(ctx.Err())
+
 	},
 }
 
+// dispatchRepos audits every repo in repos with the workflow in opts, using a
+// pool of conf.Dispatch.Workers workers, and returns the result of each
+func dispatchRepos(ctx context.Context, client *github.Client, opts dispatch.Options, repos []*github.Repository) []dispatch.Result {
+	numJobs := len(repos)
+	jobs := make(chan string, numJobs)
+	results := make(chan dispatch.Result, numJobs)
+
+	// Create a worker pool
+	for w := 1; w <= conf.Dispatch.Workers; w++ {
+		go dispatch.Worker(ctx, client, opts, w, jobs, results)
+	}
+
+	// Queue up all of the repos to be processed by the worker pool
+	for _, v := range repos {
+		jobs <- *v.Name
+	}
+
+	// TODO: the 'jobs' channel will need to remain open if we decide to requeue
+	// failed jobs in the future.
+	close(jobs)
+
+	out := make([]dispatch.Result, 0, numJobs)
+	for a := 1; a <= numJobs; a++ {
+		out = append(out, <-results)
+	}
+	return out
+}
+
 func init() {
 	rootCmd.AddCommand(dispatchCmd)
 
@@ -147,4 +175,243 @@ func init() {
 	dispatchCmd.Flags().StringP("batch-id", "i", "", "A unique identifier for the current batch of workflow runs (defaults to an autogenerated ULID)")
 	dispatchCmd.Flags().StringP("workflow", "n", "repair-repo-license.yml", "The workflow file name to be triggered")
 	dispatchCmd.Flags().String("github-org", "hashicorp", "Sets the target GitHub org who's repos you wish to audit")
+	dispatchCmd.Flags().Bool("respect-repo-config", false, "Skip repos whose .copywrite.hcl sets dispatch_opt_out or an upstream")
+	dispatchCmd.Flags().BoolVar(&dispatchDryRun, "dry-run", false, "Runs audits without opening pull requests, and collects the diffs they upload into a single bundle")
+	dispatchCmd.Flags().StringVar(&dispatchBundle, "bundle", "copywrite-dry-run.md", "Path to write the markdown bundle of diffs to when using --dry-run")
+	addTimeoutFlag(dispatchCmd)
+	addGitHubRepoFlag(dispatchCmd)
+}
+
+// writeDryRunBundle downloads the diffs uploaded by each dry-run workflow run,
+// whose results are named after the full name of the repo audited, and
+// combines them into a single markdown file at --bundle
+func writeDryRunBundle(ctx context.Context, client *github.Client, opts dispatch.Options, results []dispatch.Result) error {
+	repos := []dispatch.RepoDiffs{}
+	for _, r := range results {
+		diffs, err := dispatch.DownloadDiffs(ctx, client, opts, r.RunID)
+		if err != nil {
+			return fmt.Errorf("%v: %w", r.Name, err)
+		}
+		repos = append(repos, dispatch.RepoDiffs{
+			Repo:  r.Name,
+			Diffs: diffs,
+		})
+	}
+
+	f, err := os.Create(dispatchBundle)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(buildMetadataComment("<!-- ", " -->")); err != nil {
+		return err
+	}
+	if err := dispatch.WriteBundle(f, conf.Dispatch.BatchID, repos); err != nil {
+		return err
+	}
+	cliLogger.Info(fmt.Sprintf("Diffs from %v dry-run audits written to %v", len(repos), dispatchBundle))
+	return f.Close()
+}
+
+// loadDispatchFlags merges the flags shared by commands that audit an org's
+// repos into the dispatch config, generating a batch ID if none was given
+func loadDispatchFlags(cmd *cobra.Command) {
+	// Map command flags to config keys
+	mapping := map[string]string{
+		`batch-id`:     `dispatch.batch_id`,
+		`branch`:       `dispatch.branch`,
+		`max-attempts`: `dispatch.max_attempts`,
+		`sleep`:        `dispatch.sleep`,
+		`workers`:      `dispatch.workers`,
+		`workflow`:     `dispatch.workflow_file_name`,
+		`github-org`:   `dispatch.github_org_to_audit`,
+	}
+
+	// update the running config with any command-line flags
+	clobberWithDefaults := false
+	err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+	if err != nil {
+		cliLogger.Error("Error merging configuration", err)
+	}
+	cobra.CheckErr(err)
+
+	if len(conf.Dispatch.Orgs) > 0 && cmd.Flags().Changed("github-org") {
+		err := fmt.Errorf("--github-org can't be used when the dispatch config declares org blocks")
+		cliLogger.Error("Error validating flags", err)
+		cobra.CheckErr(err)
+	}
+	if err := validateDispatchOrgs(); err != nil {
+		cliLogger.Error("Error validating config", err)
+		cobra.CheckErr(err)
+	}
+
+	// Dynamically generate a batchID if none is supplied
+	if conf.Dispatch.BatchID == "" {
+		conf.Dispatch.BatchID = randstr.Hex(8) // 8-digit random string
+		cliLogger.Debug(fmt.Sprintf("Using auto-generated batchID: %s", conf.Dispatch.BatchID))
+	}
+}
+
+// auditOrgs returns the orgs to audit: those declared in dispatch org blocks,
+// if any, or else only dispatch.github_org_to_audit. Settings that an org
+// block leaves unset fall back to those of the dispatch block, and the
+// dispatch block's ignored repos apply to every org they belong to.
+func auditOrgs() []config.DispatchOrg {
+	if len(conf.Dispatch.Orgs) == 0 {
+		return []config.DispatchOrg{{
+			Name:             conf.Dispatch.GitHubOrgToAudit,
+			IgnoredRepos:     conf.Dispatch.IgnoredRepos,
+			WorkflowFileName: conf.Dispatch.WorkflowFileName,
+		}}
+	}
+
+	orgs := make([]config.DispatchOrg, 0, len(conf.Dispatch.Orgs))
+	for _, org := range conf.Dispatch.Orgs {
+		if org.WorkflowFileName == "" {
+			org.WorkflowFileName = conf.Dispatch.WorkflowFileName
+		}
+		ignored := lo.Filter(conf.Dispatch.IgnoredRepos, func(r string, i int) bool {
+			return strings.HasPrefix(strings.ToLower(r), strings.ToLower(org.Name)+"/")
+		})
+		org.IgnoredRepos = append(ignored, org.IgnoredRepos...)
+		orgs = append(orgs, org)
+	}
+	return orgs
+}
+
+// validateDispatchOrgs returns an error if the dispatch org blocks don't each
+// name a different org
+func validateDispatchOrgs() error {
+	seen := map[string]bool{}
+	for _, org := range conf.Dispatch.Orgs {
+		if org.Name == "" {
+			return fmt.Errorf("every dispatch org block must set a name")
+		}
+		if seen[strings.ToLower(org.Name)] {
+			return fmt.Errorf("dispatch org %q is declared more than once", org.Name)
+		}
+		seen[strings.ToLower(org.Name)] = true
+	}
+	return nil
+}
+
+// validateDispatchWorkflows returns an error if several orgs would be audited
+// with the same workflow. Workflows are only told the name of the repo to
+// audit, not its org, so each org needs its own.
+func validateDispatchWorkflows() error {
+	workflows := map[string]string{}
+	for _, org := range auditOrgs() {
+		if other, ok := workflows[org.WorkflowFileName]; ok {
+			return fmt.Errorf("dispatch orgs %q and %q are both audited with the %q workflow, which is only told the name of each repo; give each org its own workflow_file_name", other, org.Name, org.WorkflowFileName)
+		}
+		workflows[org.WorkflowFileName] = org.Name
+	}
+	return nil
+}
+
+// auditTargets returns every public, non-archived repo in org that matches the
+// dispatch filters, except for those on the org's ignore list and, if repo
+// configs are respected, those whose config opts out
+func auditTargets(ctx context.Context, client *github.Client, org config.DispatchOrg) ([]*github.Repository, error) {
+	// Retrieve all public GitHub repos for auditing
+	allRepos, err := fetchOrgRepos(ctx, org.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	targetRepos := repoFilter().Apply(allRepos)
+	cliLogger.Debug(fmt.Sprintf("%v of %v repos in %v match the dispatch filters", len(targetRepos), len(allRepos), org.Name))
+
+	if len(org.IgnoredRepos) > 0 {
+		gha.StartGroup("Exempting the following repos:")
+		for _, v := range org.IgnoredRepos {
+			cliLogger.Info(text.FgCyan.Sprint(v))
+		}
+		gha.EndGroup()
+
+		// Filter out any repos that are on the ignore list
+		targetRepos = lo.Filter(targetRepos, func(r *github.Repository, i int) bool {
+			fqn := fmt.Sprintf("%v/%v", org.Name, r.GetName())
+			return !lo.Contains(org.IgnoredRepos, fqn)
+		})
+	}
+
+	if conf.Dispatch.RespectRepoConfig {
+		targetRepos = lo.Filter(targetRepos, optOutFilter(ctx, client, org.Name, targetRepos))
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return targetRepos, nil
+}
+
+// fetchOrgRepos retrieves all public repos in org. GraphQL fetches them in far
+// fewer requests than REST, but is only available with credentials.
+func fetchOrgRepos(ctx context.Context, org string) ([]*github.Repository, error) {
+	if gh.DetectAuthMethod() != gh.AuthMethodUnauthenticated {
+		return repodata.GetReposGraphQL(ctx, org)
+	}
+	return repodata.GetRepos(ctx, org)
+}
+
+// repoFilter returns the filter for the repos to audit described by the
+// dispatch config. Archived repos are always excluded.
+func repoFilter() *repodata.Filter {
+	f := repodata.NewFilter().ExcludeArchived()
+	if conf.Dispatch.ExcludeForks {
+		f.ExcludeForks()
+	}
+	if conf.Dispatch.ExcludeTemplates {
+		f.ExcludeTemplates()
+	}
+	if conf.Dispatch.ExcludeEmpty {
+		f.ExcludeEmpty()
+	}
+	return f.Languages(conf.Dispatch.Languages...).MaxSize(conf.Dispatch.MaxRepoSize)
+}
+
+// optOutFilter fetches the config of every repo in org, using the dispatch worker
+// pool, and returns a filter that excludes the repos that opt out of audits.
+// Repos whose config can't be fetched or parsed are still audited.
+func optOutFilter(ctx context.Context, client *github.Client, org string, repos []*github.Repository) func(*github.Repository, int) bool {
+	names := lo.Map(repos, func(r *github.Repository, i int) string { return r.GetName() })
+
+	var mu sync.Mutex
+	reasons := map[string]string{}
+	job := func(ctx context.Context, repo string) error {
+		c, err := dispatch.RepoConfig(ctx, client, org, repo)
+		if err != nil {
+			return err
+		}
+		if reason := dispatch.OptOutReason(c); reason != "" {
+			mu.Lock()
+			reasons[repo] = reason
+			mu.Unlock()
+		}
+		return nil
+	}
+
+	logger := cliLogger.Named("repo-config")
+	for _, r := range dispatch.Pool(ctx, conf.Dispatch.Workers, logger, names, job) {
+		if !r.Success && ctx.Err() == nil {
+			cliLogger.Warn(fmt.Sprintf("Unable to read the config of %v/%v, auditing it anyway: %v", org, r.Name, r.Error))
+		}
+	}
+
+	if len(reasons) > 0 {
+		gha.StartGroup("Skipping the following repos based on their config:")
+		for _, name := range names {
+			if reason, ok := reasons[name]; ok {
+				cliLogger.Info(fmt.Sprintf("%v (%v)", text.FgCyan.Sprintf("%v/%v", org, name), reason))
+			}
+		}
+		gha.EndGroup()
+	}
+
+	return func(r *github.Repository, i int) bool {
+		_, skip := reasons[r.GetName()]
+		return !skip
+	}
 }
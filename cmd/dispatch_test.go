@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/hashicorp/copywrite/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_auditOrgs(t *testing.T) {
+	saved := conf.Dispatch
+	defer func() { conf.Dispatch = saved }()
+
+	conf.Dispatch = config.Dispatch{
+		GitHubOrgToAudit: "hashicorp",
+		IgnoredRepos:     []string{"hashicorp/copywrite", "hashicorp-forge/repo1"},
+		WorkflowFileName: "repair-repo-license.yml",
+	}
+	assert.Equal(t, []config.DispatchOrg{{
+		Name:             "hashicorp",
+		IgnoredRepos:     []string{"hashicorp/copywrite", "hashicorp-forge/repo1"},
+		WorkflowFileName: "repair-repo-license.yml",
+	}}, auditOrgs(), "Without org blocks, only github_org_to_audit is audited")
+
+	conf.Dispatch.Orgs = []config.DispatchOrg{
+		{Name: "hashicorp"},
+		{Name: "hashicorp-forge", IgnoredRepos: []string{"hashicorp-forge/repo2"}, WorkflowFileName: "repair-forge.yml"},
+	}
+	assert.Equal(t, []config.DispatchOrg{
+		{
+			Name:             "hashicorp",
+			IgnoredRepos:     []string{"hashicorp/copywrite"},
+			WorkflowFileName: "repair-repo-license.yml",
+		},
+		{
+			Name:             "hashicorp-forge",
+			IgnoredRepos:     []string{"hashicorp-forge/repo1", "hashicorp-forge/repo2"},
+			WorkflowFileName: "repair-forge.yml",
+		},
+	}, auditOrgs(), "Org blocks fall back to the dispatch block's settings")
+	assert.Nil(t, validateDispatchOrgs())
+	assert.Nil(t, validateDispatchWorkflows())
+
+	conf.Dispatch.Orgs[1].WorkflowFileName = ""
+	assert.ErrorContains(t, validateDispatchWorkflows(), "own workflow_file_name")
+
+	conf.Dispatch.Orgs[1].Name = "HashiCorp"
+	assert.ErrorContains(t, validateDispatchOrgs(), "more than once")
+
+	conf.Dispatch.Orgs[1].Name = ""
+	assert.ErrorContains(t, validateDispatchOrgs(), "must set a name")
+}
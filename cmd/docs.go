@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// Flag variables
+var docsDir string
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generates reference documentation for copywrite",
+	Long: `Generates reference documentation for every copywrite command, either as man
+pages for packaging or as markdown for publishing alongside the project.`,
+	// Run function is omitted, as this command exists only to house subcommands
+}
+
+var docsManCmd = &cobra.Command{
+	Use:   "man",
+	Short: "Generates man pages for every command",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		header := &doc.GenManHeader{
+			Section: "1",
+			Source:  "copywrite " + GetVersion(),
+			Manual:  "Copywrite Manual",
+		}
+		err := os.MkdirAll(docsDir, 0755)
+		if err == nil {
+			err = doc.GenManTree(rootCmd, header, docsDir)
+		}
+		if err != nil {
+			cliLogger.Error("Error generating man pages", err)
+		}
+		cobra.CheckErr(err)
+		cmd.Printf("Man pages written to: %s\n", docsDir)
+	},
+}
+
+var docsMarkdownCmd = &cobra.Command{
+	Use:   "markdown",
+	Short: "Generates markdown documentation for every command",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := os.MkdirAll(docsDir, 0755)
+		if err == nil {
+			err = doc.GenMarkdownTree(rootCmd, docsDir)
+		}
+		if err != nil {
+			cliLogger.Error("Error generating markdown documentation", err)
+		}
+		cobra.CheckErr(err)
+		cmd.Printf("Markdown documentation written to: %s\n", docsDir)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsManCmd)
+	docsCmd.AddCommand(docsMarkdownCmd)
+
+	// Generation dates would make every run produce different files
+	rootCmd.DisableAutoGenTag = true
+
+	docsCmd.PersistentFlags().StringVarP(&docsDir, "dir", "d", "docs", "Directory to write documentation to")
+}
@@ -0,0 +1,283 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/git"
+	"github.com/hashicorp/copywrite/github"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/mergestat/timediff"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var skipGitHub bool
+
+// doctorStatus is the outcome of a single diagnostic check
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is the result of a single diagnostic check, along with how to
+// remediate it if it did not pass
+type doctorCheck struct {
+	Name        string
+	Status      doctorStatus
+	Detail      string
+	Remediation string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks that the environment is set up correctly for copywrite",
+	Long: `Runs a series of diagnostic checks against the environment, suggesting how to
+fix any problems that are found:
+- git is installed, and the directory is part of a git repo
+- The config file is valid
+- The directory tree is writable
+- GitHub authentication works, and which credentials are in use
+- GitHub API rate limits have headroom remaining
+
+This goes further than "copywrite debug", which only prints information. The
+command fails if any check fails, while warnings are printed but do not fail.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := []doctorCheck{}
+		checks = append(checks, checkGit()...)
+		checks = append(checks, checkConfig())
+		checks = append(checks, checkWritable())
+		if skipGitHub {
+			checks = append(checks, doctorCheck{Name: "GitHub", Status: doctorWarn, Detail: "skipped with --skip-github"})
+		} else {
+			checks = append(checks, checkGitHub()...)
+		}
+
+		failed := 0
+		for _, c := range checks {
+			switch c.Status {
+			case doctorOK:
+				cmd.Printf("✔️ %s: %s\n", c.Name, c.Detail)
+			case doctorWarn:
+				cmd.Printf("%s %s: %s\n", text.FgYellow.Sprint("⚠️"), c.Name, c.Detail)
+			case doctorFail:
+				failed++
+				cmd.Printf("❌ %s: %s\n", c.Name, c.Detail)
+			}
+			if c.Remediation != "" && c.Status != doctorOK {
+				cmd.Printf("   %s\n", colorize(c.Remediation, text.Faint))
+			}
+		}
+
+		if failed > 0 {
+			cobra.CheckErr(fmt.Sprintf("%d checks failed", failed))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	// These flags are only locally relevant
+	doctorCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to run checks")
+	doctorCmd.Flags().BoolVar(&skipGitHub, "skip-github", false, "Skip checks that call the GitHub API")
+}
+
+// checkGit checks that git is installed and that the working directory is part
+// of a git repo, which features such as --since-tag rely on
+func checkGit() []doctorCheck {
+	version, err := git.Version()
+	if err != nil {
+		return []doctorCheck{{
+			Name:        "git",
+			Status:      doctorFail,
+			Detail:      err.Error(),
+			Remediation: "Install git and make sure it is on your PATH",
+		}}
+	}
+	checks := []doctorCheck{{Name: "git", Status: doctorOK, Detail: "version " + version}}
+
+	if _, err := git.FirstCommitDate("."); err != nil {
+		checks = append(checks, doctorCheck{
+			Name:        "git repo",
+			Status:      doctorWarn,
+			Detail:      "the directory is not part of a git repo with any commits",
+			Remediation: "Features based on git history (e.g., --since-tag and upstream) will not work",
+		})
+	} else {
+		checks = append(checks, doctorCheck{Name: "git repo", Status: doctorOK, Detail: "found"})
+	}
+	return checks
+}
+
+// checkConfig validates the values in the running config. Parse errors are
+// caught earlier, when the config file is loaded.
+func checkConfig() doctorCheck {
+	path := conf.GetConfigPath()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return doctorCheck{
+			Name:        "config",
+			Status:      doctorWarn,
+			Detail:      fmt.Sprintf("no config file found at %s, so defaults are used", path),
+			Remediation: `Run "copywrite init" to generate one`,
+		}
+	}
+
+	problems := []string{}
+	if conf.Project.License != "" && !addlicense.ValidSPDXExpression(conf.Project.License) {
+		problems = append(problems, fmt.Sprintf("project.license %q is not a valid SPDX expression", conf.Project.License))
+	}
+	if _, err := licensecheck.ParseYearStrategy(conf.Project.YearStrategy); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if conf.Project.HeaderSpacing != nil && *conf.Project.HeaderSpacing < 0 {
+		problems = append(problems, "project.header_spacing must not be negative")
+	}
+	if err := validateYAMLHeaderPosition(conf.Project.YAMLHeaderPosition); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := addlicense.ValidateCopyrightFormat(conf.Project.CopyrightFormat, conf.Project.CopyrightKeywords); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := validateSPDXOptions(); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := validateSubmodulesPolicy(conf.Project.Submodules); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := validatePublicDomainPolicy(conf.Project.PublicDomain); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := validateHeaderTemplate(); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := validateLicenseFileName(conf.Project.LicenseFileName); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if _, err := addlicense.ParseHeaderStyle(conf.Project.HeaderStyle); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := addlicense.ValidateHeaderBorder(conf.Project.HeaderBorder); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := addlicense.ValidatePatterns(conf.Project.HeaderIgnore); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := addlicense.ValidatePatterns(conf.Project.HeaderInclude); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := addlicense.ValidatePatterns(conf.Project.NeverTouch); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := addlicense.ValidateNeverTouchExceptions(conf.Project.NeverTouchExceptions); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+	if err := addlicense.ValidatePreserveAdjacent(conf.Project.PreserveAdjacent); err != nil {
+		problems = append(problems, "project."+err.Error())
+	}
+
+	if len(problems) > 0 {
+		return doctorCheck{
+			Name:        "config",
+			Status:      doctorFail,
+			Detail:      strings.Join(problems, "; "),
+			Remediation: fmt.Sprintf("Fix the listed keys in %s", path),
+		}
+	}
+	return doctorCheck{Name: "config", Status: doctorOK, Detail: path + " is valid"}
+}
+
+// checkWritable checks that files can be created in the working directory,
+// which commands that add headers or license files require
+func checkWritable() doctorCheck {
+	f, err := os.CreateTemp(".", ".copywrite-doctor-*")
+	if err != nil {
+		return doctorCheck{
+			Name:        "write permissions",
+			Status:      doctorFail,
+			Detail:      err.Error(),
+			Remediation: "Run copywrite as a user that can write to the directory, or only use --plan",
+		}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return doctorCheck{Name: "write permissions", Status: doctorOK, Detail: "the directory is writable"}
+}
+
+// checkGitHub checks which GitHub credentials are in use, whether they work,
+// and how much of the API rate limit remains
+func checkGitHub() []doctorCheck {
+	method := github.DetectAuthMethod()
+	if method == github.AuthMethodUnauthenticated {
+		return []doctorCheck{{
+			Name:        "GitHub auth",
+			Status:      doctorWarn,
+			Detail:      "no credentials found, so only public data is accessible and rate limits are low",
+			Remediation: "Set GITHUB_TOKEN or run \"gh auth login\"",
+		}}
+	}
+
+	ghc := github.NewGHClient().Raw()
+	checks := []doctorCheck{}
+
+	// GitHub App installations can't query the authenticated user
+	if method != github.AuthMethodApp {
+		user, resp, err := ghc.Users.Get(context.Background(), "")
+		if err != nil {
+			return []doctorCheck{{
+				Name:        "GitHub auth",
+				Status:      doctorFail,
+				Detail:      fmt.Sprintf("authenticating with %s failed: %v", method, err),
+				Remediation: "Check that the token is valid and has not expired",
+			}}
+		}
+
+		detail := fmt.Sprintf("authenticated as @%s using %s", user.GetLogin(), method)
+		if scopes := resp.Header.Get("X-OAuth-Scopes"); scopes != "" {
+			detail += fmt.Sprintf(" (scopes: %s)", scopes)
+		}
+		checks = append(checks, doctorCheck{Name: "GitHub auth", Status: doctorOK, Detail: detail})
+	} else {
+		checks = append(checks, doctorCheck{Name: "GitHub auth", Status: doctorOK, Detail: "using " + string(method)})
+	}
+
+	limits, _, err := ghc.RateLimits(context.Background())
+	if err != nil {
+		return append(checks, doctorCheck{
+			Name:        "GitHub rate limits",
+			Status:      doctorFail,
+			Detail:      err.Error(),
+			Remediation: "Check your network connection and GitHub credentials",
+		})
+	}
+
+	core := limits.GetCore()
+	detail := fmt.Sprintf("%d/%d requests remaining, resetting %s", core.Remaining, core.Limit, timediff.TimeDiff(core.Reset.Time))
+	if core.Remaining*10 < core.Limit {
+		return append(checks, doctorCheck{
+			Name:        "GitHub rate limits",
+			Status:      doctorWarn,
+			Detail:      detail,
+			Remediation: "Wait for the rate limit to reset before running commands that call the GitHub API",
+		})
+	}
+	return append(checks, doctorCheck{Name: "GitHub rate limits", Status: doctorOK, Detail: detail})
+}
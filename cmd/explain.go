@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/git"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <path>",
+	Short: "Explains why a file would or wouldn't be given a header",
+	Long: `Traces each decision "copywrite headers" makes about a single file, in the
+order it makes them, and reports which one decided whether the file would be
+modified:
+
+  - the built-in never-touch patterns and project.never_touch
+  - project.header_include and project.header_ignore
+  - git submodules (see project.submodules) and files unchanged from
+    project.upstream
+  - whether the file's extension has a known comment style (see
+    "copywrite filetypes")
+  - merge conflict markers
+  - an existing header, along with any third-party copyright holders it names
+  - the copywrite:ignore-file marker (see "copywrite markers")
+  - markers of generated code, and minified or bundled build artifacts
+
+The path is relative to the current directory, which is treated as the root of
+the project, as it is by "copywrite headers". Nothing is modified.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := projectRelativePath(args[0])
+		cobra.CheckErr(err)
+
+		fi, err := os.Stat(path)
+		cobra.CheckErr(err)
+		if fi.IsDir() {
+			cobra.CheckErr(fmt.Sprintf("%s is a directory; explain only works on a single file", path))
+		}
+		b, err := addlicense.ReadHead(path)
+		cobra.CheckErr(err)
+
+		opts := headerOptions()
+		skip, err := explainSkips()
+		if err != nil {
+			cliLogger.Error("Error loading filters", err)
+		}
+		cobra.CheckErr(err)
+		opts.Skip = skip
+
+		e, err := addlicense.Explain(path, b, conf.Project.HeaderIgnore, opts)
+		if err != nil {
+			cliLogger.Error("Error validating config", err)
+		}
+		cobra.CheckErr(err)
+
+		cmd.Printf("Explaining %s\n\n", path)
+		for _, s := range e.Steps {
+			mark := text.FgGreen.Sprint("✓")
+			if s.Decisive {
+				mark = text.FgYellow.Sprint("→")
+			}
+			cmd.Printf("  %s %s: %s\n", mark, s.Check, s.Result)
+		}
+		if e.Status.State == addlicense.HeaderPresent {
+			for _, s := range licensecheck.ParseCopyrightStatements(b) {
+				cmd.Printf("      copyright holder: %s%s\n", s.Holder, thirdPartyNote(s.Holder))
+			}
+		}
+		cmd.Printf("\n%s\n", explainOutcome(e.Status))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	// These flags are only locally relevant
+	explainCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the root of the project the file belongs to")
+}
+
+// projectRelativePath returns path relative to the current directory, in the
+// form that addlicense walks files in, so that patterns match it the same way
+func projectRelativePath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside of the project in %s", path, cwd)
+	}
+	return rel, nil
+}
+
+// explainSkips builds the skip function that "copywrite headers" applies on top
+// of config patterns by default: files in git submodules, unless
+// project.submodules is "check", and files unchanged from project.upstream
+func explainSkips() (func(path string) (bool, string), error) {
+	var skips []func(path string) (bool, string)
+
+	if conf.Project.Submodules != "check" {
+		submodules, err := git.Submodules(".")
+		if err != nil {
+			return nil, err
+		}
+		if len(submodules) > 0 {
+			skips = append(skips, func(path string) (bool, string) {
+				for _, s := range submodules {
+					if strings.HasPrefix(filepath.ToSlash(path), s+"/") {
+						return true, fmt.Sprintf("inside git submodule %s", s)
+					}
+				}
+				return false, ""
+			})
+		}
+	}
+
+	if conf.Project.Upstream != "" {
+		skip, err := upstreamFilter(conf.Project.Upstream)
+		if err != nil {
+			return nil, err
+		}
+		skips = append(skips, skip)
+	}
+
+	if len(skips) == 0 {
+		return nil, nil
+	}
+	return combineSkips(skips...), nil
+}
+
+// thirdPartyNote marks a copyright holder that isn't the project's own
+func thirdPartyNote(holder string) string {
+	if conf.Project.CopyrightHolder != "" && strings.Contains(strings.ToLower(holder), strings.ToLower(conf.Project.CopyrightHolder)) {
+		return ""
+	}
+	return " (third party)"
+}
+
+// explainOutcome summarizes what "copywrite headers" would do with a file
+func explainOutcome(s addlicense.Status) string {
+	switch s.State {
+	case addlicense.HeaderMissing:
+		return text.FgRed.Sprint("A header would be added to this file.")
+	case addlicense.HeaderPresent:
+		if conf.Project.HeaderSpacing != nil {
+			return "This file already has a header, so none would be added, though the blank lines after it may be adjusted to match project.header_spacing."
+		}
+		return "This file already has a header, so it would be left alone."
+	case addlicense.HeaderExempt:
+		return fmt.Sprintf("This file is exempt from needing a header (%s), so it would be left alone.", s.Reason)
+	case addlicense.HeaderIgnored:
+		return "This file is ignored, so it would be left alone."
+	}
+	return "This type of file doesn't support headers, so it would be left alone."
+}
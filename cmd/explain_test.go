@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_projectRelativePath(t *testing.T) {
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+
+	path, err := projectRelativePath("./a/../b/c.go")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("b", "c.go"), path)
+
+	path, err = projectRelativePath(filepath.Join(cwd, "b", "c.go"))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("b", "c.go"), path)
+
+	_, err = projectRelativePath(filepath.Dir(cwd))
+	assert.NotNil(t, err)
+}
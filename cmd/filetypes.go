@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+var filetypesCmd = &cobra.Command{
+	Use:   "filetypes",
+	Short: "Lists the types of files that headers can be added to",
+	Long: `Lists every file extension and name that copywrite knows how to add headers
+to, along with its language (as used by the --skip-lang and --only-lang flags)
+and the comment style its headers are written in.
+
+Files of any other type are left alone by "copywrite headers". Types are also
+reported as disabled if they are turned off by the current config, such as
+JSON files without project.json_comments, or if every file of the type would
+be excluded by project.header_ignore or project.header_include. The comment
+styles of some types depend on config too, such as assembly files with
+project.asm_comment.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		t := newTableWriter(cmd.OutOrStdout())
+		t.AppendHeader(table.Row{"File Type", "Language", "Comment Style", "Enabled", "Notes"})
+		for _, ft := range addlicense.FileTypes(conf.Project.HeaderIgnore, conf.Project.HeaderInclude) {
+			enabled := text.FgGreen.Sprint("yes")
+			notes := ft.Reason
+			if !ft.Enabled {
+				enabled = text.FgRed.Sprint("no")
+			}
+			if notes == "" && ft.Setting != "" {
+				notes = "see " + ft.Setting
+			}
+			t.AppendRow(table.Row{ft.Name, ft.Language, ft.Style.String(), enabled, notes})
+		}
+		t.Render()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(filetypesCmd)
+}
@@ -4,11 +4,21 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/copywrite/addlicense"
-	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/copywrite/git"
+	"github.com/hashicorp/copywrite/github"
+	"github.com/hashicorp/copywrite/github/actions"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
@@ -16,7 +26,17 @@ import (
 
 // Flag variables
 var (
-	plan bool
+	plan            bool
+	onlyAuthoredBy  []string
+	ignoreOlderThan int
+	parallelism     int
+	noSort          bool
+
+	allProjects        bool
+	skipNestedProjects bool
+	headersRef         string
+	failFast           bool
+	minCoverage        int
 )
 
 var headersCmd = &cobra.Command{
@@ -25,10 +45,16 @@ var headersCmd = &cobra.Command{
 	Long: `Recursively checks for all files in the given directory and subdirectories,
 adding copyright statements and license headers to any that are missing them.
 
-Autogenerated files and common file types that don't support headers (e.g., prose)
-will automatically be exempted. Any other files or folders should be added to the
-header_ignore list in your project's .copywrite.hcl config. For help adding a
-config, see the "copywrite init" command.`,
+Autogenerated files, minified or bundled build artifacts, and common file types
+that don't support headers (e.g., prose) will automatically be exempted. Any
+other files or folders should be added to the header_ignore list in your
+project's .copywrite.hcl config. For help adding a config, see the "copywrite
+init" command. Individual files can also opt out with a copywrite:ignore-file
+comment (see "copywrite markers").
+
+Every file that is updated is checked again in memory, and the command fails if
+a second run would add another header, as repeated runs in CI would otherwise
+keep stacking duplicate headers.`,
 	GroupID: "common", // Let's put this command in the common section of the help
 	PreRun: func(cmd *cobra.Command, args []string) {
 		// Change directory if needed
@@ -39,8 +65,11 @@ config, see the "copywrite init" command.`,
 
 		// Map command flags to config keys
 		mapping := map[string]string{
-			`spdx`:             `project.license`,
-			`copyright-holder`: `project.copyright_holder`,
+			`spdx`:                  `project.license`,
+			`copyright-holder`:      `project.copyright_holder`,
+			`include-spdx`:          `project.include_spdx`,
+			`spdx-only`:             `project.spdx_only`,
+			`license-templates-dir`: `project.license_templates_dir`,
 		}
 
 		// update the running config with any command-line flags
@@ -51,26 +80,424 @@ config, see the "copywrite init" command.`,
 		}
 		cobra.CheckErr(err)
 
-		// Input Validation
-		isValidSPDX := addlicense.ValidSPDX(conf.Project.License)
-		if conf.Project.License != "" && !isValidSPDX {
-			err := fmt.Errorf("invalid SPDX license identifier: %s", conf.Project.License)
-			cliLogger.Error("Error validating SPDX license", err)
+		cobra.CheckErr(validateHeaderConfig())
+		cobra.CheckErr(validateMinCoverage())
+		cobra.CheckErr(validateRolloutFlags())
+		cobra.CheckErr(validateLanguageFlags())
+
+		// Files in a git ref or checked in PR mode are never modified
+		if !plan && headersRef == "" && !prMode {
+			err := checkSafePath(".")
+			if err != nil {
+				cliLogger.Error("Error validating target directory", err)
+			}
 			cobra.CheckErr(err)
 		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		if plan {
-			cmd.Print(text.FgYellow.Sprint("Executing in dry-run mode. Rerun without the `--plan` flag to apply changes.\n\n"))
+		if allProjects {
+			cobra.CheckErr(runAllProjects(cmd, "all-projects", "dirPath"))
+			return
 		}
+		// Files in a git ref can only be checked, not changed, and PR mode
+		// only enforces headers
+		cobra.CheckErr(addHeaders(cmd, plan || headersRef != "" || prMode))
+	},
+}
 
-		if conf.Project.License == "" {
-			cmd.Printf("The --spdx flag was not specified, omitting SPDX license statements.\n\n")
-		} else {
-			cmd.Printf("Using license identifier: %s\n", conf.Project.License)
+func init() {
+	rootCmd.AddCommand(headersCmd)
+
+	// These flags are only locally relevant
+	headersCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to validate headers")
+	headersCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run, printing the names of all files missing headers")
+	headersCmd.Flags().StringSliceVar(&onlyAuthoredBy, "only-authored-by", nil, "Only process files originally committed by an author matching this substring (e.g., \"@hashicorp.com\")")
+	headersCmd.Flags().IntVar(&ignoreOlderThan, "ignore-older-than", 0, "Skip files whose most recent commit is older than the given year")
+	headersCmd.Flags().BoolVar(&noSort, "no-sort", false, "Print results as soon as each file is processed, rather than sorted by path once all files are done")
+	headersCmd.Flags().IntVar(&parallelism, "parallelism", 0, "Number of directories to read concurrently while discovering files (default is the number of CPUs)")
+	headersCmd.Flags().BoolVar(&allProjects, "all-projects", false, "Run separately for every project with its own .copywrite.hcl config in the directory tree, in parallel")
+	headersCmd.Flags().BoolVar(&skipNestedProjects, "skip-nested-projects", false, "Skip subdirectories that have their own .copywrite.hcl config")
+	cobra.CheckErr(headersCmd.Flags().MarkHidden("skip-nested-projects"))
+	headersCmd.Flags().BoolVar(&failFast, "fail-fast", false, "With --plan, stop at the first file found to be missing a header rather than reporting all of them")
+	headersCmd.Flags().IntVar(&minCoverage, "min-coverage", 0, "With --plan, only fail if fewer than this percentage of files have headers (e.g., 95)")
+	headersCmd.Flags().BoolVar(&prMode, "pr-mode", false, "In a GitHub Actions pull request workflow, only check the files the pull request changes, failing if added files are missing headers and warning about modified ones (implies --plan)")
+	headersCmd.Flags().StringVar(&headersRef, "ref", "", "Check the files in a git commit, branch, or tag rather than the working tree, without checking it out (implies --plan)")
+	headersCmd.Flags().IntVar(&maxChanges, "max-changes", 0, "Add headers to at most this many files, chosen in path order, so that large migrations can be split across several runs")
+	headersCmd.Flags().StringVar(&sample, "sample", "", "Only add headers to a percentage of files (e.g., \"5%\"), chosen by a hash of their paths so that the same files are chosen on every run")
+	addLanguageFlags(headersCmd)
+	addAllowUnsafePathsFlag(headersCmd)
+	headersCmd.MarkFlagsMutuallyExclusive("pr-mode", "ref")
+	headersCmd.MarkFlagsMutuallyExclusive("pr-mode", "all-projects")
+	headersCmd.MarkFlagsMutuallyExclusive("pr-mode", "min-coverage")
+	headersCmd.MarkFlagsMutuallyExclusive("pr-mode", "fail-fast")
+	for _, f := range []string{"plan", "ref", "pr-mode"} {
+		headersCmd.MarkFlagsMutuallyExclusive(f, "max-changes")
+		headersCmd.MarkFlagsMutuallyExclusive(f, "sample")
+	}
+
+	// These flags will get mapped to keys in the the global Config
+	headersCmd.Flags().StringP("spdx", "s", "", "SPDX-compliant license identifier or expression (e.g., 'MPL-2.0' or 'MIT OR Apache-2.0')")
+	cobra.CheckErr(headersCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
+	headersCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+	headersCmd.Flags().Bool("include-spdx", true, "Include an SPDX-License-Identifier line in headers")
+	headersCmd.Flags().Bool("spdx-only", false, "Only include an SPDX-License-Identifier line in headers, omitting the copyright statement")
+	headersCmd.Flags().String("license-templates-dir", "", "Directory of license header templates (e.g., \"LicenseRef-Acme.tmpl\") that take precedence over the built-in ones, used when project.header_template is \"license\"")
+}
+
+// validateHeaderConfig validates and normalizes the parts of the running config
+// used to generate headers
+func validateHeaderConfig() error {
+	// The license may be a single SPDX ID or a full expression, such as
+	// "MIT OR Apache-2.0", which is normalized before being written to headers
+	if conf.Project.License != "" {
+		normalized, err := addlicense.NormalizeSPDXExpression(conf.Project.License)
+		if err != nil {
+			cliLogger.Error("Error validating SPDX license", err)
+			return err
+		}
+		conf.Project.License = normalized
+	}
+
+	if err := validateSPDXOptions(); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if err := addlicense.ValidateCopyrightFormat(conf.Project.CopyrightFormat, conf.Project.CopyrightKeywords); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if err := validateHeaderTemplate(); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if conf.Project.HeaderSpacing != nil && *conf.Project.HeaderSpacing < 0 {
+		err := fmt.Errorf("invalid header_spacing %d: must not be negative", *conf.Project.HeaderSpacing)
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if err := addlicense.ValidatePreserveAdjacent(conf.Project.PreserveAdjacent); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if err := validateYAMLHeaderPosition(conf.Project.YAMLHeaderPosition); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if err := validateSubmodulesPolicy(conf.Project.Submodules); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if err := validatePublicDomainPolicy(conf.Project.PublicDomain); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+
+	if _, err := addlicense.ParseHeaderStyle(conf.Project.HeaderStyle); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+	if err := addlicense.ValidateHeaderBorder(conf.Project.HeaderBorder); err != nil {
+		cliLogger.Error("Error validating config", err)
+		return err
+	}
+	return nil
+}
+
+// validateSPDXOptions returns an error if the project.include_spdx and
+// project.spdx_only config keys conflict with each other or the license
+func validateSPDXOptions() error {
+	if !conf.Project.SPDXOnly {
+		return nil
+	}
+	if !includeSPDX() {
+		return fmt.Errorf("spdx_only and include_spdx = false are mutually exclusive")
+	}
+	if conf.Project.License == "" {
+		return fmt.Errorf("spdx_only requires a license to be set")
+	}
+	return nil
+}
+
+// validateHeaderTemplate returns an error if the project.header_template
+// config key is invalid or, when it selects license templates, there's no
+// template for the license
+func validateHeaderTemplate() error {
+	switch conf.Project.HeaderTemplate {
+	case "", "spdx":
+		return nil
+	case "license":
+	default:
+		return fmt.Errorf("invalid header_template %q: must be one of \"spdx\" or \"license\"", conf.Project.HeaderTemplate)
+	}
+	if conf.Project.License == "" {
+		return fmt.Errorf("header_template = \"license\" requires a license to be set")
+	}
+	if conf.Project.SPDXOnly {
+		return fmt.Errorf("header_template = \"license\" and spdx_only are mutually exclusive")
+	}
+	if _, err := addlicense.LicenseTemplate(conf.Project.License, conf.Project.LicenseTemplatesDir); err != nil {
+		return fmt.Errorf("header_template = \"license\": %w", err)
+	}
+	return nil
+}
+
+// includeSPDX reports whether headers should include an SPDX-License-Identifier
+// line, which they do unless disabled in config
+func includeSPDX() bool {
+	return conf.Project.IncludeSPDX == nil || *conf.Project.IncludeSPDX
+}
+
+// includeHidden reports whether hidden files and directories should be
+// processed, which they are unless project.include_hidden is false
+func includeHidden() bool {
+	return conf.Project.IncludeHidden == nil || *conf.Project.IncludeHidden
+}
+
+// headerOptions returns the addlicense options that follow from the project's
+// config, which every command that finds, reads, or writes headers must agree
+// on. Commands set any options specific to them (e.g., Stats) on the result.
+func headerOptions() addlicense.Options {
+	return addlicense.Options{
+		CopyrightKeywords:    conf.Project.CopyrightKeywords,
+		ConvertPublicDomain:  conf.Project.PublicDomain == "convert",
+		NeverTouch:           conf.Project.NeverTouch,
+		NeverTouchExceptions: conf.Project.NeverTouchExceptions,
+		Include:              conf.Project.HeaderInclude,
+		SkipHidden:           !includeHidden(),
+		Parallelism:          parallelism,
+		HeaderSpacing:        conf.Project.HeaderSpacing,
+		PreserveAdjacent:     conf.Project.PreserveAdjacent,
+		SyntaxAware:          conf.Project.SyntaxAware,
+		YAMLHeaderAtTop:      conf.Project.YAMLHeaderPosition == "top",
+		HeaderStyle:          addlicense.HeaderStyle(conf.Project.HeaderStyle),
+		HeaderBorder:         conf.Project.HeaderBorder,
+		OmitCopyright:        conf.Project.SPDXOnly,
+		CopyrightFormat:      conf.Project.CopyrightFormat,
+	}
+}
+
+// validateMinCoverage returns an error if the --min-coverage flag is out of
+// range or used with a flag that stops counting files early
+func validateMinCoverage() error {
+	if minCoverage < 0 || minCoverage > 100 {
+		return fmt.Errorf("invalid --min-coverage %d: must be a percentage between 0 and 100", minCoverage)
+	}
+	if minCoverage > 0 && failFast {
+		return fmt.Errorf("--min-coverage and --fail-fast are mutually exclusive, as coverage can't be computed once a run stops early")
+	}
+	return nil
+}
+
+// checkCoverage decides the outcome of a check-only run that found files
+// missing headers: if --min-coverage is set and enough files have headers,
+// the run passes anyway, letting legacy projects enforce headers gradually
+func checkCoverage(cmd *cobra.Command, stats addlicense.Stats, err error) error {
+	if minCoverage <= 0 || !errors.Is(err, addlicense.ErrMissingHeader) {
+		return err
+	}
+
+	percent := compliancePercent(stats)
+	if percent < minCoverage {
+		return fmt.Errorf("header coverage of %d%% is below the minimum of %d%%: %w", percent, minCoverage, err)
+	}
+	cmd.Printf("Header coverage of %d%% meets the minimum of %d%%\n", percent, minCoverage)
+	return nil
+}
+
+// validateYAMLHeaderPosition returns an error if pos is not a valid value for
+// the project.yaml_header_position config key
+func validateYAMLHeaderPosition(pos string) error {
+	switch pos {
+	case "", "document", "top":
+		return nil
+	}
+	return fmt.Errorf("invalid yaml_header_position %q: must be one of \"document\" or \"top\"", pos)
+}
+
+// validateSubmodulesPolicy returns an error if policy is not a valid value for
+// the project.submodules config key
+func validateSubmodulesPolicy(policy string) error {
+	switch policy {
+	case "", "skip", "check", "fail":
+		return nil
+	}
+	return fmt.Errorf("invalid submodules %q: must be one of \"skip\", \"check\", or \"fail\"", policy)
+}
+
+// validatePublicDomainPolicy returns an error if policy is not a valid value
+// for the project.public_domain config key
+func validatePublicDomainPolicy(policy string) error {
+	switch policy {
+	case "", "keep", "convert":
+		return nil
+	}
+	return fmt.Errorf("invalid public_domain %q: must be one of \"keep\" or \"convert\"", policy)
+}
+
+// submodulePatterns applies the project.submodules policy to the git
+// submodules checked out beneath dir, returning patterns (relative to dir)
+// matching those that must be skipped. Headers added to a submodule's files
+// can't be committed to the parent repo, and the code usually belongs to
+// someone else, so they are skipped by default.
+func submodulePatterns(cmd *cobra.Command, dir string) ([]string, error) {
+	if conf.Project.Submodules == "check" {
+		return nil, nil
+	}
+
+	submodules, err := git.Submodules(dir)
+	if err != nil || len(submodules) == 0 {
+		return nil, err
+	}
+
+	if conf.Project.Submodules == "fail" {
+		return nil, fmt.Errorf("found git submodules, which project.submodules forbids: %s", strings.Join(submodules, ", "))
+	}
+
+	patterns := make([]string, 0, len(submodules))
+	gha.StartGroup("Skipping the following git submodules:")
+	for _, s := range submodules {
+		cmd.Println(text.FgCyan.Sprint(s))
+		patterns = append(patterns, s+"/**")
+	}
+	gha.EndGroup()
+	return patterns, nil
+}
+
+// skipSubmodules adds the patterns from submodulePatterns for the current
+// directory to opts.NeverTouch. Every command that writes to files applies it,
+// so that none of them modify a submodule that the policy protects.
+func skipSubmodules(cmd *cobra.Command, opts *addlicense.Options) error {
+	if err := validateSubmodulesPolicy(conf.Project.Submodules); err != nil {
+		return err
+	}
+	patterns, err := submodulePatterns(cmd, ".")
+	if err != nil {
+		return err
+	}
+	opts.NeverTouch = append(slices.Clone(opts.NeverTouch), patterns...)
+	return nil
+}
+
+// addHeaders adds missing headers to every file in the current directory or, if
+// plan is set, only reports the files that are missing them
+func addHeaders(cmd *cobra.Command, plan bool) error {
+	if headersRef != "" {
+		infof(cmd, "Checking files at git ref %q rather than the working tree\n\n", headersRef)
+	} else if plan {
+		infof(cmd, "%s", text.FgYellow.Sprint("Executing in dry-run mode. Rerun without the `--plan` flag to apply changes.\n\n"))
+	}
+
+	if conf.Project.License == "" {
+		infof(cmd, "The --spdx flag was not specified, omitting SPDX license statements.\n\n")
+	} else if !includeSPDX() {
+		infof(cmd, "SPDX license statements are disabled by project.include_spdx, omitting them.\n\n")
+	} else {
+		infof(cmd, "Using license identifier: %s\n", conf.Project.License)
+	}
+	if conf.Project.SPDXOnly {
+		infof(cmd, "Omitting copyright statements, as project.spdx_only is set\n\n")
+	} else if conf.Project.HeaderTemplate == "license" {
+		infof(cmd, "Using the %s license header template with copyright holder: %v\n\n", conf.Project.License, conf.Project.CopyrightHolder)
+	} else {
+		infof(cmd, "Using copyright holder: %v\n\n", conf.Project.CopyrightHolder)
+	}
+
+	opts := headerOptions()
+	opts.NoSort = noSort
+	opts.FailFast = failFast
+	opts.Stats = &addlicense.Stats{}
+	if conf.Project.HeaderTemplate == "license" {
+		tpl, err := addlicense.LicenseTemplate(conf.Project.License, conf.Project.LicenseTemplatesDir)
+		if err != nil {
+			cliLogger.Error("Error reading license template", err)
+			return err
+		}
+		opts.HeaderTemplate = tpl
+		opts.TemplateEnv = conf.Project.HeaderTemplateEnv
+	}
+	if skipNestedProjects {
+		nested, err := nestedProjectPatterns()
+		if err != nil {
+			cliLogger.Error("Error discovering nested projects", err)
+			return err
+		}
+		opts.NeverTouch = append(slices.Clone(opts.NeverTouch), nested...)
+	}
+	if headersRef == "" {
+		if err := skipSubmodules(cmd, &opts); err != nil {
+			cliLogger.Error("Error applying the submodules policy", err)
+			return err
+		}
+	}
+	if len(onlyAuthoredBy) > 0 || ignoreOlderThan > 0 {
+		skip, err := historyFilter(onlyAuthoredBy, ignoreOlderThan)
+		if err != nil {
+			cliLogger.Error("Error reading git history", err)
+			return err
 		}
-		cmd.Printf("Using copyright holder: %v\n\n", conf.Project.CopyrightHolder)
+		opts.Skip = skip
+	}
+	opts.Skip = combineSkips(opts.Skip, languageFilter())
+	var changes prChanges
+	if prMode {
+		var err error
+		changes, err = loadPRChanges()
+		if err != nil {
+			cliLogger.Error("Error reading the pull request", err)
+			return err
+		}
+		infof(cmd, "Checking the %d added and %d modified files in the pull request\n\n", len(changes.Added), len(changes.Modified))
+		opts.Skip = combineSkips(opts.Skip, changes.skip)
+	}
+	if conf.Project.Upstream != "" {
+		infof(cmd, "Skipping files unchanged from upstream: %s\n\n", conf.Project.Upstream)
+		skip, err := upstreamFilter(conf.Project.Upstream)
+		if err != nil {
+			cliLogger.Error("Error reading upstream file list", err)
+			return err
+		}
+		opts.Skip = combineSkips(opts.Skip, skip)
+	}
 
+	// Keep track of files exempted by their contents (e.g., minified assets)
+	// so we can explain why they were left alone
+	var skippedMu sync.Mutex
+	skipped := map[string]string{}
+	opts.Skipped = func(path string, reason string) {
+		skippedMu.Lock()
+		defer skippedMu.Unlock()
+		skipped[path] = reason
+	}
+	// ...and of files missing headers, for the GitHub Actions job summary
+	missing := []string{}
+	opts.Changed = func(path string, existing bool) {
+		if existing {
+			return
+		}
+		skippedMu.Lock()
+		defer skippedMu.Unlock()
+		missing = append(missing, path)
+	}
+	// ...and of hidden directories, which are skipped without being listed
+	// file by file
+	hidden := []string{}
+	opts.SkippedHidden = func(dir string) {
+		skippedMu.Lock()
+		defer skippedMu.Unlock()
+		hidden = append(hidden, dir)
+	}
+
+	// The patterns in use are informational output
+	if !quiet && logFormat == "json" {
+		cliLogger.Info("Using search patterns", "header_ignore", conf.Project.HeaderIgnore, "header_include", conf.Project.HeaderInclude)
+	} else if !quiet {
 		if len(conf.Project.HeaderIgnore) == 0 {
 			cmd.Println("The project.header_ignore list was left empty in config. Processing all files by default.")
 		} else {
@@ -80,54 +507,283 @@ config, see the "copywrite init" command.`,
 			}
 			gha.EndGroup()
 		}
+		if len(conf.Project.HeaderInclude) > 0 {
+			gha.StartGroup("Only processing files matching the following search patterns:")
+			for _, v := range conf.Project.HeaderInclude {
+				cmd.Println(text.FgCyan.Sprint(v))
+			}
+			gha.EndGroup()
+		}
 		cmd.Println("")
+	}
+
+	// Construct the configuration addLicense needs to properly format headers
+	licenseData := addlicense.LicenseData{
+		Year:   "", // by default, we don't include a year in copyright statements
+		Holder: conf.Project.CopyrightHolder,
+		SPDXID: conf.Project.License,
+	}
+	if !includeSPDX() {
+		licenseData.SPDXID = ""
+	}
 
-		// Append default ignored search patterns (e.g., GitHub Actions workflows)
-		autoSkippedPatterns := []string{
-			".github/workflows/**",
-			".github/dependabot.yml",
-			"**/node_modules/**",
+	if maxChanges > 0 || sample != "" {
+		skip, err := rolloutFilter(cmd, licenseData, opts)
+		if err != nil {
+			cliLogger.Error("Error choosing files for a gradual rollout", err)
+			return err
 		}
-		ignoredPatterns := lo.Union(conf.Project.HeaderIgnore, autoSkippedPatterns)
+		opts.Skip = combineSkips(opts.Skip, skip)
+	}
+
+	verbose := true
+
+	// Wrap hclogger to use standard lib's log.Logger, printing the files
+	// addlicense reports as results
+	stdcliLogger := resultLogger(cmd)
 
-		// Construct the configuration addLicense needs to properly format headers
-		licenseData := addlicense.LicenseData{
-			Year:   "", // by default, we don't include a year in copyright statements
-			Holder: conf.Project.CopyrightHolder,
-			SPDXID: conf.Project.License,
+	// WARNING: because of the way we redirect cliLogger to os.Stdout (outside of
+	// JSON mode), anything prefixed with "[ERROR]" will not implicitly be
+	// written to stderr.
+	// However, we propagate errors upward from addlicense and then run a
+	// cobra.CheckErr on the return, which will indeed output to stderr and
+	// return a non-zero error code.
+
+	gha.StartGroup("The following files are missing headers:")
+	var err error
+	if headersRef != "" {
+		err = checkRefHeaders(headersRef, stdcliLogger, opts)
+	} else {
+		err = addlicense.Run(conf.Project.HeaderIgnore, "only", licenseData, "", verbose, plan, []string{"."}, stdcliLogger, opts)
+	}
+	gha.EndGroup()
+
+	if len(skipped) > 0 {
+		gha.StartGroup("The following files were skipped based on their contents:")
+		paths := lo.Keys(skipped)
+		sort.Strings(paths)
+		for _, path := range paths {
+			cmd.Printf("%s %s\n", path, text.FgCyan.Sprintf("(%s)", skipped[path]))
+		}
+		gha.EndGroup()
+		warnConflictedFiles(paths, skipped)
+	}
+	if len(hidden) > 0 {
+		gha.StartGroup("The following hidden directories were skipped, as project.include_hidden is false:")
+		sort.Strings(hidden)
+		for _, dir := range hidden {
+			cmd.Println(text.FgCyan.Sprint(dir))
 		}
+		gha.EndGroup()
+	}
+
+	printRunSummary(cmd, *opts.Stats, plan)
+	if prMode {
+		err = changes.enforce(cmd, missing, err)
+	}
+	if plan {
+		err = checkCoverage(cmd, *opts.Stats, err)
+	}
 
-		verbose := true
+	if gha.IsGHA() {
+		if err := gha.SetJobSummary(buildMetadataComment("<!-- ", " -->") + headersJobSummary(*opts.Stats, missing, plan)); err != nil {
+			cliLogger.Warn("Unable to write GitHub Actions job summary", "error", err)
+		}
+	}
+
+	return err
+}
 
-		// Wrap hclogger to use standard lib's log.Logger
-		stdcliLogger := cliLogger.StandardLogger(&hclog.StandardLoggerOptions{
-			// InferLevels must be true so that addLicense can set the log level via
-			// log prefix, e.g. logger.Println("[DEBUG] this is inferred as a debug log")
-			InferLevels: true,
+// warnConflictedFiles warns about every file skipped because it has merge
+// conflict markers, which usually means a merge was committed before being
+// resolved, and would otherwise go unnoticed among the other skipped files
+func warnConflictedFiles(paths []string, skipped map[string]string) {
+	for _, path := range paths {
+		if skipped[path] != addlicense.ConflictMarkersReason {
+			continue
+		}
+		cliLogger.Warn(fmt.Sprintf("Skipped %s, which has merge conflict markers. Resolve the conflict and run copywrite again.", path))
+		gha.Warning(actions.Annotation{
+			Title:   "Merge conflict markers",
+			Message: "This file has merge conflict markers, so copywrite left it alone. Resolve the conflict and run copywrite again.",
+			File:    path,
 		})
+	}
+}
 
-		// WARNING: because of the way we redirect cliLogger to os.Stdout, anything
-		// prefixed with "[ERROR]" will not implicitly be written to stderr.
-		// However, we propagate errors upward from addlicense and then run a
-		// cobra.CheckErr on the return, which will indeed output to stderr and
-		// return a non-zero error code.
+// skipCategoryLabels describe each category of skipped files in run summaries
+var skipCategoryLabels = map[addlicense.SkipCategory]string{
+	addlicense.SkipNeverTouched:     "Never touched",
+	addlicense.SkipHidden:           "Hidden",
+	addlicense.SkipNotIncluded:      "Not in header_include",
+	addlicense.SkipIgnorePattern:    "Matched header_ignore",
+	addlicense.SkipFiltered:         "Filtered (e.g., by flags)",
+	addlicense.SkipUnknownExtension: "Unknown file type",
+	addlicense.SkipConflictMarkers:  "Merge conflict markers",
+	addlicense.SkipIgnoreMarker:     "copywrite:ignore-file marker",
+	addlicense.SkipGenerated:        "Generated",
+	addlicense.SkipBuildArtifact:    "Minified or bundled",
+}
 
-		gha.StartGroup("The following files are missing headers:")
-		err := addlicense.Run(ignoredPatterns, "only", licenseData, "", verbose, plan, []string{"."}, stdcliLogger)
-		gha.EndGroup()
+// printRunSummary prints what a run of addlicense did and, when running in
+// GitHub Actions, exports each count as a step output for later steps to use
+func printRunSummary(cmd *cobra.Command, stats addlicense.Stats, plan bool) {
+	added := "Headers added"
+	if plan {
+		added = "Missing headers"
+	}
 
-		cobra.CheckErr(err)
-	},
+	summary := []struct {
+		label  string
+		output string
+		count  int
+	}{
+		{"Files scanned", "files_scanned", stats.Scanned},
+		{added, "files_added", stats.Added},
+		{"Headers updated", "files_updated", stats.Updated},
+		{"Skipped (ignored or third-party)", "files_ignored", stats.Ignored},
+		{"Skipped (generated or minified)", "files_exempted", stats.Exempted},
+	}
+	outputs := map[string]string{
+		"elapsed_seconds": fmt.Sprintf("%.2f", stats.Elapsed.Seconds()),
+	}
+
+	gha.StartGroup("Summary:")
+	for _, s := range summary {
+		cmd.Printf("%-34s %d\n", s.label+":", s.count)
+		outputs[s.output] = fmt.Sprint(s.count)
+	}
+	cmd.Printf("%-34s %s\n", "Elapsed time:", stats.Elapsed.Round(time.Millisecond))
+
+	// Break skipped files down by reason, so that a spike in one (e.g., from an
+	// overly broad ignore pattern) stands out
+	if len(stats.Skipped) > 0 {
+		cmd.Println("Skipped files by reason:")
+	}
+	for _, category := range addlicense.SkipCategories {
+		count := stats.Skipped[category]
+		if count > 0 {
+			cmd.Printf("  %-32s %d\n", skipCategoryLabels[category]+":", count)
+		}
+		outputs["skipped_"+string(category)] = fmt.Sprint(count)
+	}
+
+	if gha.IsGHA() {
+		for name, value := range outputs {
+			if err := gha.SetOutput(name, value); err != nil {
+				cliLogger.Warn(fmt.Sprintf("Unable to set GitHub Actions output %s", name), "error", err)
+			}
+		}
+	}
+	gha.EndGroup()
 }
 
-func init() {
-	rootCmd.AddCommand(headersCmd)
+// checkRefHeaders checks that every file in the tree of a git ref has a header,
+// reading them from git's object database rather than the working tree
+func checkRefHeaders(ref string, logger *log.Logger, opts addlicense.Options) error {
+	hashes, err := git.RefHashes(".", ref)
+	if err != nil {
+		return err
+	}
 
-	// These flags are only locally relevant
-	headersCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to validate headers")
-	headersCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run, printing the names of all files missing headers")
+	blobs, err := git.NewBlobReader(".")
+	if err != nil {
+		return err
+	}
+	defer blobs.Close()
 
-	// These flags will get mapped to keys in the the global Config
-	headersCmd.Flags().StringP("spdx", "s", "", "SPDX-compliant license identifier (e.g., 'MPL-2.0')")
-	headersCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+	read := func(path string) ([]byte, error) {
+		return blobs.Read(hashes[path])
+	}
+	return addlicense.CheckFiles(conf.Project.HeaderIgnore, lo.Keys(hashes), read, logger, opts)
+}
+
+// historyFilter builds a skip function for addlicense based on git history.
+// Files with no git history (e.g., new, uncommitted files) are never skipped.
+//
+// onlyAuthoredBy is a list of substrings matched against the "Name <email>" of
+// the author who introduced a file; files originally authored by anyone else
+// are skipped. ignoreOlderThan is a year; files that have not been modified
+// since before that year are skipped. Either may be left as a zero value.
+func historyFilter(onlyAuthoredBy []string, ignoreOlderThan int) (func(path string) (bool, string), error) {
+	history, err := git.History(".")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(path string) (bool, string) {
+		h, exists := history[filepath.ToSlash(path)]
+		if !exists {
+			return false, ""
+		}
+
+		if ignoreOlderThan > 0 && h.LastModified.Year() < ignoreOlderThan {
+			return true, fmt.Sprintf("last modified in %d", h.LastModified.Year())
+		}
+
+		if len(onlyAuthoredBy) > 0 {
+			matches := lo.ContainsBy(onlyAuthoredBy, func(a string) bool {
+				return strings.Contains(h.OriginalAuthor, a)
+			})
+			if !matches {
+				return true, fmt.Sprintf("originally authored by %s", h.OriginalAuthor)
+			}
+		}
+
+		return false, ""
+	}, nil
+}
+
+// upstreamFilter builds a skip function for addlicense that skips files which
+// are identical to a file at the same path in the upstream repo, so that forks
+// don't claim copyright over code they haven't changed. The upstream may be a
+// GitHub repo (e.g., "hashicorp/terraform") or the path to a local git clone.
+func upstreamFilter(upstream string) (func(path string) (bool, string), error) {
+	var hashes map[string]string
+	if fi, err := os.Stat(upstream); err == nil && fi.IsDir() {
+		hashes, err = git.TreeHashes(upstream, "HEAD")
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		owner, name, found := strings.Cut(upstream, "/")
+		if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid upstream %q: must be a GitHub repo (e.g., \"hashicorp/copywrite\") or a local directory", upstream)
+		}
+
+		client := github.NewGHClient().Raw()
+		hashes, err = github.GetRepoFileHashes(client, github.GHRepo{Owner: owner, Name: name})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(path string) (bool, string) {
+		hash, exists := hashes[filepath.ToSlash(path)]
+		if !exists {
+			return false, ""
+		}
+
+		local, err := git.FileBlobHash(path)
+		if err != nil || local != hash {
+			return false, ""
+		}
+		return true, fmt.Sprintf("unchanged from upstream %s", upstream)
+	}, nil
+}
+
+// combineSkips returns a skip function that skips a file if any of the given
+// (possibly nil) skip functions do
+func combineSkips(skips ...func(path string) (bool, string)) func(path string) (bool, string) {
+	return func(path string) (bool, string) {
+		for _, skip := range skips {
+			if skip == nil {
+				continue
+			}
+			if ok, reason := skip(path); ok {
+				return true, reason
+			}
+		}
+		return false, ""
+	}
 }
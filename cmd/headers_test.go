@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkCoverage(t *testing.T) {
+	otherErr := errors.New("permission denied")
+
+	tests := []struct {
+		name        string
+		minCoverage int
+		stats       addlicense.Stats
+		err         error
+		expectedErr string
+	}{
+		{
+			name:        "Missing headers fail without a minimum",
+			minCoverage: 0,
+			stats:       addlicense.Stats{Scanned: 100, Added: 1},
+			err:         addlicense.ErrMissingHeader,
+			expectedErr: addlicense.ErrMissingHeader.Error(),
+		},
+		{
+			name:        "Coverage at the minimum passes",
+			minCoverage: 95,
+			stats:       addlicense.Stats{Scanned: 100, Added: 5},
+			err:         addlicense.ErrMissingHeader,
+		},
+		{
+			name:        "Coverage below the minimum fails",
+			minCoverage: 95,
+			stats:       addlicense.Stats{Scanned: 100, Added: 6},
+			err:         addlicense.ErrMissingHeader,
+			expectedErr: "header coverage of 94% is below the minimum of 95%: missing license header",
+		},
+		{
+			name:        "Other errors are never forgiven",
+			minCoverage: 50,
+			stats:       addlicense.Stats{Scanned: 100},
+			err:         otherErr,
+			expectedErr: otherErr.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minCoverage = tt.minCoverage
+			defer func() { minCoverage = 0 }()
+
+			cmd := &cobra.Command{}
+			cmd.SetOut(&bytes.Buffer{})
+			err := checkCoverage(cmd, tt.stats, tt.err)
+			if tt.expectedErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func Test_submodulePatterns(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "third_party/lib/.git"), 0755))
+
+	tests := []struct {
+		policy           string
+		expectedPatterns []string
+		expectedErr      string
+	}{
+		{
+			policy:           "",
+			expectedPatterns: []string{"third_party/lib/**"},
+		},
+		{
+			policy:           "skip",
+			expectedPatterns: []string{"third_party/lib/**"},
+		},
+		{
+			policy: "check",
+		},
+		{
+			policy:      "fail",
+			expectedErr: "found git submodules, which project.submodules forbids: third_party/lib",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			conf.Project.Submodules = tt.policy
+			defer func() { conf.Project.Submodules = "" }()
+
+			cmd := &cobra.Command{}
+			cmd.SetOut(&bytes.Buffer{})
+			patterns, err := submodulePatterns(cmd, dir)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedPatterns, patterns)
+		})
+	}
+}
+
+func Test_headerOptions(t *testing.T) {
+	project := conf.Project
+	defer func() { conf.Project = project }()
+
+	hidden := false
+	conf.Project.NeverTouch = []string{"vendor/**"}
+	conf.Project.HeaderInclude = []string{"**/*.go"}
+	conf.Project.IncludeHidden = &hidden
+	conf.Project.PublicDomain = "convert"
+	conf.Project.YAMLHeaderPosition = "top"
+	conf.Project.HeaderStyle = "boxed"
+	conf.Project.HeaderBorder = "*"
+	conf.Project.SPDXOnly = true
+
+	opts := headerOptions()
+	assert.Equal(t, []string{"vendor/**"}, opts.NeverTouch)
+	assert.Equal(t, []string{"**/*.go"}, opts.Include)
+	assert.True(t, opts.SkipHidden)
+	assert.True(t, opts.ConvertPublicDomain)
+	assert.True(t, opts.YAMLHeaderAtTop)
+	assert.Equal(t, addlicense.HeaderStyle("boxed"), opts.HeaderStyle)
+	assert.Equal(t, "*", opts.HeaderBorder)
+	assert.True(t, opts.OmitCopyright)
+}
+
+func Test_addHeadersQuiet(t *testing.T) {
+	var logs bytes.Buffer
+	cliLogger = hclog.New(&hclog.LoggerOptions{
+		Level:  hclog.Error,
+		Output: &logs,
+	})
+	defer func() { cliLogger = hclog.NewNullLogger() }()
+
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.go"), []byte("// Copyright (c) Acme Inc.\n\npackage b\n"), 0644))
+
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(dir))
+	defer func() { assert.Nil(t, os.Chdir(cwd)) }()
+
+	quiet = true
+	defer func() { quiet = false }()
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	err = addHeaders(cmd, true)
+	assert.ErrorIs(t, err, addlicense.ErrMissingHeader)
+	assert.Contains(t, out.String(), "a.go\n", "Files missing headers are printed with --quiet")
+	assert.NotContains(t, out.String(), "b.go")
+	assert.NotContains(t, out.String(), "Executing in dry-run mode", "Informational output is omitted with --quiet")
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+var ignoreCmd = &cobra.Command{
+	Use:   "ignore",
+	Short: "Helps write the header_ignore patterns in config",
+	// Run function is omitted, as this command exists only to house subcommands
+}
+
+var ignoreTestCmd = &cobra.Command{
+	Use:   "test [pattern] [paths...]",
+	Short: "Tests which files an ignore pattern matches",
+	Long: `Evaluates a doublestar pattern against the files in the project, exactly as
+"copywrite headers" evaluates header_ignore patterns, and lists every file it
+matches. Any further arguments limit the search to the given files and
+directories, relative to the project root.
+
+If no pattern is given, each of the project.header_ignore patterns in config is
+tested instead, and the number of files each matches is reported.
+
+Warnings are printed for patterns that match no files, along with likely fixes
+for common mistakes, such as a leading "./" or a directory name without "/**".`,
+	Example: `  copywrite ignore test "**/*.pb.go"
+  copywrite ignore test "vendor/**" vendor/github.com
+  copywrite ignore test`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		patterns := conf.Project.HeaderIgnore
+		var paths []string
+		if len(args) > 0 {
+			patterns, paths = args[:1], args[1:]
+		}
+		if len(patterns) == 0 {
+			cmd.Println("The project.header_ignore list was left empty in config, so there are no patterns to test.")
+			return
+		}
+
+		err := addlicense.ValidatePatterns(patterns)
+		if err != nil {
+			cliLogger.Error("Error validating patterns", err)
+		}
+		cobra.CheckErr(err)
+
+		files, err := projectFiles(paths)
+		cobra.CheckErr(err)
+		matches := matchIgnorePatterns(patterns, files)
+
+		if len(args) > 0 {
+			pattern := patterns[0]
+			gha.StartGroup(fmt.Sprintf("Pattern %q matches %d of %d files:", pattern, len(matches[pattern]), len(files)))
+			for _, path := range matches[pattern] {
+				cmd.Println(text.FgCyan.Sprint(path))
+			}
+			gha.EndGroup()
+		} else {
+			t := newTableWriter(cmd.OutOrStdout())
+			t.AppendHeader(table.Row{"Pattern", "Files Matched"})
+			for _, pattern := range patterns {
+				t.AppendRow(table.Row{pattern, len(matches[pattern])})
+			}
+			t.Render()
+		}
+
+		for _, pattern := range patterns {
+			if len(matches[pattern]) > 0 {
+				continue
+			}
+			msg := fmt.Sprintf("Pattern %q matches no files", pattern)
+			if hints := ignorePatternHints(pattern); len(hints) > 0 {
+				msg += ": " + strings.Join(hints, "; ")
+			}
+			cliLogger.Warn(msg)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ignoreCmd)
+	ignoreCmd.AddCommand(ignoreTestCmd)
+
+	// These flags are only locally relevant
+	ignoreTestCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the root of the project to test patterns against")
+}
+
+// projectFiles returns every file beneath the given paths, or the current
+// directory if none are given, as paths relative to it in the form that
+// addlicense matches patterns against. Git's own files are left out.
+func projectFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files := []string{}
+	for _, p := range paths {
+		err := filepath.WalkDir(filepath.Clean(p), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// matchIgnorePatterns returns the files matched by each pattern
+func matchIgnorePatterns(patterns []string, files []string) map[string][]string {
+	matches := map[string][]string{}
+	for _, pattern := range patterns {
+		for _, path := range files {
+			if addlicense.FileMatches(path, []string{pattern}) {
+				matches[pattern] = append(matches[pattern], path)
+			}
+		}
+	}
+	return matches
+}
+
+// ignorePatternHints suggests fixes for common mistakes that keep a pattern
+// from matching any files
+func ignorePatternHints(pattern string) []string {
+	hints := []string{}
+	if trimmed, ok := strings.CutPrefix(pattern, "./"); ok {
+		hints = append(hints, fmt.Sprintf("paths don't start with \"./\", so try %q", trimmed))
+	} else if strings.HasPrefix(pattern, "/") {
+		hints = append(hints, fmt.Sprintf("paths are relative to the project root, so try %q", strings.TrimLeft(pattern, "/")))
+	}
+	if strings.Contains(pattern, `\`) {
+		hints = append(hints, `paths are always separated by "/", even on Windows`)
+	}
+	if strings.HasSuffix(pattern, "/") {
+		hints = append(hints, fmt.Sprintf("patterns match files, not directories, so try %q", pattern+"**"))
+	} else if fi, err := os.Stat(pattern); err == nil && fi.IsDir() {
+		hints = append(hints, fmt.Sprintf("%s is a directory, and patterns match files, so try %q", pattern, pattern+"/**"))
+	}
+	return hints
+}
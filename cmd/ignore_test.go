@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_matchIgnorePatterns(t *testing.T) {
+	files := []string{"a.go", "gen/b.pb.go", "vendor/c.go", "vendor/d/e.go"}
+	matches := matchIgnorePatterns([]string{"vendor/**", "**/*.pb.go", "vendor", "./vendor/**"}, files)
+
+	assert.Equal(t, []string{"vendor/c.go", "vendor/d/e.go"}, matches["vendor/**"])
+	assert.Equal(t, []string{"gen/b.pb.go"}, matches["**/*.pb.go"])
+	assert.Empty(t, matches["vendor"])
+	assert.Empty(t, matches["./vendor/**"])
+}
+
+func Test_ignorePatternHints(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{pattern: "vendor/**", expected: []string{}},
+		{pattern: "./vendor/**", expected: []string{`paths don't start with "./", so try "vendor/**"`}},
+		{pattern: "/vendor/**", expected: []string{`paths are relative to the project root, so try "vendor/**"`}},
+		{pattern: "vendor/", expected: []string{`patterns match files, not directories, so try "vendor/**"`}},
+		{pattern: `vendor\**`, expected: []string{`paths are always separated by "/", even on Windows`}},
+	}
+
+	dir, err := os.MkdirTemp(".", "ignore-test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	dir = filepath.Base(dir)
+	tests = append(tests, struct {
+		pattern  string
+		expected []string
+	}{pattern: dir, expected: []string{dir + ` is a directory, and patterns match files, so try "` + dir + `/**"`}})
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ignorePatternHints(tt.pattern))
+		})
+	}
+}
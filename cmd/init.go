@@ -8,7 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -36,7 +39,10 @@ var initCmd = &cobra.Command{
 
 License type and copyright year are inferred from GitHub, and prompts are made
 for any unknown values. If you are running this command in CI, please use the
---year and --spdx flags, as prompts are disabled when no TTY is present.`,
+--year and --spdx flags, as prompts are disabled when no TTY is present.
+
+Vendored directories and concentrations of generated files found in the repo are
+suggested as commented-out header_ignore entries for you to review.`,
 	GroupID: "common", // Let's put this command in the common section of the help
 	PreRun: func(cmd *cobra.Command, args []string) {
 		// Validate we aren't going to write over an existing config
@@ -48,9 +54,9 @@ for any unknown values. If you are running this command in CI, please use the
 		// Input Validation
 		spdx, err := cmd.Flags().GetString("spdx")
 		cobra.CheckErr(err)
-		// SPDX flag must either be an empty string _or_ a valid SPDX list option
-		if spdx != "" && !addlicense.ValidSPDX(spdx) {
-			err := fmt.Errorf("invalid SPDX license identifier: %s", spdx)
+		// SPDX flag must either be an empty string _or_ a valid SPDX ID or expression
+		if spdx != "" && !addlicense.ValidSPDXExpression(spdx) {
+			err := fmt.Errorf("invalid SPDX license identifier or expression: %s", spdx)
 			cobra.CheckErr(err)
 		}
 	},
@@ -72,7 +78,7 @@ for any unknown values. If you are running this command in CI, please use the
 		cobra.CheckErr(err)
 
 		// Try to autodiscover license and year
-		if repo, err := github.DiscoverRepo(); err == nil {
+		if repo, err := discoverRepo(); err == nil {
 			client := github.NewGHClient().Raw()
 			data, _, err := client.Repositories.Get(context.Background(), repo.Owner, repo.Name)
 			if err == nil {
@@ -98,12 +104,25 @@ for any unknown values. If you are running this command in CI, please use the
 			cmd.Println("No TTY detected: if running in CI, use `--year` and `--spdx` flags to set values as needed")
 		}
 
+		// Look for vendored and generated code that probably shouldn't get headers
+		suggestions, err := suggestHeaderIgnores(".")
+		if err != nil {
+			cliLogger.Debug(fmt.Sprintf("Unable to analyze the repo for header_ignore suggestions: %v", err))
+		}
+		if len(suggestions) > 0 {
+			cmd.Println("The following files look vendored or generated, and have been suggested for header_ignore:")
+			for _, s := range suggestions {
+				cmd.Printf("  %s %s\n", text.FgCyan.Sprint(s.Pattern), colorize(fmt.Sprintf("(%s)", s.Reason), text.Faint))
+			}
+			cmd.Println("Review them and uncomment any you wish to ignore in the generated config.")
+		}
+
 		// Render it out!
 		f, err := os.Create(".copywrite.hcl")
 		cobra.CheckErr(err)
 		defer f.Close()
 
-		err = configToHCL(*newConfig, f)
+		err = configToHCL(*newConfig, suggestions, f)
 		cobra.CheckErr(err)
 
 		successText := text.Color(text.FgGreen).Sprintf("✔️ A config has been successfully generated at: ./%s", f.Name())
@@ -120,15 +139,18 @@ func init() {
 	// These flags will get mapped to keys in the the global Config
 	initCmd.Flags().IntP("year", "y", 0, "Year that the copyright statement should include")
 	initCmd.Flags().StringP("spdx", "s", "", "SPDX License Identifier indicating what the project should be licensed under")
+	addGitHubRepoFlag(initCmd)
+	cobra.CheckErr(initCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
 }
 
 // configToHCL takes in a Config object and writes an example HCL configuration,
 // filling in the `project.license` and `project.copyright_year` keys, along
-// with helpful comments. Any io.Writer interface is accepted, be it stdout
+// with helpful comments. Any header_ignore suggestions are included as
+// commented-out entries. Any io.Writer interface is accepted, be it stdout
 // or a file writer.
 //
 // Config keys other than license and copyright year are currently unsupported.
-func configToHCL(c config.Config, wr io.Writer) error {
+func configToHCL(c config.Config, suggestions []ignoreSuggestion, wr io.Writer) error {
 	tmpl, err := template.New(".copywrite.hcl").Parse(`schema_version = {{.SchemaVersion}}
 
 project {
@@ -139,8 +161,15 @@ project {
   # Supports doublestar glob patterns for more flexibility in defining which
   # files or folders should be ignored
   header_ignore = [
+{{- if .Suggestions}}
+    # Suggested from an analysis of the repo; uncomment any you wish to ignore
+{{- range .Suggestions}}
+    # "{{.Pattern}}", # {{.Reason}}
+{{- end}}
+{{- else}}
     # "vendor/**",
     # "**autogen**",
+{{- end}}
   ]
 }
 `)
@@ -148,7 +177,14 @@ project {
 		return err
 	}
 
-	err = tmpl.Execute(wr, c)
+	data := struct {
+		config.Config
+		Suggestions []ignoreSuggestion
+	}{c, suggestions}
+	if _, err := io.WriteString(wr, buildMetadataComment("# ", "")); err != nil {
+		return err
+	}
+	err = tmpl.Execute(wr, data)
 	if err != nil {
 		return err
 	}
@@ -229,3 +265,106 @@ func promptForConfigValues(c *config.Config) error {
 
 	return nil
 }
+
+// ignoreSuggestion is a header_ignore pattern suggested by `copywrite init`,
+// along with why it was suggested
+type ignoreSuggestion struct {
+	Pattern string
+	Reason  string
+}
+
+// vendoredDirs are the names of directories that typically hold vendored
+// dependencies or build output rather than a project's own source code.
+// node_modules is omitted, as it is never processed to begin with.
+var vendoredDirs = map[string]string{
+	"vendor":      "vendored dependencies",
+	"third_party": "third-party code",
+	"third-party": "third-party code",
+	"dist":        "build output",
+}
+
+// generatedFilePatterns match the names of files that are typically generated
+var generatedFilePatterns = []string{
+	"*.pb.go",
+	"*.pb.gw.go",
+	"*_pb2.py",
+	"*_pb2_grpc.py",
+	"zz_generated*.go",
+}
+
+// minGeneratedFiles is how many files must match a generated file pattern for
+// it to be suggested, so that one-off matches don't clutter the config
+const minGeneratedFiles = 5
+
+// suggestHeaderIgnores analyzes the tree under root for vendored directories
+// and concentrations of generated files, and returns header_ignore patterns
+// for them, sorted by pattern. Only files that copywrite would otherwise add
+// headers to are counted.
+func suggestHeaderIgnores(root string) ([]ignoreSuggestion, error) {
+	vendored := map[string]int{}
+	generated := map[string]int{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := addlicense.CommentStyleFor(path); !ok {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Attribute files to the outermost vendored directory containing them
+		dirs := strings.Split(rel, "/")
+		for i, dir := range dirs[:len(dirs)-1] {
+			if _, ok := vendoredDirs[dir]; ok {
+				vendored[strings.Join(dirs[:i+1], "/")]++
+				return nil
+			}
+		}
+
+		for _, pattern := range generatedFilePatterns {
+			if ok, _ := filepath.Match(pattern, d.Name()); ok {
+				generated[pattern]++
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []ignoreSuggestion{}
+	for dir, n := range vendored {
+		reason := vendoredDirs[dir[strings.LastIndex(dir, "/")+1:]]
+		suggestions = append(suggestions, ignoreSuggestion{
+			Pattern: dir + "/**",
+			Reason:  fmt.Sprintf("%s, %d files", reason, n),
+		})
+	}
+	for pattern, n := range generated {
+		if n < minGeneratedFiles {
+			continue
+		}
+		suggestions = append(suggestions, ignoreSuggestion{
+			Pattern: "**/" + pattern,
+			Reason:  fmt.Sprintf("generated code, %d files", n),
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Pattern < suggestions[j].Pattern
+	})
+	return suggestions, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/copywrite/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_suggestHeaderIgnores(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"main.go",
+		"vendor/github.com/acme/lib/lib.go",
+		"vendor/github.com/acme/lib/third_party/x.go",
+		"vendor/modules.txt",
+		"ui/dist/app.js",
+		"ui/dist/app.css",
+		"node_modules/dep/index.js",
+		"proto/a.pb.go",
+		"proto/b.pb.go",
+		"proto/c.pb.go",
+		"proto/d.pb.go",
+		"proto/e.pb.go",
+		"api/zz_generated.deepcopy.go",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte("content"), 0644))
+	}
+
+	actual, err := suggestHeaderIgnores(root)
+	assert.Nil(t, err)
+
+	expected := []ignoreSuggestion{
+		{Pattern: "**/*.pb.go", Reason: "generated code, 5 files"},
+		{Pattern: "ui/dist/**", Reason: "build output, 2 files"},
+		{Pattern: "vendor/**", Reason: "vendored dependencies, 2 files"},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func Test_configToHCL(t *testing.T) {
+	c := config.Config{SchemaVersion: 1}
+	c.Project.License = "MPL-2.0"
+	c.Project.CopyrightYear = 2023
+
+	var withoutSuggestions strings.Builder
+	assert.Nil(t, configToHCL(c, nil, &withoutSuggestions))
+	assert.Contains(t, withoutSuggestions.String(), `license        = "MPL-2.0"`)
+	assert.Contains(t, withoutSuggestions.String(), `# "vendor/**",`, "Examples are given when there are no suggestions")
+
+	var withSuggestions strings.Builder
+	suggestions := []ignoreSuggestion{{Pattern: "third_party/**", Reason: "third-party code, 3 files"}}
+	assert.Nil(t, configToHCL(c, suggestions, &withSuggestions))
+	assert.Contains(t, withSuggestions.String(), `    # "third_party/**", # third-party code, 3 files`)
+	assert.NotContains(t, withSuggestions.String(), `# "vendor/**",`)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+)
+
+// compliancePercent returns the percentage of scanned files that had a header
+// before a run, rounded down so that 100% is only shown when every file did
+func compliancePercent(stats addlicense.Stats) int {
+	if stats.Scanned == 0 {
+		return 100
+	}
+	return (stats.Scanned - stats.Added) * 100 / stats.Scanned
+}
+
+// complianceColor returns the shields.io color for a compliance percentage
+func complianceColor(percent int) string {
+	switch {
+	case percent >= 100:
+		return "brightgreen"
+	case percent >= 90:
+		return "green"
+	case percent >= 75:
+		return "yellow"
+	case percent >= 50:
+		return "orange"
+	}
+	return "red"
+}
+
+// headersJobSummary renders a markdown summary of a run of the headers command
+// for a GitHub Actions job summary: a compliance badge, followed by a
+// collapsible table of the files that were missing headers (or had them
+// added), grouped by directory
+func headersJobSummary(stats addlicense.Stats, changed []string, plan bool) string {
+	percent := compliancePercent(stats)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Copyright headers\n\n")
+	fmt.Fprintf(&b, "![Header compliance](https://img.shields.io/badge/%s-%s-%s)\n\n",
+		url.PathEscape("header compliance"), url.PathEscape(fmt.Sprintf("%d%%", percent)), complianceColor(percent))
+
+	verb := "had headers added"
+	if plan {
+		verb = "are missing headers"
+	}
+	fmt.Fprintf(&b, "%d of %d files scanned %s.\n", len(changed), stats.Scanned, verb)
+	if len(changed) == 0 {
+		return b.String()
+	}
+
+	dirs := map[string][]string{}
+	for _, p := range changed {
+		dir, file := path.Split(filepath.ToSlash(p))
+		dir = strings.TrimSuffix(dir, "/")
+		if dir == "" {
+			dir = "."
+		}
+		dirs[dir] = append(dirs[dir], file)
+	}
+	names := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		names = append(names, dir)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(&b, "\n<details>\n<summary>Files by directory (%d directories)</summary>\n\n", len(names))
+	fmt.Fprintf(&b, "| Directory | Files | Names |\n| --- | ---: | --- |\n")
+	for _, dir := range names {
+		files := dirs[dir]
+		sort.Strings(files)
+		for i, f := range files {
+			files[i] = "`" + f + "`"
+		}
+		fmt.Fprintf(&b, "| `%s` | %d | %s |\n", dir, len(files), strings.Join(files, ", "))
+	}
+	fmt.Fprintf(&b, "\n</details>\n")
+	return b.String()
+}
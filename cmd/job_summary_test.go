@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_headersJobSummary(t *testing.T) {
+	tests := []struct {
+		name           string
+		stats          addlicense.Stats
+		changed        []string
+		plan           bool
+		expectedOutput string
+	}{
+		{
+			name:  "Compliant repos only show a badge",
+			stats: addlicense.Stats{Scanned: 10},
+			plan:  true,
+			expectedOutput: "## Copyright headers\n\n" +
+				"![Header compliance](https://img.shields.io/badge/header%20compliance-100%25-brightgreen)\n\n" +
+				"0 of 10 files scanned are missing headers.\n",
+		},
+		{
+			name:    "Files missing headers are grouped by directory",
+			stats:   addlicense.Stats{Scanned: 8, Added: 3},
+			changed: []string{"b/z.go", "main.go", "b/a.go"},
+			plan:    true,
+			expectedOutput: "## Copyright headers\n\n" +
+				"![Header compliance](https://img.shields.io/badge/header%20compliance-62%25-orange)\n\n" +
+				"3 of 8 files scanned are missing headers.\n\n" +
+				"<details>\n<summary>Files by directory (2 directories)</summary>\n\n" +
+				"| Directory | Files | Names |\n| --- | ---: | --- |\n" +
+				"| `.` | 1 | `main.go` |\n" +
+				"| `b` | 2 | `a.go`, `z.go` |\n" +
+				"\n</details>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, headersJobSummary(tt.stats, tt.changed, tt.plan))
+		})
+	}
+}
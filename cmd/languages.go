@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	skipLangs []string
+	onlyLangs []string
+)
+
+// addLanguageFlags registers the --skip-lang and --only-lang flags for a
+// command, which must then call validateLanguageFlags and apply languageFilter
+func addLanguageFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&skipLangs, "skip-lang", nil, "Skip files in these languages (e.g., \"go,python\"); see \"copywrite filetypes\" for the file types in each")
+	cmd.Flags().StringSliceVar(&onlyLangs, "only-lang", nil, "Only process files in these languages (e.g., \"go\"); see \"copywrite filetypes\" for the file types in each")
+	cmd.MarkFlagsMutuallyExclusive("skip-lang", "only-lang")
+	cobra.CheckErr(cmd.RegisterFlagCompletionFunc("skip-lang", completeLanguages))
+	cobra.CheckErr(cmd.RegisterFlagCompletionFunc("only-lang", completeLanguages))
+}
+
+// completeLanguages completes the names of languages for --skip-lang and
+// --only-lang
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return addlicense.LanguageNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// validateLanguageFlags returns an error if --skip-lang or --only-lang name a
+// language that copywrite doesn't know about
+func validateLanguageFlags() error {
+	if err := addlicense.ValidateLanguages(skipLangs); err != nil {
+		return fmt.Errorf("invalid --skip-lang: %w", err)
+	}
+	if err := addlicense.ValidateLanguages(onlyLangs); err != nil {
+		return fmt.Errorf("invalid --only-lang: %w", err)
+	}
+	return nil
+}
+
+// languageFilter returns a skip function for addlicense that skips files in a
+// language given to --skip-lang, or not in one given to --only-lang, or nil if
+// neither flag was given
+func languageFilter() func(path string) (bool, string) {
+	skip := lowerAll(skipLangs)
+	only := lowerAll(onlyLangs)
+	if len(skip) == 0 && len(only) == 0 {
+		return nil
+	}
+
+	return func(path string) (bool, string) {
+		lang := addlicense.LanguageOf(path)
+		if len(skip) > 0 && lang != "" && slices.Contains(skip, lang) {
+			return true, fmt.Sprintf("%s files skipped by --skip-lang", lang)
+		}
+		if len(only) > 0 && !slices.Contains(only, lang) {
+			return true, "language not given to --only-lang"
+		}
+		return false, ""
+	}
+}
+
+// lowerAll returns a copy of s with every element lowercased
+func lowerAll(s []string) []string {
+	out := make([]string, 0, len(s))
+	for _, v := range s {
+		out = append(out, strings.ToLower(v))
+	}
+	return out
+}
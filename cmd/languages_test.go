@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_languageFilter(t *testing.T) {
+	defer func() { skipLangs, onlyLangs = nil, nil }()
+
+	skipLangs, onlyLangs = nil, nil
+	assert.Nil(t, languageFilter())
+
+	skipLangs = []string{"Go", "python"}
+	skip := languageFilter()
+	skipped, reason := skip("cmd/main.go")
+	assert.True(t, skipped)
+	assert.Equal(t, "go files skipped by --skip-lang", reason)
+	skipped, _ = skip("scripts/build.py")
+	assert.True(t, skipped)
+	skipped, _ = skip("main.tf")
+	assert.False(t, skipped)
+	skipped, _ = skip("README.md")
+	assert.False(t, skipped)
+
+	skipLangs, onlyLangs = nil, []string{"go"}
+	skip = languageFilter()
+	skipped, _ = skip("cmd/main.go")
+	assert.False(t, skipped)
+	skipped, reason = skip("main.tf")
+	assert.True(t, skipped)
+	assert.Equal(t, "language not given to --only-lang", reason)
+	skipped, _ = skip("README.md")
+	assert.True(t, skipped)
+}
+
+func Test_validateLanguageFlags(t *testing.T) {
+	defer func() { skipLangs, onlyLangs = nil, nil }()
+
+	skipLangs, onlyLangs = []string{"go", "python"}, nil
+	assert.Nil(t, validateLanguageFlags())
+
+	skipLangs = []string{"golang"}
+	assert.ErrorContains(t, validateLanguageFlags(), "invalid --skip-lang: unknown languages: golang")
+
+	skipLangs, onlyLangs = nil, []string{"cobol"}
+	assert.ErrorContains(t, validateLanguageFlags(), "invalid --only-lang")
+}
@@ -4,19 +4,25 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
 
+	"github.com/hashicorp/copywrite/addlicense"
 	"github.com/hashicorp/copywrite/github"
 	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
 )
 
 // Flag variables
 var (
-	dirPath string
+	dirPath          string
+	strictMatch      bool
+	licenseFormat    string
+	licenseRecursive bool
 )
 
 // licenseCmd represents the license command
@@ -26,7 +32,8 @@ var licenseCmd = &cobra.Command{
 	Long: `Validates that a LICENSE file is present and remediates any issues if found:
 - Check if any files appear to be licenses
 - If no files are found, a license will be added
-- If a file is found but it does not adhere to the "LICENSE" desired nomenclature, it will be renamed
+- If a file is found but it does not adhere to the "LICENSE" desired nomenclature (or project.license_file_name, if set), it will be renamed
+- LICENCE and COPYING files are recognized as license files, and UNLICENSE files are left as-is
 - If a file is found that matches the desired naming scheme, it is left alone
 - If multiple files are found, an error will be returned`,
 	GroupID: "common", // Let's put this command in the common section of the help
@@ -44,122 +51,61 @@ var licenseCmd = &cobra.Command{
 		cobra.CheckErr(err)
 
 		// Input Validation
-		if conf.Project.CopyrightYear == 0 {
-			errYearNotFound := errors.New("Unable to automatically determine copyright year. Please specify it manually in the config or via the --year flag")
-
-			cliLogger.Info("Copyright year was not supplied via config or via the --year flag. Attempting to infer from the year the GitHub repo was created.")
-			repo, err := github.DiscoverRepo()
-			if err != nil {
-				cobra.CheckErr(fmt.Errorf("%v: %w", errYearNotFound, err))
-			}
-
-			client := github.NewGHClient().Raw()
-			year, err := github.GetRepoCreationYear(client, repo)
-			if err != nil {
-				cobra.CheckErr(fmt.Errorf("%v: %w", errYearNotFound, err))
+		switch licenseFormat {
+		case "text":
+		case "json":
+			if !plan {
+				cobra.CheckErr("--format=json requires --plan")
 			}
-			conf.Project.CopyrightYear = year
+			// Keep stdout machine-readable
+			cliLogger.SetLevel(hclog.Off)
+		default:
+			cobra.CheckErr(fmt.Sprintf("invalid --format %q: must be \"text\" or \"json\"", licenseFormat))
 		}
+		cobra.CheckErr(addlicense.ValidateCopyrightFormat(conf.Project.CopyrightFormat, conf.Project.CopyrightKeywords))
+		cobra.CheckErr(validateLicenseFileName(conf.Project.LicenseFileName))
+		cobra.CheckErr(inferCopyrightYear())
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-
-		cmd.Printf("Licensing under the following terms: %s\n", conf.Project.License)
-		cmd.Printf("Using year of initial copyright: %v\n", conf.Project.CopyrightYear)
-		cmd.Printf("Using copyright holder: %v\n\n", conf.Project.CopyrightHolder)
-
-		copyright := "Copyright (c) " + strconv.Itoa(conf.Project.CopyrightYear) + " " + conf.Project.CopyrightHolder
-
-		licenseFiles, err := licensecheck.FindLicenseFiles(dirPath)
-		if err != nil {
-			cliLogger.Error("Error when discovering license files", err)
-		}
-		cobra.CheckErr(err)
-
-		var file string
-
-		if len(licenseFiles) > 1 {
-			err = fmt.Errorf("More than one license file exists. Please review the following files and manually ensure only one is present: %s", licenseFiles)
-			cliLogger.Error(err.Error())
-			cobra.CheckErr(err)
+		if licenseRecursive {
+			cobra.CheckErr(runLicenseRecursive(cmd, dirPath))
 			return
 		}
 
-		if len(licenseFiles) == 0 {
-			if plan {
-				cobra.CheckErr("missing license file. Run without the --plan flag to fix this")
-			}
-
-			cmd.Println("No license file found, creating one.")
-			path, err := licensecheck.AddLicenseFile(dirPath, conf.Project.License)
-			if err != nil {
-				cliLogger.Error("Error adding new license file", err)
-			}
+		if licenseFormat == "json" {
+			copyright, err := licenseCopyright()
+			cobra.CheckErr(err)
+			p, err := planLicenseFile(dirPath, copyright)
 			cobra.CheckErr(err)
-			file = path
-		}
-
-		if len(licenseFiles) == 1 {
-			file = licenseFiles[0]
-		}
-
-		// Only a single license file is present beyond this point
 
-		// Let's make sure the license file adheres to our naming standard
-		if plan {
-			dir, _ := filepath.Split(file)
-			desiredPath := filepath.Join(dir, "LICENSE")
-			if file != desiredPath {
-				err := fmt.Errorf("license file is misnamed. Run without the --plan flag to fix this")
-				cliLogger.Error(err.Error())
-				cobra.CheckErr(err)
-			} else {
-				cmd.Println("License file is present and named properly!")
-			}
-		} else {
-			file, err = licensecheck.EnsureCorrectName(file)
-			if err != nil {
-				cliLogger.Error("Problem correcting LICENSE filename", err)
-			}
+			b, err := json.MarshalIndent(p, "", "  ")
 			cobra.CheckErr(err)
+			cmd.Println(string(b))
+			cobra.CheckErr(p.err())
+			return
 		}
 
-		// TODO: make sure the LICENSE file contains the appropriate license text
-
-		// Let's make sure it has a valid copyright header, too
-		cmd.Println("Validating presence of license header")
-
-		hasCopyright, err := licensecheck.HasCopyright(file)
-		if err != nil {
-			cliLogger.Error("Problem verifying a copyright statement", err)
-		}
-		cobra.CheckErr(err)
+		cmd.Printf("Licensing under the following terms: %s\n", conf.Project.License)
+		infof(cmd, "Using year of initial copyright: %v\n", conf.Project.CopyrightYear)
+		infof(cmd, "Using copyright holder: %v\n\n", conf.Project.CopyrightHolder)
 
-		hasValidCopyright, err := licensecheck.HasMatchingCopyright(file, copyright, true)
+		copyright, err := licenseCopyright()
 		if err != nil {
-			cliLogger.Error("Problem matching copyright", err)
+			cliLogger.Error("Error generating copyright statement", err)
 		}
 		cobra.CheckErr(err)
 
-		if hasCopyright {
-			if hasValidCopyright {
-				cmd.Println("Copyright statement is valid!")
-			} else {
-				err = fmt.Errorf("license file has a copyright statement, but it is malformed; Expected to find: \"%s\" Please resolve this manually", copyright)
-				cliLogger.Error(err.Error())
-				cobra.CheckErr(err)
-			}
-		} else {
-			if plan {
-				cobra.CheckErr("a LICENSE file exists, but the copyright statement is missing. Run without the --plan flag to fix this")
-			}
-
-			cmd.Println("Copyright statement is missing... attempting to add it")
-			err = licensecheck.AddHeader(file, copyright)
+		if plan {
+			err := validateLicenseFile(dirPath, copyright)
 			if err != nil {
-				cliLogger.Error("Error adding header", err)
+				cliLogger.Error(err.Error())
 			}
 			cobra.CheckErr(err)
+			cmd.Println("License file is present, named properly, and has a valid copyright statement!")
+			return
 		}
+
+		cobra.CheckErr(fixLicenseFile(cmd, dirPath, copyright))
 	},
 }
 
@@ -169,10 +115,282 @@ func init() {
 	// These flags are only locally relevant
 	licenseCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to validate a LICENSE file in")
 	licenseCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run and gives a non-zero return if improperly licensed")
+	licenseCmd.Flags().StringVar(&licenseFormat, "format", "text", "Output format of --plan: \"text\" or \"json\"")
+	licenseCmd.Flags().BoolVarP(&licenseRecursive, "recursive", "r", false, "Also validate a LICENSE file in every module (a directory with a go.mod, package.json, or Cargo.toml) beneath --dirPath")
+	licenseCmd.Flags().BoolVar(&strictMatch, "strict", false, "Require the copyright statement to match exactly, rather than tolerating differences in whitespace, copyright symbols, and year ranges")
 
 	// These flags will get mapped to keys in the the global Config
 	// TODO: eventually, the copyrightYear should be dynamically inferred from the repo
 	licenseCmd.Flags().IntP("year", "y", 0, "Year that the copyright statement should include")
 	licenseCmd.Flags().StringP("spdx", "s", "", "SPDX License Identifier indicating what the LICENSE file should represent")
+	cobra.CheckErr(licenseCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
 	licenseCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+	addGitHubRepoFlag(licenseCmd)
+}
+
+// inferCopyrightYear fills in the project's copyright year from the year its
+// GitHub repo was created, if it wasn't supplied via config or flags
+func inferCopyrightYear() error {
+	if conf.Project.CopyrightYear != 0 {
+		return nil
+	}
+	errYearNotFound := errors.New("Unable to automatically determine copyright year. Please specify it manually in the config or via the --year flag")
+
+	cliLogger.Info("Copyright year was not supplied via config or via the --year flag. Attempting to infer from the year the GitHub repo was created.")
+	repo, err := discoverRepo()
+	if err != nil {
+		return fmt.Errorf("%v: %w", errYearNotFound, err)
+	}
+
+	client := github.NewGHClient().Raw()
+	year, err := github.GetRepoCreationYear(client, repo)
+	if err != nil {
+		return fmt.Errorf("%v: %w", errYearNotFound, err)
+	}
+	conf.Project.CopyrightYear = year
+	return nil
+}
+
+// licenseCopyright returns the copyright statement expected in the LICENSE
+// file, in the project's copyright_format
+func licenseCopyright() (string, error) {
+	return addlicense.FormatCopyright(conf.Project.CopyrightFormat, addlicense.LicenseData{
+		Year:   strconv.Itoa(conf.Project.CopyrightYear),
+		Holder: conf.Project.CopyrightHolder,
+	})
+}
+
+// licenseFileName returns the canonical name of the project's license file
+func licenseFileName() string {
+	if conf.Project.LicenseFileName != "" {
+		return conf.Project.LicenseFileName
+	}
+	return licensecheck.DefaultLicenseFileName
+}
+
+// validateLicenseFileName returns an error if name is not a valid value for the
+// project.license_file_name config key. Only names that are recognized as
+// license files are allowed, so that later runs can find the file again.
+func validateLicenseFileName(name string) error {
+	if name == "" || (licensecheck.IsLicenseFileName(name) && !licensecheck.IsUnlicense(name)) {
+		return nil
+	}
+	return fmt.Errorf("invalid license_file_name %q: must be a name such as \"LICENSE\", \"LICENCE\", \"LICENSE.md\", or \"COPYING\"", name)
+}
+
+// licenseMatchOptions returns how the LICENSE file's copyright statement is
+// compared with the expected one: exactly with --strict, and otherwise
+// tolerating the differences introduced by hand edits
+func licenseMatchOptions() licensecheck.MatchOptions {
+	if strictMatch {
+		return licensecheck.MatchOptions{CaseSensitive: true}
+	}
+	return licensecheck.TolerantMatch
+}
+
+// licensePlan describes the state of a directory's LICENSE file, and the
+// actions the license command would take to fix it. It is printed by
+// `copywrite license --plan --format=json`.
+type licensePlan struct {
+	reportMetadata
+	Dir               string   `json:"dir,omitempty"`
+	LicenseFiles      []string `json:"license_files"`
+	Unlicense         bool     `json:"unlicense,omitempty"`
+	LicenseFileFound  bool     `json:"license_file_found"`
+	NameCorrect       bool     `json:"name_correct"`
+	CopyrightPresent  bool     `json:"copyright_present"`
+	CopyrightMatches  bool     `json:"copyright_matches"`
+	ExpectedCopyright string   `json:"expected_copyright"`
+	ActionsNeeded     []string `json:"actions_needed"`
+}
+
+// Actions that may be listed in licensePlan.ActionsNeeded. Those that can't be
+// fixed automatically require manual intervention.
+const (
+	licenseActionAdd          = "add_license_file"
+	licenseActionRename       = "rename_license_file"
+	licenseActionAddCopyright = "add_copyright"
+	licenseActionRemoveExtra  = "remove_extra_license_files" // manual
+	licenseActionFixCopyright = "fix_copyright"              // manual
+)
+
+// fixLicenseFile makes sure dir contains exactly one license file, named
+// according to the project config and containing the given copyright statement,
+// adding or renaming the file and adding the statement where needed
+func fixLicenseFile(cmd *cobra.Command, dir string, copyright string) error {
+	licenseFiles, err := licensecheck.FindLicenseFiles(dir)
+	if err != nil {
+		cliLogger.Error("Error when discovering license files", err)
+		return err
+	}
+
+	var file string
+
+	if len(licenseFiles) > 1 {
+		err = fmt.Errorf("More than one license file exists. Please review the following files and manually ensure only one is present: %s", licenseFiles)
+		cliLogger.Error(err.Error())
+		return err
+	}
+
+	if len(licenseFiles) == 0 {
+		cmd.Println("No license file found, creating one.")
+		path, err := licensecheck.AddLicenseFileAs(dir, conf.Project.License, licenseFileName())
+		if err != nil {
+			cliLogger.Error("Error adding new license file", err)
+			return err
+		}
+		file = path
+	}
+
+	if len(licenseFiles) == 1 {
+		file = licenseFiles[0]
+	}
+
+	// Only a single license file is present beyond this point
+
+	// An UNLICENSE dedicates the project to the public domain, so it keeps
+	// its conventional name and has no copyright holder
+	if licensecheck.IsUnlicense(file) {
+		cmd.Printf("Validated file: %s\n", file)
+		cmd.Println("UNLICENSE files dedicate a project to the public domain; no copyright statement is needed")
+		return nil
+	}
+
+	// Let's make sure the license file adheres to our naming standard
+	originalPath := file
+	file, err = licensecheck.EnsureCorrectNameAs(file, licenseFileName())
+	if err != nil {
+		cliLogger.Error("Problem correcting LICENSE filename", err)
+		return err
+	}
+
+	if file != originalPath {
+		cmd.Printf("Found improperly named file %q. Renamed to %q\n", originalPath, file)
+	} else {
+		cmd.Printf("Validated file: %s\n", file)
+	}
+
+	// TODO: make sure the LICENSE file contains the appropriate license text
+
+	// Let's make sure it has a valid copyright header, too
+	cmd.Println("Validating presence of license header")
+
+	hasCopyright, err := licensecheck.HasCopyright(file)
+	if err != nil {
+		cliLogger.Error("Problem verifying a copyright statement", err)
+		return err
+	}
+
+	hasValidCopyright, err := licensecheck.HasMatchingCopyrightWithOptions(file, copyright, licenseMatchOptions())
+	if err != nil {
+		cliLogger.Error("Problem matching copyright", err)
+		return err
+	}
+
+	if hasCopyright {
+		if !hasValidCopyright {
+			err = fmt.Errorf("license file has a copyright statement, but it is malformed; Expected to find: \"%s\" Please resolve this manually", copyright)
+			cliLogger.Error(err.Error())
+			return err
+		}
+		cmd.Println("Copyright statement is valid!")
+		return nil
+	}
+
+	cmd.Println("Copyright statement is missing... attempting to add it")
+	err = licensecheck.AddHeader(file, copyright)
+	if err != nil {
+		cliLogger.Error("Error adding header", err)
+	}
+	return err
+}
+
+// planLicenseFile inspects the license files in dir, without changing
+// anything, and returns what would need to be done for dir to contain exactly
+// one file named "LICENSE" with the given copyright statement
+func planLicenseFile(dir string, copyright string) (licensePlan, error) {
+	p := licensePlan{
+		reportMetadata:    newReportMetadata(),
+		LicenseFiles:      []string{},
+		ExpectedCopyright: copyright,
+		ActionsNeeded:     []string{},
+	}
+
+	licenseFiles, err := licensecheck.FindLicenseFiles(dir)
+	if err != nil {
+		return p, fmt.Errorf("error when discovering license files: %w", err)
+	}
+	p.LicenseFiles = append(p.LicenseFiles, licenseFiles...)
+	p.LicenseFileFound = len(licenseFiles) > 0
+
+	switch {
+	case len(licenseFiles) == 0:
+		p.ActionsNeeded = append(p.ActionsNeeded, licenseActionAdd)
+		return p, nil
+	case len(licenseFiles) > 1:
+		p.ActionsNeeded = append(p.ActionsNeeded, licenseActionRemoveExtra)
+		return p, nil
+	}
+
+	file := licenseFiles[0]
+	if licensecheck.IsUnlicense(file) {
+		// Public domain dedications have no copyright statement to check
+		p.Unlicense = true
+		p.NameCorrect = true
+		return p, nil
+	}
+
+	fileDir, _ := filepath.Split(file)
+	p.NameCorrect = file == filepath.Join(fileDir, licenseFileName())
+	if !p.NameCorrect {
+		p.ActionsNeeded = append(p.ActionsNeeded, licenseActionRename)
+	}
+
+	p.CopyrightPresent, err = licensecheck.HasCopyright(file)
+	if err != nil {
+		return p, fmt.Errorf("problem verifying a copyright statement: %w", err)
+	}
+	p.CopyrightMatches, err = licensecheck.HasMatchingCopyrightWithOptions(file, copyright, licenseMatchOptions())
+	if err != nil {
+		return p, fmt.Errorf("problem matching copyright: %w", err)
+	}
+
+	switch {
+	case !p.CopyrightPresent:
+		p.ActionsNeeded = append(p.ActionsNeeded, licenseActionAddCopyright)
+	case !p.CopyrightMatches:
+		p.ActionsNeeded = append(p.ActionsNeeded, licenseActionFixCopyright)
+	}
+	return p, nil
+}
+
+// validateLicenseFile checks, without changing anything, that dir contains
+// exactly one license file, that it is named "LICENSE", and that it contains
+// the given copyright statement
+func validateLicenseFile(dir string, copyright string) error {
+	p, err := planLicenseFile(dir, copyright)
+	if err != nil {
+		return err
+	}
+	return p.err()
+}
+
+// err returns an error describing the first action in the plan, or nil if no
+// actions are needed
+func (p licensePlan) err() error {
+	if len(p.ActionsNeeded) == 0 {
+		return nil
+	}
+
+	switch p.ActionsNeeded[0] {
+	case licenseActionAdd:
+		return errors.New("missing license file. Run without the --plan flag to fix this")
+	case licenseActionRemoveExtra:
+		return fmt.Errorf("More than one license file exists. Please review the following files and manually ensure only one is present: %s", p.LicenseFiles)
+	case licenseActionRename:
+		return errors.New("license file is misnamed. Run without the --plan flag to fix this")
+	case licenseActionAddCopyright:
+		return errors.New("a LICENSE file exists, but the copyright statement is missing. Run without the --plan flag to fix this")
+	}
+	return fmt.Errorf("license file has a copyright statement, but it is malformed; Expected to find: \"%s\" Please resolve this manually", p.ExpectedCopyright)
 }
@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/copywrite/config"
+	"github.com/spf13/cobra"
+)
+
+// moduleMarkers are the files that mark the root of a module, which may be
+// published (and so licensed) separately from the rest of its repo
+var moduleMarkers = []string{"go.mod", "package.json", "Cargo.toml"}
+
+// discoverModules returns root and every directory beneath it that contains a
+// module marker, sorted by path. Vendored dependencies and test fixtures are not
+// searched, as they contain modules that belong to someone else.
+func discoverModules(root string) ([]string, error) {
+	modules := []string{root}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "testdata":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if slices.Contains(moduleMarkers, d.Name()) && !slices.Contains(modules, dir) {
+			modules = append(modules, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(modules)
+	return modules, nil
+}
+
+// moduleConfig returns the config for the module in dir: base, overridden by
+// the module's own .copywrite.hcl if it has one. This lets a module in a
+// monorepo be published under a different license or copyright holder.
+func moduleConfig(base *config.Config, dir string) (*config.Config, error) {
+	c, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.LoadConfMap(base.Map()); err != nil {
+		return nil, err
+	}
+
+	cfgPath, err := filepath.Abs(filepath.Join(dir, ".copywrite.hcl"))
+	if err != nil {
+		return nil, err
+	}
+	// The base config has already been loaded, along with any flags that
+	// override it, so it must not be loaded a second time
+	if cfgPath == base.GetConfigPath() {
+		return c, nil
+	}
+	if _, err := os.Stat(cfgPath); errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err := c.LoadConfigFile(cfgPath); err != nil {
+		return nil, err
+	}
+	return c, validateLicenseFileName(c.Project.LicenseFileName)
+}
+
+// runLicenseRecursive validates (or, without --plan, fixes) the license file
+// of root and of every module beneath it, each using its own config, and
+// prints a combined report
+func runLicenseRecursive(cmd *cobra.Command, root string) error {
+	modules, err := discoverModules(root)
+	if err != nil {
+		cliLogger.Error("Error discovering modules", err)
+		return err
+	}
+
+	// The license helpers read the running config, so it is swapped for that
+	// of each module in turn
+	rootConf := conf
+	defer func() { conf = rootConf }()
+
+	plans := []licensePlan{}
+	results := make([]projectResult, len(modules))
+	for i, dir := range modules {
+		results[i].Dir = dir
+
+		c, err := moduleConfig(rootConf, dir)
+		if err != nil {
+			cliLogger.Error(fmt.Sprintf("Error loading the config of %q", dir), err)
+			results[i].Err = err
+			continue
+		}
+		conf = c
+
+		copyright, err := licenseCopyright()
+		if err != nil {
+			cliLogger.Error("Error generating copyright statement", err)
+			results[i].Err = err
+			continue
+		}
+
+		if licenseFormat == "json" {
+			p, err := planLicenseFile(dir, copyright)
+			if err == nil {
+				p.Dir = dir
+				plans = append(plans, p)
+				err = p.err()
+			}
+			results[i].Err = err
+			continue
+		}
+
+		gha.StartGroup(fmt.Sprintf("Module: %s", dir))
+		cmd.Printf("Licensing under the following terms: %s\n", conf.Project.License)
+		infof(cmd, "Using copyright statement: %s\n", copyright)
+		if plan {
+			err = validateLicenseFile(dir, copyright)
+			if err != nil {
+				cliLogger.Error(err.Error())
+			}
+		} else {
+			err = fixLicenseFile(cmd, dir, copyright)
+		}
+		results[i].Err = err
+		gha.EndGroup()
+		cmd.Println("")
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+
+	if licenseFormat == "json" {
+		b, err := json.MarshalIndent(plans, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(b))
+	} else {
+		gha.StartGroup("Results:")
+		for _, r := range results {
+			if r.Err != nil {
+				cmd.Printf("❌ %s: %s\n", r.Dir, r.Err)
+			} else {
+				cmd.Printf("✔️ %s\n", r.Dir)
+			}
+		}
+		gha.EndGroup()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d modules failed license validation", failed, len(modules))
+	}
+	return nil
+}
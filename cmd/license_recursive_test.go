@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/copywrite/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_discoverModules(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"go.mod",
+		"sdk/go.mod",
+		"sdk/internal/testdata/go.mod",
+		"web/package.json",
+		"web/node_modules/dep/package.json",
+		"crates/parser/Cargo.toml",
+		"crates/parser/package.json",
+		"vendor/github.com/dep/go.mod",
+		"docs/index.md",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(""), 0644))
+	}
+
+	actual, err := discoverModules(root)
+	assert.Nil(t, err)
+
+	expected := []string{
+		root,
+		filepath.Join(root, "crates/parser"),
+		filepath.Join(root, "sdk"),
+		filepath.Join(root, "web"),
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func Test_moduleConfig(t *testing.T) {
+	base := config.MustNew()
+	assert.Nil(t, base.LoadConfMap(map[string]interface{}{
+		"project.license":        "MPL-2.0",
+		"project.copyright_year": 2020,
+	}))
+
+	root := t.TempDir()
+	sdk := filepath.Join(root, "sdk")
+	assert.Nil(t, os.MkdirAll(sdk, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(sdk, ".copywrite.hcl"), []byte(`
+schema_version = 1
+project {
+  license = "Apache-2.0"
+}
+`), 0644))
+
+	// Modules without a config inherit the base config
+	c, err := moduleConfig(base, root)
+	assert.Nil(t, err)
+	assert.Equal(t, "MPL-2.0", c.Project.License)
+	assert.Equal(t, 2020, c.Project.CopyrightYear)
+
+	// Nested configs override the base config
+	c, err = moduleConfig(base, sdk)
+	assert.Nil(t, err)
+	assert.Equal(t, "Apache-2.0", c.Project.License)
+	assert.Equal(t, 2020, c.Project.CopyrightYear)
+	assert.Equal(t, "HashiCorp, Inc.", c.Project.CopyrightHolder)
+
+	// The base config is left alone
+	assert.Equal(t, "MPL-2.0", base.Project.License)
+}
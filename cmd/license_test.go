@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_planLicenseFile(t *testing.T) {
+	const copyright = "Copyright (c) 2023 HashiCorp, Inc."
+
+	tests := []struct {
+		name             string
+		files            map[string]string
+		expectedActions  []string
+		expectedFound    bool
+		expectedName     bool
+		expectedPresent  bool
+		expectedMatches  bool
+		expectedErrorMsg string
+	}{
+		{
+			name:             "Missing license file",
+			files:            map[string]string{},
+			expectedActions:  []string{"add_license_file"},
+			expectedErrorMsg: "missing license file. Run without the --plan flag to fix this",
+		},
+		{
+			name:            "Compliant license file",
+			files:           map[string]string{"LICENSE": copyright + "\n\nMozilla Public License"},
+			expectedActions: []string{},
+			expectedFound:   true,
+			expectedName:    true,
+			expectedPresent: true,
+			expectedMatches: true,
+		},
+		{
+			name:             "Misnamed license file without a copyright statement",
+			files:            map[string]string{"LICENSE.md": "Mozilla Public License"},
+			expectedActions:  []string{"rename_license_file", "add_copyright"},
+			expectedFound:    true,
+			expectedErrorMsg: "license file is misnamed. Run without the --plan flag to fix this",
+		},
+		{
+			name:             "Wrong copyright statement",
+			files:            map[string]string{"LICENSE": "Copyright (c) 2023 Someone Else\n"},
+			expectedActions:  []string{"fix_copyright"},
+			expectedFound:    true,
+			expectedName:     true,
+			expectedPresent:  true,
+			expectedErrorMsg: `license file has a copyright statement, but it is malformed; Expected to find: "` + copyright + `" Please resolve this manually`,
+		},
+		{
+			name:            "UNLICENSE needs no copyright statement",
+			files:           map[string]string{"UNLICENSE": "This is free and unencumbered software released into the public domain."},
+			expectedActions: []string{},
+			expectedFound:   true,
+			expectedName:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+
+			p, err := planLicenseFile(dir, copyright)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedActions, p.ActionsNeeded)
+			assert.Equal(t, tt.expectedFound, p.LicenseFileFound)
+			assert.Equal(t, tt.expectedName, p.NameCorrect)
+			assert.Equal(t, tt.expectedPresent, p.CopyrightPresent)
+			assert.Equal(t, tt.expectedMatches, p.CopyrightMatches)
+			assert.Equal(t, copyright, p.ExpectedCopyright)
+
+			if tt.expectedErrorMsg == "" {
+				assert.Nil(t, p.err())
+			} else {
+				assert.EqualError(t, p.err(), tt.expectedErrorMsg)
+			}
+		})
+	}
+}
+
+func Test_licenseCopyright(t *testing.T) {
+	defer func() {
+		conf.Project.CopyrightYear = 0
+		conf.Project.CopyrightHolder = ""
+		conf.Project.CopyrightFormat = ""
+	}()
+	conf.Project.CopyrightYear = 2023
+	conf.Project.CopyrightHolder = "HashiCorp, Inc."
+
+	copyright, err := licenseCopyright()
+	assert.Nil(t, err)
+	assert.Equal(t, "Copyright (c) 2023 HashiCorp, Inc.", copyright)
+
+	conf.Project.CopyrightFormat = "© {{.Year}} {{.Holder}}"
+	copyright, err = licenseCopyright()
+	assert.Nil(t, err)
+	assert.Equal(t, "© 2023 HashiCorp, Inc.", copyright)
+
+	// A year that is already known isn't looked up on GitHub
+	assert.Nil(t, inferCopyrightYear())
+	assert.Equal(t, 2023, conf.Project.CopyrightYear)
+}
+
+func Test_validateLicenseFile(t *testing.T) {
+	const copyright = "Copyright (c) 2023 HashiCorp, Inc."
+
+	dir := t.TempDir()
+	assert.EqualError(t, validateLicenseFile(dir, copyright), "missing license file. Run without the --plan flag to fix this")
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "LICENSE"), []byte(copyright+"\n\nMozilla Public License"), 0644))
+	assert.Nil(t, validateLicenseFile(dir, copyright))
+}
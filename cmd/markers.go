@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/spf13/cobra"
+)
+
+var markersCmd = &cobra.Command{
+	Use:   "markers",
+	Short: "Describes the comments that exempt files and lines from copywrite",
+	Long: `Comments containing the following markers can be added to source files to
+exempt them from copywrite, much like editor-config or linter directives:
+
+  ` + addlicense.IgnoreFileMarker + `
+      When found in any of the first 10 lines of a file, the file is skipped
+      entirely by "copywrite headers" and "copywrite bump-years".
+
+  ` + addlicense.IgnoreNextLineMarker + `
+      Protects the line that follows it from being modified. This is useful
+      for keeping a third-party copyright statement verbatim when years are
+      refreshed.
+
+Markers may be written in any comment style, and may be followed by an
+explanation. For example:
+
+  // copywrite:ignore-file -- vendored from github.com/example/project
+
+  # copywrite:ignore-next-line
+  # Copyright (c) 2015 Example Corp`,
+	// Run function is omitted, as this command exists only to document markers
+}
+
+func init() {
+	rootCmd.AddCommand(markersCmd)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// reportSchemaVersion is the version of the format of every JSON document
+// copywrite writes, such as `report trend` files and `debug --format=json`
+// output. It must be incremented whenever a field is removed or its meaning
+// changes, so that downstream parsers can handle both formats.
+const reportSchemaVersion = 1
+
+// Flag variables
+var buildMetadata bool
+
+// now returns the current time, and may be replaced in tests
+var now = time.Now
+
+// reportMetadata identifies the build of copywrite that generated a JSON
+// document. It is embedded in every JSON document copywrite writes; the build
+// fields are only set when --build-metadata is given.
+type reportMetadata struct {
+	SchemaVersion int        `json:"schema_version"`
+	GeneratedBy   string     `json:"generated_by,omitempty"`
+	GeneratedAt   *time.Time `json:"generated_at,omitempty"`
+}
+
+// newReportMetadata returns the metadata for a JSON document being written now
+func newReportMetadata() reportMetadata {
+	m := reportMetadata{SchemaVersion: reportSchemaVersion}
+	if buildMetadata {
+		t := now().UTC().Truncate(time.Second)
+		m.GeneratedBy = "copywrite " + GetVersion()
+		m.GeneratedAt = &t
+	}
+	return m
+}
+
+// buildMetadataComment returns a comment naming the version of copywrite and
+// the time at which a file is being generated, wrapped in the comment syntax
+// of the file (e.g., "# " and "" for HCL, or "<!-- " and " -->" for markdown).
+// If --build-metadata wasn't given, "" is returned.
+func buildMetadataComment(open string, close string) string {
+	if !buildMetadata {
+		return ""
+	}
+	return fmt.Sprintf("%sGenerated by copywrite %s on %s%s\n", open, GetVersion(), now().UTC().Format(time.RFC3339), close)
+}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_buildMetadata(t *testing.T) {
+	defer func() {
+		buildMetadata = false
+		now = time.Now
+	}()
+	now = func() time.Time { return time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC) }
+
+	buildMetadata = false
+	assert.Equal(t, "", buildMetadataComment("# ", ""))
+	b, err := json.Marshal(newReportMetadata())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"schema_version":1}`, string(b))
+
+	buildMetadata = true
+	assert.Equal(t, "<!-- Generated by copywrite "+GetVersion()+" on 2025-03-04T05:06:07Z -->\n", buildMetadataComment("<!-- ", " -->"))
+	b, err = json.Marshal(newReportMetadata())
+	assert.NoError(t, err)
+	assert.Equal(t, `{"schema_version":1,"generated_by":"copywrite `+GetVersion()+`","generated_at":"2025-03-04T05:06:07Z"}`, string(b))
+}
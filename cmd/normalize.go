@@ -0,0 +1,293 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compactLicenseText bool
+	compactAuditLog    string
+)
+
+var normalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Rewrites existing headers into a single canonical form",
+	Long: `Recursively checks for all files in the given directory and subdirectories,
+rewriting headers that are already compliant into the exact form that
+"copywrite headers" would add today:
+
+  - the copyright statement comes first, followed by the SPDX identifier
+  - the copyright holder is written exactly as configured, in the configured
+    copyright_format, with years written as "2019-2023" or "2019, 2021"
+  - blank comment lines within the header are removed, and header_spacing
+    (or a single blank line) separates it from the rest of the file
+
+Years are never changed, and attribution lines such as "Original author: ..."
+are kept directly below the header (see project.preserve_adjacent). Only
+headers made up of a copyright statement for the configured holder and an
+SPDX identifier matching the configured license are rewritten; headers with
+any other text, such as license text or third-party notices, are left alone,
+as are boxed and banner headers.
+
+Uniform headers make diffs across many repos consistent and simplify matching
+headers automatically.
+
+Use --compact to also replace license text embedded at the top of a file, such
+as the full text of the Apache License or its standard notice, with the short
+header. This is only done when the text identifies a license matching the
+configured one, and when any copyright statements in it are for the
+configured holder. Use --audit-log to append the original text of every
+replaced header to a file.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`spdx`:             `project.license`,
+			`copyright-holder`: `project.copyright_holder`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+
+		cobra.CheckErr(validateHeaderConfig())
+
+		if compactAuditLog != "" && !compactLicenseText {
+			cobra.CheckErr("the --audit-log flag can only be used with --compact")
+		}
+		if conf.Project.HeaderTemplate == "license" {
+			cobra.CheckErr("headers rendered from license templates (project.header_template = \"license\") can't be normalized")
+		}
+
+		if !plan {
+			err := checkSafePath(".")
+			if err != nil {
+				cliLogger.Error("Error validating target directory", err)
+			}
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if plan {
+			infof(cmd, "%s", text.FgYellow.Sprint("Executing in dry-run mode. Rerun without the `--plan` flag to apply changes.\n\n"))
+		}
+
+		infof(cmd, "Using copyright holder: %v\n", conf.Project.CopyrightHolder)
+		infof(cmd, "Using license identifier: %v\n\n", conf.Project.License)
+
+		stdcliLogger := resultLogger(cmd)
+
+		opts := headerOptions()
+		err := skipSubmodules(cmd, &opts)
+		if err != nil {
+			cliLogger.Error("Error applying the submodules policy", err)
+		}
+		cobra.CheckErr(err)
+
+		licenseData := addlicense.LicenseData{
+			Holder: conf.Project.CopyrightHolder,
+			SPDXID: conf.Project.License,
+		}
+		if !includeSPDX() {
+			licenseData.SPDXID = ""
+		}
+
+		normalizer, err := addlicense.NewNormalizer(licenseData, opts)
+		if err != nil {
+			cliLogger.Error("Error validating config", err)
+		}
+		cobra.CheckErr(err)
+
+		var mu sync.Mutex
+		changed := map[string][2][]byte{}
+		compacted := map[string]*addlicense.Compaction{}
+
+		err = addlicense.Walk(conf.Project.HeaderIgnore, []string{"."}, stdcliLogger, opts, func(path string) error {
+			b, err := addlicense.ReadHead(path)
+			if err != nil {
+				return err
+			}
+			_, compaction, err := compactHeader(normalizer, path, b)
+			if err != nil {
+				return err
+			}
+			if compaction == nil {
+				if _, ok, err := normalizer.Normalize(path, b); err != nil || !ok {
+					return err
+				}
+			}
+
+			// Only read the full file once it's known to need changes
+			b, err = os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			updated, compaction, err := compactHeader(normalizer, path, b)
+			if err != nil {
+				return err
+			}
+			if compaction == nil {
+				updated, _, err = normalizer.Normalize(path, b)
+				if err != nil {
+					return err
+				}
+			}
+
+			mu.Lock()
+			changed[path] = [2][]byte{b, updated}
+			if compaction != nil {
+				compacted[path] = compaction
+			}
+			mu.Unlock()
+
+			if plan {
+				return nil
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(path, updated, fi.Mode())
+		})
+		if err != nil {
+			cliLogger.Error("Error normalizing headers", err)
+		}
+		cobra.CheckErr(err)
+
+		paths := lo.Keys(changed)
+		sort.Strings(paths)
+
+		gha.StartGroup("The following files have headers that aren't in canonical form:")
+		for _, path := range paths {
+			if compacted[path] != nil {
+				cmd.Printf("%s (embedded %s license text)\n", path, compacted[path].License)
+			} else {
+				cmd.Println(path)
+			}
+			if showDiff {
+				cmd.Print(headerDiff(path, changed[path][0], changed[path][1]))
+			}
+		}
+		gha.EndGroup()
+
+		if compactAuditLog != "" && !plan && len(compacted) > 0 {
+			err := appendCompactionLog(compactAuditLog, paths, compacted)
+			if err != nil {
+				cliLogger.Error("Error writing audit log", err)
+			}
+			cobra.CheckErr(err)
+		}
+
+		if plan && len(paths) > 0 {
+			cobra.CheckErr(fmt.Sprintf("%d files have headers that aren't in canonical form. Run without the --plan flag to fix this", len(paths)))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(normalizeCmd)
+
+	// These flags are only locally relevant
+	normalizeCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to normalize headers")
+	normalizeCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run, printing the names of all files whose headers would be rewritten")
+	normalizeCmd.Flags().BoolVar(&showDiff, "diff", false, "Prints the original and rewritten header of each file")
+	normalizeCmd.Flags().BoolVar(&compactLicenseText, "compact", false, "Replaces license text embedded at the top of files with the short header")
+	normalizeCmd.Flags().StringVar(&compactAuditLog, "audit-log", "", "Path of a file to append the original text of every header replaced by --compact to")
+	normalizeCmd.Flags().IntVar(&parallelism, "parallelism", 0, "Number of directories to read concurrently while discovering files (default is the number of CPUs)")
+	addAllowUnsafePathsFlag(normalizeCmd)
+
+	// These flags will get mapped to keys in the the global Config
+	normalizeCmd.Flags().StringP("spdx", "s", "", "SPDX-compliant license identifier or expression that headers must match (e.g., 'MPL-2.0')")
+	cobra.CheckErr(normalizeCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
+	normalizeCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder that headers must name (default \"HashiCorp, Inc.\")")
+}
+
+// compactHeader replaces embedded license text in b, the contents of the file
+// at path, if --compact is set
+func compactHeader(normalizer *addlicense.Normalizer, path string, b []byte) ([]byte, *addlicense.Compaction, error) {
+	if !compactLicenseText {
+		return b, nil, nil
+	}
+	return normalizer.Compact(path, b)
+}
+
+// appendCompactionLog appends the original text of each compacted header to
+// the audit log at logPath, creating it if needed
+func appendCompactionLog(logPath string, paths []string, compacted map[string]*addlicense.Compaction) error {
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s: compacted embedded license text into %q headers\n", time.Now().UTC().Format(time.RFC3339), conf.Project.License)
+	for _, path := range paths {
+		c, ok := compacted[path]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s (%s)\n", path, c.License)
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(c.Original, "\n"), "\n") {
+			fmt.Fprintf(&sb, "- %s", line)
+		}
+		sb.WriteString("\n")
+	}
+
+	if _, err := f.WriteString(sb.String()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// headerDiff renders the lines that differ between two versions of a file
+// whose header was rewritten, which may have a different number of lines, as
+// a single hunk in a minimal unified diff format
+func headerDiff(path string, before []byte, after []byte) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+
+	beforeLines := bytes.Split(before, []byte("\n"))
+	afterLines := bytes.Split(after, []byte("\n"))
+
+	prefix := 0
+	for prefix < len(beforeLines) && prefix < len(afterLines) && bytes.Equal(beforeLines[prefix], afterLines[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(beforeLines)-prefix && suffix < len(afterLines)-prefix &&
+		bytes.Equal(beforeLines[len(beforeLines)-1-suffix], afterLines[len(afterLines)-1-suffix]) {
+		suffix++
+	}
+
+	for _, line := range beforeLines[prefix : len(beforeLines)-suffix] {
+		fmt.Fprintln(&b, text.FgRed.Sprintf("-%s", line))
+	}
+	for _, line := range afterLines[prefix : len(afterLines)-suffix] {
+		fmt.Fprintln(&b, text.FgGreen.Sprintf("+%s", line))
+	}
+	return b.String()
+}
@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var noticesOutput string
+
+// attributionFiles are the names of files whose contents are included verbatim
+// as the attribution text for a third-party component
+var attributionFiles = []string{"NOTICE", "NOTICE.txt", "NOTICE.md", "LICENSE", "LICENSE.txt", "LICENSE.md", "COPYING"}
+
+var noticesCmd = &cobra.Command{
+	Use:   "notices",
+	Short: "Manages third-party notices for a project",
+	// Run function is omitted, as this command exists only to house subcommands
+}
+
+var noticesGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generates a THIRD_PARTY_NOTICES.md file from in-tree copyright statements",
+	Long: `Assembles a notices file listing each third-party component found in the
+project (see "copywrite report provenance"), along with its copyright statement,
+license, and any attribution text from NOTICE or LICENSE files that accompany it.
+
+The output is deterministic, so the file is only rewritten when its contents
+would change. With --plan, the command instead fails if the file is out of date,
+which is useful for validating it in CI.
+
+Only third-party code in the project's own tree is listed. Module and package
+dependencies are not, as copywrite has no dependency report to build on.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`copyright-holder`: `project.copyright_holder`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cobra.CheckErr(generateNotices(cmd, plan))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(noticesCmd)
+	noticesCmd.AddCommand(noticesGenerateCmd)
+
+	// These flags are only locally relevant
+	noticesGenerateCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to generate notices")
+	noticesGenerateCmd.Flags().StringVarP(&noticesOutput, "output", "o", "THIRD_PARTY_NOTICES.md", "Path of the notices file, relative to --dirPath")
+	noticesGenerateCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run, failing if the notices file is out of date")
+
+	// These flags will get mapped to keys in the the global Config
+	noticesGenerateCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+}
+
+// generateNotices writes the notices file for the current directory, unless it
+// is already up to date. If plan is set, it instead fails if the file is out of
+// date.
+func generateNotices(cmd *cobra.Command, plan bool) error {
+	// Don't mistake the statements in a previously generated file for more
+	// third-party code
+	entries, err := scanProvenance(conf.Project.CopyrightHolder, noticesOutput)
+	if err != nil {
+		cliLogger.Error("Error scanning copyright statements", err)
+		return err
+	}
+
+	rendered, err := renderNotices(groupProvenance(entries))
+	if err != nil {
+		cliLogger.Error("Error reading attribution files", err)
+		return err
+	}
+
+	existing, err := os.ReadFile(noticesOutput)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if bytes.Equal(existing, rendered) {
+		cmd.Printf("%s is up to date\n", noticesOutput)
+		return nil
+	}
+
+	if plan {
+		return fmt.Errorf("%s is out of date. Run without the --plan flag to regenerate it", noticesOutput)
+	}
+
+	err = os.WriteFile(noticesOutput, rendered, 0644)
+	if err != nil {
+		cliLogger.Error("Error writing notices file", err)
+		return err
+	}
+	cmd.Println(text.FgGreen.Sprintf("Wrote %s", noticesOutput))
+	return nil
+}
+
+// renderNotices renders a markdown notices file for the given components
+func renderNotices(components []noticeComponent) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# Third-Party Notices\n\n")
+	buf.WriteString("<!-- This file is generated by `copywrite notices generate`. Do not edit it by hand. -->\n\n")
+	if len(components) == 0 {
+		buf.WriteString("This project does not include any third-party components.\n")
+		return buf.Bytes(), nil
+	}
+	buf.WriteString("This project includes the third-party components listed below.\n")
+
+	for _, c := range components {
+		fmt.Fprintf(&buf, "\n## %s\n\n", c.Holder)
+		fmt.Fprintf(&buf, "- Copyright: %s\n", c.Statement())
+		fmt.Fprintf(&buf, "- License: %s\n", c.License())
+		fmt.Fprintf(&buf, "- Location: %s\n", strings.Join(c.Dirs, ", "))
+
+		for _, p := range findAttributionFiles(c.Dirs) {
+			b, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+
+			// Make sure the fence can't be closed by the file's own contents
+			fence := "```"
+			for bytes.Contains(b, []byte(fence)) {
+				fence += "`"
+			}
+			fmt.Fprintf(&buf, "\n### %s\n\n%stext\n%s\n%s\n", p, fence, bytes.TrimSpace(b), fence)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// findAttributionFiles returns the NOTICE and LICENSE files that sit alongside
+// the given directories or in their closest ancestor that has any. Files at the
+// root of the project are not included, as they belong to the project itself.
+func findAttributionFiles(dirs []string) []string {
+	found := []string{}
+	for _, dir := range dirs {
+		for d := dir; d != "." && d != "/"; d = path.Dir(d) {
+			matches := []string{}
+			for _, name := range attributionFiles {
+				p := path.Join(d, name)
+				if fi, err := os.Stat(p); err == nil && fi.Mode().IsRegular() {
+					matches = append(matches, p)
+				}
+			}
+			if len(matches) > 0 {
+				for _, m := range matches {
+					if !lo.Contains(found, m) {
+						found = append(found, m)
+					}
+				}
+				break
+			}
+		}
+	}
+	return found
+}
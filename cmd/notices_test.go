@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// chdirTemp changes into a new temporary directory for the rest of the test,
+// creating the given files in it
+func chdirTemp(t *testing.T, files map[string]string) {
+	dir := t.TempDir()
+	for name, contents := range files {
+		p := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, os.WriteFile(p, []byte(contents), 0644))
+	}
+
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(dir))
+	t.Cleanup(func() { assert.Nil(t, os.Chdir(cwd)) })
+}
+
+func Test_findAttributionFiles(t *testing.T) {
+	chdirTemp(t, map[string]string{
+		"LICENSE":                 "project license",
+		"vendor/a/LICENSE":        "a license",
+		"vendor/a/NOTICE":         "a notice",
+		"vendor/a/sub/lib.go":     "",
+		"vendor/b/lib.go":         "",
+		"third_party/c/COPYING":   "c license",
+		"third_party/c/x/y/z.go":  "",
+		"third_party/c/LICENSE/x": "not a regular file",
+	})
+
+	tests := []struct {
+		name          string
+		dirs          []string
+		expectedFiles []string
+	}{
+		{
+			name:          "Files alongside a directory",
+			dirs:          []string{"vendor/a"},
+			expectedFiles: []string{"vendor/a/NOTICE", "vendor/a/LICENSE"},
+		},
+		{
+			name:          "Files in the closest ancestor with any, listed once",
+			dirs:          []string{"vendor/a/sub", "vendor/a", "third_party/c/x/y"},
+			expectedFiles: []string{"vendor/a/NOTICE", "vendor/a/LICENSE", "third_party/c/COPYING"},
+		},
+		{
+			name:          "Files at the project root are never included",
+			dirs:          []string{"vendor/b", "."},
+			expectedFiles: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedFiles, findAttributionFiles(tt.dirs))
+		})
+	}
+}
+
+func Test_renderNotices(t *testing.T) {
+	chdirTemp(t, map[string]string{
+		"vendor/a/LICENSE": "Use it with ```fences``` and ````more````\n",
+	})
+
+	rendered, err := renderNotices(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, string(rendered), "This project does not include any third-party components.\n")
+
+	rendered, err = renderNotices([]noticeComponent{{
+		Holder:   "Acme Inc.",
+		Years:    []string{"2020"},
+		Licenses: []string{"MIT"},
+		Dirs:     []string{"vendor/a"},
+	}})
+	assert.Nil(t, err)
+	assert.Contains(t, string(rendered), "## Acme Inc.\n\n- Copyright: Copyright 2020 Acme Inc.\n- License: MIT\n- Location: vendor/a\n")
+	assert.Contains(t, string(rendered), "### vendor/a/LICENSE\n\n`````text\nUse it with ```fences``` and ````more````\n`````\n",
+		"The fence is longer than any run of backticks in the file")
+}
+
+func Test_generateNotices(t *testing.T) {
+	cliLogger = hclog.NewNullLogger()
+	chdirTemp(t, map[string]string{
+		"main.go":          "// Copyright (c) HashiCorp, Inc.\n\npackage main\n",
+		"vendor/a/a.go":    "// Copyright (c) 2020 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage a\n",
+		"vendor/a/LICENSE": "MIT License\n\nCopyright (c) 2020 Example Corp\n",
+	})
+
+	conf.Project.CopyrightHolder = "HashiCorp, Inc."
+	defer func() { conf.Project.CopyrightHolder = "" }()
+	noticesOutput = "NOTICES.md"
+	defer func() { noticesOutput = "THIRD_PARTY_NOTICES.md" }()
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+
+	err := generateNotices(cmd, true)
+	assert.EqualError(t, err, "NOTICES.md is out of date. Run without the --plan flag to regenerate it", "A missing file is out of date")
+	assert.NoFileExists(t, "NOTICES.md", "Nothing is written with --plan")
+
+	assert.Nil(t, generateNotices(cmd, false))
+	b, err := os.ReadFile("NOTICES.md")
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "## Acme Inc.\n")
+	assert.Contains(t, string(b), "### vendor/a/LICENSE\n")
+	assert.NotContains(t, string(b), "HashiCorp", "The project's own copyright holder is not a third party")
+
+	// Regenerating doesn't mistake the notices file itself for third-party code
+	out.Reset()
+	assert.Nil(t, generateNotices(cmd, true))
+	assert.Equal(t, "NOTICES.md is up to date\n", out.String())
+	assert.Nil(t, generateNotices(cmd, false))
+	after, err := os.ReadFile("NOTICES.md")
+	assert.Nil(t, err)
+	assert.Equal(t, b, after)
+
+	assert.Nil(t, os.WriteFile("NOTICES.md", append(after, "edited\n"...), 0644))
+	assert.Error(t, generateNotices(cmd, true), "An edited file is out of date")
+	assert.Nil(t, generateNotices(cmd, false))
+	after, err = os.ReadFile("NOTICES.md")
+	assert.Nil(t, err)
+	assert.Equal(t, b, after, "Regenerating restores the file")
+}
@@ -0,0 +1,313 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/dispatch"
+	"github.com/hashicorp/copywrite/git"
+	gh "github.com/hashicorp/copywrite/github"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	orchestrateAuthor    string
+	orchestrateChunkSize int
+)
+
+// orchestrateChunkLabel is the label added to every pull request that is one
+// part of a change split up by --chunk-size
+const orchestrateChunkLabel = "copywrite"
+
+// orchestratePRBody is the description of every pull request opened by the
+// orchestrate command
+const orchestratePRBody = `This PR was opened by ` + "`copywrite orchestrate`" + ` to add missing copyright
+headers and/or fix the LICENSE file, as part of batch %s.
+
+Please review the changes and merge them if they look correct. Files that
+shouldn't have headers can be excluded with ` + "`header_ignore`" + ` in ` + "`.copywrite.hcl`" + `.`
+
+// orchestrateChunkPRBody is appended to the description of each pull request
+// that is one part of a change split up by --chunk-size
+const orchestrateChunkPRBody = `
+
+This is part %d of %d, each of which changes at most %d files. The parts are
+independent of each other and can be reviewed and merged in any order.`
+
+var orchestrateCmd = &cobra.Command{
+	Use:   "orchestrate",
+	Short: "Audits a list of repos locally and opens pull requests with fixes",
+	Long: `Audits all public and non-archived repos in an org without GitHub Actions.
+
+Each repo is cloned to a temporary directory and "copywrite headers" and
+"copywrite license" are run against it, using the repo's own .copywrite.hcl if
+it has one. If that results in any changes, they are committed to a new branch,
+pushed, and a pull request is opened against the repo's default branch.
+
+Repos are targeted and processed by the same worker pool as "copywrite
+dispatch", so the dispatch configuration (github_org_to_audit or org blocks,
+ignored_repos, workers, and batch_id) applies here too. Use --plan to list which repos would
+change without pushing anything.
+
+Repos with a huge number of changed files can be split into several pull
+requests of at most --chunk-size files each, so that reviewers aren't presented
+with a single enormous diff. Each is numbered in its title (e.g., "(2/7)") and
+labeled "copywrite".`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		loadDispatchFlags(cmd)
+
+		if orchestrateChunkSize < 0 {
+			err := fmt.Errorf("invalid --chunk-size %d: must not be negative", orchestrateChunkSize)
+			cliLogger.Error("Error validating flags", err)
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		client := gh.NewGHClient().Raw()
+
+		// Repos are referred to by their full names, as they may belong to
+		// several orgs
+		defaultBranches := map[string]string{}
+		names := []string{}
+		for _, org := range auditOrgs() {
+			repos, err := auditTargets(ctx, client, org)
+			checkTimeout(err)
+			for _, r := range repos {
+				name := fmt.Sprintf("%v/%v", org.Name, r.GetName())
+				defaultBranches[name] = r.GetDefaultBranch()
+				names = append(names, name)
+			}
+		}
+
+		self, err := os.Executable()
+		if err != nil {
+			cliLogger.Error("Unable to locate the copywrite executable", err)
+		}
+		cobra.CheckErr(err)
+
+		token := ""
+		if !plan {
+			var exists bool
+			token, exists, err = gh.Token(ctx)
+			if err == nil && !exists {
+				err = errors.New("no GitHub credentials found; pushing branches requires a GitHub App, GITHUB_TOKEN, or gh CLI login")
+			}
+			if err != nil {
+				cliLogger.Error("Unable to authenticate with GitHub", err)
+			}
+			cobra.CheckErr(err)
+		}
+
+		cliLogger.Info(fmt.Sprintf("Set to process %v GitHub repositories with %v concurrent workers", len(names), conf.Dispatch.Workers))
+
+		// Repos that had changes, and the PR opened for each (if any)
+		var mu sync.Mutex
+		changed := map[string]string{}
+
+		job := func(ctx context.Context, repo string) error {
+			owner, name, _ := strings.Cut(repo, "/")
+			a := orchestrateAudit{
+				owner:      owner,
+				repo:       name,
+				base:       defaultBranches[repo],
+				executable: self,
+				token:      token,
+			}
+			url, hasChanges, err := a.run(ctx, client)
+			if hasChanges {
+				mu.Lock()
+				changed[repo] = url
+				mu.Unlock()
+			}
+			return err
+		}
+
+		results := dispatch.Pool(ctx, conf.Dispatch.Workers, cliLogger.Named("orchestrate"), names, job)
+
+		if len(changed) > 0 {
+			repos := make([]string, 0, len(changed))
+			for r := range changed {
+				repos = append(repos, r)
+			}
+			sort.Strings(repos)
+
+			t := newTableWriter(cmd.OutOrStdout())
+			t.AppendHeader(table.Row{"Repo", "Pull Request"})
+			for _, r := range repos {
+				t.AppendRow(table.Row{r, changed[r]})
+			}
+			t.Render()
+		} else {
+			cliLogger.Info("No repos required changes")
+		}
+
+		if plan {
+			cliLogger.Info(text.FgYellow.Sprintf("Executing in dry-run mode. Rerun without the `--plan` flag to open pull requests against all %v repos.", len(changed)))
+		}
+
+		failures := []dispatch.Result{}
+		for _, r := range results {
+			if !r.Success {
+				failures = append(failures, r)
+			}
+		}
+		if len(failures) > 0 {
+			cliLogger.Error(fmt.Sprintf("Job failures occurred %d times:", len(failures)))
+			for _, f := range failures {
+				cliLogger.Error(fmt.Sprint(f))
+			}
+		}
+
+		checkTimeout(ctx.Err())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(orchestrateCmd)
+
+	// These flags are only locally relevant
+	orchestrateCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run, auditing every repo but not pushing any changes or opening pull requests")
+	orchestrateCmd.Flags().IntP("workers", "w", 2, "Concurrent jobs that can be ran")
+	orchestrateCmd.Flags().StringP("batch-id", "i", "", "A unique identifier for the current batch of audits, used in branch names (defaults to an autogenerated ID)")
+	orchestrateCmd.Flags().String("github-org", "hashicorp", "Sets the target GitHub org who's repos you wish to audit")
+	orchestrateCmd.Flags().Bool("respect-repo-config", false, "Skip repos whose .copywrite.hcl sets dispatch_opt_out or an upstream")
+	orchestrateCmd.Flags().IntVar(&orchestrateChunkSize, "chunk-size", 0, "Split the changes to each repo into pull requests of at most this many files (default is a single pull request)")
+	orchestrateCmd.Flags().StringVar(&orchestrateAuthor, "author", "copywrite <copywrite@users.noreply.github.com>", "Author of the commits made to each repo, as \"Name <email>\"")
+	addTimeoutFlag(orchestrateCmd)
+}
+
+// orchestrateAudit is the audit of a single repo by the orchestrate command
+type orchestrateAudit struct {
+	owner      string
+	repo       string
+	base       string
+	executable string
+	token      string
+}
+
+// run clones the repo, runs copywrite against it, and (unless --plan is set)
+// opens a pull request with any changes. It returns the URL of the pull
+// request, or "(plan)" in plan mode, and whether the repo had any changes.
+func (a orchestrateAudit) run(ctx context.Context, client *github.Client) (string, bool, error) {
+	tmp, err := os.MkdirTemp("", "copywrite-orchestrate-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(tmp)
+
+	dir := filepath.Join(tmp, a.repo)
+	url := fmt.Sprintf("https://github.com/%s/%s.git", a.owner, a.repo)
+	if err := git.Clone(url, dir); err != nil {
+		return "", false, err
+	}
+
+	for _, args := range [][]string{{"headers"}, {"license"}} {
+		c := exec.CommandContext(ctx, a.executable, append(args, "--quiet")...)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			return "", false, fmt.Errorf("copywrite %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	hasChanges, err := git.HasChanges(dir)
+	if err != nil || !hasChanges {
+		return "", false, err
+	}
+
+	var chunks [][]string
+	if orchestrateChunkSize > 0 {
+		files, err := git.ChangedFiles(dir)
+		if err != nil {
+			return "", true, err
+		}
+		chunks = lo.Chunk(files, orchestrateChunkSize)
+	}
+	if plan {
+		if len(chunks) > 1 {
+			return fmt.Sprintf("(plan: %d pull requests)", len(chunks)), true, nil
+		}
+		return "(plan)", true, nil
+	}
+
+	branch := "copywrite/" + conf.Dispatch.BatchID
+	title := "[COMPLIANCE] Add Copyright and License Headers"
+	if len(chunks) > 1 {
+		return a.openChunkedPRs(ctx, client, dir, url, branch, title, chunks)
+	}
+	if err := git.CommitAll(dir, branch, title, orchestrateAuthor); err != nil {
+		return "", true, err
+	}
+	if err := git.Push(dir, url, branch, a.token); err != nil {
+		return "", true, err
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, a.owner, a.repo, &github.NewPullRequest{
+		Title: github.String(title),
+		Head:  github.String(branch),
+		Base:  github.String(a.base),
+		Body:  github.String(fmt.Sprintf(orchestratePRBody, conf.Dispatch.BatchID)),
+	})
+	if err != nil {
+		return "", true, err
+	}
+	return pr.GetHTMLURL(), true, nil
+}
+
+// openChunkedPRs commits each chunk of the changed files in the clone at dir to
+// a branch of its own, all started from the commit that was cloned, and opens a
+// numbered and labeled pull request for each. It returns the URLs of the pull
+// requests, one per line.
+func (a orchestrateAudit) openChunkedPRs(ctx context.Context, client *github.Client, dir string, url string, branch string, title string, chunks [][]string) (string, bool, error) {
+	start, err := git.HeadCommit(dir)
+	if err != nil {
+		return "", true, err
+	}
+
+	urls := []string{}
+	for i, files := range chunks {
+		part := fmt.Sprintf("%d/%d", i+1, len(chunks))
+		chunkBranch := fmt.Sprintf("%s-%d", branch, i+1)
+		chunkTitle := fmt.Sprintf("%s (%s)", title, part)
+		if err := git.CommitFiles(dir, start, chunkBranch, chunkTitle, orchestrateAuthor, files); err != nil {
+			return strings.Join(urls, "\n"), true, fmt.Errorf("part %s: %w", part, err)
+		}
+		if err := git.Push(dir, url, chunkBranch, a.token); err != nil {
+			return strings.Join(urls, "\n"), true, fmt.Errorf("part %s: %w", part, err)
+		}
+
+		body := fmt.Sprintf(orchestratePRBody, conf.Dispatch.BatchID) + fmt.Sprintf(orchestrateChunkPRBody, i+1, len(chunks), orchestrateChunkSize)
+		pr, _, err := client.PullRequests.Create(ctx, a.owner, a.repo, &github.NewPullRequest{
+			Title: github.String(chunkTitle),
+			Head:  github.String(chunkBranch),
+			Base:  github.String(a.base),
+			Body:  github.String(body),
+		})
+		if err != nil {
+			return strings.Join(urls, "\n"), true, fmt.Errorf("part %s: %w", part, err)
+		}
+		urls = append(urls, pr.GetHTMLURL())
+
+		if _, _, err := client.Issues.AddLabelsToIssue(ctx, a.owner, a.repo, pr.GetNumber(), []string{orchestrateChunkLabel}); err != nil {
+			cliLogger.Warn(fmt.Sprintf("Unable to label %s", pr.GetHTMLURL()), "error", err)
+		}
+	}
+	return strings.Join(urls, "\n"), true, nil
+}
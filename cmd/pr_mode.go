@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/git"
+	gh "github.com/hashicorp/copywrite/github"
+	"github.com/hashicorp/copywrite/github/actions"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var prMode bool
+
+// prChanges are the files changed by a pull request, relative to the current
+// directory
+type prChanges struct {
+	// Added files are new in the pull request, and must have headers
+	Added map[string]bool
+
+	// Modified files existed before the pull request (including renamed files),
+	// and are only warned about if they're missing headers
+	Modified map[string]bool
+}
+
+// loadPRChanges reads the pull request that triggered the running GitHub
+// Actions workflow from its event payload, and lists the files it changes
+func loadPRChanges() (prChanges, error) {
+	if !gha.IsGHA() {
+		return prChanges{}, errors.New("--pr-mode can only be used in GitHub Actions")
+	}
+
+	event, err := readPREvent(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return prChanges{}, err
+	}
+	owner, name, _ := strings.Cut(event.GetRepo().GetFullName(), "/")
+	repo := gh.GHRepo{Owner: owner, Name: name}
+
+	client := gh.NewGHClient().Raw()
+	files, err := gh.GetPullRequestFiles(client, repo, event.GetNumber())
+	if err != nil {
+		return prChanges{}, fmt.Errorf("unable to list the files changed by pull request #%d: %w", event.GetNumber(), err)
+	}
+
+	prefix, err := git.Prefix(".")
+	if err != nil {
+		return prChanges{}, err
+	}
+	return classifyPRFiles(files, prefix), nil
+}
+
+// readPREvent parses the GitHub Actions event payload at path, which must be
+// for a pull_request or pull_request_target event
+func readPREvent(path string) (*github.PullRequestEvent, error) {
+	errNotPR := errors.New("--pr-mode requires a workflow triggered by a pull_request or pull_request_target event")
+	if path == "" {
+		return nil, errNotPR
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the GitHub Actions event payload: %w", err)
+	}
+	var event github.PullRequestEvent
+	if err := json.Unmarshal(b, &event); err != nil {
+		return nil, fmt.Errorf("unable to parse the GitHub Actions event payload: %w", err)
+	}
+	if event.PullRequest == nil || event.GetNumber() == 0 || event.GetRepo().GetFullName() == "" {
+		return nil, errNotPR
+	}
+	return &event, nil
+}
+
+// classifyPRFiles sorts the files changed by a pull request into those that
+// were added and modified, relative to prefix (the current directory's path
+// within the repo, as returned by git.Prefix). Removed files and those outside
+// of prefix are left out.
+func classifyPRFiles(files []*github.CommitFile, prefix string) prChanges {
+	c := prChanges{Added: map[string]bool{}, Modified: map[string]bool{}}
+	for _, f := range files {
+		path, ok := strings.CutPrefix(f.GetFilename(), prefix)
+		if !ok {
+			continue
+		}
+		switch f.GetStatus() {
+		case "added":
+			c.Added[path] = true
+		case "removed":
+		default:
+			c.Modified[path] = true
+		}
+	}
+	return c
+}
+
+// skip is an addlicense skip function that skips files that the pull request
+// doesn't change
+func (c prChanges) skip(path string) (bool, string) {
+	path = filepath.ToSlash(path)
+	if c.Added[path] || c.Modified[path] {
+		return false, ""
+	}
+	return true, "not changed by the pull request"
+}
+
+// enforce decides the outcome of a check-only run in PR mode, given the files
+// found to be missing headers and the error returned by the run: only added
+// files fail the check, while modified files that predate the pull request are
+// reported as warnings, so that enforcement can be phased in without requiring
+// every legacy file to be fixed first
+func (c prChanges) enforce(cmd *cobra.Command, missing []string, err error) error {
+	if !errors.Is(err, addlicense.ErrMissingHeader) {
+		return err
+	}
+
+	added := 0
+	for _, path := range missing {
+		path = filepath.ToSlash(path)
+		if c.Added[path] {
+			added++
+			gha.Error(actions.Annotation{
+				Title:   "Missing copyright header",
+				Message: "New files must have a copyright and license header. Run `copywrite headers` to add one.",
+				File:    path,
+			})
+			continue
+		}
+		cliLogger.Warn(fmt.Sprintf("%s is missing a header, but predates this pull request", path))
+		gha.Warning(actions.Annotation{
+			Title:   "Missing copyright header",
+			Message: "This file is missing a copyright and license header. Consider running `copywrite headers` to add one.",
+			File:    path,
+		})
+	}
+
+	if added > 0 {
+		return fmt.Errorf("%d files added by the pull request are missing headers: %w", added, err)
+	}
+	cmd.Printf("All files added by the pull request have headers; %d modified files are missing them\n", len(missing))
+	return nil
+}
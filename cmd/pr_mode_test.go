@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readPREvent(t *testing.T) {
+	dir := t.TempDir()
+
+	prEvent := filepath.Join(dir, "pull_request.json")
+	assert.Nil(t, os.WriteFile(prEvent, []byte(`{
+  "action": "synchronize",
+  "number": 42,
+  "pull_request": {"number": 42},
+  "repository": {"full_name": "hashicorp/copywrite"}
+}`), 0644))
+	event, err := readPREvent(prEvent)
+	assert.Nil(t, err)
+	assert.Equal(t, 42, event.GetNumber())
+	assert.Equal(t, "hashicorp/copywrite", event.GetRepo().GetFullName())
+
+	pushEvent := filepath.Join(dir, "push.json")
+	assert.Nil(t, os.WriteFile(pushEvent, []byte(`{"ref": "refs/heads/main", "repository": {"full_name": "hashicorp/copywrite"}}`), 0644))
+	_, err = readPREvent(pushEvent)
+	assert.EqualError(t, err, "--pr-mode requires a workflow triggered by a pull_request or pull_request_target event")
+
+	_, err = readPREvent("")
+	assert.NotNil(t, err)
+}
+
+func Test_classifyPRFiles(t *testing.T) {
+	files := []*github.CommitFile{
+		{Filename: github.String("sub/new.go"), Status: github.String("added")},
+		{Filename: github.String("sub/old.go"), Status: github.String("modified")},
+		{Filename: github.String("sub/moved.go"), Status: github.String("renamed")},
+		{Filename: github.String("sub/gone.go"), Status: github.String("removed")},
+		{Filename: github.String("other/new.go"), Status: github.String("added")},
+	}
+
+	c := classifyPRFiles(files, "sub/")
+	assert.Equal(t, map[string]bool{"new.go": true}, c.Added)
+	assert.Equal(t, map[string]bool{"old.go": true, "moved.go": true}, c.Modified)
+
+	skip, _ := c.skip("new.go")
+	assert.False(t, skip)
+	skip, _ = c.skip("old.go")
+	assert.False(t, skip)
+	skip, reason := c.skip("untouched.go")
+	assert.True(t, skip)
+	assert.Equal(t, "not changed by the pull request", reason)
+}
+
+func Test_prChanges_enforce(t *testing.T) {
+	cliLogger = hclog.NewNullLogger()
+
+	c := prChanges{
+		Added:    map[string]bool{"new.go": true},
+		Modified: map[string]bool{"old.go": true},
+	}
+
+	tests := []struct {
+		name        string
+		missing     []string
+		err         error
+		expectedErr string
+	}{
+		{
+			name: "No files missing headers",
+		},
+		{
+			name:    "Only modified files missing headers",
+			missing: []string{"old.go"},
+			err:     addlicense.ErrMissingHeader,
+		},
+		{
+			name:        "Added files missing headers",
+			missing:     []string{"new.go", "old.go"},
+			err:         addlicense.ErrMissingHeader,
+			expectedErr: "1 files added by the pull request are missing headers: missing license header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.SetOut(&bytes.Buffer{})
+			err := c.enforce(cmd, tt.missing, tt.err)
+			if tt.expectedErr == "" {
+				assert.Nil(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
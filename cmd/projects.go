@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// projectResult is the outcome of running a command against a single project
+// in a monorepo
+type projectResult struct {
+	Dir    string
+	Output []byte
+	Err    error
+}
+
+// discoverProjects returns the directory of every .copywrite.hcl config under
+// root, including root itself if it has one, sorted by path
+func discoverProjects(root string) ([]string, error) {
+	projects := []string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == ".copywrite.hcl" {
+			projects = append(projects, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(projects)
+	return projects, nil
+}
+
+// nestedProjectPatterns returns patterns matching every project nested beneath
+// the current directory, so that a project's files are only ever processed
+// using its own config
+func nestedProjectPatterns() ([]string, error) {
+	projects, err := discoverProjects(".")
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := []string{}
+	for _, p := range projects {
+		if p != "." {
+			patterns = append(patterns, filepath.ToSlash(p)+"/**")
+		}
+	}
+	return patterns, nil
+}
+
+// runAllProjects re-runs cmd in a separate process for every project found
+// under the current directory, so that each is processed with its own config,
+// and prints a combined report. Running each project in its own process keeps
+// their configs isolated from one another while they run in parallel. Any flags
+// set on cmd, other than --config and skipFlags, are passed along to every
+// project.
+func runAllProjects(cmd *cobra.Command, skipFlags ...string) error {
+	projects, err := discoverProjects(".")
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		return fmt.Errorf("no .copywrite.hcl configs were found")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := strings.Fields(cmd.CommandPath())[1:]
+	args = append(args, "--skip-nested-projects")
+	skipFlags = append(skipFlags, "config")
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		for _, skip := range skipFlags {
+			if f.Name == skip {
+				return
+			}
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+
+	cmd.Printf("Found %d projects, running: copywrite %s\n\n", len(projects), strings.Join(args, " "))
+
+	results := make([]projectResult, len(projects))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, dir := range projects {
+		i, dir := i, dir // https://golang.org/doc/faq#closures_and_goroutines
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var out bytes.Buffer
+			c := exec.Command(exe, args...)
+			c.Dir = dir
+			// Output from every project is printed within a group of its own, and
+			// GitHub Actions does not support nested groups
+			c.Env = append(os.Environ(), "GITHUB_ACTIONS=false")
+			c.Stdout = &out
+			c.Stderr = &out
+			results[i] = projectResult{Dir: dir, Err: c.Run()}
+			results[i].Output = out.Bytes()
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		gha.StartGroup(fmt.Sprintf("Project: %s", r.Dir))
+		cmd.Print(string(r.Output))
+		gha.EndGroup()
+		cmd.Println("")
+	}
+
+	failed := 0
+	gha.StartGroup("Results:")
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			cmd.Printf("❌ %s: %s\n", r.Dir, r.Err)
+		} else {
+			cmd.Printf("✔️ %s\n", r.Dir)
+		}
+	}
+	gha.EndGroup()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d projects failed", failed, len(projects))
+	}
+	return nil
+}
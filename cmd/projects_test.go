@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_discoverProjects(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		".copywrite.hcl",
+		"services/api/.copywrite.hcl",
+		"services/api/main.go",
+		"services/web/main.go",
+		"libs/sdk/.copywrite.hcl",
+		"node_modules/dep/.copywrite.hcl",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(""), 0644))
+	}
+
+	actual, err := discoverProjects(root)
+	assert.Nil(t, err)
+
+	expected := []string{
+		root,
+		filepath.Join(root, "libs/sdk"),
+		filepath.Join(root, "services/api"),
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"github.com/hashicorp/copywrite/github"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var githubRepo string
+
+// addGitHubRepoFlag registers the --github-repo flag for a command that needs
+// to know which GitHub repo it is running against, which must then use
+// discoverRepo rather than calling github.DiscoverRepo directly
+func addGitHubRepoFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&githubRepo, "github-repo", "", "GitHub repo to use, as \"owner/name\" (default is discovered from git remotes or $GITHUB_REPOSITORY)")
+}
+
+// discoverRepo returns the GitHub repo given by the --github-repo flag or, if
+// it wasn't set, the one related to the current directory
+func discoverRepo() (github.GHRepo, error) {
+	if githubRepo != "" {
+		return github.ParseRepo(githubRepo)
+	}
+	return github.DiscoverRepo()
+}
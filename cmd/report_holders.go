@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var holderExamples int
+
+// holderSummary is a distinct copyright holder found in the project, along
+// with the files whose headers name it
+type holderSummary struct {
+	Holder string
+	Files  []string
+}
+
+var reportHoldersCmd = &cobra.Command{
+	Use:   "holders",
+	Short: "Inventories every copyright holder named in the project",
+	Long: `Scans the headers of all files in the project for copyright statements and
+lists every distinct copyright holder they name, exactly as written, along with
+how many files name it and a few example paths. Holders other than the
+project's copyright holder are marked as third-party.
+
+Holders are listed as written, so that variations such as "HashiCorp Inc" and
+"HashiCorp, Inc." can be found and cleaned up. This is useful for inventorying
+third-party code before a relicense; use --csv to export the list for review.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`copyright-holder`: `project.copyright_holder`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+
+		if holderExamples < 0 {
+			cobra.CheckErr(fmt.Sprintf("invalid --examples %d: must not be negative", holderExamples))
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if csv {
+			text.DisableColors()
+		}
+
+		holders, err := scanHolders()
+		if err != nil {
+			cliLogger.Error("Error scanning copyright statements", err)
+		}
+		cobra.CheckErr(err)
+
+		if len(holders) == 0 {
+			cmd.Println("No copyright statements found")
+			return
+		}
+
+		t := newTableWriter(cmd.OutOrStdout())
+		t.AppendHeader(table.Row{"Holder", "Files", "Third-Party", "Examples"})
+		for _, h := range holders {
+			thirdParty := "no"
+			if thirdPartyNote(h.Holder) != "" {
+				thirdParty = text.FgYellow.Sprint("yes")
+			}
+			examples := h.Files[:min(len(h.Files), holderExamples)]
+			t.AppendRow(table.Row{h.Holder, len(h.Files), thirdParty, strings.Join(examples, ", ")})
+		}
+		if csv {
+			t.RenderCSV()
+		} else {
+			t.Render()
+		}
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportHoldersCmd)
+
+	// These flags are only locally relevant
+	reportHoldersCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to scan headers")
+	reportHoldersCmd.Flags().IntVar(&holderExamples, "examples", 3, "Number of example paths to list for each holder")
+	reportHoldersCmd.Flags().BoolVar(&csv, "csv", false, "Render output as CSV instead of a table")
+
+	// These flags will get mapped to keys in the the global Config
+	reportHoldersCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+}
+
+// scanHolders returns every distinct copyright holder named in the headers of
+// the project's files, with the files naming each sorted by path, ordered from
+// the most files to the fewest
+func scanHolders() ([]holderSummary, error) {
+	var mu sync.Mutex
+	files := map[string][]string{}
+
+	opts := headerOptions()
+	err := addlicense.Walk(conf.Project.HeaderIgnore, []string{"."}, stdLogger(), opts, func(path string) error {
+		b, err := addlicense.ReadHead(path)
+		if err != nil {
+			return err
+		}
+
+		seen := map[string]bool{}
+		for _, s := range licensecheck.ParseCopyrightStatements(b) {
+			if seen[s.Holder] {
+				continue
+			}
+			seen[s.Holder] = true
+
+			mu.Lock()
+			files[s.Holder] = append(files[s.Holder], path)
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeHolders(files), nil
+}
+
+// summarizeHolders sorts the files naming each holder, and orders holders from
+// the most files to the fewest, then by name
+func summarizeHolders(files map[string][]string) []holderSummary {
+	holders := make([]holderSummary, 0, len(files))
+	for holder, paths := range files {
+		sort.Strings(paths)
+		holders = append(holders, holderSummary{Holder: holder, Files: paths})
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if len(holders[i].Files) != len(holders[j].Files) {
+			return len(holders[i].Files) > len(holders[j].Files)
+		}
+		return holders[i].Holder < holders[j].Holder
+	})
+	return holders
+}
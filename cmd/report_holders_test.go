@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_summarizeHolders(t *testing.T) {
+	holders := summarizeHolders(map[string][]string{
+		"HashiCorp, Inc.": {"b.go", "a.go", "c/d.go"},
+		"Example Corp":    {"vendor/x.go"},
+		"Another Corp":    {"vendor/y.go"},
+	})
+
+	assert.Equal(t, []holderSummary{
+		{Holder: "HashiCorp, Inc.", Files: []string{"a.go", "b.go", "c/d.go"}},
+		{Holder: "Another Corp", Files: []string{"vendor/y.go"}},
+		{Holder: "Example Corp", Files: []string{"vendor/x.go"}},
+	}, holders)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/dispatch"
+	gh "github.com/hashicorp/copywrite/github"
+	"github.com/hashicorp/copywrite/repodata"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var driftWorkers int
+
+// licenseDriftRow is a repo whose config and LICENSE file disagree
+type licenseDriftRow struct {
+	Repo     string
+	Declared string
+	Detected string
+	Problem  string
+}
+
+var reportLicenseDriftCmd = &cobra.Command{
+	Use:   "license-drift",
+	Short: "Finds repos whose LICENSE file doesn't match their .copywrite.hcl",
+	Long: `Finds repos whose LICENSE file doesn't match their .copywrite.hcl
+
+For every public, non-archived repo in the org, the license GitHub detects from
+the repo's LICENSE file is compared with the project.license declared in the
+repo's .copywrite.hcl (fetched via the contents API). Repos where the two
+disagree, such as after a relicense that only updated one of them, are listed.
+Repos without a .copywrite.hcl or without a declared license are not checked.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if csv {
+			text.DisableColors()
+		}
+
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		client := gh.NewGHClient().Raw()
+
+		allRepos, err := fetchOrgRepos(ctx, githubOrgToAudit)
+		if err != nil {
+			cliLogger.Error(fmt.Sprintf("Error retrieving public repos for the \"%v\" org", githubOrgToAudit), err)
+		}
+		checkTimeout(err)
+
+		drift, failures := findLicenseDrift(ctx, client, repodata.FilterRepos(allRepos))
+		for _, f := range failures {
+			cliLogger.Warn(fmt.Sprintf("Unable to read the config of %v/%v: %v", githubOrgToAudit, f.Name, f.Error))
+		}
+		checkTimeout(ctx.Err())
+
+		if len(drift) == 0 {
+			cmd.Println("No license drift found")
+			return
+		}
+
+		t := newTableWriter(cmd.OutOrStdout())
+		t.AppendHeader(table.Row{"Repo", "Declared in Config", "Detected by GitHub", "Problem"})
+		for _, d := range drift {
+			t.AppendRow(table.Row{text.FgCyan.Sprint(d.Repo), d.Declared, d.Detected, d.Problem})
+		}
+		if csv {
+			t.RenderCSV()
+		} else {
+			t.Render()
+		}
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportLicenseDriftCmd)
+
+	reportLicenseDriftCmd.Flags().StringVar(&githubOrgToAudit, "github-org", "hashicorp", "Sets the target GitHub org who's repos you wish to audit")
+	reportLicenseDriftCmd.Flags().IntVarP(&driftWorkers, "workers", "w", 4, "Number of repo configs to fetch concurrently")
+	reportLicenseDriftCmd.Flags().BoolVar(&csv, "csv", false, "Render output as CSV instead of a table")
+	addTimeoutFlag(reportLicenseDriftCmd)
+}
+
+// findLicenseDrift fetches the config of every repo and returns those whose
+// declared license disagrees with GitHub's detection, sorted by repo, along
+// with the repos whose config couldn't be fetched
+func findLicenseDrift(ctx context.Context, client *github.Client, repos []*github.Repository) ([]licenseDriftRow, []dispatch.Result) {
+	detected := map[string]string{}
+	names := make([]string, 0, len(repos))
+	for _, r := range repos {
+		detected[r.GetName()] = r.GetLicense().GetSPDXID()
+		names = append(names, r.GetName())
+	}
+
+	var mu sync.Mutex
+	drift := []licenseDriftRow{}
+	job := func(ctx context.Context, repo string) error {
+		c, err := dispatch.RepoConfig(ctx, client, githubOrgToAudit, repo)
+		if err != nil || c == nil {
+			return err
+		}
+		if problem := licenseDrift(c.Project.License, detected[repo]); problem != "" {
+			mu.Lock()
+			drift = append(drift, licenseDriftRow{
+				Repo:     fmt.Sprintf("%v/%v", githubOrgToAudit, repo),
+				Declared: c.Project.License,
+				Detected: detected[repo],
+				Problem:  problem,
+			})
+			mu.Unlock()
+		}
+		return nil
+	}
+
+	failures := []dispatch.Result{}
+	for _, r := range dispatch.Pool(ctx, driftWorkers, cliLogger.Named("license-drift"), names, job) {
+		if !r.Success {
+			failures = append(failures, r)
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Repo < drift[j].Repo })
+	return drift, failures
+}
+
+// licenseDrift describes how the license declared in a repo's config differs
+// from the SPDX identifier GitHub detected from its LICENSE file, or returns ""
+// if they agree or no license is declared
+func licenseDrift(declared string, detected string) string {
+	switch {
+	case declared == "":
+		return ""
+	case detected == "":
+		return "no LICENSE file detected"
+	case detected == "NOASSERTION":
+		return "LICENSE file not recognized by GitHub"
+	case !strings.EqualFold(declared, detected):
+		return "licenses differ"
+	}
+	return ""
+}
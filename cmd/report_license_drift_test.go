@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_licenseDrift(t *testing.T) {
+	tests := []struct {
+		name           string
+		declared       string
+		detected       string
+		expectedOutput string
+	}{
+		{
+			name:           "Matching licenses don't drift",
+			declared:       "MPL-2.0",
+			detected:       "MPL-2.0",
+			expectedOutput: "",
+		},
+		{
+			name:           "Case is ignored",
+			declared:       "mpl-2.0",
+			detected:       "MPL-2.0",
+			expectedOutput: "",
+		},
+		{
+			name:           "Repos that don't declare a license aren't checked",
+			declared:       "",
+			detected:       "MIT",
+			expectedOutput: "",
+		},
+		{
+			name:           "Relicensed repos drift",
+			declared:       "BUSL-1.1",
+			detected:       "MPL-2.0",
+			expectedOutput: "licenses differ",
+		},
+		{
+			name:           "Missing LICENSE files drift",
+			declared:       "MPL-2.0",
+			detected:       "",
+			expectedOutput: "no LICENSE file detected",
+		},
+		{
+			name:           "Unrecognized LICENSE files drift",
+			declared:       "MPL-2.0",
+			detected:       "NOASSERTION",
+			expectedOutput: "LICENSE file not recognized by GitHub",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, licenseDrift(tt.declared, tt.detected))
+		})
+	}
+}
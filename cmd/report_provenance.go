@@ -0,0 +1,232 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var noticesDraft bool
+
+// provenanceEntry records a copyright statement for a third-party holder found
+// in the header of a file
+type provenanceEntry struct {
+	Path    string
+	Holder  string
+	Years   string
+	License string
+}
+
+var reportProvenanceCmd = &cobra.Command{
+	Use:   "provenance",
+	Short: "Reports on files whose copyright holder differs from the project's",
+	Long: `Scans the headers of all files in the project for copyright statements naming
+a holder other than the configured copyright holder, such as code vendored from
+or contributed by third parties, and reports them grouped by holder and
+directory.
+
+Use the --notices-draft flag to instead print a draft of third-party notices,
+which can be used as the starting point for a NOTICE file.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`copyright-holder`: `project.copyright_holder`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := scanProvenance(conf.Project.CopyrightHolder)
+		if err != nil {
+			cliLogger.Error("Error scanning copyright statements", err)
+		}
+		cobra.CheckErr(err)
+
+		if len(entries) == 0 {
+			cmd.Printf("All copyright statements name the project's copyright holder: %s\n", conf.Project.CopyrightHolder)
+			return
+		}
+
+		if noticesDraft {
+			cmd.Print(renderNoticesDraft(entries))
+			return
+		}
+
+		// Group files by holder, then by directory
+		type group struct {
+			holder   string
+			dir      string
+			files    int
+			licenses []string
+		}
+		groups := map[[2]string]*group{}
+		for _, e := range entries {
+			key := [2]string{e.Holder, filepath.Dir(e.Path)}
+			g, exists := groups[key]
+			if !exists {
+				g = &group{holder: key[0], dir: key[1]}
+				groups[key] = g
+			}
+			g.files++
+			if e.License != "" && !lo.Contains(g.licenses, e.License) {
+				g.licenses = append(g.licenses, e.License)
+			}
+		}
+
+		rows := lo.Values(groups)
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].holder != rows[j].holder {
+				return rows[i].holder < rows[j].holder
+			}
+			return rows[i].dir < rows[j].dir
+		})
+
+		t := newTableWriter(cmd.OutOrStdout())
+		t.AppendHeader(stringArrayToRow([]string{"Holder", "Directory", "Files", "Licenses"}))
+		for _, g := range rows {
+			sort.Strings(g.licenses)
+			t.AppendRow(stringArrayToRow([]string{g.holder, g.dir, strconv.Itoa(g.files), strings.Join(g.licenses, ", ")}))
+		}
+		t.Render()
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportProvenanceCmd)
+
+	// These flags are only locally relevant
+	reportProvenanceCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to scan headers")
+	reportProvenanceCmd.Flags().BoolVar(&noticesDraft, "notices-draft", false, "Print a draft of third-party notices instead of a table")
+
+	// These flags will get mapped to keys in the the global Config
+	reportProvenanceCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+}
+
+// scanProvenance returns every copyright statement in the project whose holder
+// does not include the given holder, sorted by path. Files matching any of the
+// exclude patterns are not scanned, in addition to the header_ignore list.
+func scanProvenance(holder string, exclude ...string) ([]provenanceEntry, error) {
+	var mu sync.Mutex
+	entries := []provenanceEntry{}
+
+	opts := headerOptions()
+	ignore := append(append([]string{}, conf.Project.HeaderIgnore...), exclude...)
+	err := addlicense.Walk(ignore, []string{"."}, stdLogger(), opts, func(path string) error {
+		b, err := addlicense.ReadHead(path)
+		if err != nil {
+			return err
+		}
+
+		license := strings.Join(addlicense.SPDXExpressions(b), " AND ")
+		for _, s := range licensecheck.ParseCopyrightStatements(b) {
+			if strings.Contains(strings.ToLower(s.Holder), strings.ToLower(holder)) {
+				continue
+			}
+
+			mu.Lock()
+			entries = append(entries, provenanceEntry{Path: path, Holder: s.Holder, Years: s.Years, License: license})
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// noticeComponent summarizes all copyright statements for a single third-party
+// holder
+type noticeComponent struct {
+	Holder   string
+	Years    []string
+	Licenses []string
+	Dirs     []string
+}
+
+// Statement returns the copyright statement for the component, e.g.
+// "Copyright 2015, 2018 Jane Doe"
+func (c noticeComponent) Statement() string {
+	if len(c.Years) == 0 {
+		return "Copyright " + c.Holder
+	}
+	return fmt.Sprintf("Copyright %s %s", strings.Join(c.Years, ", "), c.Holder)
+}
+
+// License returns the licenses of the component, or "Unknown"
+func (c noticeComponent) License() string {
+	if len(c.Licenses) == 0 {
+		return "Unknown"
+	}
+	return strings.Join(c.Licenses, ", ")
+}
+
+// groupProvenance groups provenance entries into one component per holder,
+// sorted by holder
+func groupProvenance(entries []provenanceEntry) []noticeComponent {
+	byHolder := lo.GroupBy(entries, func(e provenanceEntry) string { return e.Holder })
+	holders := lo.Keys(byHolder)
+	sort.Strings(holders)
+
+	components := []noticeComponent{}
+	for _, holder := range holders {
+		group := byHolder[holder]
+
+		c := noticeComponent{
+			Holder:   holder,
+			Years:    lo.Uniq(lo.FilterMap(group, func(e provenanceEntry, _ int) (string, bool) { return e.Years, e.Years != "" })),
+			Licenses: lo.Uniq(lo.FilterMap(group, func(e provenanceEntry, _ int) (string, bool) { return e.License, e.License != "" })),
+			Dirs:     lo.Uniq(lo.Map(group, func(e provenanceEntry, _ int) string { return filepath.ToSlash(filepath.Dir(e.Path)) })),
+		}
+		sort.Strings(c.Years)
+		sort.Strings(c.Licenses)
+		sort.Strings(c.Dirs)
+		components = append(components, c)
+	}
+	return components
+}
+
+// renderNoticesDraft renders third-party copyright statements as a markdown
+// draft of a notices file, with a section for each holder
+func renderNoticesDraft(entries []provenanceEntry) string {
+	var sb strings.Builder
+	sb.WriteString("# Third-Party Notices (Draft)\n\n")
+	sb.WriteString("This project includes material from the third parties listed below.\n")
+
+	for _, c := range groupProvenance(entries) {
+		fmt.Fprintf(&sb, "\n## %s\n\n%s\n\nLicense: %s\n\nFound in:\n", c.Holder, c.Statement(), c.License())
+		for _, d := range c.Dirs {
+			fmt.Fprintf(&sb, "- %s\n", d)
+		}
+	}
+	return sb.String()
+}
@@ -4,8 +4,8 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/google/go-github/v45/github"
@@ -35,6 +35,9 @@ copyright notice automation tooling will be authored by "hashicorp-copywrite"`,
 			text.DisableColors()
 		}
 
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
 		client := gh.NewGHClient().Raw()
 
 		opt := &github.SearchOptions{
@@ -61,10 +64,9 @@ copyright notice automation tooling will be authored by "hashicorp-copywrite"`,
 		// pagination to retrieve all issues
 		var prs []github.Issue
 		for {
-			page, current, err := client.Search.Issues(context.Background(), query, opt)
+			page, current, err := client.Search.Issues(ctx, query, opt)
 
-			// TODO: retry and gracefully degrade
-			cobra.CheckErr(err)
+			checkTimeout(err)
 
 			for _, issue := range page.Issues {
 				prs = append(prs, *issue)
@@ -77,6 +79,14 @@ copyright notice automation tooling will be authored by "hashicorp-copywrite"`,
 			opt.Page = current.NextPage
 		}
 
+		// Sort by repo and PR number so that repeated runs are easy to diff
+		sort.SliceStable(prs, func(i, j int) bool {
+			if *prs[i].RepositoryURL != *prs[j].RepositoryURL {
+				return *prs[i].RepositoryURL < *prs[j].RepositoryURL
+			}
+			return *prs[i].Number < *prs[j].Number
+		})
+
 		// Let's turn this into some tabular data and render it out
 
 		t := newTableWriter(cmd.OutOrStdout())
@@ -111,4 +121,5 @@ func init() {
 	reportPRsCmd.Flags().BoolVar(&csv, "csv", false, "Outputs data in CSV format")
 	reportPRsCmd.Flags().StringVar(&author, "author", "app/hashicorp-copywrite", "Search for PRs created by a specific author")
 	reportPRsCmd.Flags().StringVar(&status, "status", "open", "Filters on PR status, valid options are: open|closed|all")
+	addTimeoutFlag(reportPRsCmd)
 }
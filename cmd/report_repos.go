@@ -6,6 +6,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/hashicorp/copywrite/repodata"
 	"github.com/spf13/cobra"
@@ -36,16 +37,24 @@ Outputs the fields you specify in a repodata.csv file in the working directory.`
 		cobra.CheckErr(err)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
 		// get all public repos under org
-		unfilteredRepos, err := repodata.GetRepos(githubOrgToAudit)
+		unfilteredRepos, err := repodata.GetRepos(ctx, githubOrgToAudit)
 		if err != nil {
 			cliLogger.Error(fmt.Sprintf("Error retrieving public repos for the \"%v\" org", githubOrgToAudit), err)
 		}
-		cobra.CheckErr(err)
+		checkTimeout(err)
 
 		// remove archived repos
 		filteredRepos := repodata.FilterRepos(unfilteredRepos)
 
+		// Sort by name so that repeated runs are easy to diff
+		sort.SliceStable(filteredRepos, func(i, j int) bool {
+			return filteredRepos[i].GetFullName() < filteredRepos[j].GetFullName()
+		})
+
 		// transform repos into a string map
 		outputData, err := repodata.Transform(filteredRepos)
 		if err != nil {
@@ -93,4 +102,5 @@ func init() {
 
 	reportReposCmd.Flags().StringVarP(&fields, "fields", "f", "Name,License,HTMLURL", "Repo attributes you wish to report on")
 	reportReposCmd.Flags().StringVar(&githubOrgToAudit, "github-org", "hashicorp", "Sets the target GitHub org who's repos you wish to audit")
+	addTimeoutFlag(reportReposCmd)
 }
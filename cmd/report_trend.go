@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/copywrite/git"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	trendDB       string
+	trendNoRecord bool
+)
+
+var reportTrendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Records header compliance over time and reports the trend",
+	Long: `Checks every file in the project for a copyright header, the same way as
+"copywrite headers --plan", and appends the results to a JSON file of past runs.
+A table of every recorded run is then printed, showing how compliance has
+changed over time.
+
+Run it on a schedule (e.g., weekly in CI) and persist the file between runs to
+show progress quarter over quarter. Use --no-record to print the table without
+checking headers or adding a run.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runs, err := readTrend(trendDB)
+		if err != nil {
+			cliLogger.Error("Error reading trend file", err)
+		}
+		cobra.CheckErr(err)
+
+		if !trendNoRecord {
+			stats, err := headerCompliance()
+			if err != nil {
+				cliLogger.Error("Error checking headers", err)
+			}
+			cobra.CheckErr(err)
+
+			run := trendRun{
+				Time:    time.Now().UTC().Truncate(time.Second),
+				Scanned: stats.Scanned,
+				Missing: stats.Added,
+				Percent: compliancePercent(stats),
+			}
+			if c, err := git.HeadCommit("."); err == nil {
+				run.Commit = c
+			}
+			runs = append(runs, run)
+
+			err = writeTrend(trendDB, runs)
+			if err != nil {
+				cliLogger.Error("Error writing trend file", err)
+			}
+			cobra.CheckErr(err)
+		}
+
+		if len(runs) == 0 {
+			cmd.Printf("No runs have been recorded in %s\n", trendDB)
+			return
+		}
+		printTrend(cmd, runs)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportTrendCmd)
+
+	// These flags are only locally relevant
+	reportTrendCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to check headers")
+	reportTrendCmd.Flags().StringVar(&trendDB, "db", "copywrite-trend.json", "Path to the JSON file in which runs are recorded, relative to --dirPath")
+	reportTrendCmd.Flags().BoolVar(&trendNoRecord, "no-record", false, "Print the recorded trend without checking headers or recording a new run")
+}
+
+// trendRun is the header compliance of a project at one point in time
+type trendRun struct {
+	Time    time.Time `json:"time"`
+	Commit  string    `json:"commit,omitempty"`
+	Scanned int       `json:"scanned"`
+	Missing int       `json:"missing"`
+	Percent int       `json:"percent"`
+}
+
+// trendFile is the format of the file in which runs are recorded
+type trendFile struct {
+	reportMetadata
+	Runs []trendRun `json:"runs"`
+}
+
+// readTrend returns the runs recorded in the file at path, which may not exist
+// yet
+func readTrend(path string) ([]trendRun, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f trendFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("%s is not a valid trend file: %w", path, err)
+	}
+	if f.SchemaVersion > reportSchemaVersion {
+		return nil, fmt.Errorf("%s was written by a newer version of copywrite (schema version %d)", path, f.SchemaVersion)
+	}
+	return f.Runs, nil
+}
+
+// writeTrend records runs in the file at path, replacing its contents
+func writeTrend(path string, runs []trendRun) error {
+	b, err := json.MarshalIndent(trendFile{reportMetadata: newReportMetadata(), Runs: runs}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// trendBar returns a bar representing percent, for a chart of compliance over
+// time
+func trendBar(percent int) string {
+	const width = 20
+	filled := min(max(percent*width/100, 0), width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// printTrend prints a table of runs, with the change in compliance since the
+// previous run and a bar chart of compliance
+func printTrend(cmd *cobra.Command, runs []trendRun) {
+	t := newTableWriter(cmd.OutOrStdout())
+	t.AppendHeader(table.Row{"Date", "Commit", "Scanned", "Missing", "Compliance", "Change", ""})
+	for i, r := range runs {
+		change := ""
+		if i > 0 {
+			change = fmt.Sprintf("%+d%%", r.Percent-runs[i-1].Percent)
+		}
+		t.AppendRow(table.Row{r.Time.Format("2006-01-02"), r.Commit, r.Scanned, r.Missing, fmt.Sprintf("%d%%", r.Percent), change, trendBar(r.Percent)})
+	}
+	t.Render()
+}
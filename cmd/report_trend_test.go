@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_trendFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trend.json")
+
+	runs, err := readTrend(path)
+	assert.NoError(t, err, "A missing trend file has no runs")
+	assert.Empty(t, runs)
+
+	runs = []trendRun{
+		{Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Commit: "abc1234", Scanned: 10, Missing: 5, Percent: 50},
+		{Time: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Scanned: 12, Missing: 0, Percent: 100},
+	}
+	assert.NoError(t, writeTrend(path, runs))
+
+	actual, err := readTrend(path)
+	assert.NoError(t, err)
+	assert.Equal(t, runs, actual)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"schema_version": 1`)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"schema_version": 99, "runs": []}`), 0644))
+	_, err = readTrend(path)
+	assert.ErrorContains(t, err, "newer version of copywrite")
+}
+
+func Test_trendBar(t *testing.T) {
+	assert.Equal(t, "░░░░░░░░░░░░░░░░░░░░", trendBar(0))
+	assert.Equal(t, "██████████░░░░░░░░░░", trendBar(50))
+	assert.Equal(t, "████████████████████", trendBar(100))
+}
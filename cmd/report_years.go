@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/git"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	anomalyYear int
+	fixYears    bool
+)
+
+var reportYearsCmd = &cobra.Command{
+	Use:   "years",
+	Short: "Reports copyright statements with implausible years",
+	Long: `Scans the headers of all files in the project for copyright statements whose
+years are implausible, suggesting a corrected statement for each:
+
+  reversed         the end of a range precedes its start, e.g. "2025, 2019"
+  future           a year is after the current year
+  before-creation  a year is before the project was created
+
+The project's creation year is taken from the project.copyright_year config key,
+or else from the date of the first commit in the git history. If neither is
+available, years before creation are not flagged.
+
+The command fails if any anomalies are found. Use --fix to apply the suggested
+corrections instead.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		if fixYears {
+			err := checkSafePath(".")
+			if err != nil {
+				cliLogger.Error("Error validating target directory", err)
+			}
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		found, err := scanYearAnomalies(cmd, anomalyYear, fixYears)
+		if err != nil {
+			cliLogger.Error("Error scanning copyright years", err)
+		}
+		cobra.CheckErr(err)
+
+		if len(found) == 0 {
+			cmd.Println("No implausible copyright years found")
+			return
+		}
+		printYearAnomalies(cmd, found)
+
+		if fixYears {
+			cmd.Println(text.FgGreen.Sprintf("\nFixed copyright years in %d files", len(found)))
+			return
+		}
+		cobra.CheckErr(fmt.Sprintf("%d files have implausible copyright years. Run with the --fix flag to apply the suggested corrections", len(found)))
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportYearsCmd)
+
+	// These flags are only locally relevant
+	reportYearsCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to scan headers")
+	reportYearsCmd.Flags().IntVar(&anomalyYear, "year", time.Now().Year(), "Current year, after which years are considered to be in the future")
+	reportYearsCmd.Flags().BoolVar(&fixYears, "fix", false, "Apply the suggested corrections")
+	reportYearsCmd.Flags().IntVar(&parallelism, "parallelism", 0, "Number of directories to read concurrently while discovering files (default is the number of CPUs)")
+	addAllowUnsafePathsFlag(reportYearsCmd)
+}
+
+// applyYearFixes replaces each anomalous line in b with its suggested fix
+func applyYearFixes(b []byte, anomalies []licensecheck.YearAnomaly) []byte {
+	lines := strings.Split(string(b), "\n")
+	for _, a := range anomalies {
+		if a.Line-1 < len(lines) && lines[a.Line-1] == a.Original {
+			lines[a.Line-1] = a.Suggested
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// scanYearAnomalies finds copyright statements with implausible years in every
+// file in the current directory, keyed by path, skipping git submodules per the
+// project.submodules policy. If fix is set, the suggested corrections are
+// applied as well.
+func scanYearAnomalies(cmd *cobra.Command, year int, fix bool) (map[string][]licensecheck.YearAnomaly, error) {
+	earliest := conf.Project.CopyrightYear
+	if earliest == 0 {
+		if first, err := git.FirstCommitDate("."); err == nil {
+			earliest = first.Year()
+		} else {
+			cliLogger.Debug(fmt.Sprintf("Unable to determine the project's creation year: %v", err))
+		}
+	}
+
+	opts := headerOptions()
+	if err := skipSubmodules(cmd, &opts); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	found := map[string][]licensecheck.YearAnomaly{}
+
+	err := addlicense.Walk(conf.Project.HeaderIgnore, []string{"."}, stdLogger(), opts, func(path string) error {
+		b, err := addlicense.ReadHead(path)
+		if err != nil {
+			return err
+		}
+
+		anomalies := licensecheck.FindYearAnomalies(b, year, earliest)
+		if len(anomalies) == 0 {
+			return nil
+		}
+
+		mu.Lock()
+		found[path] = anomalies
+		mu.Unlock()
+
+		if !fix {
+			return nil
+		}
+		b, err = os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, applyYearFixes(b, anomalies), fi.Mode())
+	})
+	return found, err
+}
+
+// printYearAnomalies prints each anomaly found by scanYearAnomalies along with
+// its suggested correction
+func printYearAnomalies(cmd *cobra.Command, found map[string][]licensecheck.YearAnomaly) {
+	paths := lo.Keys(found)
+	sort.Strings(paths)
+
+	gha.StartGroup("The following copyright statements have implausible years:")
+	for _, path := range paths {
+		for _, a := range found[path] {
+			cmd.Printf("%s:%d: %s\n", path, a.Line, a.Message())
+			cmd.Printf("%s\n%s\n", text.FgRed.Sprintf("-%s", a.Original), text.FgGreen.Sprintf("+%s", a.Suggested))
+		}
+	}
+	gha.EndGroup()
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_scanYearAnomaliesSkipsSubmodules(t *testing.T) {
+	cliLogger = hclog.NewNullLogger()
+
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "third_party/lib/.git"), 0755))
+	header := []byte("// Copyright (c) Acme Inc. 2030\n\npackage main\n")
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "main.go"), header, 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "third_party/lib/lib.go"), header, 0644))
+
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(dir))
+	defer func() { assert.Nil(t, os.Chdir(cwd)) }()
+
+	conf.Project.CopyrightYear = 2020
+	defer func() { conf.Project.CopyrightYear = 0 }()
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	found, err := scanYearAnomalies(cmd, 2025, true)
+	assert.Nil(t, err)
+	assert.Len(t, found, 1)
+	assert.Contains(t, found, "main.go")
+
+	b, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	assert.Nil(t, err)
+	assert.NotEqual(t, header, b, "Files outside of submodules are fixed")
+	b, err = os.ReadFile(filepath.Join(dir, "third_party/lib/lib.go"))
+	assert.Nil(t, err)
+	assert.Equal(t, header, b, "Files in submodules are left alone")
+
+	conf.Project.Submodules = "fail"
+	defer func() { conf.Project.Submodules = "" }()
+	_, err = scanYearAnomalies(cmd, 2025, true)
+	assert.EqualError(t, err, "found git submodules, which project.submodules forbids: third_party/lib")
+}
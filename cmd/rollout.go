@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	maxChanges int
+	sample     string
+)
+
+// parseSample parses the --sample flag, a percentage of files such as "5%" or
+// "12.5", returning 0 if it is empty
+func parseSample(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("invalid --sample %q: must be a percentage greater than 0 and at most 100 (e.g., \"5%%\")", s)
+	}
+	return percent, nil
+}
+
+// validateRolloutFlags returns an error if the --max-changes or --sample flags
+// are out of range
+func validateRolloutFlags() error {
+	if maxChanges < 0 {
+		return fmt.Errorf("invalid --max-changes %d: must not be negative", maxChanges)
+	}
+	_, err := parseSample(sample)
+	return err
+}
+
+// inSample reports whether path is among the given percentage of files. Files
+// are chosen by a hash of their path, so the same files are chosen on every
+// run and raising the percentage only ever adds to them.
+func inSample(path string, percent float64) bool {
+	h := fnv.New32a()
+	h.Write([]byte(filepath.ToSlash(path)))
+	return float64(h.Sum32()%10000) < percent*100
+}
+
+// selectRollout chooses which of the files missing headers a gradual rollout
+// should change: those in the sample, if percent is non-zero, and then only the
+// first max of them in path order, if max is non-zero. Taking files in path
+// order keeps each batch of changes grouped by directory.
+func selectRollout(missing []string, max int, percent float64) []string {
+	selected := []string{}
+	for _, path := range missing {
+		if percent == 0 || inSample(path, percent) {
+			selected = append(selected, path)
+		}
+	}
+	sort.Strings(selected)
+	if max > 0 && len(selected) > max {
+		selected = selected[:max]
+	}
+	return selected
+}
+
+// rolloutFilter finds the files missing headers with a check-only pass using
+// opts, then builds a skip function for addlicense that limits a run to the
+// files chosen by --sample and --max-changes, so that enormous migrations can
+// be landed in reviewable chunks
+func rolloutFilter(cmd *cobra.Command, license addlicense.LicenseData, opts addlicense.Options) (func(path string) (bool, string), error) {
+	percent, err := parseSample(sample)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	missing := []string{}
+	check := opts
+	check.FailFast = false
+	check.Stats = nil
+	check.Skipped = nil
+	check.SkippedHidden = nil
+	check.Changed = func(path string, existing bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		missing = append(missing, path)
+	}
+	discard := log.New(io.Discard, "", 0)
+	err = addlicense.Run(conf.Project.HeaderIgnore, "only", license, "", false, true, []string{"."}, discard, check)
+	if err != nil && !errors.Is(err, addlicense.ErrMissingHeader) {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for _, path := range selectRollout(missing, maxChanges, percent) {
+		selected[filepath.ToSlash(path)] = true
+	}
+	if len(selected) < len(missing) {
+		cmd.Printf("Limiting this run to %d of the %d files missing headers. Rerun to continue the rollout.\n\n", len(selected), len(missing))
+	}
+
+	return func(path string) (bool, string) {
+		if selected[filepath.ToSlash(path)] {
+			return false, ""
+		}
+		return true, "deferred by --max-changes or --sample"
+	}, nil
+}
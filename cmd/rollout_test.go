@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseSample(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    float64
+		expectedErr bool
+	}{
+		{input: "", expected: 0},
+		{input: "5%", expected: 5},
+		{input: "12.5", expected: 12.5},
+		{input: "100%", expected: 100},
+		{input: "0%", expectedErr: true},
+		{input: "101%", expectedErr: true},
+		{input: "five", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := parseSample(tt.input)
+			if tt.expectedErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_selectRollout(t *testing.T) {
+	missing := []string{}
+	for i := 0; i < 1000; i++ {
+		missing = append(missing, fmt.Sprintf("dir%d/file.go", i))
+	}
+
+	// Sampling is deterministic, roughly proportional, and only ever grows
+	ten := selectRollout(missing, 0, 10)
+	assert.Equal(t, ten, selectRollout(missing, 0, 10))
+	assert.InDelta(t, 100, len(ten), 40)
+	twenty := selectRollout(missing, 0, 20)
+	assert.Subset(t, twenty, ten)
+	assert.Len(t, selectRollout(missing, 0, 100), len(missing))
+
+	// The first files in path order are taken, regardless of discovery order
+	assert.Equal(t, []string{"a.go", "b/c.go"}, selectRollout([]string{"d.go", "b/c.go", "a.go"}, 2, 0))
+	assert.Equal(t, ten[:5], selectRollout(missing, 5, 10))
+	assert.Len(t, selectRollout(missing, 5000, 0), len(missing))
+}
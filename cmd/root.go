@@ -4,12 +4,20 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"os"
+	"strings"
 
+	"github.com/hashicorp/copywrite/addlicense"
 	"github.com/hashicorp/copywrite/config"
 	"github.com/hashicorp/copywrite/github/actions"
+	"github.com/hashicorp/copywrite/semver"
 	"github.com/hashicorp/go-hclog"
+	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +34,15 @@ var (
 
 	// Named subsystem logger for copywrite-cli commands
 	cliLogger hclog.Logger
+
+	// Only print errors and results (not informational output) when set
+	quiet bool
+
+	// Verbosity level, incremented by each use of the --verbose flag
+	verbosity int
+
+	// Log output format, either "text" or "json"
+	logFormat string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -62,6 +79,11 @@ func init() {
 	})
 
 	rootCmd.PersistentFlags().StringVar(&cfgPath, "config", ".copywrite.hcl", "config file")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print errors and results, omitting informational output")
+	rootCmd.PersistentFlags().CountVar(&verbosity, "verbose", "Print debug logs, or trace logs if given twice")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log output format: \"text\" or \"json\"")
+	rootCmd.PersistentFlags().BoolVar(&buildMetadata, "build-metadata", false, "Include the copywrite version and a timestamp in generated files and reports")
 
 	// Let's make sure Cobra doesn't default to stderr
 	rootCmd.SetOut(os.Stdout)
@@ -74,6 +96,47 @@ func initConfig() {
 		return
 	}
 	cobra.CheckErr(err)
+
+	if conf.Project.ScanLines < 0 || conf.Project.ScanBytes < 0 {
+		cobra.CheckErr("project.scan_lines and project.scan_bytes must not be negative")
+	}
+	addlicense.HeaderScanWindow = addlicense.ScanWindow{
+		Lines: conf.Project.ScanLines,
+		Bytes: conf.Project.ScanBytes,
+	}
+	addlicense.JSONComments = conf.Project.JSONComments
+	addlicense.MATLABFiles = conf.Project.MATLABFiles
+	if !addlicense.ValidAssemblyComment(conf.Project.AssemblyComment) {
+		cobra.CheckErr(fmt.Sprintf("invalid project.asm_comment %q: must be one of \";\", \"#\", \"//\", or \"/*\"", conf.Project.AssemblyComment))
+	}
+	addlicense.AssemblyComment = conf.Project.AssemblyComment
+
+	cobra.CheckErr(checkRequiredVersion(conf.RequiredVersion, version))
+}
+
+// checkRequiredVersion returns an error if the running version of copywrite
+// does not satisfy the required_version constraints in config, so that older
+// binaries don't silently apply outdated policy. Development builds are exempt.
+func checkRequiredVersion(required string, running string) error {
+	if required == "" {
+		return nil
+	}
+
+	constraints, err := semver.ParseConstraints(required)
+	if err != nil {
+		return fmt.Errorf("invalid required_version in %s: %w", cfgPath, err)
+	}
+
+	current, err := semver.Parse(running)
+	if err != nil {
+		// Not a release build (e.g., "dev"), so there's nothing to compare
+		return nil
+	}
+
+	if !constraints.Check(current) {
+		return fmt.Errorf("copywrite %s does not satisfy required_version %q in %s; run \"copywrite self-update\" or install a compatible release", running, required, cfgPath)
+	}
+	return nil
 }
 
 func initLogger() {
@@ -94,11 +157,100 @@ func initLogger() {
 		logLevel = hclog.LevelFromString(levelEnv)
 	}
 
+	// Command-line flags are the most explicit, so they take precedence over
+	// any environment variables
+	switch {
+	case quiet:
+		logLevel = hclog.Error
+	case verbosity == 1:
+		logLevel = hclog.Debug
+	case verbosity > 1:
+		logLevel = hclog.Trace
+	}
+
+	// JSON logs are meant for machines, so never colorize them, and write them
+	// to stderr so that they aren't interleaved with the plain text results
+	// that commands print to stdout
+	jsonFormat := false
+	color := hclog.AutoColor
+	output := os.Stdout
+	switch logFormat {
+	case "text":
+	case "json":
+		jsonFormat = true
+		color = hclog.ColorOff
+		output = os.Stderr
+	default:
+		cobra.CheckErr(fmt.Errorf("invalid log format %q: must be one of \"text\" or \"json\"", logFormat))
+	}
+
 	hclog.Default().Named("cli")
 	cliLogger = hclog.New(&hclog.LoggerOptions{
-		Name:   "cli",
-		Level:  logLevel,
-		Color:  hclog.AutoColor,
-		Output: os.Stdout,
+		Name:       "cli",
+		Level:      logLevel,
+		Color:      color,
+		JSONFormat: jsonFormat,
+		Output:     output,
 	})
 }
+
+// infof prints informational output, such as the settings a command is using,
+// unless --quiet is set. With --log-format=json, it is logged instead, so that
+// it is indexed along with everything else. The results of a command are
+// printed with cmd.Printf directly, so that they are never lost.
+func infof(cmd *cobra.Command, format string, a ...any) {
+	if quiet {
+		return
+	}
+	if logFormat == "json" {
+		if msg := strings.TrimSpace(text.StripEscape(fmt.Sprintf(format, a...))); msg != "" {
+			cliLogger.Info(msg)
+		}
+		return
+	}
+	cmd.Printf(format, a...)
+}
+
+// stdLogger adapts cliLogger for packages that log through a standard library
+// logger, such as addlicense. Messages with a level prefix (e.g., "[DEBUG]") are
+// logged at that level, so they respect the --quiet and --verbose flags.
+func stdLogger() *log.Logger {
+	return cliLogger.StandardLogger(&hclog.StandardLoggerOptions{
+		InferLevels: true,
+	})
+}
+
+// levelPrefixes are the prefixes hclog infers a message's level from
+var levelPrefixes = [][]byte{
+	[]byte("[TRACE]"), []byte("[DEBUG]"), []byte("[INFO]"),
+	[]byte("[WARN]"), []byte("[ERROR]"), []byte("[ERR]"),
+}
+
+// resultWriter sends messages with a level prefix to a log writer, and prints
+// everything else, such as the paths addlicense reports, as results
+type resultWriter struct {
+	out io.Writer
+	log io.Writer
+}
+
+func (w resultWriter) Write(p []byte) (int, error) {
+	for _, prefix := range levelPrefixes {
+		if bytes.HasPrefix(p, prefix) {
+			return w.log.Write(p)
+		}
+	}
+	return w.out.Write(p)
+}
+
+// resultLogger is like stdLogger, but for addlicense runs that report the
+// files missing headers or modified: messages without a level prefix are
+// results, so they are printed to the command's output, even with --quiet or
+// --log-format=json, rather than logged at the Info level
+func resultLogger(cmd *cobra.Command) *log.Logger {
+	return log.New(resultWriter{
+		out: cmd.OutOrStdout(),
+		log: cliLogger.StandardWriter(&hclog.StandardLoggerOptions{
+			InferLevels: true,
+		}),
+	}, "", 0)
+}
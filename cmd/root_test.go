@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_initLogger(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           string
+		quiet         bool
+		verbosity     int
+		expectedLevel hclog.Level
+	}{
+		{
+			name:          "Defaults to info",
+			expectedLevel: hclog.Info,
+		},
+		{
+			name:          "Environment variable sets the level",
+			env:           "warn",
+			expectedLevel: hclog.Warn,
+		},
+		{
+			name:          "Quiet only logs errors",
+			env:           "trace",
+			quiet:         true,
+			expectedLevel: hclog.Error,
+		},
+		{
+			name:          "Verbose logs debug messages",
+			env:           "warn",
+			verbosity:     1,
+			expectedLevel: hclog.Debug,
+		},
+		{
+			name:          "Verbose twice logs trace messages",
+			verbosity:     2,
+			expectedLevel: hclog.Trace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RUNNER_DEBUG", "")
+			if tt.env != "" {
+				t.Setenv("COPYWRITE_LOG_LEVEL", tt.env)
+			}
+			quiet = tt.quiet
+			verbosity = tt.verbosity
+			defer func() { quiet, verbosity = false, 0 }()
+
+			initLogger()
+			assert.Equal(t, tt.expectedLevel, cliLogger.GetLevel())
+		})
+	}
+}
+
+func Test_resultLogger(t *testing.T) {
+	var logs bytes.Buffer
+	cliLogger = hclog.New(&hclog.LoggerOptions{
+		Level:  hclog.Error,
+		Output: &logs,
+	})
+	defer func() { cliLogger = hclog.NewNullLogger() }()
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+
+	logger := resultLogger(cmd)
+	logger.Printf("%s\n", "a.go")
+	logger.Printf("[DEBUG] skipping: %s", "b.go")
+	logger.Printf("[ERROR] unable to read: %s", "c.go")
+
+	assert.Equal(t, "a.go\n", out.String(), "Results are printed whatever the log level")
+	assert.NotContains(t, logs.String(), "b.go", "Messages below the log level are dropped")
+	assert.Contains(t, logs.String(), "[ERROR] unable to read: c.go")
+}
+
+// captureFile replaces *f, such as os.Stderr, with a temporary file for the
+// rest of the test, returning a function that reads what was written to it
+func captureFile(t *testing.T, f **os.File) func() string {
+	tmp, err := os.CreateTemp(t.TempDir(), "output")
+	assert.Nil(t, err)
+	saved := *f
+	*f = tmp
+	t.Cleanup(func() {
+		*f = saved
+		tmp.Close()
+	})
+	return func() string {
+		b, err := os.ReadFile(tmp.Name())
+		assert.Nil(t, err)
+		return string(b)
+	}
+}
+
+func Test_jsonLogFormat(t *testing.T) {
+	stdout := captureFile(t, &os.Stdout)
+	stderr := captureFile(t, &os.Stderr)
+
+	logFormat = "json"
+	defer func() { logFormat = "text" }()
+	initLogger()
+	defer func() { cliLogger = hclog.NewNullLogger() }()
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+
+	cliLogger.Warn("Skipping file", "path", "a.go")
+	infof(cmd, "%s", text.FgYellow.Sprint("Using copyright holder: Acme Inc.\n\n"))
+	resultLogger(cmd).Printf("%s\n", "b.go")
+	cmd.Printf("Files scanned: %d\n", 2)
+
+	assert.Equal(t, "", stdout(), "Logs are never written to stdout")
+	assert.Equal(t, "b.go\nFiles scanned: 2\n", out.String(), "Results are printed as plain text")
+
+	lines := strings.Split(strings.TrimSpace(stderr()), "\n")
+	if assert.Len(t, lines, 2) {
+		var record map[string]any
+		assert.Nil(t, json.Unmarshal([]byte(lines[0]), &record))
+		assert.Equal(t, "warn", record["@level"])
+		assert.Equal(t, "Skipping file", record["@message"])
+		assert.Equal(t, "a.go", record["path"])
+
+		record = nil
+		assert.Nil(t, json.Unmarshal([]byte(lines[1]), &record))
+		assert.Equal(t, "info", record["@level"])
+		assert.Equal(t, "Using copyright holder: Acme Inc.", record["@message"], "Informational output is logged without color")
+	}
+}
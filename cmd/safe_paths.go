@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/copywrite/git"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var allowUnsafePaths bool
+
+// addAllowUnsafePathsFlag registers the --allow-unsafe-paths flag for a command
+// that modifies files, which must then call checkSafePath before doing so
+func addAllowUnsafePathsFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&allowUnsafePaths, "allow-unsafe-paths", false, "Allow modifying files in the filesystem root, your home directory, or a directory outside of any git repo")
+}
+
+// checkSafePath returns an error if dir is somewhere that files are unlikely to
+// have been meant to be modified, such as after mistakenly running
+// `copywrite headers -d ~`: the root of the filesystem, the user's home
+// directory itself, or any directory that isn't inside a git repo. Nothing is
+// checked if --allow-unsafe-paths was given.
+func checkSafePath(dir string) error {
+	if allowUnsafePaths {
+		return nil
+	}
+
+	reason, err := unsafePathReason(dir)
+	if err != nil || reason == "" {
+		return err
+	}
+	abs, _ := filepath.Abs(dir)
+	return fmt.Errorf("refusing to modify files in %s, which is %s; pass --allow-unsafe-paths if this is intended", abs, reason)
+}
+
+// unsafePathReason describes why dir is unsafe to modify files in, or returns ""
+// if it is safe
+func unsafePathReason(dir string) (string, error) {
+	abs, err := resolvePath(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if filepath.Dir(abs) == abs {
+		return "the root of the filesystem", nil
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if home, err := resolvePath(home); err == nil && home == abs {
+			return "your home directory", nil
+		}
+	}
+	if _, err := git.TopLevel(abs); err != nil {
+		return "not inside a git repo", nil
+	}
+	return "", nil
+}
+
+// resolvePath returns the absolute path of dir with any symlinks resolved, so
+// that paths can be compared
+func resolvePath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
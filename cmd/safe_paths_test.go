@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_unsafePathReason(t *testing.T) {
+	repo := t.TempDir()
+	if err := exec.Command("git", "-C", repo, "init", "-q").Run(); err != nil {
+		t.Skipf("git is unavailable: %v", err)
+	}
+	sub := filepath.Join(repo, "sub")
+	assert.Nil(t, os.Mkdir(sub, 0755))
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name     string
+		dir      string
+		expected string
+	}{
+		{name: "Filesystem root", dir: "/", expected: "the root of the filesystem"},
+		{name: "Home directory", dir: home, expected: "your home directory"},
+		{name: "Outside of a git repo", dir: t.TempDir(), expected: "not inside a git repo"},
+		{name: "Top level of a git repo", dir: repo, expected: ""},
+		{name: "Subdirectory of a git repo", dir: sub, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := unsafePathReason(tt.dir)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, reason)
+		})
+	}
+}
+
+func Test_checkSafePath(t *testing.T) {
+	t.Cleanup(func() { allowUnsafePaths = false })
+
+	allowUnsafePaths = false
+	err := checkSafePath("/")
+	assert.EqualError(t, err, "refusing to modify files in /, which is the root of the filesystem; pass --allow-unsafe-paths if this is intended")
+
+	allowUnsafePaths = true
+	assert.Nil(t, checkSafePath("/"))
+}
@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/archive"
+	gh "github.com/hashicorp/copywrite/github"
+	"github.com/hashicorp/copywrite/semver"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	updateChannel   string
+	updatePublicKey string
+	updateForce     bool
+)
+
+// copywriteRepo is where copywrite releases are published
+var copywriteRepo = gh.GHRepo{Owner: "hashicorp", Name: "copywrite"}
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Updates copywrite to the latest release",
+	Long: `Checks GitHub for the latest release of copywrite and, if it is newer than the
+running version, replaces the running binary with it.
+
+Updating requires --public-key, the path to the armored PGP public key that
+releases are signed with. The release's SHA256SUMS file must carry a valid
+detached signature (SHA256SUMS.sig) made by that key, and the downloaded archive
+must match its checksum, or the update is aborted.
+
+Use --channel=prerelease to also consider prereleases, and --plan to only check
+whether an update is available.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if updateChannel != "stable" && updateChannel != "prerelease" {
+			cobra.CheckErr(fmt.Sprintf("invalid --channel %q: must be \"stable\" or \"prerelease\"", updateChannel))
+		}
+		if updatePublicKey == "" && !plan {
+			cobra.CheckErr("--public-key is required to verify the signature of the release")
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		client := gh.NewGHClient().Raw()
+		release, err := gh.GetLatestRelease(client, copywriteRepo, updateChannel == "prerelease")
+		if err != nil {
+			cliLogger.Error("Error retrieving the latest release", err)
+		}
+		cobra.CheckErr(err)
+
+		latest, err := semver.Parse(release.GetTagName())
+		if err != nil {
+			cliLogger.Error("Error parsing the latest release version", err)
+		}
+		cobra.CheckErr(err)
+
+		// Development builds can't be compared, so are always updated
+		current, err := semver.Parse(version)
+		if err == nil && current.Compare(latest) >= 0 && !updateForce {
+			cmd.Printf("copywrite %s is already up to date (latest %s release is %s)\n", current, updateChannel, latest)
+			return
+		}
+
+		infof(cmd, "Updating copywrite from %s to %s\n", version, latest)
+		if plan {
+			cobra.CheckErr(fmt.Sprintf("copywrite %s is available. Run without the --plan flag to update", latest))
+		}
+
+		exe, err := os.Executable()
+		cobra.CheckErr(err)
+		exe, err = filepath.EvalSymlinks(exe)
+		cobra.CheckErr(err)
+
+		err = selfUpdate(release, latest, exe)
+		if err != nil {
+			cliLogger.Error("Error updating copywrite", err)
+		}
+		cobra.CheckErr(err)
+
+		cmd.Println(text.FgGreen.Sprintf("Updated %s to copywrite %s", exe, latest))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	// These flags are only locally relevant
+	selfUpdateCmd.Flags().StringVar(&updateChannel, "channel", "stable", "Release channel to update from: \"stable\" or \"prerelease\"")
+	selfUpdateCmd.Flags().StringVar(&updatePublicKey, "public-key", "", "Path to the armored PGP public key that must have signed the release checksums (required unless --plan is set)")
+	selfUpdateCmd.Flags().BoolVar(&updateForce, "force", false, "Reinstall the latest release even if it is not newer than the running version")
+	selfUpdateCmd.Flags().BoolVar(&plan, "plan", false, "Only check whether an update is available, failing if one is")
+}
+
+// selfUpdate downloads and verifies the archive for the current platform from
+// release, then replaces the binary at exe with the one it contains
+func selfUpdate(release *github.RepositoryRelease, v semver.Version, exe string) error {
+	assets := map[string]string{}
+	for _, a := range release.Assets {
+		assets[a.GetName()] = a.GetBrowserDownloadURL()
+	}
+
+	name := releaseArchiveName(v, runtime.GOOS, runtime.GOARCH)
+	if assets[name] == "" {
+		return fmt.Errorf("release %s has no archive for %s/%s (expected %s)", release.GetTagName(), runtime.GOOS, runtime.GOARCH, name)
+	}
+	if assets["SHA256SUMS"] == "" {
+		return fmt.Errorf("release %s has no SHA256SUMS file", release.GetTagName())
+	}
+
+	tmp, err := os.MkdirTemp("", "copywrite-update-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	sums, err := download(assets["SHA256SUMS"])
+	if err != nil {
+		return err
+	}
+	if assets["SHA256SUMS.sig"] == "" {
+		return fmt.Errorf("release %s has no SHA256SUMS.sig signature to verify", release.GetTagName())
+	}
+	sig, err := download(assets["SHA256SUMS.sig"])
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(updatePublicKey, sums, sig); err != nil {
+		return err
+	}
+
+	archivePath := filepath.Join(tmp, name)
+	b, err := download(assets[name])
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(archivePath, b, 0600); err != nil {
+		return err
+	}
+	if err := archive.VerifyChecksum(archivePath, sums); err != nil {
+		return err
+	}
+
+	root, err := archive.Extract(archivePath, filepath.Join(tmp, "extracted"))
+	if err != nil {
+		return err
+	}
+	binary := "copywrite"
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	return replaceExecutable(exe, filepath.Join(root, binary))
+}
+
+// releaseArchiveName returns the name of the release archive for a platform,
+// matching the name_template in .goreleaser.yaml
+func releaseArchiveName(v semver.Version, goos string, goarch string) string {
+	arch := goarch
+	if arch == "amd64" {
+		arch = "x86_64"
+	}
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("copywrite_%s_%s_%s.%s", v, goos, arch, ext)
+}
+
+// downloadClient is used for release downloads, which are small enough that a
+// connection still going after a few minutes has stalled
+var downloadClient = &http.Client{Timeout: 5 * time.Minute}
+
+// download returns the body of a GET request to url
+func download(url string) ([]byte, error) {
+	resp, err := downloadClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// verifySignature checks that sig is a valid detached signature of signed by
+// the armored public key at keyPath
+func verifySignature(keyPath string, signed []byte, sig []byte) error {
+	f, err := os.Open(keyPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	keyring, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return fmt.Errorf("unable to read public key %s: %w", keyPath, err)
+	}
+	if _, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(signed), bytes.NewReader(sig), nil); err != nil {
+		return fmt.Errorf("invalid signature for SHA256SUMS: %w", err)
+	}
+	return nil
+}
+
+// replaceExecutable replaces the binary at exe with the one at src. The new
+// binary is first copied alongside exe and then renamed over it, so that exe is
+// never left partially written. Windows doesn't allow a running executable to
+// be replaced, but does allow it to be renamed out of the way.
+func replaceExecutable(exe string, src string) error {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	next := exe + ".new"
+	if err := os.WriteFile(next, b, 0755); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			os.Remove(next)
+			return err
+		}
+	}
+
+	if err := os.Rename(next, exe); err != nil {
+		os.Remove(next)
+		return err
+	}
+	return nil
+}
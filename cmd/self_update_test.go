@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/copywrite/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_releaseArchiveName(t *testing.T) {
+	v, err := semver.Parse("v1.2.3")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		goos     string
+		goarch   string
+		expected string
+	}{
+		{"linux", "amd64", "copywrite_1.2.3_linux_x86_64.tar.gz"},
+		{"linux", "arm64", "copywrite_1.2.3_linux_arm64.tar.gz"},
+		{"darwin", "arm64", "copywrite_1.2.3_darwin_arm64.tar.gz"},
+		{"windows", "amd64", "copywrite_1.2.3_windows_x86_64.zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.goarch, func(t *testing.T) {
+			assert.Equal(t, tt.expected, releaseArchiveName(v, tt.goos, tt.goarch))
+		})
+	}
+}
+
+func Test_replaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "copywrite")
+	src := filepath.Join(dir, "extracted")
+	assert.Nil(t, os.WriteFile(exe, []byte("old"), 0755))
+	assert.Nil(t, os.WriteFile(src, []byte("new"), 0644))
+
+	assert.Nil(t, replaceExecutable(exe, src))
+
+	b, err := os.ReadFile(exe)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", string(b))
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(exe)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0755), fi.Mode().Perm())
+	}
+	assert.NoFileExists(t, exe+".new")
+
+	// A missing source leaves the original alone
+	assert.NotNil(t, replaceExecutable(exe, filepath.Join(dir, "missing")))
+	b, err = os.ReadFile(exe)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", string(b))
+}
+
+func Test_verifySignature(t *testing.T) {
+	signer, err := openpgp.NewEntity("Release", "", "release@example.com", nil)
+	assert.Nil(t, err)
+	other, err := openpgp.NewEntity("Other", "", "other@example.com", nil)
+	assert.Nil(t, err)
+
+	keyPath := filepath.Join(t.TempDir(), "key.asc")
+	var key bytes.Buffer
+	w, err := armor.Encode(&key, openpgp.PublicKeyType, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, signer.Serialize(w))
+	assert.Nil(t, w.Close())
+	assert.Nil(t, os.WriteFile(keyPath, key.Bytes(), 0644))
+
+	sums := []byte("abc123  copywrite_1.2.3_linux_x86_64.tar.gz\n")
+	var sig, otherSig bytes.Buffer
+	assert.Nil(t, openpgp.DetachSign(&sig, signer, bytes.NewReader(sums), nil))
+	assert.Nil(t, openpgp.DetachSign(&otherSig, other, bytes.NewReader(sums), nil))
+
+	assert.Nil(t, verifySignature(keyPath, sums, sig.Bytes()))
+	assert.NotNil(t, verifySignature(keyPath, []byte("tampered"), sig.Bytes()))
+	assert.NotNil(t, verifySignature(keyPath, sums, otherSig.Bytes()))
+	assert.NotNil(t, verifySignature(filepath.Join(t.TempDir(), "missing.asc"), sums, sig.Bytes()))
+}
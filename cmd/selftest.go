@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/copywrite/testrepos"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+var selftestKeep bool
+
+// selftestSteps are the commands run, in order, against each golden repo.
+// The final check-only run fails if processing the repo again would change it.
+var selftestSteps = [][]string{
+	{"headers", "--allow-unsafe-paths"},
+	{"normalize", "--allow-unsafe-paths"},
+	{"license"},
+	{"headers", "--plan"},
+}
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest [repos...]",
+	Short: "Runs copywrite against the golden repos embedded in it",
+	Long: `Extracts each golden repo embedded in copywrite (or only those named) to a
+temporary directory, runs the whole pipeline against it using its own config,
+and compares the result with the expected tree:
+
+  - copywrite headers
+  - copywrite normalize
+  - copywrite license
+  - copywrite headers --plan, which must find nothing left to do
+
+The golden repos cover many languages, tricky preambles, and third-party code,
+catching regressions in how commands work together before a release. Each step
+runs in a separate copywrite process, just as it would in CI. Use --keep to
+leave the processed repos in place for inspection.`,
+	Hidden: true,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, _ := testrepos.Names()
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		names := args
+		if len(names) == 0 {
+			var err error
+			names, err = testrepos.Names()
+			cobra.CheckErr(err)
+		}
+
+		exe, err := os.Executable()
+		cobra.CheckErr(err)
+
+		failed := 0
+		for _, name := range names {
+			gha.StartGroup(fmt.Sprintf("Golden repo %s:", name))
+			err := selftestRepo(cmd, exe, name)
+			if err != nil {
+				failed++
+				cmd.Println(text.FgRed.Sprintf("❌ %s: %s", name, err))
+			} else {
+				cmd.Printf("✔️ %s\n", name)
+			}
+			gha.EndGroup()
+		}
+
+		if failed > 0 {
+			cobra.CheckErr(fmt.Sprintf("%d of %d golden repos failed", failed, len(names)))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+
+	selftestCmd.Flags().BoolVar(&selftestKeep, "keep", false, "Keep the processed repos rather than deleting them, printing where they are")
+}
+
+// selftestRepo extracts the named golden repo, runs selftestSteps against it
+// with the copywrite executable at exe, and compares the result with the
+// expected tree
+func selftestRepo(cmd *cobra.Command, exe string, name string) error {
+	tmp, err := os.MkdirTemp("", "copywrite-selftest-"+name+"-")
+	if err != nil {
+		return err
+	}
+	if selftestKeep {
+		cmd.Printf("Processing %s in %s\n", name, tmp)
+	} else {
+		defer os.RemoveAll(tmp)
+	}
+
+	if err := testrepos.Extract(name, tmp); err != nil {
+		return err
+	}
+
+	for _, args := range selftestSteps {
+		var out bytes.Buffer
+		c := exec.Command(exe, args...)
+		c.Dir = tmp
+		c.Env = append(os.Environ(), "GITHUB_ACTIONS=false")
+		c.Stdout = &out
+		c.Stderr = &out
+		if err := c.Run(); err != nil {
+			cmd.Print(out.String())
+			return fmt.Errorf("copywrite %s failed: %w", strings.Join(args, " "), err)
+		}
+	}
+
+	mismatches, err := testrepos.Compare(name, tmp)
+	if err != nil {
+		return err
+	}
+	for _, m := range mismatches {
+		switch {
+		case m.Want == nil:
+			cmd.Printf("%s was not expected to exist\n", filepath.ToSlash(m.Path))
+		case m.Got == nil:
+			cmd.Printf("%s is missing\n", filepath.ToSlash(m.Path))
+		default:
+			cmd.Print(headerDiff(m.Path, m.Want, m.Got))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d files differ from the expected tree", len(mismatches))
+	}
+	return nil
+}
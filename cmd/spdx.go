@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var listExceptions bool
+
+var spdxCmd = &cobra.Command{
+	Use:   "spdx",
+	Short: "Inspects and updates the SPDX license list used by copywrite",
+	Long: `Copywrite validates license identifiers against the official SPDX license list.
+A copy of the list is embedded in copywrite, which can be refreshed with the
+"copywrite spdx update" command to pick up newly published licenses.`,
+	// Run function is omitted, as this command exists only to house subcommands
+}
+
+var spdxUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Downloads the latest SPDX license list",
+	Long: `Downloads the latest official SPDX license and exception lists and caches
+them locally, where they take precedence over the lists embedded in copywrite.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		licenses, err := fetch(addlicense.SPDXLicensesURL)
+		if err != nil {
+			cliLogger.Error("Error downloading SPDX license list", err)
+		}
+		cobra.CheckErr(err)
+
+		exceptions, err := fetch(addlicense.SPDXExceptionsURL)
+		if err != nil {
+			cliLogger.Error("Error downloading SPDX exception list", err)
+		}
+		cobra.CheckErr(err)
+
+		version, err := addlicense.UpdateSPDXCache(licenses, exceptions)
+		if err != nil {
+			cliLogger.Error("Error saving SPDX license list", err)
+		}
+		cobra.CheckErr(err)
+
+		dir, _ := addlicense.SPDXCacheDir()
+		cmd.Printf("Updated SPDX license list to version %s (%d licenses, %d exceptions)\n", version, len(addlicense.SPDXLicenses()), len(addlicense.SPDXExceptions()))
+		cmd.Printf("Saved to: %s\n", dir)
+	},
+}
+
+var spdxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all valid SPDX license identifiers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		list := addlicense.SPDXLicenses()
+		if listExceptions {
+			list = addlicense.SPDXExceptions()
+		}
+
+		printSPDXTable(cmd, list)
+		if version := addlicense.SPDXListVersion(); version != "" {
+			cmd.Printf("\nSPDX license list version: %s\n", version)
+		}
+	},
+}
+
+var spdxSearchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Searches SPDX licenses and exceptions by identifier or name",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		term := args[0]
+
+		matches := addlicense.SearchSPDX(term)
+		if len(matches) > 0 {
+			printSPDXTable(cmd, matches)
+			return
+		}
+
+		cmd.Printf("No SPDX licenses match %q\n", term)
+		if suggestions := addlicense.SuggestSPDX(term); len(suggestions) > 0 {
+			cmd.Printf("Did you mean: %s\n", strings.Join(suggestions, ", "))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(spdxCmd)
+	spdxCmd.AddCommand(spdxUpdateCmd)
+	spdxCmd.AddCommand(spdxListCmd)
+	spdxCmd.AddCommand(spdxSearchCmd)
+
+	spdxListCmd.Flags().BoolVar(&listExceptions, "exceptions", false, "List license exceptions (used with the WITH operator) instead of licenses")
+}
+
+func printSPDXTable(cmd *cobra.Command, list []addlicense.SPDXLicense) {
+	t := newTableWriter(cmd.OutOrStdout())
+	t.AppendHeader(stringArrayToRow([]string{"ID", "Name"}))
+	for _, l := range list {
+		t.AppendRow(stringArrayToRow([]string{l.ID, l.Name}))
+	}
+	t.Render()
+}
+
+// fetch downloads the body of the given URL
+func fetch(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// exitCodeTimeout is the exit code used when a command is stopped because its
+// --timeout elapsed, matching the convention of GNU timeout(1)
+const exitCodeTimeout = 124
+
+// Flag variables
+var timeout time.Duration
+
+// addTimeoutFlag registers the --timeout flag for a long-running command, which
+// must then use commandContext for all of its GitHub API calls
+func addTimeoutFlag(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Maximum time the command may run for, e.g. \"30m\" (default is no limit)")
+}
+
+// commandContext returns a context for cmd that is cancelled once the --timeout
+// elapses, if one was set
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// checkTimeout exits with exitCodeTimeout if err was caused by the --timeout
+// elapsing, so that CI can tell a stuck job apart from a failed one. Any other
+// error is handled by cobra.CheckErr.
+func checkTimeout(err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		fmt.Fprintf(os.Stderr, "Error: timed out after %s: %v\n", timeout, err)
+		os.Exit(exitCodeTimeout)
+	}
+	cobra.CheckErr(err)
+}
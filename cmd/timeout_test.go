@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_commandContext(t *testing.T) {
+	cmd := &cobra.Command{}
+
+	ctx, cancel := commandContext(cmd)
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline, "Without --timeout, there is no deadline")
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	timeout = time.Millisecond
+	defer func() { timeout = 0 }()
+	ctx, cancel = commandContext(cmd)
+	defer cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	cmd.SetContext(parent)
+	timeout = time.Hour
+	ctx, cancel = commandContext(cmd)
+	defer cancel()
+	cancelParent()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled, "The command's own context is respected")
+}
+
+func Test_checkTimeout(t *testing.T) {
+	if err := os.Getenv("CHECK_TIMEOUT_ERR"); err != "" {
+		if err == "timeout" {
+			checkTimeout(fmt.Errorf("Error attempting to find the workflow run: %w", context.DeadlineExceeded))
+		}
+		checkTimeout(errors.New(err))
+		return
+	}
+
+	tests := []struct {
+		err          string
+		expectedCode int
+	}{
+		{"timeout", exitCodeTimeout},
+		{"some other failure", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^Test_checkTimeout$")
+			cmd.Env = append(os.Environ(), "CHECK_TIMEOUT_ERR="+tt.err)
+			err := cmd.Run()
+
+			var exitErr *exec.ExitError
+			if assert.ErrorAs(t, err, &exitErr) {
+				assert.Equal(t, tt.expectedCode, exitErr.ExitCode())
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	transferFrom     string
+	transferTo       string
+	transferYear     int
+	transferAnnotate bool
+	transferAuditLog string
+)
+
+var transferCmd = &cobra.Command{
+	Use:   "transfer",
+	Short: "Transfers copyright statements from one holder to another",
+	Long: `Rewrites the copyright statements in file headers that name the --from holder
+so that they name the --to holder instead, such as after an acquisition.
+
+The end year of each rewritten statement is advanced to --effective-year, and by
+default the original holder is kept alongside the new one, for example:
+
+  Copyright (c) 2019-2023 Acme Inc.
+  Copyright (c) 2019-2026 IBM Corp. (formerly Acme Inc.)
+
+Use --annotate=false to drop the original holder from the statement, and
+--audit-log to append a record of every original and rewritten line to a file,
+which may be needed to satisfy legal requirements. Holders are matched exactly,
+and statements that have already been transferred are left alone.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Change directory if needed
+		if dirPath != "." {
+			err := os.Chdir(dirPath)
+			cobra.CheckErr(err)
+		}
+
+		// Input Validation
+		if strings.TrimSpace(transferFrom) == "" || strings.TrimSpace(transferTo) == "" {
+			err := fmt.Errorf("both --from and --to must be set to a copyright holder")
+			cliLogger.Error("Error validating flags", err)
+			cobra.CheckErr(err)
+		}
+		if transferFrom == transferTo {
+			err := fmt.Errorf("--from and --to must name different copyright holders")
+			cliLogger.Error("Error validating flags", err)
+			cobra.CheckErr(err)
+		}
+
+		if !plan {
+			err := checkSafePath(".")
+			if err != nil {
+				cliLogger.Error("Error validating target directory", err)
+			}
+			cobra.CheckErr(err)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if plan {
+			infof(cmd, "%s", text.FgYellow.Sprint("Executing in dry-run mode. Rerun without the `--plan` flag to apply changes.\n\n"))
+		}
+
+		infof(cmd, "Transferring copyright statements from %q to %q, effective %d\n\n", transferFrom, transferTo, transferYear)
+
+		stdcliLogger := resultLogger(cmd)
+
+		opts := headerOptions()
+		err := skipSubmodules(cmd, &opts)
+		if err != nil {
+			cliLogger.Error("Error applying the submodules policy", err)
+		}
+		cobra.CheckErr(err)
+
+		var mu sync.Mutex
+		transfers := map[string][]licensecheck.HolderTransfer{}
+
+		err = addlicense.Walk(conf.Project.HeaderIgnore, []string{"."}, stdcliLogger, opts, func(path string) error {
+			b, err := addlicense.ReadHead(path)
+			if err != nil {
+				return err
+			}
+
+			if addlicense.HasIgnoreFileMarker(b) {
+				stdcliLogger.Printf("[DEBUG] skipping: %s (%s marker)", path, addlicense.IgnoreFileMarker)
+				return nil
+			}
+
+			if _, t := licensecheck.TransferCopyrightHolder(b, transferFrom, transferTo, transferYear, transferAnnotate); len(t) == 0 {
+				return nil
+			}
+
+			// Only read the full file once it's known to need changes
+			b, err = os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			updated, t := licensecheck.TransferCopyrightHolder(b, transferFrom, transferTo, transferYear, transferAnnotate)
+
+			mu.Lock()
+			transfers[path] = t
+			mu.Unlock()
+
+			if plan {
+				return nil
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(path, updated, fi.Mode())
+		})
+		if err != nil {
+			cliLogger.Error("Error transferring copyright statements", err)
+		}
+		cobra.CheckErr(err)
+
+		paths := lo.Keys(transfers)
+		sort.Strings(paths)
+
+		gha.StartGroup("The following files have copyright statements to transfer:")
+		for _, path := range paths {
+			cmd.Println(path)
+			if showDiff {
+				for _, t := range transfers[path] {
+					cmd.Printf("%s\n%s\n", text.FgRed.Sprintf("-%s", t.Original), text.FgGreen.Sprintf("+%s", t.Updated))
+				}
+			}
+		}
+		gha.EndGroup()
+
+		if plan {
+			if len(paths) > 0 {
+				cobra.CheckErr(fmt.Sprintf("%d files have copyright statements naming %s. Run without the --plan flag to transfer them", len(paths), transferFrom))
+			}
+			return
+		}
+
+		if transferAuditLog != "" && len(paths) > 0 {
+			err := appendTransferLog(transferAuditLog, paths, transfers)
+			if err != nil {
+				cliLogger.Error("Error writing audit log", err)
+			}
+			cobra.CheckErr(err)
+			cmd.Printf("\nRecorded %d files in %s\n", len(paths), transferAuditLog)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(transferCmd)
+
+	// These flags are only locally relevant
+	transferCmd.Flags().StringVarP(&dirPath, "dirPath", "d", ".", "Path to the directory in which you wish to transfer copyright statements")
+	transferCmd.Flags().BoolVar(&plan, "plan", false, "Performs a dry-run, printing the names of all files that would be changed")
+	transferCmd.Flags().BoolVar(&showDiff, "diff", false, "Prints the changed lines of each file")
+	transferCmd.Flags().StringVar(&transferFrom, "from", "", "Copyright holder to transfer statements from (e.g., \"Acme Inc.\")")
+	transferCmd.Flags().StringVar(&transferTo, "to", "", "Copyright holder to transfer statements to (e.g., \"IBM Corp.\")")
+	transferCmd.Flags().IntVar(&transferYear, "effective-year", time.Now().Year(), "Year the transfer takes effect, to which end years are advanced (0 leaves years alone)")
+	transferCmd.Flags().BoolVar(&transferAnnotate, "annotate", true, "Keep the original holder in the statement, e.g. \"(formerly Acme Inc.)\"")
+	transferCmd.Flags().StringVar(&transferAuditLog, "audit-log", "", "Path of a file to append a record of every rewritten line to")
+	transferCmd.Flags().IntVar(&parallelism, "parallelism", 0, "Number of directories to read concurrently while discovering files (default is the number of CPUs)")
+	addAllowUnsafePathsFlag(transferCmd)
+	cobra.CheckErr(transferCmd.MarkFlagRequired("from"))
+	cobra.CheckErr(transferCmd.MarkFlagRequired("to"))
+}
+
+// appendTransferLog appends a record of the given transfers to the audit log at
+// logPath, creating it if needed
+func appendTransferLog(logPath string, paths []string, transfers map[string][]licensecheck.HolderTransfer) error {
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s: transferred from %q to %q, effective %d\n", time.Now().UTC().Format(time.RFC3339), transferFrom, transferTo, transferYear)
+	for _, path := range paths {
+		for _, t := range transfers[path] {
+			fmt.Fprintf(&sb, "%s:%d\n- %s\n+ %s\n", path, t.Line, t.Original, t.Updated)
+		}
+	}
+
+	if _, err := f.WriteString(sb.String()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/archive"
+	"github.com/hashicorp/copywrite/licensecheck"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+var verifyArchiveCmd = &cobra.Command{
+	Use:   "verify-archive <path>",
+	Short: "Validates license and header compliance of a release archive",
+	Long: `Extracts a .zip, .tar, .tar.gz, or .tgz release artifact to a temporary
+directory and validates that:
+- A LICENSE file is present at the root of the archive
+- All source files contained in the archive have copyright headers
+
+This allows release pipelines to gate on the artifact that is actually shipped,
+rather than the repo it was built from. The project.header_ignore list from
+config is applied relative to the root of the archive.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`spdx`:             `project.license`,
+			`copyright-holder`: `project.copyright_holder`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		err := verifyArchive(cmd, args[0])
+		cobra.CheckErr(err)
+		cmd.Println(text.FgGreen.Sprint("Archive is compliant"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyArchiveCmd)
+
+	// These flags will get mapped to keys in the the global Config
+	verifyArchiveCmd.Flags().StringP("spdx", "s", "", "SPDX-compliant license identifier or expression (e.g., 'MPL-2.0')")
+	cobra.CheckErr(verifyArchiveCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
+	verifyArchiveCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+}
+
+// verifyArchive extracts the archive at path and checks it for compliance. It
+// is split out from the command so that the temporary directory is always
+// cleaned up before exiting.
+func verifyArchive(cmd *cobra.Command, path string) error {
+	tmp, err := os.MkdirTemp("", "copywrite-archive")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	cmd.Printf("Extracting %s\n\n", path)
+	root, err := archive.Extract(path, tmp)
+	if err != nil {
+		cliLogger.Error("Error extracting archive", err)
+		return err
+	}
+
+	// Paths are reported relative to the root of the archive
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(root); err != nil {
+		return err
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	problems := []error{}
+
+	licenseFiles, err := licensecheck.FindLicenseFiles(".")
+	if err != nil {
+		return err
+	}
+	if len(licenseFiles) == 0 {
+		cmd.Println(text.FgRed.Sprint("No LICENSE file was found at the root of the archive"))
+		problems = append(problems, errors.New("missing license file"))
+	} else {
+		cmd.Printf("Found license file: %s\n", licenseFiles[0])
+	}
+	cmd.Println("")
+
+	licenseData := addlicense.LicenseData{
+		Holder: conf.Project.CopyrightHolder,
+		SPDXID: conf.Project.License,
+	}
+	opts := headerOptions()
+	stdcliLogger := resultLogger(cmd)
+
+	gha.StartGroup("The following files in the archive are missing headers:")
+	err = addlicense.Run(conf.Project.HeaderIgnore, "only", licenseData, "", false, true, []string{"."}, stdcliLogger, opts)
+	gha.EndGroup()
+	if err != nil {
+		problems = append(problems, err)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("archive is not compliant: %w", errors.Join(problems...))
+	}
+	return nil
+}
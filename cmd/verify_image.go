@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/hashicorp/copywrite/archive"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+)
+
+// Flag variables
+var (
+	requiredImagePaths []string
+	imageSourcePaths   []string
+)
+
+var verifyImageCmd = &cobra.Command{
+	Use:   "verify-image <ref|path>",
+	Short: "Validates license and header compliance of a container image",
+	Long: `Pulls a container image, flattens its layers into a temporary directory, and
+validates that:
+- Each path given with --require (e.g., LICENSE and NOTICE files) exists
+- All source files beneath each path given with --source-path have copyright headers
+
+Images are pulled and exported using the docker CLI. Alternatively, a path to a
+tarball created with "docker save" or an OCI image layout archive may be given.
+
+This is useful for teams shipping source code in images, where license
+compliance (e.g., for AGPL or MPL code) depends on what is in the image itself.`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Map command flags to config keys
+		mapping := map[string]string{
+			`spdx`:             `project.license`,
+			`copyright-holder`: `project.copyright_holder`,
+		}
+
+		// update the running config with any command-line flags
+		clobberWithDefaults := false
+		err := conf.LoadCommandFlags(cmd.Flags(), mapping, clobberWithDefaults)
+		if err != nil {
+			cliLogger.Error("Error merging configuration", err)
+		}
+		cobra.CheckErr(err)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		err := verifyImage(cmd, args[0])
+		cobra.CheckErr(err)
+		cmd.Println(text.FgGreen.Sprint("Image is compliant"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyImageCmd)
+
+	// These flags are only locally relevant
+	verifyImageCmd.Flags().StringSliceVar(&requiredImagePaths, "require", []string{"LICENSE"}, "Paths within the image that must exist (e.g., \"/usr/share/doc/myapp/LICENSE\")")
+	verifyImageCmd.Flags().StringSliceVar(&imageSourcePaths, "source-path", nil, "Paths within the image containing source files that must have headers (e.g., \"/app/src\")")
+
+	// These flags will get mapped to keys in the the global Config
+	verifyImageCmd.Flags().StringP("spdx", "s", "", "SPDX-compliant license identifier or expression (e.g., 'MPL-2.0')")
+	cobra.CheckErr(verifyImageCmd.RegisterFlagCompletionFunc("spdx", completeSPDX))
+	verifyImageCmd.Flags().StringP("copyright-holder", "c", "", "Copyright holder (default \"HashiCorp, Inc.\")")
+}
+
+// verifyImage exports and flattens the image, then checks it for compliance.
+// It is split out from the command so that temporary directories are always
+// cleaned up before exiting.
+func verifyImage(cmd *cobra.Command, ref string) error {
+	tmp, err := os.MkdirTemp("", "copywrite-image")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	imagePath := ref
+	if _, err := os.Stat(ref); err != nil {
+		imagePath = filepath.Join(tmp, "image.tar")
+		if err := saveImage(cmd, ref, imagePath); err != nil {
+			cliLogger.Error("Error exporting image", err)
+			return err
+		}
+	}
+
+	root := filepath.Join(tmp, "rootfs")
+	cmd.Printf("Extracting image layers from %s\n\n", ref)
+	if err := archive.ExtractImage(imagePath, root); err != nil {
+		cliLogger.Error("Error extracting image", err)
+		return err
+	}
+
+	problems := []error{}
+
+	for _, p := range requiredImagePaths {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(p))); err != nil {
+			cmd.Println(text.FgRed.Sprintf("Required file is missing from the image: %s", p))
+			problems = append(problems, fmt.Errorf("missing required file %s", p))
+		} else {
+			cmd.Printf("Found required file: %s\n", p)
+		}
+	}
+	cmd.Println("")
+
+	if len(imageSourcePaths) == 0 {
+		infof(cmd, "No --source-path was given, skipping header validation.\n")
+	} else {
+		// Paths are reported relative to the root of the image
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(root); err != nil {
+			return err
+		}
+		defer func() { _ = os.Chdir(wd) }()
+
+		patterns := []string{}
+		for _, p := range imageSourcePaths {
+			patterns = append(patterns, filepath.Clean("."+string(filepath.Separator)+filepath.FromSlash(p)))
+		}
+
+		licenseData := addlicense.LicenseData{
+			Holder: conf.Project.CopyrightHolder,
+			SPDXID: conf.Project.License,
+		}
+		opts := headerOptions()
+		stdcliLogger := resultLogger(cmd)
+
+		gha.StartGroup("The following files in the image are missing headers:")
+		err = addlicense.Run(conf.Project.HeaderIgnore, "only", licenseData, "", false, true, patterns, stdcliLogger, opts)
+		gha.EndGroup()
+		if err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("image is not compliant: %w", errors.Join(problems...))
+	}
+	return nil
+}
+
+// saveImage pulls an image by reference and exports it to a tarball with the
+// docker CLI
+func saveImage(cmd *cobra.Command, ref string, dest string) error {
+	for _, args := range [][]string{
+		{"pull", ref},
+		{"save", "--output", dest, ref},
+	} {
+		cmd.Printf("Running: docker %s\n", strings.Join(args, " "))
+
+		var stderr bytes.Buffer
+		c := exec.Command("docker", args...)
+		c.Stderr = &stderr
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return nil
+}
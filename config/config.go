@@ -7,12 +7,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/parsers/hcl"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
+	"github.com/knadh/koanf/providers/rawbytes"
 	"github.com/spf13/pflag"
 )
 
@@ -30,8 +33,116 @@ type Project struct {
 	HeaderIgnore    []string `koanf:"header_ignore"`
 	License         string   `koanf:"license"`
 
+	// HeaderInclude, if set, restricts every command that checks or updates
+	// headers to files matching one of its globs
+	HeaderInclude []string `koanf:"header_include"`
+
+	// IncludeSPDX controls whether headers include an SPDX-License-Identifier
+	// line (the default), and SPDXOnly omits the copyright statement from
+	// headers, leaving only the SPDX-License-Identifier line
+	IncludeSPDX *bool `koanf:"include_spdx"`
+	SPDXOnly    bool  `koanf:"spdx_only"`
+
+	// HeaderTemplate selects what new headers contain: "spdx" (the default)
+	// for a copyright statement and SPDX identifier, or "license" for the
+	// license's own header template, such as the Apache License notice
+	HeaderTemplate string `koanf:"header_template"`
+
+	// LicenseTemplatesDir is an optional directory of license header
+	// templates, named after their license (e.g., "LicenseRef-Acme.tmpl"),
+	// which take precedence over the built-in ones
+	LicenseTemplatesDir string `koanf:"license_templates_dir"`
+
+	// HeaderTemplateEnv lists the environment variables that license header
+	// templates may read with the env function
+	HeaderTemplateEnv []string `koanf:"header_template_env"`
+
+	// CopyrightFormat is the template for generated copyright statements, e.g.
+	// "Copyright {{.Years}} {{.Holder}}. All rights reserved."
+	CopyrightFormat string `koanf:"copyright_format"`
+
+	// LicenseFileName is the canonical name that the license command gives
+	// license files, such as "LICENCE" or "COPYING" (default "LICENSE")
+	LicenseFileName string `koanf:"license_file_name"`
+
 	// Upstream is optional and only used if a given repo pulls from another
 	Upstream string `koanf:"upstream"`
+
+	// DispatchOptOut excludes the repo from org-wide audits by `copywrite
+	// dispatch` and `copywrite orchestrate` that respect repo configs
+	DispatchOptOut bool `koanf:"dispatch_opt_out"`
+
+	// CopyrightKeywords are additional words or phrases (e.g., translations of
+	// "copyright") that indicate a file already has a copyright statement
+	CopyrightKeywords []string `koanf:"copyright_keywords"`
+
+	// NeverTouch is a list of globs that are always skipped by every command,
+	// in addition to built-in defaults such as lockfiles and minified assets
+	NeverTouch []string `koanf:"never_touch"`
+
+	// NeverTouchExceptions are built-in never-touch patterns (e.g.,
+	// "**/*.pb.go") that should be processed after all
+	NeverTouchExceptions []string `koanf:"never_touch_exceptions"`
+
+	// IncludeHidden controls whether hidden files and directories, whose names
+	// start with a dot, are processed (the default)
+	IncludeHidden *bool `koanf:"include_hidden"`
+
+	// HeaderSpacing is the number of blank lines placed after copyright headers.
+	// If unset, new headers are followed by a single blank line and existing
+	// headers are left as they are.
+	HeaderSpacing *int `koanf:"header_spacing"`
+
+	// PreserveAdjacent are regular expressions for attribution lines (e.g.,
+	// "Original author: ...") that are kept attached to the header above them,
+	// in addition to built-in defaults
+	PreserveAdjacent []string `koanf:"preserve_adjacent"`
+
+	// HeaderStyle controls how new headers are framed: "plain" (default),
+	// "boxed", or "banner", with borders drawn using HeaderBorder ("=" if unset)
+	HeaderStyle  string `koanf:"header_style"`
+	HeaderBorder string `koanf:"header_border"`
+
+	// SyntaxAware places new headers using knowledge of each language's
+	// syntax, where available, rather than only by matching lines at the top
+	// of files. It doesn't change how existing headers are detected.
+	SyntaxAware bool `koanf:"syntax_aware"`
+
+	// JSONComments enables headers in JSON files that tolerate comments, such
+	// as .jsonc and .json5 files and tsconfig.json
+	JSONComments bool `koanf:"json_comments"`
+
+	// MATLABFiles treats .m files as MATLAB rather than Objective-C
+	MATLABFiles bool `koanf:"matlab_m_files"`
+
+	// AssemblyComment is the comment syntax used for headers in assembly files:
+	// ";", "#", "//", or "/*". If unset, it depends on the file extension.
+	AssemblyComment string `koanf:"asm_comment"`
+
+	// YAMLHeaderPosition controls where headers are added to YAML files:
+	// "document" (default) places them below any directives and the marker
+	// that starts the first document, and "top" at the very top of the file
+	YAMLHeaderPosition string `koanf:"yaml_header_position"`
+
+	// Submodules controls how commands that modify headers treat checked-out
+	// git submodules: "skip" (default) leaves them alone, "check" processes
+	// them like any other folder, and "fail" returns an error if any are found
+	Submodules string `koanf:"submodules"`
+
+	// PublicDomain controls how files dedicated to the public domain, such as
+	// with the Unlicense or CC0, are treated: "keep" (default) counts the
+	// dedication as a header and leaves them alone, and "convert" replaces it
+	// with the configured header
+	PublicDomain string `koanf:"public_domain"`
+
+	// YearStrategy controls how `copywrite bump-years` refreshes the years in
+	// existing copyright statements: "range" (default) or "current"
+	YearStrategy string `koanf:"year_strategy"`
+
+	// ScanLines and ScanBytes limit how much of the top of each file is searched
+	// for existing headers. If unset, the first 1k bytes are searched.
+	ScanLines int `koanf:"scan_lines"`
+	ScanBytes int `koanf:"scan_bytes"`
 }
 
 // Dispatch represents data needed by the `copywrite dispatch` command, and is
@@ -46,11 +157,28 @@ type Dispatch struct {
 	// The GitHub Organization who's repositories you want to audit
 	GitHubOrgToAudit string `koanf:"github_org_to_audit"`
 
+	// Orgs, if set, are several GitHub Organizations to audit in one batch,
+	// in place of GitHubOrgToAudit, each declared in its own org block
+	Orgs []DispatchOrg `koanf:"org"`
+
 	// A list of repos that should be exempted from scans.
 	// Repo names must be fully-qualified (i.e., include the org name), like so:
 	// "hashicorp/copywrite"
 	IgnoredRepos []string `koanf:"ignored_repos"`
 
+	// Repos that are forks, templates, or empty can be excluded from audits,
+	// since auditing them rarely results in useful changes
+	ExcludeForks     bool `koanf:"exclude_forks"`
+	ExcludeTemplates bool `koanf:"exclude_templates"`
+	ExcludeEmpty     bool `koanf:"exclude_empty"`
+
+	// Languages, if set, limits audits to repos whose primary language (as
+	// detected by GitHub) is in the list, e.g. ["Go", "HCL"]
+	Languages []string `koanf:"languages"`
+
+	// MaxRepoSize, if set, excludes repos larger than this many kilobytes
+	MaxRepoSize int `koanf:"max_repo_size"`
+
 	// Sleep time in seconds between polling operations
 	Sleep int `koanf:"sleep"`
 
@@ -63,6 +191,26 @@ type Dispatch struct {
 
 	// The workflow file name to be used when triggering GitHub Actions jobs
 	WorkflowFileName string `koanf:"workflow_file_name"`
+
+	// RespectRepoConfig fetches the .copywrite.hcl config of each repo before
+	// auditing it, and skips repos that set project.dispatch_opt_out or
+	// project.upstream
+	RespectRepoConfig bool `koanf:"respect_repo_config"`
+}
+
+// DispatchOrg is a GitHub Organization audited by `copywrite dispatch` as part
+// of a batch spanning several orgs. Settings left unset fall back to those of
+// the dispatch block.
+type DispatchOrg struct {
+	Name string `koanf:"name"`
+
+	// Repos in the org that should be exempted from scans, in addition to the
+	// dispatch block's, which must also be fully-qualified
+	IgnoredRepos []string `koanf:"ignored_repos"`
+
+	// The workflow file name to be used when triggering GitHub Actions jobs
+	// for repos in the org
+	WorkflowFileName string `koanf:"workflow_file_name"`
 }
 
 // Config is a struct representing the data from a well-defined config file
@@ -71,6 +219,10 @@ type Config struct {
 	Project       Project  `koanf:"project"`
 	Dispatch      Dispatch `koanf:"dispatch"`
 
+	// RequiredVersion is an optional list of version constraints, such as
+	// ">= 0.20, < 1.0", that the running copywrite binary must satisfy
+	RequiredVersion string `koanf:"required_version"`
+
 	// Global koanf instance
 	globalKoanf *koanf.Koanf
 
@@ -226,14 +378,75 @@ func (c *Config) LoadConfigFile(cfgPath string) error {
 	return nil
 }
 
+// LoadConfigBytes parses the contents of a .copywrite.hcl config, such as one
+// fetched from another repo, and merges it with the running config
+func (c *Config) LoadConfigBytes(b []byte) error {
+	err := c.globalKoanf.Load(rawbytes.Provider(b), hcl.Parser(true))
+	if err != nil {
+		return fmt.Errorf("Unable to load config: %w", err)
+	}
+
+	err = c.globalKoanf.Unmarshal("", &c)
+	if err != nil {
+		return fmt.Errorf("Unable to unmarshal config: %w", err)
+	}
+
+	return nil
+}
+
 // Sprint returns a textual version of the current running config.
 // The string is newline-delimited and contains alphabetical key -> value pairs
 func (c *Config) Sprint() string {
 	return c.globalKoanf.Sprint()
 }
 
+// Map returns the running config as a map of delimited keys (e.g.,
+// "project.license") to their values
+func (c *Config) Map() map[string]interface{} {
+	return c.globalKoanf.All()
+}
+
+// Get returns the running value of a delimited configuration key, such as
+// "project.license", or nil if it is not set
+func (c *Config) Get(key string) interface{} {
+	return c.globalKoanf.Get(key)
+}
+
 // GetConfigPath returns the absolute path of the last loaded HCL config.
 // If LoadConfigFile() has not been called, it will return an empty string.
 func (c *Config) GetConfigPath() string {
 	return c.absCfgPath
 }
+
+// Keys returns every configuration key that may be set in a .copywrite.hcl
+// file, in alphabetical order, e.g. "project.license" or "schema_version"
+func Keys() []string {
+	keys := []string{}
+	for k := range keyTypes(reflect.TypeOf(Config{}), "") {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keyTypes recursively maps the delimited koanf keys of a struct type to the
+// types of their fields
+func keyTypes(t reflect.Type, prefix string) map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("koanf")
+		if tag == "" || !f.IsExported() {
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			for k, v := range keyTypes(f.Type, prefix+tag+delim) {
+				types[k] = v
+			}
+		} else {
+			types[prefix+tag] = f.Type
+		}
+	}
+	return types
+}
@@ -5,6 +5,7 @@ package config
 
 import (
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
@@ -196,6 +197,13 @@ func Test_LoadConfigFile(t *testing.T) {
 				SchemaVersion: 42,
 			},
 		},
+		{
+			description:  "File with required_version updates config accordingly",
+			inputCfgPath: "testdata/config_with_required_version.hcl",
+			expectedOutput: &Config{
+				RequiredVersion: ">= 0.20, < 1.0",
+			},
+		},
 		// Test Project-Related Configuration
 		{
 			description:  "File with project.copyright_holder populates accordingly",
@@ -266,6 +274,11 @@ func Test_LoadConfigFile(t *testing.T) {
 						"org/repo1",
 						"org/repo2",
 					},
+					ExcludeForks:     true,
+					ExcludeTemplates: true,
+					ExcludeEmpty:     true,
+					Languages:        []string{"Go", "HCL"},
+					MaxRepoSize:      500000,
 					Sleep:            42,
 					MaxAttempts:      3,
 					Workers:          12,
@@ -273,6 +286,25 @@ func Test_LoadConfigFile(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:  "File with several dispatch orgs populates accordingly",
+			inputCfgPath: "testdata/dispatch/multi_org_dispatch.hcl",
+			expectedOutput: &Config{
+				SchemaVersion: 1,
+				Dispatch: Dispatch{
+					IgnoredRepos:     []string{"hashicorp/copywrite"},
+					WorkflowFileName: "repair-repo-headers.yml",
+					Orgs: []DispatchOrg{
+						{Name: "hashicorp"},
+						{
+							Name:             "hashicorp-forge",
+							IgnoredRepos:     []string{"hashicorp-forge/repo1"},
+							WorkflowFileName: "repair-forge-headers.yml",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -293,6 +325,16 @@ func Test_LoadConfigFile(t *testing.T) {
 	}
 }
 
+func Test_LoadConfigBytes(t *testing.T) {
+	c := &Config{globalKoanf: koanf.New(delim)}
+	err := c.LoadConfigBytes([]byte("project {\n  dispatch_opt_out = true\n}\n"))
+	assert.Nil(t, err, "Loading should not error")
+	assert.True(t, c.Project.DispatchOptOut)
+
+	err = c.LoadConfigBytes([]byte("project {"))
+	assert.NotNil(t, err, "Malformed HCL should error")
+}
+
 func Test_Sprint(t *testing.T) {
 	tests := []struct {
 		description    string
@@ -329,9 +371,14 @@ func Test_Sprint(t *testing.T) {
 			expectedOutput: strings.Join([]string{
 				"dispatch.batch_id -> aZ0-9",
 				"dispatch.branch -> main",
+				"dispatch.exclude_empty -> true",
+				"dispatch.exclude_forks -> true",
+				"dispatch.exclude_templates -> true",
 				"dispatch.github_org_to_audit -> hashicorp-forge",
 				"dispatch.ignored_repos -> [org/repo1 org/repo2]",
+				"dispatch.languages -> [Go HCL]",
 				"dispatch.max_attempts -> 3",
+				"dispatch.max_repo_size -> 500000",
 				"dispatch.sleep -> 42",
 				"dispatch.workers -> 12",
 				"dispatch.workflow_file_name -> repair-repo-headers.yml",
@@ -354,6 +401,21 @@ func Test_Sprint(t *testing.T) {
 	}
 }
 
+func Test_Map(t *testing.T) {
+	c := MustNew()
+	err := c.LoadConfMap(map[string]interface{}{
+		"project.license": "MPL-2.0",
+	})
+	assert.Nil(t, err)
+
+	expectedOutput := map[string]interface{}{
+		"project.copyright_holder": "HashiCorp, Inc.",
+		"project.license":          "MPL-2.0",
+		"schema_version":           1,
+	}
+	assert.Equal(t, expectedOutput, c.Map())
+}
+
 func Test_GetConfigPath(t *testing.T) {
 	// test new config without calling Load
 	actualOutput := MustNew()
@@ -367,3 +429,13 @@ func Test_GetConfigPath(t *testing.T) {
 	abs, _ := filepath.Abs(cfgPath)
 	assert.Equal(t, abs, actualOutput.GetConfigPath(), "Loaded config should return abs file path")
 }
+
+func Test_Keys(t *testing.T) {
+	keys := Keys()
+
+	assert.Contains(t, keys, "schema_version")
+	assert.Contains(t, keys, "project.license")
+	assert.Contains(t, keys, "dispatch.ignored_repos")
+	assert.NotContains(t, keys, "project", "Sections are not keys themselves")
+	assert.True(t, sort.StringsAreSorted(keys), "Keys must be sorted")
+}
@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+)
+
+// SetFileValue sets a single delimited key (e.g., "project.license") in the HCL
+// config file at cfgPath, creating the file if it does not exist. List keys
+// accept any number of values, while all other keys require exactly one.
+//
+// Rather than re-rendering the whole file, only the value of the key is
+// replaced (or a new line inserted), so that comments and formatting elsewhere
+// in the file are preserved.
+func SetFileValue(cfgPath string, key string, values ...string) error {
+	src, err := os.ReadFile(cfgPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	out, err := setValue(src, key, values)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(cfgPath); err == nil {
+		mode = fi.Mode()
+	}
+	return os.WriteFile(cfgPath, out, mode)
+}
+
+// setValue returns a copy of the HCL source with key set to the given values
+func setValue(src []byte, key string, values []string) ([]byte, error) {
+	t, ok := keyTypes(reflect.TypeOf(Config{}), "")[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown config key %q", key)
+	}
+
+	val, err := formatValue(t, values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	f, err := parser.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+	root, ok := f.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, errors.New("unable to parse config: unexpected structure")
+	}
+
+	section, name, nested := strings.Cut(key, delim)
+	if !nested {
+		name = section
+		return setItem(src, root, name, val, -1, ""), nil
+	}
+
+	// Find the block for the section, e.g. project { ... }
+	for _, item := range root.Items {
+		obj, ok := item.Val.(*ast.ObjectType)
+		if !ok || len(item.Keys) != 1 || keyName(item.Keys[0]) != section {
+			continue
+		}
+		return setItem(src, obj.List, name, val, obj.Rbrace.Offset, blockIndent(src, obj)), nil
+	}
+
+	// No block exists yet, so add one to the end of the file
+	out := bytes.TrimRight(src, "\n")
+	if len(out) > 0 {
+		out = append(out, "\n\n"...)
+	}
+	out = append(out, fmt.Sprintf("%s {\n  %s = %s\n}\n", section, name, val)...)
+	return out, nil
+}
+
+// setItem replaces the value of the named item in list. If no such item exists,
+// a new line is inserted before the closing brace at rbrace, or appended to the
+// end of the file for top-level items (when rbrace is negative).
+func setItem(src []byte, list *ast.ObjectList, name string, val string, rbrace int, indent string) []byte {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 || keyName(item.Keys[0]) != name {
+			continue
+		}
+		start, end := item.Val.Pos().Offset, nodeEnd(item.Val)
+		return splice(src, start, end, val)
+	}
+
+	line := fmt.Sprintf("%s%s = %s\n", indent, name, val)
+	if rbrace < 0 {
+		out := bytes.TrimRight(src, "\n")
+		if len(out) > 0 {
+			out = append(out, '\n')
+		}
+		return append(out, line...)
+	}
+
+	// Insert on its own line, even if the closing brace shares a line with the
+	// opening one (e.g., "project {}")
+	lineStart := bytes.LastIndexByte(src[:rbrace], '\n') + 1
+	if len(bytes.TrimSpace(src[lineStart:rbrace])) == 0 {
+		return splice(src, lineStart, lineStart, line)
+	}
+	return splice(src, rbrace, rbrace, "\n"+line)
+}
+
+// blockIndent returns the indentation used by the first item in a block, or
+// two spaces if the block is empty
+func blockIndent(src []byte, obj *ast.ObjectType) string {
+	if len(obj.List.Items) == 0 {
+		return "  "
+	}
+	off := obj.List.Items[0].Pos().Offset
+	lineStart := bytes.LastIndexByte(src[:off], '\n') + 1
+	if indent := src[lineStart:off]; len(bytes.TrimSpace(indent)) == 0 {
+		return string(indent)
+	}
+	return "  "
+}
+
+// formatValue renders values as an HCL literal appropriate for type t
+func formatValue(t reflect.Type, values []string) (string, error) {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct {
+		return "", fmt.Errorf("blocks can't be set from the command line")
+	}
+	if t.Kind() == reflect.Slice {
+		quoted := []string{}
+		for _, v := range values {
+			quoted = append(quoted, strconv.Quote(v))
+		}
+		return "[" + strings.Join(quoted, ", ") + "]", nil
+	}
+
+	// Optional values are represented by pointers
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if len(values) != 1 {
+		return "", fmt.Errorf("expected exactly one value, got %d", len(values))
+	}
+	v := values[0]
+
+	switch t.Kind() {
+	case reflect.Int:
+		if _, err := strconv.Atoi(v); err != nil {
+			return "", fmt.Errorf("%q is not a number", v)
+		}
+		return v, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a boolean", v)
+		}
+		return strconv.FormatBool(b), nil
+	default:
+		return strconv.Quote(v), nil
+	}
+}
+
+// keyName returns the unquoted name of an object key
+func keyName(k *ast.ObjectKey) string {
+	if s, ok := k.Token.Value().(string); ok {
+		return s
+	}
+	return k.Token.Text
+}
+
+// nodeEnd returns the offset just past the end of a value node
+func nodeEnd(n ast.Node) int {
+	switch n := n.(type) {
+	case *ast.LiteralType:
+		return n.Token.Pos.Offset + len(n.Token.Text)
+	case *ast.ListType:
+		return n.Rbrack.Offset + 1
+	case *ast.ObjectType:
+		return n.Rbrace.Offset + 1
+	}
+	return n.Pos().Offset
+}
+
+// splice replaces src[start:end] with s
+func splice(src []byte, start int, end int, s string) []byte {
+	out := make([]byte, 0, len(src)+len(s))
+	out = append(out, src[:start]...)
+	out = append(out, s...)
+	return append(out, src[end:]...)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_setValue(t *testing.T) {
+	src := `schema_version = 1
+
+project {
+  # The license for this repo
+  license = "MPL-2.0" # keep this comment
+
+  header_ignore = [
+    "vendor/**",
+  ]
+}
+`
+
+	tests := []struct {
+		description    string
+		src            string
+		key            string
+		values         []string
+		expectedOutput string
+		expectErr      bool
+	}{
+		{
+			description: "Replace an existing string, preserving comments",
+			src:         src,
+			key:         "project.license",
+			values:      []string{"MIT"},
+			expectedOutput: `schema_version = 1
+
+project {
+  # The license for this repo
+  license = "MIT" # keep this comment
+
+  header_ignore = [
+    "vendor/**",
+  ]
+}
+`,
+		},
+		{
+			description: "Replace an existing list",
+			src:         src,
+			key:         "project.header_ignore",
+			values:      []string{"a/**", "b/**"},
+			expectedOutput: `schema_version = 1
+
+project {
+  # The license for this repo
+  license = "MPL-2.0" # keep this comment
+
+  header_ignore = ["a/**", "b/**"]
+}
+`,
+		},
+		{
+			description: "Replace a top-level number",
+			src:         src,
+			key:         "schema_version",
+			values:      []string{"2"},
+			expectedOutput: `schema_version = 2
+
+project {
+  # The license for this repo
+  license = "MPL-2.0" # keep this comment
+
+  header_ignore = [
+    "vendor/**",
+  ]
+}
+`,
+		},
+		{
+			description: "Add a key to an existing block",
+			src:         src,
+			key:         "project.copyright_year",
+			values:      []string{"2020"},
+			expectedOutput: `schema_version = 1
+
+project {
+  # The license for this repo
+  license = "MPL-2.0" # keep this comment
+
+  header_ignore = [
+    "vendor/**",
+  ]
+  copyright_year = 2020
+}
+`,
+		},
+		{
+			description: "Add a key to an empty single-line block",
+			src:         "project {}\n",
+			key:         "project.license",
+			values:      []string{"MIT"},
+			expectedOutput: `project {
+  license = "MIT"
+}
+`,
+		},
+		{
+			description: "Add a block that does not exist yet",
+			src:         src,
+			key:         "dispatch.branch",
+			values:      []string{"main"},
+			expectedOutput: src + `
+dispatch {
+  branch = "main"
+}
+`,
+		},
+		{
+			description:    "Create a config from scratch",
+			src:            "",
+			key:            "project.license",
+			values:         []string{"MIT"},
+			expectedOutput: "project {\n  license = \"MIT\"\n}\n",
+		},
+		{
+			description:    "Optional numbers are written as numbers",
+			src:            "project {}\n",
+			key:            "project.header_spacing",
+			values:         []string{"2"},
+			expectedOutput: "project {\n  header_spacing = 2\n}\n",
+		},
+		{
+			description: "Unknown keys are rejected",
+			src:         src,
+			key:         "project.licence",
+			values:      []string{"MIT"},
+			expectErr:   true,
+		},
+		{
+			description: "Blocks are rejected",
+			src:         src,
+			key:         "dispatch.org",
+			values:      []string{"hashicorp"},
+			expectErr:   true,
+		},
+		{
+			description: "Numbers are validated",
+			src:         src,
+			key:         "project.copyright_year",
+			values:      []string{"last year"},
+			expectErr:   true,
+		},
+		{
+			description: "Scalars require exactly one value",
+			src:         src,
+			key:         "project.license",
+			values:      []string{"MIT", "Apache-2.0"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, err := setValue([]byte(tt.src), tt.key, tt.values)
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedOutput, string(actualOutput))
+		})
+	}
+}
@@ -0,0 +1 @@
+required_version = ">= 0.20, < 1.0"
@@ -12,6 +12,14 @@ dispatch {
     "org/repo2",
   ]
 
+  exclude_forks     = true
+  exclude_templates = true
+  exclude_empty     = true
+
+  languages = ["Go", "HCL"]
+
+  max_repo_size = 500000
+
   sleep = 42
 
   max_attempts = 3
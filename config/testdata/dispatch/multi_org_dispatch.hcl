@@ -0,0 +1,21 @@
+schema_version = 1
+
+dispatch {
+  workflow_file_name = "repair-repo-headers.yml"
+
+  ignored_repos = [
+    "hashicorp/copywrite",
+  ]
+
+  org {
+    name = "hashicorp"
+  }
+
+  org {
+    name               = "hashicorp-forge"
+    workflow_file_name = "repair-forge-headers.yml"
+    ignored_repos = [
+      "hashicorp-forge/repo1",
+    ]
+  }
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dispatch
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// maxArtifactBytes caps the size of each artifact downloaded by DownloadDiffs
+const maxArtifactBytes = 100 << 20
+
+// Diff is a diff or patch file uploaded as an artifact by a dry-run workflow
+type Diff struct {
+	Name    string
+	Content []byte
+}
+
+// RepoDiffs are the diffs produced by the dry-run audit of a single repo
+type RepoDiffs struct {
+	Repo  string
+	Diffs []Diff
+}
+
+// DownloadDiffs downloads every artifact of the given workflow run and returns
+// the diff and patch files (those ending in .diff or .patch) found inside them
+func DownloadDiffs(ctx context.Context, client *github.Client, opts Options, runID int64) ([]Diff, error) {
+	diffs := []Diff{}
+	listOpts := &github.ListOptions{PerPage: 100}
+	for {
+		list, resp, err := client.Actions.ListWorkflowRunArtifacts(ctx, opts.GitHubOwner, opts.GitHubRepo, runID, listOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range list.Artifacts {
+			b, err := downloadArtifact(ctx, client, opts, a)
+			if err != nil {
+				return nil, err
+			}
+			found, err := extractDiffs(b)
+			if err != nil {
+				return nil, fmt.Errorf("artifact %q: %w", a.GetName(), err)
+			}
+			diffs = append(diffs, found...)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+	return diffs, nil
+}
+
+// downloadArtifact returns the zip archive of a workflow run artifact
+func downloadArtifact(ctx context.Context, client *github.Client, opts Options, a *github.Artifact) ([]byte, error) {
+	u, _, err := client.Actions.DownloadArtifact(ctx, opts.GitHubOwner, opts.GitHubRepo, a.GetID(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The URL is pre-signed, so no GitHub credentials are needed to download it
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading artifact %q: %s", a.GetName(), resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxArtifactBytes))
+}
+
+// extractDiffs returns the diff and patch files in a zip archive, sorted by name
+func extractDiffs(b []byte) ([]Diff, error) {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []Diff{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if ext := path.Ext(f.Name); ext != ".diff" && ext != ".patch" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxArtifactBytes))
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, Diff{Name: f.Name, Content: content})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs, nil
+}
+
+// WriteBundle writes a single markdown document containing the diffs of every
+// repo in a dry-run batch, so that all of the changes can be reviewed before a
+// real run. Repos are listed in alphabetical order, followed by those that had
+// no changes.
+func WriteBundle(w io.Writer, batchID string, repos []RepoDiffs) error {
+	sorted := append([]RepoDiffs{}, repos...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Repo < sorted[j].Repo })
+
+	changed := []RepoDiffs{}
+	unchanged := []string{}
+	for _, r := range sorted {
+		if len(r.Diffs) == 0 {
+			unchanged = append(unchanged, r.Repo)
+		} else {
+			changed = append(changed, r)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Dry-run changes for batch %s\n\n", batchID)
+	fmt.Fprintf(&b, "%d of %d repos would be changed.\n", len(changed), len(sorted))
+
+	for _, r := range changed {
+		fmt.Fprintf(&b, "\n## %s\n", r.Repo)
+		for _, d := range r.Diffs {
+			content := strings.TrimRight(string(d.Content), "\n")
+			fence := "```"
+			for strings.Contains(content, fence) {
+				fence += "`"
+			}
+			fmt.Fprintf(&b, "\n`%s`\n\n%sdiff\n%s\n%s\n", d.Name, fence, content, fence)
+		}
+	}
+
+	if len(unchanged) > 0 {
+		fmt.Fprintf(&b, "\n## Repos without changes\n\n")
+		for _, r := range unchanged {
+			fmt.Fprintf(&b, "- %s\n", r)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
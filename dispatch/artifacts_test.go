@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dispatch
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractDiffs(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"repo/headers.patch": "+// Copyright\n",
+		"license.diff":       "+MPL-2.0\n",
+		"summary.txt":        "ignored",
+	} {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zw.Close())
+
+	diffs, err := extractDiffs(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, []Diff{
+		{Name: "license.diff", Content: []byte("+MPL-2.0\n")},
+		{Name: "repo/headers.patch", Content: []byte("+// Copyright\n")},
+	}, diffs)
+
+	_, err = extractDiffs([]byte("not a zip"))
+	assert.NotNil(t, err)
+}
+
+func TestWriteBundle(t *testing.T) {
+	var b strings.Builder
+	err := WriteBundle(&b, "abc123", []RepoDiffs{
+		{Repo: "org/zeta"},
+		{Repo: "org/alpha", Diffs: []Diff{{Name: "changes.diff", Content: []byte("+// Copyright\n")}}},
+	})
+	assert.Nil(t, err)
+
+	expectedOutput := "# Dry-run changes for batch abc123\n\n" +
+		"1 of 2 repos would be changed.\n\n" +
+		"## org/alpha\n\n" +
+		"`changes.diff`\n\n" +
+		"```diff\n+// Copyright\n```\n\n" +
+		"## Repos without changes\n\n" +
+		"- org/zeta\n"
+	assert.Equal(t, expectedOutput, b.String())
+}
@@ -18,6 +18,10 @@ type Result struct {
 	Name    string
 	Success bool
 	Error   error
+
+	// RunID is the ID of the GitHub Actions workflow run that audited the repo,
+	// or 0 if no run was found
+	RunID int64
 }
 
 // Options provides a way to define how frequently the GitHub APIs should be
@@ -31,11 +35,15 @@ type Options struct {
 	WorkflowFileName    string
 	GitHubOwner         string
 	GitHubRepo          string
+
+	// DryRun asks workflow runs to audit repos without opening pull requests,
+	// uploading the changes they would make as artifacts instead
+	DryRun bool
 }
 
 // WaitRunFinished watches a GitHub Actions Workflow Run and returns once the
-// workflow has finished processing
-func WaitRunFinished(client *github.Client, opts Options, run github.WorkflowRun) error {
+// workflow has finished processing, or ctx is done
+func WaitRunFinished(ctx context.Context, client *github.Client, opts Options, run github.WorkflowRun) error {
 	// Short circuit if stuff went really fast
 	if *run.Status == "completed" {
 		return nil
@@ -43,9 +51,11 @@ func WaitRunFinished(client *github.Client, opts Options, run github.WorkflowRun
 
 	for i := 0; i < opts.MaxAttempts; i++ {
 		opts.Logger.Debug(fmt.Sprintf("Waiting %d of 5 for run to finish: %s", i, *run.Name))
-		time.Sleep(time.Duration(opts.SecondsBetweenPolls) * time.Second)
+		if err := sleep(ctx, time.Duration(opts.SecondsBetweenPolls)*time.Second); err != nil {
+			return err
+		}
 
-		this, _, err := client.Actions.GetWorkflowRunByID(context.Background(), opts.GitHubOwner, opts.GitHubRepo, *run.ID)
+		this, _, err := client.Actions.GetWorkflowRunByID(ctx, opts.GitHubOwner, opts.GitHubRepo, *run.ID)
 		if err != nil {
 			return err
 		}
@@ -72,8 +82,9 @@ func WaitRunFinished(client *github.Client, opts Options, run github.WorkflowRun
 // refer to: https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#run-name
 //
 // Polling is defined by the `Options.SecondsBetweenPolls` parameter.
-// If no run is returned after `Options.MaxAttempts` attempts, an error is returned
-func FindRun(client *github.Client, opts Options, runName string) (github.WorkflowRun, error) {
+// If no run is returned after `Options.MaxAttempts` attempts, or ctx is done
+// first, an error is returned
+func FindRun(ctx context.Context, client *github.Client, opts Options, runName string) (github.WorkflowRun, error) {
 	searchOpts := &github.ListWorkflowRunsOptions{
 		Branch: opts.BranchRef,
 		// Only search for workflow runs from today
@@ -83,9 +94,8 @@ func FindRun(client *github.Client, opts Options, runName string) (github.Workfl
 	for i := 0; i < opts.MaxAttempts; i++ {
 		opts.Logger.Debug(fmt.Sprintf("Attempt %d of %d to find run for %s", i, opts.MaxAttempts, runName))
 
-		runs, _, err := client.Actions.ListWorkflowRunsByFileName(context.Background(), opts.GitHubOwner, opts.GitHubRepo, opts.WorkflowFileName, searchOpts)
+		runs, _, err := client.Actions.ListWorkflowRunsByFileName(ctx, opts.GitHubOwner, opts.GitHubRepo, opts.WorkflowFileName, searchOpts)
 		if err != nil {
-			// TODO: handle rate limiting
 			return github.WorkflowRun{}, fmt.Errorf("Error attempting to find the \"%s\" workflow run: %w", runName, err)
 		}
 
@@ -95,7 +105,9 @@ func FindRun(client *github.Client, opts Options, runName string) (github.Workfl
 			}
 		}
 
-		time.Sleep(time.Duration(opts.SecondsBetweenPolls) * time.Second)
+		if err := sleep(ctx, time.Duration(opts.SecondsBetweenPolls)*time.Second); err != nil {
+			return github.WorkflowRun{}, err
+		}
 	}
 	return github.WorkflowRun{}, fmt.Errorf("Timed out polling for workflow job")
 }
@@ -106,8 +118,9 @@ func FindRun(client *github.Client, opts Options, runName string) (github.Workfl
 //
 // Workers create a GitHub Actions workflow run and follow the status of the job
 // until it completes or errors out. The `results` channel is populated with
-// the outcome of any jobs.
-func Worker(client *github.Client, opts Options, id int, jobs <-chan string, results chan<- Result) {
+// the outcome of any jobs. Once ctx is done, any remaining jobs fail with the
+// context's error.
+func Worker(ctx context.Context, client *github.Client, opts Options, id int, jobs <-chan string, results chan<- Result) {
 	for repo := range jobs {
 		opts.Logger.Info(fmt.Sprint("worker ", id, " started job ", repo))
 
@@ -124,12 +137,12 @@ func Worker(client *github.Client, opts Options, id int, jobs <-chan string, res
 			Inputs: map[string]interface{}{
 				"repo":      repo,
 				"unique_id": opts.BatchID,
-				"dry_run":   "false",
+				"dry_run":   fmt.Sprint(opts.DryRun),
 			},
 		}
 
 		opts.Logger.Debug(fmt.Sprintf("Starting workflow run: %s", runName))
-		_, err := client.Actions.CreateWorkflowDispatchEventByFileName(context.Background(), opts.GitHubOwner, opts.GitHubRepo, opts.WorkflowFileName, event)
+		_, err := client.Actions.CreateWorkflowDispatchEventByFileName(ctx, opts.GitHubOwner, opts.GitHubRepo, opts.WorkflowFileName, event)
 		if err != nil {
 			results <- Result{
 				Name:    repo,
@@ -143,7 +156,7 @@ func Worker(client *github.Client, opts Options, id int, jobs <-chan string, res
 		// GitHub Actions only returns a 200 OK when dispatching a job. It doesn't
 		// return any Job ID or other identifying info, so we have to poll GitHub's
 		// API to grab info about the actual run we spawned.
-		run, err := FindRun(client, opts, runName)
+		run, err := FindRun(ctx, client, opts, runName)
 		if err != nil {
 			results <- Result{
 				Name:    repo,
@@ -156,12 +169,13 @@ func Worker(client *github.Client, opts Options, id int, jobs <-chan string, res
 
 		// Now that we have identified a Job ID for the run we care about, let's
 		// follow it until the run is done (successful, failed, or cancelled)
-		err = WaitRunFinished(client, opts, run)
+		err = WaitRunFinished(ctx, client, opts, run)
 		if err != nil {
 			results <- Result{
 				Name:    repo,
 				Success: false,
 				Error:   err,
+				RunID:   run.GetID(),
 			}
 			opts.Logger.Debug(fmt.Sprintf("Failed workflow run: %s", runName))
 			continue
@@ -173,6 +187,21 @@ func Worker(client *github.Client, opts Options, id int, jobs <-chan string, res
 			Name:    repo,
 			Success: true,
 			Error:   nil,
+			RunID:   run.GetID(),
 		}
 	}
 }
+
+// sleep pauses for the given duration, returning early with the context's error
+// if ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// pollingClient returns a client for a GitHub API that never has the run
+// being looked for, and whose runs never finish
+func pollingClient(t *testing.T) *github.Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/org/repo/actions/workflows/audit.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "workflow_runs": [{"id": 1, "name": "other run", "status": "queued"}]}`)
+	})
+	mux.HandleFunc("/repos/org/repo/actions/runs/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "name": "run", "status": "in_progress"}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client
+}
+
+// pollingOptions polls for long enough that only ctx can end a test promptly
+var pollingOptions = Options{
+	SecondsBetweenPolls: 60,
+	MaxAttempts:         10,
+	Logger:              hclog.NewNullLogger(),
+	WorkflowFileName:    "audit.yml",
+	GitHubOwner:         "org",
+	GitHubRepo:          "repo",
+}
+
+func TestFindRunContext(t *testing.T) {
+	client := pollingClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := FindRun(ctx, client, pollingOptions, "run")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Polling stops once the deadline passes")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// If the deadline passes during an API call, the error is wrapped
+	_, err = FindRun(ctx, client, pollingOptions, "run")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitRunFinishedContext(t *testing.T) {
+	client := pollingClient(t)
+	run := github.WorkflowRun{ID: github.Int64(1), Name: github.String("run"), Status: github.String("queued")}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	err := WaitRunFinished(ctx, client, pollingOptions, run)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	run.Status = github.String("completed")
+	assert.Nil(t, WaitRunFinished(ctx, client, pollingOptions, run), "Completed runs aren't polled")
+}
+
+func TestSleep(t *testing.T) {
+	assert.Nil(t, sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := sleep(ctx, time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dispatch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Job processes a single repo, returning an error if it could not be processed
+type Job func(ctx context.Context, repo string) error
+
+// Pool runs job for every repo using the given number of concurrent workers,
+// in the same way as a pool of Workers, and returns the outcome for each repo
+// in the order they finished. Once ctx is done, any remaining repos fail with
+// the context's error without job being run.
+func Pool(ctx context.Context, workers int, logger hclog.Logger, repos []string, job Job) []Result {
+	jobs := make(chan string, len(repos))
+	results := make(chan Result, len(repos))
+
+	for w := 1; w <= max(workers, 1); w++ {
+		go func(id int) {
+			for repo := range jobs {
+				if err := ctx.Err(); err != nil {
+					results <- Result{Name: repo, Success: false, Error: err}
+					continue
+				}
+
+				logger.Info(fmt.Sprint("worker ", id, " started job ", repo))
+				if err := job(ctx, repo); err != nil {
+					logger.Debug(fmt.Sprintf("Failed job %s: %v", repo, err))
+					results <- Result{Name: repo, Success: false, Error: err}
+					continue
+				}
+				logger.Info(fmt.Sprint("worker ", id, " finished job ", repo))
+				results <- Result{Name: repo, Success: true, Error: nil}
+			}
+		}(w)
+	}
+
+	for _, repo := range repos {
+		jobs <- repo
+	}
+	close(jobs)
+
+	out := make([]Result, 0, len(repos))
+	for range repos {
+		out = append(out, <-results)
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	var ran atomic.Int32
+	job := func(ctx context.Context, repo string) error {
+		ran.Add(1)
+		if repo == "broken" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	results := Pool(context.Background(), 3, hclog.NewNullLogger(), []string{"a", "broken", "b", "c"}, job)
+	assert.Equal(t, int32(4), ran.Load())
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	assert.Equal(t, []Result{
+		{Name: "a", Success: true},
+		{Name: "b", Success: true},
+		{Name: "broken", Success: false, Error: errors.New("boom")},
+		{Name: "c", Success: true},
+	}, results)
+}
+
+func TestPoolCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var ran atomic.Int32
+	job := func(ctx context.Context, repo string) error {
+		ran.Add(1)
+		return nil
+	}
+
+	results := Pool(ctx, 2, hclog.NewNullLogger(), []string{"a", "b"}, job)
+	assert.Equal(t, int32(0), ran.Load())
+	assert.Len(t, results, 2)
+	for _, r := range results {
+		assert.False(t, r.Success)
+		assert.ErrorIs(t, r.Error, context.Canceled)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/config"
+)
+
+// RepoConfig fetches and parses the .copywrite.hcl config at the root of the
+// default branch of owner/repo. If the repo has no config, nil is returned.
+func RepoConfig(ctx context.Context, client *github.Client, owner string, repo string) (*config.Config, error) {
+	file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, ".copywrite.hcl", nil)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.New(".copywrite.hcl is not a file")
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.LoadConfigBytes([]byte(content)); err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", owner, repo, err)
+	}
+	return c, nil
+}
+
+// OptOutReason returns why a repo with the given config should not be audited,
+// or "" if it should be. Repos opt out explicitly with project.dispatch_opt_out,
+// and forks and mirrors that declare a project.upstream are skipped so that
+// changes are made upstream instead.
+func OptOutReason(c *config.Config) string {
+	switch {
+	case c == nil:
+		return ""
+	case c.Project.DispatchOptOut:
+		return "opted out with dispatch_opt_out"
+	case c.Project.Upstream != "":
+		return fmt.Sprintf("declares upstream %q", c.Project.Upstream)
+	}
+	return ""
+}
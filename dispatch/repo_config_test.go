@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package dispatch
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/hashicorp/copywrite/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoConfig(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/org/fork/contents/.copywrite.hcl", func(w http.ResponseWriter, r *http.Request) {
+		content := base64.StdEncoding.EncodeToString([]byte("project {\n  upstream = \"other/repo\"\n}\n"))
+		fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, content)
+	})
+	mux.HandleFunc("/repos/org/plain/contents/.copywrite.hcl", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	c, err := RepoConfig(context.Background(), client, "org", "fork")
+	assert.Nil(t, err)
+	assert.Equal(t, "other/repo", c.Project.Upstream)
+
+	c, err = RepoConfig(context.Background(), client, "org", "plain")
+	assert.Nil(t, err)
+	assert.Nil(t, c)
+}
+
+func TestOptOutReason(t *testing.T) {
+	tests := []struct {
+		description    string
+		input          *config.Config
+		expectedOutput string
+	}{
+		{
+			description:    "Repos without a config are audited",
+			input:          nil,
+			expectedOutput: "",
+		},
+		{
+			description:    "Repos with a plain config are audited",
+			input:          &config.Config{},
+			expectedOutput: "",
+		},
+		{
+			description:    "Repos can opt out explicitly",
+			input:          &config.Config{Project: config.Project{DispatchOptOut: true}},
+			expectedOutput: "opted out with dispatch_opt_out",
+		},
+		{
+			description:    "Forks that declare an upstream are skipped",
+			input:          &config.Config{Project: config.Project{Upstream: "hashicorp/copywrite"}},
+			expectedOutput: `declares upstream "hashicorp/copywrite"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, OptOutReason(tt.input))
+		})
+	}
+}
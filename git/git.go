@@ -0,0 +1,543 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// FileHistory summarizes what git knows about a single file
+type FileHistory struct {
+	// Author of the commit that first introduced the file, formatted as
+	// "Name <email>"
+	OriginalAuthor string
+
+	// Every distinct author who has committed changes to the file
+	Authors []string
+
+	// Timestamp of the most recent commit that touched the file
+	LastModified time.Time
+}
+
+// run is an internal helper that executes git in the given directory and
+// returns stdout. Stderr is folded into the error if the command fails.
+func run(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// History walks the git log of the repo containing dir and returns a map of
+// file paths (relative to dir) to a summary of their history. Files that have
+// never been committed will not be present in the map.
+//
+// A single pass over the log is used rather than one git invocation per file,
+// as the latter is prohibitively slow on large repos.
+func History(dir string) (map[string]FileHistory, error) {
+	// With -z, each commit is emitted as a NUL-prefixed "author\x00timestamp"
+	// header, followed by the NUL-terminated list of files it touched (relative
+	// to dir), which git doesn't quote
+	out, err := run(dir, "log", "-z", "--relative", "--no-renames", "--name-only", "--format=%x00%an <%ae>%x00%at")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHistory(out)
+}
+
+// parseHistory turns the output of the `git log` invocation used by History
+// into a per-file summary. Commits are listed newest first, so the last
+// author seen for a given file is the one who introduced it.
+func parseHistory(out []byte) (map[string]FileHistory, error) {
+	history := map[string]FileHistory{}
+
+	var author string
+	var timestamp time.Time
+
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i < len(fields); i++ {
+		// An empty field starts each commit header, as paths are never empty
+		if fields[i] == "" {
+			if i+2 >= len(fields) {
+				break
+			}
+			unix, err := strconv.ParseInt(fields[i+2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected git log timestamp %q: %w", fields[i+2], err)
+			}
+			author = fields[i+1]
+			timestamp = time.Unix(unix, 0).UTC()
+			i += 2
+
+			// The header is separated from the first file by a newline
+			if i+1 < len(fields) {
+				fields[i+1] = strings.TrimPrefix(fields[i+1], "\n")
+			}
+			continue
+		}
+
+		// File touched by the current commit
+		path := fields[i]
+		h, seen := history[path]
+		if !seen {
+			h.LastModified = timestamp
+		}
+		h.OriginalAuthor = author
+		if !lo.Contains(h.Authors, author) {
+			h.Authors = append(h.Authors, author)
+		}
+		history[path] = h
+	}
+
+	return history, nil
+}
+
+// ChangedSinceRef returns the set of files (relative to dir) touched by any
+// commit reachable from HEAD but not from ref, such as a release tag
+func ChangedSinceRef(dir string, ref string) (map[string]bool, error) {
+	out, err := run(dir, "log", "-z", "--relative", "--no-renames", "--name-only", "--format=", ref+"..HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return parseFileList(out), nil
+}
+
+// ChangedSinceDate returns the set of files (relative to dir) touched by any
+// commit made on or after the given date
+func ChangedSinceDate(dir string, date time.Time) (map[string]bool, error) {
+	out, err := run(dir, "log", "-z", "--relative", "--no-renames", "--name-only", "--format=", "--since="+date.Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	return parseFileList(out), nil
+}
+
+// parseFileList turns NUL-delimited git output (e.g., from `git log -z`) into a
+// set of paths
+func parseFileList(out []byte) map[string]bool {
+	files := map[string]bool{}
+	for _, path := range strings.Split(string(out), "\x00") {
+		if path != "" {
+			files[path] = true
+		}
+	}
+	return files
+}
+
+// BlobHash returns the object ID git assigns to a file with the given contents,
+// which can be compared against the IDs listed by TreeHashes
+func BlobHash(b []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(b))
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FileBlobHash returns the object ID git assigns to the file at path, streaming
+// its contents rather than reading them into memory
+func FileBlobHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", fi.Size())
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// TreeHashes returns a map of every file in the tree of ref (relative to the
+// root of the repo containing dir) to its blob object ID
+func TreeHashes(dir string, ref string) (map[string]string, error) {
+	out, err := run(dir, "ls-tree", "-r", "-z", "--full-tree", ref)
+	if err != nil {
+		return nil, err
+	}
+	return parseTree(out)
+}
+
+// RefHashes returns a map of every file in the tree of ref beneath dir, with
+// paths relative to dir, to its blob object ID
+func RefHashes(dir string, ref string) (map[string]string, error) {
+	out, err := run(dir, "ls-tree", "-r", "-z", ref)
+	if err != nil {
+		return nil, err
+	}
+	return parseTree(out)
+}
+
+// parseTree turns the NUL-delimited output of `git ls-tree -r -z` into a map of
+// file paths to blob object IDs. Entries other than blobs (e.g., submodules)
+// are omitted.
+func parseTree(out []byte) (map[string]string, error) {
+	hashes := map[string]string{}
+	for _, line := range strings.Split(string(out), "\x00") {
+		if line == "" {
+			continue
+		}
+
+		// Each line is formatted as "<mode> <type> <object>\t<path>"
+		meta, path, found := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git ls-tree line: %q", line)
+		}
+		if fields[1] == "blob" {
+			hashes[path] = fields[2]
+		}
+	}
+	return hashes, nil
+}
+
+// FirstCommitDate returns the date of the earliest root commit reachable from
+// HEAD in the repo containing dir, which approximates when the repo was created
+func FirstCommitDate(dir string) (time.Time, error) {
+	out, err := run(dir, "log", "--max-parents=0", "--format=%at", "HEAD")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseEarliestTimestamp(out)
+}
+
+// parseEarliestTimestamp returns the earliest of a newline-delimited list of
+// unix timestamps
+func parseEarliestTimestamp(out []byte) (time.Time, error) {
+	var earliest time.Time
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		unix, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unexpected git log timestamp %q: %w", line, err)
+		}
+		if t := time.Unix(unix, 0).UTC(); earliest.IsZero() || t.Before(earliest) {
+			earliest = t
+		}
+	}
+	if earliest.IsZero() {
+		return time.Time{}, fmt.Errorf("no commits found")
+	}
+	return earliest, nil
+}
+
+// Prefix returns the path of dir relative to the top level of the repo
+// containing it, with forward slashes and a trailing slash (e.g., "sub/dir/"),
+// or "" if dir is the top level
+func Prefix(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// TopLevel returns the absolute path of the top level of the repo containing
+// dir, or an error if dir isn't inside a git repo
+func TopLevel(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HeadCommit returns the abbreviated hash of the commit checked out in the repo
+// containing dir
+func HeadCommit(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Version returns the version of the git executable, e.g. "2.39.2"
+func Version() (string, error) {
+	out, err := run(".", "--version")
+	if err != nil {
+		return "", err
+	}
+	return parseVersion(out)
+}
+
+// parseVersion extracts the version number from the output of `git --version`,
+// such as "git version 2.39.2 (Apple Git-143)"
+func parseVersion(out []byte) (string, error) {
+	fields := strings.Fields(string(out))
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return "", fmt.Errorf("unexpected git version output: %q", strings.TrimSpace(string(out)))
+	}
+	return fields[2], nil
+}
+
+// Remote is a git remote and the URL it fetches from
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// Remotes returns every remote configured for the repo containing dir, in the
+// order git lists them
+func Remotes(dir string) ([]Remote, error) {
+	out, err := run(dir, "remote", "-v")
+	if err != nil {
+		return nil, err
+	}
+	return parseRemotes(out), nil
+}
+
+// parseRemotes parses the output of `git remote -v`, where each remote is listed
+// once for fetching and once for pushing, such as:
+//
+//	origin	git@github.com:hashicorp/copywrite.git (fetch)
+//	origin	git@github.com:hashicorp/copywrite.git (push)
+func parseRemotes(out []byte) []Remote {
+	remotes := []Remote{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[2] != "(fetch)" {
+			continue
+		}
+		remotes = append(remotes, Remote{Name: fields[0], URL: fields[1]})
+	}
+	return remotes
+}
+
+// BlobReader reads the contents of blobs from the object database of a repo
+// using a single long-running git process, which is much faster than running
+// git once per blob. It is safe for concurrent use.
+type BlobReader struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+// NewBlobReader starts a BlobReader for the repo containing dir. It must be
+// closed once no longer needed.
+func NewBlobReader(dir string) (*BlobReader, error) {
+	cmd := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	return &BlobReader{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+// Read returns the contents of the blob with the given object ID
+func (r *BlobReader) Read(oid string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := fmt.Fprintln(r.stdin, oid); err != nil {
+		return nil, err
+	}
+
+	// Each object is preceded by a header of "<oid> <type> <size>", or
+	// "<oid> missing" if it doesn't exist, and followed by a newline
+	header, err := r.stdout.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unable to read git object %s: %s", oid, strings.TrimSpace(header))
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected git cat-file header: %q", header)
+	}
+
+	b := make([]byte, size+1)
+	if _, err := io.ReadFull(r.stdout, b); err != nil {
+		return nil, err
+	}
+	return b[:size], nil
+}
+
+// Close stops the underlying git process
+func (r *BlobReader) Close() error {
+	r.stdin.Close()
+	return r.cmd.Wait()
+}
+
+// Submodules returns the path (relative to dir, with forward slashes) of every
+// checked-out git submodule beneath dir, sorted by path. A submodule is found
+// by its own .git file or folder, so nested repos that were cloned by hand are
+// included as well. Submodules nested within other submodules are not
+// returned, as they are covered by their parent.
+func Submodules(dir string) ([]string, error) {
+	submodules := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == dir {
+			return nil
+		}
+		if d.Name() == ".git" || d.Name() == "node_modules" {
+			return filepath.SkipDir
+		}
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			submodules = append(submodules, filepath.ToSlash(rel))
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return submodules, nil
+}
+
+// Clone makes a shallow clone of the default branch of the repo at url into
+// dir, which must not exist yet
+func Clone(url string, dir string) error {
+	_, err := run(".", "clone", "--quiet", "--depth", "1", url, dir)
+	return err
+}
+
+// HasChanges reports whether the working tree of the repo containing dir has
+// any uncommitted changes, including untracked files
+func HasChanges(dir string) (bool, error) {
+	out, err := run(dir, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(out)) > 0, nil
+}
+
+// CommitAll commits every change in the working tree of the repo containing
+// dir to a new branch, as the given author (formatted as "Name <email>")
+func CommitAll(dir string, branch string, message string, author string) error {
+	if _, err := run(dir, "checkout", "--quiet", "-b", branch); err != nil {
+		return err
+	}
+	if _, err := run(dir, "add", "--all"); err != nil {
+		return err
+	}
+	name, email, _ := strings.Cut(strings.TrimSuffix(author, ">"), " <")
+	_, err := run(dir, "-c", "user.name="+name, "-c", "user.email="+email, "commit", "--quiet", "-m", message)
+	return err
+}
+
+// ChangedFiles returns the paths of every file with uncommitted changes in the
+// working tree of the repo containing dir, including untracked files, relative
+// to the top level of the repo and in sorted order
+func ChangedFiles(dir string) ([]string, error) {
+	out, err := run(dir, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	return parseStatus(out), nil
+}
+
+// parseStatus extracts the paths from the NUL-delimited output of
+// `git status --porcelain -z`. Renamed and copied entries are followed by
+// their original path, which is skipped.
+func parseStatus(out []byte) []string {
+	paths := []string{}
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// CommitFiles commits the given files (relative to the top level of the repo)
+// from the working tree of the repo containing dir to a new branch started at
+// start, as the given author (formatted as "Name <email>"). Other uncommitted
+// changes are left in the working tree, so that they can be committed to
+// branches of their own.
+func CommitFiles(dir string, start string, branch string, message string, author string, files []string) error {
+	if _, err := run(dir, "checkout", "--quiet", "-b", branch, start); err != nil {
+		return err
+	}
+	// Files are added in batches to stay well under the OS's argument limit
+	for _, batch := range lo.Chunk(files, 100) {
+		if _, err := run(dir, append([]string{"add", "--all", "--"}, batch...)...); err != nil {
+			return err
+		}
+	}
+	name, email, _ := strings.Cut(strings.TrimSuffix(author, ">"), " <")
+	_, err := run(dir, "-c", "user.name="+name, "-c", "user.email="+email, "commit", "--quiet", "-m", message)
+	return err
+}
+
+// Push pushes branch from the repo containing dir to the repo at url,
+// authenticating to GitHub with token if it is set. The token is passed to git
+// through its environment, so it never appears in arguments or errors.
+func Push(dir string, url string, branch string, token string) error {
+	args := []string{"-C", dir, "push", "--quiet", url, "HEAD:refs/heads/" + branch}
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if token != "" {
+		basic := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+basic,
+		)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args[2:], " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,437 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseHistory(t *testing.T) {
+	tests := []struct {
+		description    string
+		input          string
+		expectedOutput map[string]FileHistory
+	}{
+		{
+			description:    "Empty log results in empty history",
+			input:          "",
+			expectedOutput: map[string]FileHistory{},
+		},
+		{
+			description: "Single commit",
+			input:       "\x00Alice <alice@example.com>\x001262304000\x00\nmain.go\x00",
+			expectedOutput: map[string]FileHistory{
+				"main.go": {
+					OriginalAuthor: "Alice <alice@example.com>",
+					Authors:        []string{"Alice <alice@example.com>"},
+					LastModified:   time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			description: "Newest commit sets modification time, oldest sets original author",
+			input: "\x00Bob <bob@example.com>\x001577836800\x00\nmain.go\x00" +
+				"\x00Alice <alice@example.com>\x001262304000\x00\nmain.go\x00other.go\x00" +
+				"\x00Bob <bob@example.com>\x001230768000\x00\nmain.go\x00",
+			expectedOutput: map[string]FileHistory{
+				"main.go": {
+					OriginalAuthor: "Bob <bob@example.com>",
+					Authors:        []string{"Bob <bob@example.com>", "Alice <alice@example.com>"},
+					LastModified:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				"other.go": {
+					OriginalAuthor: "Alice <alice@example.com>",
+					Authors:        []string{"Alice <alice@example.com>"},
+					LastModified:   time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			description: "Commits without files and unusual paths",
+			input: "\x00Bob <bob@example.com>\x001577836800\x00" +
+				"\x00Alice <alice@example.com>\x001262304000\x00\nnew\nline.go\x00héllo wörld.go\x00",
+			expectedOutput: map[string]FileHistory{
+				"new\nline.go": {
+					OriginalAuthor: "Alice <alice@example.com>",
+					Authors:        []string{"Alice <alice@example.com>"},
+					LastModified:   time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				"héllo wörld.go": {
+					OriginalAuthor: "Alice <alice@example.com>",
+					Authors:        []string{"Alice <alice@example.com>"},
+					LastModified:   time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, err := parseHistory([]byte(tt.input))
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func Test_parseFileList(t *testing.T) {
+	tests := []struct {
+		description    string
+		input          string
+		expectedOutput map[string]bool
+	}{
+		{
+			description:    "Empty output results in empty set",
+			input:          "",
+			expectedOutput: map[string]bool{},
+		},
+		{
+			description: "Duplicates are collapsed",
+			input:       "main.go\x00cmd/root.go\x00main.go\x00",
+			expectedOutput: map[string]bool{
+				"main.go":     true,
+				"cmd/root.go": true,
+			},
+		},
+		{
+			description: "Paths are taken verbatim",
+			input:       "new\nline.go\x00héllo wörld.go\x00 space.go\x00",
+			expectedOutput: map[string]bool{
+				"new\nline.go":   true,
+				"héllo wörld.go": true,
+				" space.go":      true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, parseFileList([]byte(tt.input)))
+		})
+	}
+}
+
+func Test_History(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := run(dir, "init", "-q"); err != nil {
+		t.Skipf("git is unavailable: %v", err)
+	}
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "héllo wörld.go"), []byte("package main\n"), 0644))
+	_, err := run(dir, "add", ".")
+	assert.Nil(t, err)
+	_, err = run(dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init")
+	assert.Nil(t, err)
+
+	history, err := History(dir)
+	assert.Nil(t, err)
+	assert.Contains(t, history, "héllo wörld.go", "Non-ASCII paths aren't quoted")
+	assert.Equal(t, "test <test@example.com>", history["héllo wörld.go"].OriginalAuthor)
+}
+
+func Test_ChangedSinceRef(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := run(dir, "init", "-q"); err != nil {
+		t.Skipf("git is unavailable: %v", err)
+	}
+	commit := func(name string) {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte("package main\n"), 0644))
+		_, err := run(dir, "add", ".")
+		assert.Nil(t, err)
+		_, err = run(dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", name)
+		assert.Nil(t, err)
+	}
+	commit("old.go")
+	_, err := run(dir, "tag", "v1.0.0")
+	assert.Nil(t, err)
+	commit("héllo wörld.go")
+
+	changed, err := ChangedSinceRef(dir, "v1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"héllo wörld.go": true}, changed, "Non-ASCII paths aren't quoted")
+
+	changed, err = ChangedSinceDate(dir, time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"old.go": true, "héllo wörld.go": true}, changed)
+}
+
+func Test_BlobHash(t *testing.T) {
+	// Known values from `git hash-object`
+	assert.Equal(t, "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", BlobHash([]byte("")))
+	assert.Equal(t, "ce013625030ba8dba906f756967f9e9ca394464a", BlobHash([]byte("hello\n")))
+}
+
+func Test_FileBlobHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("hello\n"), 0644))
+
+	hash, err := FileBlobHash(path)
+	assert.Nil(t, err)
+	assert.Equal(t, BlobHash([]byte("hello\n")), hash)
+
+	_, err = FileBlobHash(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.NotNil(t, err)
+}
+
+func Test_parseTree(t *testing.T) {
+	tests := []struct {
+		description    string
+		input          string
+		expectedOutput map[string]string
+		expectErr      bool
+	}{
+		{
+			description:    "Empty output results in empty map",
+			input:          "",
+			expectedOutput: map[string]string{},
+		},
+		{
+			description: "Blobs are mapped and submodules are omitted",
+			input: "100644 blob ce013625030ba8dba906f756967f9e9ca394464a\tmain.go\x00" +
+				"100755 blob e69de29bb2d1d6434b8b29ae775ad8c2e48c5391\tscripts/my script.sh\x00" +
+				"160000 commit 0123456789abcdef0123456789abcdef01234567\tthird_party/lib\x00",
+			expectedOutput: map[string]string{
+				"main.go":              "ce013625030ba8dba906f756967f9e9ca394464a",
+				"scripts/my script.sh": "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391",
+			},
+		},
+		{
+			description: "Malformed lines are rejected",
+			input:       "not a tree\x00",
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, err := parseTree([]byte(tt.input))
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func Test_parseEarliestTimestamp(t *testing.T) {
+	tests := []struct {
+		description    string
+		input          string
+		expectedOutput time.Time
+		expectErr      bool
+	}{
+		{
+			description:    "Single root commit",
+			input:          "1262304000\n",
+			expectedOutput: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			description:    "Earliest of several root commits",
+			input:          "1577836800\n1262304000\n",
+			expectedOutput: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			description: "No commits",
+			input:       "",
+			expectErr:   true,
+		},
+		{
+			description: "Malformed timestamp",
+			input:       "yesterday\n",
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, err := parseEarliestTimestamp([]byte(tt.input))
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func Test_parseVersion(t *testing.T) {
+	tests := []struct {
+		description    string
+		input          string
+		expectedOutput string
+		expectErr      bool
+	}{
+		{
+			description:    "Plain version",
+			input:          "git version 2.43.0\n",
+			expectedOutput: "2.43.0",
+		},
+		{
+			description:    "Vendor suffix",
+			input:          "git version 2.39.2 (Apple Git-143)\n",
+			expectedOutput: "2.39.2",
+		},
+		{
+			description: "Unexpected output",
+			input:       "command not found\n",
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, err := parseVersion([]byte(tt.input))
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func Test_parseRemotes(t *testing.T) {
+	input := `origin	git@github.com:hashicorp/copywrite.git (fetch)
+origin	git@github.com:hashicorp/copywrite.git (push)
+upstream	https://github.com/hashicorp/copywrite (fetch)
+upstream	no_push (push)
+`
+	expected := []Remote{
+		{Name: "origin", URL: "git@github.com:hashicorp/copywrite.git"},
+		{Name: "upstream", URL: "https://github.com/hashicorp/copywrite"},
+	}
+	assert.Equal(t, expected, parseRemotes([]byte(input)))
+	assert.Equal(t, []Remote{}, parseRemotes([]byte("")), "Repos without remotes have none")
+}
+
+func Test_BlobReader(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := run(dir, "init", "-q"); err != nil {
+		t.Skipf("git is unavailable: %v", err)
+	}
+	for name, content := range map[string]string{"a.go": "package a\n", "b/c.txt": ""} {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	_, err := run(dir, "add", ".")
+	assert.Nil(t, err)
+	_, err = run(dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init")
+	assert.Nil(t, err)
+
+	hashes, err := RefHashes(dir, "HEAD")
+	assert.Nil(t, err)
+	assert.Len(t, hashes, 2)
+
+	r, err := NewBlobReader(dir)
+	assert.Nil(t, err)
+	defer r.Close()
+
+	b, err := r.Read(hashes["a.go"])
+	assert.Nil(t, err)
+	assert.Equal(t, "package a\n", string(b))
+
+	b, err = r.Read(hashes["b/c.txt"])
+	assert.Nil(t, err)
+	assert.Equal(t, "", string(b), "Empty blobs are read")
+
+	_, err = r.Read("0000000000000000000000000000000000000000")
+	assert.NotNil(t, err, "Missing objects return an error")
+}
+
+func Test_CommitAll(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := run(dir, "init", "-q"); err != nil {
+		t.Skipf("git is unavailable: %v", err)
+	}
+
+	changed, err := HasChanges(dir)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+	changed, err = HasChanges(dir)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	assert.Nil(t, CommitAll(dir, "copywrite/abc123", "Add headers", "Test User <test@example.com>"))
+	changed, err = HasChanges(dir)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	out, err := run(dir, "log", "-1", "--format=%an <%ae> %s%n%D")
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), "Test User <test@example.com> Add headers")
+	assert.Contains(t, string(out), "copywrite/abc123")
+}
+
+func Test_Submodules(t *testing.T) {
+	dir := t.TempDir()
+	dirs := []string{
+		".git",
+		"third_party/lib/.git",
+		"third_party/lib/nested/.git",
+		"tools/proto/.git",
+		"src",
+		"node_modules/dep/.git",
+	}
+	for _, d := range dirs {
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, d), 0755))
+	}
+	// Submodules normally have a .git file pointing into the parent repo
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "vendor/mod"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "vendor/mod/.git"), []byte("gitdir: ../../.git/modules/mod\n"), 0644))
+
+	actual, err := Submodules(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"third_party/lib", "tools/proto", "vendor/mod"}, actual)
+}
+
+func Test_parseStatus(t *testing.T) {
+	out := []byte(" M b.go\x00?? a/new.go\x00R  renamed.go\x00old.go\x00 D gone.go\x00")
+	assert.Equal(t, []string{"a/new.go", "b.go", "gone.go", "renamed.go"}, parseStatus(out))
+	assert.Equal(t, []string{}, parseStatus(nil))
+}
+
+func Test_CommitFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := run(dir, "init", "-q"); err != nil {
+		t.Skipf("git is unavailable: %v", err)
+	}
+	author := "Test User <test@example.com>"
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\n"), 0644))
+	assert.Nil(t, CommitAll(dir, "main", "Initial commit", author))
+	start, err := HeadCommit(dir)
+	assert.Nil(t, err)
+
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte("package main\n"), 0644))
+	}
+	files, err := ChangedFiles(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.go", "b.go", "c.go"}, files)
+
+	// Each branch only has its own files, and starts at the same commit
+	assert.Nil(t, CommitFiles(dir, start, "part-1", "Part 1", author, []string{"a.go", "b.go"}))
+	assert.Nil(t, CommitFiles(dir, start, "part-2", "Part 2", author, []string{"c.go"}))
+
+	out, err := run(dir, "diff", "--name-only", start, "part-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "a.go\nb.go\n", string(out))
+	out, err = run(dir, "diff", "--name-only", start, "part-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "c.go\n", string(out))
+
+	changed, err := HasChanges(dir)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
@@ -44,6 +44,19 @@ func (c *GHClient) Raw() *github.Client {
 // getGHAppConfig looks for Github App configurations and sets them appropriately.
 // if configuration is not found, return false
 func getGHAppConfig() (cc GHClientConfig, exists bool) {
+	cc = readGHAppConfig()
+	if cc.appPEM != "" && cc.appID != 0 && cc.instID != 0 {
+		return cc, true
+	}
+
+	// Nothing worked
+	logger.Debug("Problem with retrieving Github App identifiers, skipping GHApp configuration")
+	return GHClientConfig{}, false
+}
+
+// readGHAppConfig reads whichever GitHub App identifiers are set in a .env file
+// or the environment, even if some are missing
+func readGHAppConfig() (cc GHClientConfig) {
 	k := koanf.New(".")
 	var err error
 
@@ -63,14 +76,7 @@ func getGHAppConfig() (cc GHClientConfig, exists bool) {
 	cc.appID = k.Int64("APP_ID")
 	cc.instID = k.Int64("INSTALLATION_ID")
 	cc.appPEM = strings.ReplaceAll(k.String("APP_PEM"), "\\n", "\n")
-
-	if cc.appPEM != "" && cc.appID != 0 && cc.instID != 0 {
-		return cc, true
-	}
-
-	// Nothing worked
-	logger.Debug("Problem with retrieving Github App identifiers, skipping GHApp configuration")
-	return GHClientConfig{}, false
+	return cc
 }
 
 // getGitHubCLIConfig attempts to find a GitHub CLI (gh) configuration in the
@@ -113,12 +119,62 @@ func getGitHubCLIConfig() (token string, exists bool) {
 	return "", false
 }
 
+// AuthMethod identifies the credentials used by NewGHClient
+type AuthMethod string
+
+// Supported auth methods, in order of precedence
+const (
+	AuthMethodApp             AuthMethod = "GitHub App (APP_ID, INSTALLATION_ID, and APP_PEM)"
+	AuthMethodToken           AuthMethod = "GITHUB_TOKEN environment variable"
+	AuthMethodCLI             AuthMethod = "GitHub CLI (gh) config"
+	AuthMethodUnauthenticated AuthMethod = "unauthenticated"
+)
+
+// DetectAuthMethod returns the credentials NewGHClient will use, following the
+// same order of precedence
+func DetectAuthMethod() AuthMethod {
+	if _, exists := getGHAppConfig(); exists {
+		return AuthMethodApp
+	}
+	if _, exists := os.LookupEnv("GITHUB_TOKEN"); exists {
+		return AuthMethodToken
+	}
+	if _, exists := getGitHubCLIConfig(); exists {
+		return AuthMethodCLI
+	}
+	return AuthMethodUnauthenticated
+}
+
+// Credentials reports which GitHub credentials are present, without exposing
+// their values, so that they can be safely included in bug reports
+type Credentials struct {
+	AppID          bool `json:"app_id"`
+	InstallationID bool `json:"installation_id"`
+	AppPEM         bool `json:"app_pem"`
+	GitHubToken    bool `json:"github_token"`
+	GitHubCLIToken bool `json:"gh_cli_token"`
+}
+
+// DetectCredentials returns which GitHub credentials are present
+func DetectCredentials() Credentials {
+	cc := readGHAppConfig()
+	_, token := os.LookupEnv("GITHUB_TOKEN")
+	_, cli := getGitHubCLIConfig()
+
+	return Credentials{
+		AppID:          cc.appID != 0,
+		InstallationID: cc.instID != 0,
+		AppPEM:         cc.appPEM != "",
+		GitHubToken:    token,
+		GitHubCLIToken: cli,
+	}
+}
+
 // NewGHClient uses the copyright Github App for client requests
 func NewGHClient() *GHClient {
 
-	// Shared transport to reuse TCP connections and default to background context
-	tr := http.DefaultTransport
-	ctx := context.Background()
+	// Shared transport to reuse TCP connections, retrying transient failures
+	tr := newRetryTransport(http.DefaultTransport)
 
 	// First, let's see if we can use GitHub App creds
 	// This serves the use case of running as `hashicorp-copywrite[bot]` for
@@ -143,7 +199,7 @@ func NewGHClient() *GHClient {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: token},
 		)
-		tc := oauth2.NewClient(ctx, ts)
+		tc := &http.Client{Transport: &oauth2.Transport{Source: ts, Base: tr}}
 		return &GHClient{gh: github.NewClient(tc)}
 	}
 
@@ -155,12 +211,36 @@ func NewGHClient() *GHClient {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: token},
 		)
-		tc := oauth2.NewClient(ctx, ts)
+		tc := &http.Client{Transport: &oauth2.Transport{Source: ts, Base: tr}}
 		return &GHClient{gh: github.NewClient(tc)}
 	}
 
 	// If all else fails, fallback to an unauthenticated client
 	// This only gives access to public information
 	logger.Info("No Github auth credentials found, using unauthenticated GH Client")
-	return &GHClient{gh: github.NewClient(nil)}
+	return &GHClient{gh: github.NewClient(&http.Client{Transport: tr})}
+}
+
+// Token returns a token for authenticating git operations (such as pushing a
+// branch) as the same identity NewGHClient uses, following the same order of
+// precedence. If no credentials are found, exists is false.
+func Token(ctx context.Context) (token string, exists bool, err error) {
+	if cc, ok := getGHAppConfig(); ok {
+		itr, err := ghinstallation.New(http.DefaultTransport, cc.appID, cc.instID, []byte(cc.appPEM))
+		if err != nil {
+			return "", false, err
+		}
+		token, err = itr.Token(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		return token, true, nil
+	}
+	if token, ok := os.LookupEnv("GITHUB_TOKEN"); ok {
+		return token, true, nil
+	}
+	if token, ok := getGitHubCLIConfig(); ok {
+		return token, true, nil
+	}
+	return "", false, nil
 }
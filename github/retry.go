@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxAttempts is the number of times a GitHub API request is attempted
+// before giving up, unless overridden by the COPYWRITE_GITHUB_MAX_ATTEMPTS
+// environment variable
+const DefaultMaxAttempts = 5
+
+// Bounds on the delay between attempts, which otherwise doubles each time
+const (
+	minRetryDelay = 1 * time.Second
+	maxRetryDelay = 60 * time.Second
+)
+
+// retryTransport is an http.RoundTripper that retries GitHub API requests which
+// fail for transient reasons: network errors, server errors, and secondary rate
+// limits. It backs off exponentially between attempts, honoring any
+// Retry-After header GitHub sends. Requests that aren't idempotent, such as
+// those that create pull requests, may have taken effect despite a network or
+// server error, so they are only retried after secondary rate limits, which
+// GitHub rejects without processing.
+type retryTransport struct {
+	base        http.RoundTripper
+	maxAttempts int
+
+	// sleep waits for d, returning early with an error if the request is
+	// cancelled. It is overridden in tests.
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+// newRetryTransport wraps base so that every request made through it is retried
+func newRetryTransport(base http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		base:        base,
+		maxAttempts: maxAttempts(),
+		sleep:       sleepContext,
+	}
+}
+
+// maxAttempts returns the number of attempts configured via the environment
+func maxAttempts() int {
+	v, ok := os.LookupEnv("COPYWRITE_GITHUB_MAX_ATTEMPTS")
+	if !ok {
+		return DefaultMaxAttempts
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		logger.Warn(fmt.Sprintf("Ignoring invalid COPYWRITE_GITHUB_MAX_ATTEMPTS %q: must be a positive integer", v))
+		return DefaultMaxAttempts
+	}
+	return n
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		// Bodies are consumed by each attempt, so they must be replayable
+		if attempt > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+
+		retry, delay := shouldRetry(req, resp, err, attempt)
+		if !retry || attempt >= t.maxAttempts {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		reason := fmt.Sprint(err)
+		if resp != nil {
+			reason = resp.Status
+			// Drain the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		logger.Debug(fmt.Sprintf("Retrying %s %s in %s after attempt %d of %d failed: %s", req.Method, req.URL.Path, delay, attempt, t.maxAttempts, reason))
+
+		if err := t.sleep(req, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether a request should be attempted again given the
+// outcome of its latest attempt, and if so, how long to wait first
+func shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	backoff := backoffDelay(attempt)
+	idempotent := isIdempotent(req.Method)
+
+	if err != nil {
+		// Requests that were deliberately cancelled (e.g., by --timeout) are done
+		if req.Context().Err() != nil {
+			return false, 0
+		}
+		return idempotent, backoff
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusForbidden:
+		// GitHub signals secondary rate limits with a Retry-After header, which
+		// must be honored, although waits longer than maxRetryDelay aren't worth
+		// stalling for. A 403 without one is a permissions problem, and an
+		// exhausted primary rate limit won't reset soon enough to be worth waiting
+		// for, so neither is retried.
+		if d, ok := retryAfter(resp); ok {
+			return d <= maxRetryDelay, d
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("X-RateLimit-Remaining") != "0" && idempotent {
+			return true, backoff
+		}
+		return false, 0
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !idempotent {
+			return false, 0
+		}
+		if d, ok := retryAfter(resp); ok {
+			return d <= maxRetryDelay, d
+		}
+		return true, backoff
+	}
+	return false, 0
+}
+
+// isIdempotent reports whether repeating a request with method has the same
+// effect as making it once, so that it is safe to retry after a failure that
+// may have happened after the server acted on it
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header of resp, which may be a number of
+// seconds or an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// backoffDelay returns how long to wait after the given attempt when the server
+// didn't say: doubling each time, with up to 50% jitter so that concurrent
+// workers don't retry in lockstep
+func backoffDelay(attempt int) time.Duration {
+	d := maxRetryDelay
+	if attempt < 7 {
+		d = min(minRetryDelay<<(attempt-1), maxRetryDelay)
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// sleepContext waits for d, or until the request is cancelled
+func sleepContext(req *http.Request, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-t.C:
+		return nil
+	}
+}
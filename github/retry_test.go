@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_retryTransport(t *testing.T) {
+	tests := []struct {
+		description      string
+		method           string
+		responses        []int
+		header           http.Header
+		expectedStatus   int
+		expectedAttempts int
+		expectedDelays   []time.Duration
+	}{
+		{
+			description:      "Successful requests are not retried",
+			responses:        []int{200},
+			expectedStatus:   200,
+			expectedAttempts: 1,
+		},
+		{
+			description:      "Server errors are retried until they succeed",
+			responses:        []int{502, 503, 200},
+			expectedStatus:   200,
+			expectedAttempts: 3,
+		},
+		{
+			description:      "Attempts are capped",
+			responses:        []int{500, 500, 500, 500},
+			expectedStatus:   500,
+			expectedAttempts: 3,
+		},
+		{
+			description:      "Client errors are not retried",
+			responses:        []int{404, 200},
+			expectedStatus:   404,
+			expectedAttempts: 1,
+		},
+		{
+			description:      "Forbidden without Retry-After is not retried",
+			responses:        []int{403, 200},
+			expectedStatus:   403,
+			expectedAttempts: 1,
+		},
+		{
+			description:      "Secondary rate limits honor Retry-After",
+			responses:        []int{403, 200},
+			header:           http.Header{"Retry-After": []string{"7"}},
+			expectedStatus:   200,
+			expectedAttempts: 2,
+			expectedDelays:   []time.Duration{7 * time.Second},
+		},
+		{
+			description:      "Retry-After longer than the maximum delay is not waited for",
+			responses:        []int{403, 200},
+			header:           http.Header{"Retry-After": []string{"3600"}},
+			expectedStatus:   403,
+			expectedAttempts: 1,
+		},
+		{
+			description:      "Server errors are not retried for requests that aren't idempotent",
+			method:           "POST",
+			responses:        []int{502, 200},
+			expectedStatus:   502,
+			expectedAttempts: 1,
+		},
+		{
+			description:      "Secondary rate limits are retried for requests that aren't idempotent",
+			method:           "POST",
+			responses:        []int{403, 200},
+			header:           http.Header{"Retry-After": []string{"7"}},
+			expectedStatus:   200,
+			expectedAttempts: 2,
+			expectedDelays:   []time.Duration{7 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body := make([]byte, r.ContentLength)
+				_, _ = r.Body.Read(body)
+				assert.Equal(t, "payload", string(body), "Request bodies are replayed on every attempt")
+
+				status := tt.responses[attempts]
+				attempts++
+				if status != 200 {
+					for k, v := range tt.header {
+						w.Header()[k] = v
+					}
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			delays := []time.Duration{}
+			rt := &retryTransport{
+				base:        http.DefaultTransport,
+				maxAttempts: 3,
+				sleep: func(req *http.Request, d time.Duration) error {
+					delays = append(delays, d)
+					return nil
+				},
+			}
+
+			method := tt.method
+			if method == "" {
+				method = "PUT"
+			}
+			req, err := http.NewRequest(method, server.URL, strings.NewReader("payload"))
+			assert.Nil(t, err)
+			resp, err := rt.RoundTrip(req)
+			assert.Nil(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedAttempts, attempts)
+			assert.Len(t, delays, tt.expectedAttempts-1)
+			if tt.expectedDelays != nil {
+				assert.Equal(t, tt.expectedDelays, delays)
+			}
+		})
+	}
+}
+
+func Test_backoffDelay(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := backoffDelay(attempt)
+		assert.GreaterOrEqual(t, d, minRetryDelay/2)
+		assert.LessOrEqual(t, d, maxRetryDelay)
+	}
+	assert.LessOrEqual(t, backoffDelay(1), minRetryDelay)
+	assert.GreaterOrEqual(t, backoffDelay(10), maxRetryDelay/2)
+}
+
+func Test_retryTransportNetworkErrors(t *testing.T) {
+	for _, method := range []string{"GET", "POST"} {
+		t.Run(method, func(t *testing.T) {
+			attempts := 0
+			rt := &retryTransport{
+				base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return nil, errors.New("connection reset by peer")
+				}),
+				maxAttempts: 3,
+				sleep:       func(req *http.Request, d time.Duration) error { return nil },
+			}
+
+			req, err := http.NewRequest(method, "https://api.github.com/", nil)
+			assert.Nil(t, err)
+			_, err = rt.RoundTrip(req)
+			assert.NotNil(t, err)
+			if method == "GET" {
+				assert.Equal(t, 3, attempts)
+			} else {
+				assert.Equal(t, 1, attempts, "Requests that aren't idempotent may have taken effect")
+			}
+		})
+	}
+}
+
+// roundTripFunc adapts a function to an http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+)
+
+// YearAnomalyKind identifies a kind of implausible copyright year
+type YearAnomalyKind string
+
+const (
+	// YearAnomalyReversed is a range whose end precedes its start, e.g.,
+	// "2025, 2019"
+	YearAnomalyReversed YearAnomalyKind = "reversed"
+
+	// YearAnomalyFuture is a year after the current one
+	YearAnomalyFuture YearAnomalyKind = "future"
+
+	// YearAnomalyBeforeCreation is a year before the project was created
+	YearAnomalyBeforeCreation YearAnomalyKind = "before-creation"
+)
+
+// YearAnomaly describes an implausible year in a copyright statement, along
+// with a suggested fix
+type YearAnomaly struct {
+	// Line number of the statement, starting at 1
+	Line int
+
+	// Kinds of anomalies found in the statement
+	Kinds []YearAnomalyKind
+
+	// Original contents of the line
+	Original string
+
+	// Suggested contents of the line, with the years corrected
+	Suggested string
+}
+
+// Message returns a human-readable description of the anomalies
+func (a YearAnomaly) Message() string {
+	messages := []string{}
+	for _, k := range a.Kinds {
+		switch k {
+		case YearAnomalyReversed:
+			messages = append(messages, "years are reversed")
+		case YearAnomalyFuture:
+			messages = append(messages, "year is in the future")
+		case YearAnomalyBeforeCreation:
+			messages = append(messages, "year predates the project")
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FindYearAnomalies returns the copyright statements in the header region of b
+// (see addlicense.HeaderScanWindow) whose years are implausible: reversed ranges, years after the
+// given year, or years before earliest (typically the year the project was
+// created). An earliest of zero disables the latter check.
+//
+// Each anomaly includes a suggested line in which the range is put in order
+// and clamped between earliest and year.
+func FindYearAnomalies(b []byte, year int, earliest int) []YearAnomaly {
+	anomalies := []YearAnomaly{}
+
+	limit := len(addlicense.HeaderRegion(b))
+	start := 0
+	for n := 1; start < limit; n++ {
+		end := bytes.IndexByte(b[start:], '\n')
+		if end == -1 {
+			end = len(b)
+		} else {
+			end += start
+		}
+
+		line := b[start:end]
+		if bytes.Contains(bytes.ToLower(line), []byte("copyright")) {
+			if a, ok := yearAnomaly(line, year, earliest); ok {
+				a.Line = n
+				anomalies = append(anomalies, a)
+			}
+		}
+
+		start = end + 1
+	}
+
+	return anomalies
+}
+
+// yearAnomaly checks the first year or range of years in a single line
+func yearAnomaly(line []byte, year int, earliest int) (YearAnomaly, bool) {
+	loc := yearExpr.FindSubmatchIndex(line)
+	if loc == nil {
+		return YearAnomaly{}, false
+	}
+
+	first, _ := strconv.Atoi(string(line[loc[2]:loc[3]]))
+	last := first
+	separator := "-"
+	if loc[4] != -1 {
+		separator = string(line[loc[4]:loc[5]])
+		last, _ = strconv.Atoi(string(line[loc[6]:loc[7]]))
+	}
+
+	kinds := []YearAnomalyKind{}
+	if last < first {
+		kinds = append(kinds, YearAnomalyReversed)
+		first, last = last, first
+	}
+	if last > year {
+		kinds = append(kinds, YearAnomalyFuture)
+		last = year
+		first = min(first, year)
+	}
+	if earliest != 0 && first < earliest {
+		kinds = append(kinds, YearAnomalyBeforeCreation)
+		first = min(earliest, last)
+	}
+	if len(kinds) == 0 {
+		return YearAnomaly{}, false
+	}
+
+	replacement := strconv.Itoa(first)
+	if last != first {
+		replacement = fmt.Sprintf("%d%s%d", first, separator, last)
+	}
+
+	// If the fix can't be applied safely, the anomaly is still reported, but
+	// the suggestion leaves the line as is
+	suggested, _ := replaceInLine(line, loc[0], loc[1], []byte(replacement))
+
+	return YearAnomaly{Kinds: kinds, Original: string(line), Suggested: string(suggested)}, true
+}
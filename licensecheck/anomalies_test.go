@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindYearAnomalies(t *testing.T) {
+	cases := []struct {
+		description    string
+		fileContents   string
+		earliest       int
+		expectedOutput []YearAnomaly
+	}{
+		{
+			description:    "Plausible years are not flagged",
+			fileContents:   "// Copyright (c) 2019-2025 HashiCorp, Inc.\n",
+			earliest:       2019,
+			expectedOutput: []YearAnomaly{},
+		},
+		{
+			description:  "Reversed ranges are put in order",
+			fileContents: "package main\n\n# Copyright IBM Corp. 2025, 2019\n",
+			expectedOutput: []YearAnomaly{
+				{Line: 3, Kinds: []YearAnomalyKind{YearAnomalyReversed}, Original: "# Copyright IBM Corp. 2025, 2019", Suggested: "# Copyright IBM Corp. 2019, 2025"},
+			},
+		},
+		{
+			description:  "Future years are clamped to the current year",
+			fileContents: "// Copyright (c) 2020-2031 HashiCorp, Inc.\n",
+			expectedOutput: []YearAnomaly{
+				{Line: 1, Kinds: []YearAnomalyKind{YearAnomalyFuture}, Original: "// Copyright (c) 2020-2031 HashiCorp, Inc.", Suggested: "// Copyright (c) 2020-2025 HashiCorp, Inc."},
+			},
+		},
+		{
+			description:  "Ranges entirely in the future collapse to the current year",
+			fileContents: "// Copyright (c) 2031 HashiCorp, Inc.\n",
+			expectedOutput: []YearAnomaly{
+				{Line: 1, Kinds: []YearAnomalyKind{YearAnomalyFuture}, Original: "// Copyright (c) 2031 HashiCorp, Inc.", Suggested: "// Copyright (c) 2025 HashiCorp, Inc."},
+			},
+		},
+		{
+			description:  "Years before the project was created are flagged",
+			fileContents: "// Copyright (c) 2009-2023 HashiCorp, Inc.\n",
+			earliest:     2019,
+			expectedOutput: []YearAnomaly{
+				{Line: 1, Kinds: []YearAnomalyKind{YearAnomalyBeforeCreation}, Original: "// Copyright (c) 2009-2023 HashiCorp, Inc.", Suggested: "// Copyright (c) 2019-2023 HashiCorp, Inc."},
+			},
+		},
+		{
+			description:    "Creation year check is disabled when unknown",
+			fileContents:   "// Copyright (c) 2009-2023 HashiCorp, Inc.\n",
+			expectedOutput: []YearAnomaly{},
+		},
+		{
+			description:  "Multiple anomalies in one statement",
+			fileContents: "/* Copyright 2030-2009 Acme Corp */\n",
+			earliest:     2019,
+			expectedOutput: []YearAnomaly{
+				{Line: 1, Kinds: []YearAnomalyKind{YearAnomalyReversed, YearAnomalyFuture, YearAnomalyBeforeCreation}, Original: "/* Copyright 2030-2009 Acme Corp */", Suggested: "/* Copyright 2019-2025 Acme Corp */"},
+			},
+		},
+		{
+			description:    "Years outside of copyright statements are ignored",
+			fileContents:   "// Deprecated in 2031\n",
+			expectedOutput: []YearAnomaly{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput := FindYearAnomalies([]byte(tt.fileContents), 2025, tt.earliest)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func TestYearAnomalyMessage(t *testing.T) {
+	a := YearAnomaly{Kinds: []YearAnomalyKind{YearAnomalyReversed, YearAnomalyFuture}}
+	assert.Equal(t, "years are reversed; year is in the future", a.Message())
+}
@@ -5,7 +5,10 @@ package licensecheck
 
 import (
 	"bytes"
-	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/copywrite/addlicense"
 )
 
 // HasCopyright reports whether or not a file contains a copyright statement
@@ -18,25 +21,130 @@ func HasCopyright(filePath string) (bool, error) {
 	return HasMatchingCopyright(filePath, "copyright", false)
 }
 
+// MatchOptions control how strictly HasMatchingCopyrightWithOptions compares a
+// file's copyright statement with the expected one
+type MatchOptions struct {
+	// CaseSensitive requires the statement's case to match exactly
+	CaseSensitive bool
+
+	// CollapseWhitespace treats any run of whitespace, including line breaks,
+	// as a single space
+	CollapseWhitespace bool
+
+	// EquateCopyrightSymbols treats "(c)", "(C)", and "©" as equivalent
+	EquateCopyrightSymbols bool
+
+	// AcceptYearRanges accepts years that cover the expected years, such as
+	// "2019-2025" where "2022" is expected
+	AcceptYearRanges bool
+}
+
+// TolerantMatch accepts the variations commonly introduced when LICENSE files
+// are edited by hand
+var TolerantMatch = MatchOptions{
+	CollapseWhitespace:     true,
+	EquateCopyrightSymbols: true,
+	AcceptYearRanges:       true,
+}
+
+// licenseScanBytes is the number of bytes at the top of a LICENSE file that are
+// searched for a copyright statement when no scan window is configured. It is
+// smaller than the window for source files, since the statement is expected
+// to come first and license texts mention "copyright" further down.
+const licenseScanBytes = 300
+
 // HasMatchingCopyright takes an explicit copyright statement and validates that
-// a given file contains that string in the header (first 1k chars)
+// a given file contains that string in its header region (see
+// addlicense.HeaderScanWindow, which defaults to the first 300 bytes here)
 func HasMatchingCopyright(filePath string, copyrightStatement string, caseSensitive bool) (bool, error) {
-	b, err := os.ReadFile(filePath)
+	return HasMatchingCopyrightWithOptions(filePath, copyrightStatement, MatchOptions{CaseSensitive: caseSensitive})
+}
+
+// HasMatchingCopyrightWithOptions is like HasMatchingCopyright, but the
+// comparison may tolerate differences in formatting, as set in opts
+func HasMatchingCopyrightWithOptions(filePath string, copyrightStatement string, opts MatchOptions) (bool, error) {
+	b, err := addlicense.ReadHead(filePath)
 	if err != nil {
 		return false, err
 	}
 
-	// Check the first 300 characters
-	n := 300
-	if len(b) < n {
-		n = len(b)
+	w := addlicense.HeaderScanWindow
+	if w == (addlicense.ScanWindow{}) {
+		w.Bytes = licenseScanBytes
+	}
+	return matchesCopyright(w.Head(b), []byte(copyrightStatement), opts), nil
+}
+
+var whitespaceRun = regexp.MustCompile(`\s+`)
+
+// normalizeStatement applies the normalizations selected in opts to b
+func normalizeStatement(b []byte, opts MatchOptions) []byte {
+	if !opts.CaseSensitive {
+		b = bytes.ToLower(b)
+	}
+	if opts.CollapseWhitespace {
+		b = whitespaceRun.ReplaceAll(b, []byte(" "))
+	}
+	if opts.EquateCopyrightSymbols {
+		b = bytes.ReplaceAll(b, []byte("©"), []byte("(c)"))
+		b = bytes.ReplaceAll(b, []byte("(C)"), []byte("(c)"))
+	}
+	return b
+}
+
+// matchesCopyright reports whether header contains expected, as compared
+// according to opts
+func matchesCopyright(header []byte, expected []byte, opts MatchOptions) bool {
+	header = normalizeStatement(header, opts)
+	expected = normalizeStatement(expected, opts)
+	if !opts.AcceptYearRanges {
+		return bytes.Contains(header, expected)
+	}
+
+	// Each year expression in expected becomes a capture group that matches
+	// any year expression, which must then cover the expected years
+	locs := yearExpr.FindAllSubmatchIndex(expected, -1)
+	if len(locs) == 0 {
+		return bytes.Contains(header, expected)
+	}
+	pattern := ""
+	prev := 0
+	for _, loc := range locs {
+		pattern += regexp.QuoteMeta(string(expected[prev:loc[0]])) + `(` + yearExpr.String() + `)`
+		prev = loc[1]
+	}
+	pattern += regexp.QuoteMeta(string(expected[prev:]))
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
 	}
 
-	expected := []byte(copyrightStatement)
-	header := b[:n]
-	if !caseSensitive {
-		header = bytes.ToLower(header)
-		expected = bytes.ToLower(expected)
+	groups := yearExpr.NumSubexp() + 1
+	for _, m := range re.FindAllSubmatch(header, -1) {
+		covered := true
+		for i, loc := range locs {
+			wantFirst, wantLast := yearBounds(expected[loc[0]:loc[1]])
+			gotFirst, gotLast := yearBounds(m[1+i*groups])
+			if gotFirst > wantFirst || gotLast < wantLast {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+// yearBounds returns the first and last years of a year expression matched by
+// yearExpr
+func yearBounds(b []byte) (int, int) {
+	m := yearExpr.FindSubmatch(b)
+	first, _ := strconv.Atoi(string(m[1]))
+	last := first
+	if len(m[3]) > 0 {
+		last, _ = strconv.Atoi(string(m[3]))
 	}
-	return bytes.Contains(header, expected), nil
+	return min(first, last), max(first, last)
 }
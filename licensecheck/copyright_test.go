@@ -276,3 +276,83 @@ func TestHasCopyright(t *testing.T) {
 		})
 	}
 }
+
+func TestHasMatchingCopyrightWithOptions(t *testing.T) {
+	AppFs := afero.NewOsFs()
+	tempDir := t.TempDir()
+
+	desiredCopyrightString := "Copyright (c) 2022 HashiCorp, Inc."
+
+	cases := []struct {
+		description   string
+		fileContents  string
+		opts          MatchOptions
+		expectedValid bool
+	}{
+		{
+			description:   "Exact copyright statement should pass in strict mode",
+			fileContents:  "Copyright (c) 2022 HashiCorp, Inc.",
+			opts:          MatchOptions{CaseSensitive: true},
+			expectedValid: true,
+		},
+		{
+			description:   "Extra whitespace should fail in strict mode",
+			fileContents:  "Copyright (c)  2022\nHashiCorp, Inc.",
+			opts:          MatchOptions{CaseSensitive: true},
+			expectedValid: false,
+		},
+		{
+			description:   "Extra whitespace should pass when collapsed",
+			fileContents:  "Copyright (c)  2022\nHashiCorp, Inc.",
+			opts:          TolerantMatch,
+			expectedValid: true,
+		},
+		{
+			description:   "Copyright symbol should pass when symbols are equated",
+			fileContents:  "Copyright © 2022 HashiCorp, Inc.",
+			opts:          TolerantMatch,
+			expectedValid: true,
+		},
+		{
+			description:   "Uppercase copyright symbol should pass when symbols are equated",
+			fileContents:  "Copyright (C) 2022 HashiCorp, Inc.",
+			opts:          MatchOptions{CaseSensitive: true, EquateCopyrightSymbols: true},
+			expectedValid: true,
+		},
+		{
+			description:   "Year range containing the expected year should pass",
+			fileContents:  "Copyright (c) 2019-2025 HashiCorp, Inc.",
+			opts:          TolerantMatch,
+			expectedValid: true,
+		},
+		{
+			description:   "Year range not containing the expected year should fail",
+			fileContents:  "Copyright (c) 2023-2025 HashiCorp, Inc.",
+			opts:          TolerantMatch,
+			expectedValid: false,
+		},
+		{
+			description:   "Different year should fail",
+			fileContents:  "Copyright (c) 2021 HashiCorp, Inc.",
+			opts:          TolerantMatch,
+			expectedValid: false,
+		},
+		{
+			description:   "Different holder should fail",
+			fileContents:  "Copyright (c) 2022 Acme, Inc.",
+			opts:          TolerantMatch,
+			expectedValid: false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			f, _ := afero.TempFile(AppFs, tempDir, "")
+			_ = afero.WriteFile(AppFs, f.Name(), []byte(tt.fileContents), 0644)
+			// run test
+			actualValid, err := HasMatchingCopyrightWithOptions(f.Name(), desiredCopyrightString, tt.opts)
+			assert.Nil(t, err, tt.description)
+			assert.Equal(t, tt.expectedValid, actualValid, tt.description)
+		})
+	}
+}
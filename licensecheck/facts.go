@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"github.com/hashicorp/copywrite/addlicense"
+)
+
+// FileFacts is everything copywrite can determine from the header of a single
+// file. It is intended for tools that need the same parse results as copywrite
+// itself without re-implementing its detection logic.
+type FileFacts struct {
+	// Path of the file, as given to ScanFile
+	Path string `json:"path"`
+
+	// Copyrights are the copyright statements found in the header, in order
+	Copyrights []CopyrightStatement `json:"copyrights"`
+
+	// SPDXExpressions are the expressions of every SPDX-License-Identifier line
+	// found in the header, in order
+	SPDXExpressions []string `json:"spdx_expressions"`
+
+	// SkipReason describes why the file is exempt from needing a header, such
+	// as "generated file" or "minified asset", or is empty if it is not exempt
+	SkipReason string `json:"skip_reason,omitempty"`
+
+	// CommentStyle is the style used for headers in this type of file, or nil
+	// if headers are not supported for it
+	CommentStyle *addlicense.CommentStyle `json:"comment_style,omitempty"`
+}
+
+// ScanFile reads the file at path and returns the facts parsed from its header.
+// Only the header region of the file is searched (see
+// addlicense.HeaderScanWindow), with the exception of generation markers.
+func ScanFile(path string) (FileFacts, error) {
+	b, err := addlicense.ReadHead(path)
+	if err != nil {
+		return FileFacts{}, err
+	}
+
+	facts := FileFacts{
+		Path:            path,
+		Copyrights:      ParseCopyrightStatements(b),
+		SPDXExpressions: addlicense.SPDXExpressions(b),
+		SkipReason:      addlicense.SkipReason(path, b),
+	}
+	if style, ok := addlicense.CommentStyleFor(path); ok {
+		facts.CommentStyle = &style
+	}
+	return facts, nil
+}
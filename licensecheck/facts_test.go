@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanFile(t *testing.T) {
+	cases := []struct {
+		description    string
+		fileName       string
+		fileContents   string
+		expectedOutput FileFacts
+	}{
+		{
+			description:  "Header with a copyright statement and license",
+			fileName:     "main.go",
+			fileContents: "// Copyright (c) 2023 HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expectedOutput: FileFacts{
+				Copyrights:      []CopyrightStatement{{Years: "2023", Holder: "HashiCorp, Inc."}},
+				SPDXExpressions: []string{"MPL-2.0"},
+				CommentStyle:    &addlicense.CommentStyle{Mid: "// "},
+			},
+		},
+		{
+			description:  "Generated file without a header",
+			fileName:     "zz_generated.go",
+			fileContents: "// Code generated by controller-gen. DO NOT EDIT.\n\npackage v1\n",
+			expectedOutput: FileFacts{
+				Copyrights:      []CopyrightStatement{},
+				SPDXExpressions: []string{},
+				SkipReason:      "generated file",
+				CommentStyle:    &addlicense.CommentStyle{Mid: "// "},
+			},
+		},
+		{
+			description:  "Unsupported file type",
+			fileName:     "notes.txt",
+			fileContents: "Copyright 2019 Jane Doe\n",
+			expectedOutput: FileFacts{
+				Copyrights:      []CopyrightStatement{{Years: "2019", Holder: "Jane Doe"}},
+				SPDXExpressions: []string{},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			assert.Nil(t, os.WriteFile(path, []byte(tt.fileContents), 0644))
+
+			tt.expectedOutput.Path = path
+			actualOutput, err := ScanFile(path)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+
+	_, err := ScanFile(filepath.Join(t.TempDir(), "missing.go"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/copywrite/addlicense"
+	"github.com/samber/lo"
+)
+
+// CopyrightStatement is a single copyright statement parsed from a file header
+type CopyrightStatement struct {
+	// Years as written in the statement (e.g., "2019" or "2019-2023"), if any
+	Years string `json:"years,omitempty"`
+
+	// Holder named by the statement, e.g. "HashiCorp, Inc."
+	Holder string `json:"holder"`
+}
+
+var (
+	// leadingYears matches the years immediately following the word copyright,
+	// e.g. "2019-2023, " in "Copyright 2019-2023, Acme Corp"
+	leadingYears = regexp.MustCompile(`^((?:19|20)\d{2}(?:\s*(?:[-–]|,)\s*(?:19|20)\d{2})*)[,\s]*`)
+
+	// trailingYears matches years at the end of a statement, e.g. ", 2018" in
+	// "Copyright IBM Corp. 2018"
+	trailingYears = regexp.MustCompile(`[,\s]+((?:19|20)\d{2}(?:\s*(?:[-–]|,)\s*(?:19|20)\d{2})*)$`)
+
+	// allRightsReserved matches the boilerplate that often trails a holder
+	allRightsReserved = regexp.MustCompile(`(?i)[,.;]?\s*all rights reserved\.?$`)
+)
+
+// holderAbbreviations are common endings of legal entity names, whose trailing
+// period is part of the holder rather than the end of a sentence
+var holderAbbreviations = []string{"inc.", "ltd.", "corp.", "co.", "l.l.c.", "s.a.", "b.v.", "n.v.", "a.g."}
+
+// ParseCopyrightStatements returns every copyright statement found in the
+// header region of b (see addlicense.HeaderScanWindow), in the order they
+// appear.
+//
+// To avoid mistaking prose such as "the above copyright notice" for a
+// statement, the word "copyright" must be accompanied by a copyright symbol or
+// at least one year.
+func ParseCopyrightStatements(b []byte) []CopyrightStatement {
+	statements := []CopyrightStatement{}
+	for _, line := range bytes.Split(addlicense.HeaderRegion(b), []byte("\n")) {
+		if s, ok := parseCopyrightLine(string(line)); ok {
+			statements = append(statements, s)
+		}
+	}
+	return statements
+}
+
+// parseCopyrightLine parses a single line of a header, which may include
+// comment characters, into a copyright statement
+func parseCopyrightLine(line string) (CopyrightStatement, bool) {
+	i := strings.Index(strings.ToLower(line), "copyright")
+	if i == -1 {
+		return CopyrightStatement{}, false
+	}
+	rest := line[i+len("copyright"):]
+
+	// Drop the closing delimiter of single-line block comments
+	rest = strings.TrimSpace(rest)
+	for _, closer := range []string{"*/", "-->", "*)", "}}", "%>"} {
+		rest = strings.TrimSpace(strings.TrimSuffix(rest, closer))
+	}
+
+	marked := false
+	var years string
+	for {
+		if r, ok := cutCopyrightSymbol(rest); ok {
+			rest, marked = r, true
+			continue
+		}
+		if m := leadingYears.FindStringSubmatch(rest); m != nil && years == "" {
+			years = m[1]
+			rest = rest[len(m[0]):]
+			continue
+		}
+		break
+	}
+
+	rest = strings.TrimSpace(allRightsReserved.ReplaceAllString(rest, ""))
+	if years == "" {
+		if m := trailingYears.FindStringSubmatchIndex(rest); m != nil {
+			years = rest[m[2]:m[3]]
+			rest = rest[:m[0]]
+		}
+	}
+
+	if !marked && years == "" {
+		return CopyrightStatement{}, false
+	}
+
+	holder := strings.TrimRight(strings.TrimSpace(rest), ",;")
+	if strings.HasSuffix(holder, ".") {
+		fields := strings.Fields(strings.ToLower(holder))
+		if !lo.Contains(holderAbbreviations, fields[len(fields)-1]) {
+			holder = strings.TrimSuffix(holder, ".")
+		}
+	}
+	if holder == "" {
+		return CopyrightStatement{}, false
+	}
+
+	return CopyrightStatement{Years: years, Holder: holder}, true
+}
+
+// cutCopyrightSymbol removes a leading "(c)" or "©" from s
+func cutCopyrightSymbol(s string) (string, bool) {
+	for _, symbol := range []string{"(c)", "(C)", "©"} {
+		if r, ok := strings.CutPrefix(s, symbol); ok {
+			return strings.TrimSpace(r), true
+		}
+	}
+	return s, false
+}
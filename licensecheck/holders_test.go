@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCopyrightStatements(t *testing.T) {
+	cases := []struct {
+		description    string
+		fileContents   string
+		expectedOutput []CopyrightStatement
+	}{
+		{
+			description:    "No statements",
+			fileContents:   "package main\n",
+			expectedOutput: []CopyrightStatement{},
+		},
+		{
+			description:    "Statement without a year",
+			fileContents:   "// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n",
+			expectedOutput: []CopyrightStatement{{Holder: "HashiCorp, Inc."}},
+		},
+		{
+			description:    "Statement with years and boilerplate",
+			fileContents:   "// Copyright 2009-2012 The Go Authors. All rights reserved.\n",
+			expectedOutput: []CopyrightStatement{{Years: "2009-2012", Holder: "The Go Authors"}},
+		},
+		{
+			description:    "Symbol after the years",
+			fileContents:   "# Copyright 2020 (c) Acme Corp.\n",
+			expectedOutput: []CopyrightStatement{{Years: "2020", Holder: "Acme Corp."}},
+		},
+		{
+			description:    "Years after the holder",
+			fileContents:   "/* Copyright IBM Corp. 2018, 2023 */\n",
+			expectedOutput: []CopyrightStatement{{Years: "2018, 2023", Holder: "IBM Corp."}},
+		},
+		{
+			description:  "Multiple statements",
+			fileContents: "// Copyright (c) HashiCorp, Inc.\n// Copyright © 2015 Jane Doe <jane@example.com>\n",
+			expectedOutput: []CopyrightStatement{
+				{Holder: "HashiCorp, Inc."},
+				{Years: "2015", Holder: "Jane Doe <jane@example.com>"},
+			},
+		},
+		{
+			description:    "Prose mentioning copyright is ignored",
+			fileContents:   "The above copyright notice and this permission notice shall be included\n",
+			expectedOutput: []CopyrightStatement{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, ParseCopyrightStatements([]byte(tt.fileContents)))
+		})
+	}
+}
@@ -13,20 +13,47 @@ import (
 	"github.com/samber/lo"
 )
 
+// DefaultLicenseFileName is the name license files are normalized to, unless
+// another canonical name is chosen
+const DefaultLicenseFileName = "LICENSE"
+
+// licenseFileName matches the names of license files, without case
+// sensitivity: LICENSE, LICENSE.txt, and LICENSE.md, the British spelling
+// LICENCE with the same extensions, COPYING and COPYING.txt (used by GNU
+// projects), and UNLICENSE
+var licenseFileName = regexp.MustCompile(`^(?i)((license|licence)(\.md|\.txt)?|copying(\.txt)?|unlicense)$`)
+
+// IsLicenseFileName reports whether name is recognized as the name of a
+// license file by FindLicenseFiles
+func IsLicenseFileName(name string) bool {
+	return licenseFileName.MatchString(name)
+}
+
+// IsUnlicense reports whether the file at filePath is an UNLICENSE file, which
+// dedicates a project to the public domain. Such files are conventionally
+// named UNLICENSE and have no copyright holder, so they are neither renamed
+// nor given a copyright statement.
+func IsUnlicense(filePath string) bool {
+	return strings.EqualFold(filepath.Base(filePath), "UNLICENSE")
+}
+
 // EnsureCorrectName fixes a malformed license file name and returns the
 // new (corrected) file path
 // E.g., "license.txt" --> "LICENSE"
 func EnsureCorrectName(filePath string) (string, error) {
+	return EnsureCorrectNameAs(filePath, DefaultLicenseFileName)
+}
+
+// EnsureCorrectNameAs is like EnsureCorrectName, but normalizes the file name
+// to the given canonical name (e.g., "COPYING") rather than "LICENSE"
+func EnsureCorrectNameAs(filePath string, name string) (string, error) {
 	dir, _ := filepath.Split(filePath)
-	desiredPath := filepath.Join(dir, "LICENSE")
+	desiredPath := filepath.Join(dir, name)
 	if desiredPath != filePath {
-		fmt.Printf("Found improperly named file \"%s\". Renaming to \"%s\"", filePath, desiredPath)
-		err := os.Rename(filePath, filepath.Join(dir, "LICENSE"))
+		err := os.Rename(filePath, desiredPath)
 		if err != nil {
 			return "", fmt.Errorf("Unable to rename file \"%s\". Full error context: %s", filePath, err)
 		}
-	} else {
-		fmt.Printf("Validated file: %s\n", filePath)
 	}
 
 	return desiredPath, nil
@@ -50,13 +77,19 @@ func AddHeader(filePath string, header string) error {
 // NOTE: this function will NOT add a copyright statement for you. You must
 // manually call AddHeader() afterward if you wish to have copyright headers
 func AddLicenseFile(dirPath string, spdxID string) (string, error) {
+	return AddLicenseFileAs(dirPath, spdxID, DefaultLicenseFileName)
+}
+
+// AddLicenseFileAs is like AddLicenseFile, but names the file it creates with
+// the given canonical name (e.g., "COPYING") rather than "LICENSE"
+func AddLicenseFileAs(dirPath string, spdxID string, name string) (string, error) {
 	template, exists := licenseTemplate[spdxID]
 	if !exists {
 		validOptions := strings.Join(lo.Keys(licenseTemplate), ", ")
 		return "", fmt.Errorf("Failed to add license file, unknown SPDX license ID: %s. The following options are supported at this time: %s", spdxID, validOptions)
 	}
 
-	destinationPath, err := filepath.Abs(filepath.Join(dirPath, "LICENSE"))
+	destinationPath, err := filepath.Abs(filepath.Join(dirPath, name))
 	if err != nil {
 		return "", err
 	}
@@ -77,12 +110,11 @@ func FindLicenseFiles(dirPath string) ([]string, error) {
 		return []string{}, err
 	}
 
-	// filter without case sensitivity for LICENSE, LICENSE.txt, and LICENSE.md
-	r := regexp.MustCompile(`^(?i)(license.md|license.txt|license)$`)
-
+	// filter without case sensitivity for LICENSE, LICENSE.txt, LICENSE.md, and
+	// the other recognized variants
 	matches := lo.Filter(files, func(f string, _ int) bool {
 		_, file := filepath.Split(f)
-		return r.MatchString(file)
+		return IsLicenseFileName(file)
 	})
 
 	return matches, nil
@@ -85,6 +85,44 @@ func TestEnsureCorrectName(t *testing.T) {
 	}
 }
 
+func TestEnsureCorrectNameAs(t *testing.T) {
+	AppFs := afero.NewOsFs()
+
+	cases := []struct {
+		description  string
+		fileToCreate string
+		name         string
+	}{
+		{
+			description:  "File is renamed to the British spelling",
+			fileToCreate: "LICENSE.md",
+			name:         "LICENCE",
+		},
+		{
+			description:  "File is renamed to COPYING",
+			fileToCreate: "license.txt",
+			name:         "COPYING",
+		},
+		{
+			description:  "Correctly named file should be left alone",
+			fileToCreate: "COPYING",
+			name:         "COPYING",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			tempDir, filePaths := createTempFiles(t, []string{tt.fileToCreate})
+			newPath, err := EnsureCorrectNameAs(filePaths[0], tt.name)
+			assert.Nil(t, err)
+			assert.Equal(t, filepath.Join(tempDir, tt.name), newPath)
+			fileExists, err := afero.Exists(AppFs, newPath)
+			assert.True(t, fileExists)
+			assert.Nil(t, err)
+		})
+	}
+}
+
 func TestAddHeader(t *testing.T) {
 	// stub
 	t.Skip()
@@ -158,6 +196,26 @@ func TestFindLicenseFiles(t *testing.T) {
 			input:          []string{"LICENSE", "license/blah.txt"},
 			expectedOutput: []string{"LICENSE"},
 		},
+		{
+			description:    "COPYING files are matched",
+			input:          []string{"COPYING", "copying.txt"},
+			expectedOutput: []string{"COPYING", "copying.txt"},
+		},
+		{
+			description:    "British spelling is matched",
+			input:          []string{"LICENCE", "Licence.md", "licence.TXT"},
+			expectedOutput: []string{"LICENCE", "Licence.md", "licence.TXT"},
+		},
+		{
+			description:    "UNLICENSE files are matched",
+			input:          []string{"UNLICENSE"},
+			expectedOutput: []string{"UNLICENSE"},
+		},
+		{
+			description:    "Don't match COPYING or UNLICENSE files with other extensions",
+			input:          []string{"COPYING.md", "COPYING.LESSER", "UNLICENSE.txt"},
+			expectedOutput: []string{},
+		},
 	}
 
 	for _, tt := range cases {
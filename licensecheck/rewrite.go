@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// commentDelimiters are the tokens that open or close a block comment in the
+// comment styles used for headers (see addlicense.CommentStyleFor). Longer
+// tokens are listed first so that they take precedence when tokenizing.
+var commentDelimiters = [][]byte{
+	[]byte("<!--"),
+	[]byte("-->"),
+	[]byte("{{!"),
+	[]byte("}}"),
+	[]byte("/*"),
+	[]byte("*/"),
+	[]byte("(*"),
+	[]byte("*)"),
+	[]byte("<%"),
+	[]byte("%>"),
+}
+
+// delimitersIn returns the comment delimiters found in b, in order
+func delimitersIn(b []byte) []string {
+	found := []string{}
+	for i := 0; i < len(b); {
+		matched := false
+		for _, d := range commentDelimiters {
+			if bytes.HasPrefix(b[i:], d) {
+				found = append(found, string(d))
+				i += len(d)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			i++
+		}
+	}
+	return found
+}
+
+// boxEdge matches the right border of a line in a boxed header (see
+// addlicense.HeaderStyleBoxed): padding followed by a single border character
+var boxEdge = regexp.MustCompile(`( +)[=*#~+]$`)
+
+// copyrightWord matches the word copyright in any case. Unlike searching a
+// lowercased copy of a line, it reports offsets that are valid in the original
+// line even if it contains non-ASCII text whose lowercase form is a different
+// length.
+var copyrightWord = regexp.MustCompile(`(?i)copyright`)
+
+// copyrightYears returns the submatch indices of yearExpr for the years of the
+// copyright statement in line: the first year or range of years following the
+// word "copyright" that stands alone, rather than being part of a URL, version
+// number, or identifier. It returns nil if the statement has no years.
+//
+// Rewriters only ever change a statement's holder and these years, so any
+// text that trails them, such as "All rights reserved." or a link to a legal
+// page that happens to contain a year, is preserved verbatim.
+func copyrightYears(line []byte) []int {
+	w := copyrightWord.FindIndex(line)
+	if w == nil {
+		return nil
+	}
+	offset := w[1]
+
+	for _, loc := range yearExpr.FindAllSubmatchIndex(line[offset:], -1) {
+		lo, hi := offset+loc[0], offset+loc[1]
+		if lo > 0 && strings.IndexByte(`/\.:#=-_`, line[lo-1]) != -1 {
+			continue
+		}
+		if hi < len(line) && strings.IndexByte(`/\-_`, line[hi]) != -1 {
+			continue
+		}
+		if hi+1 < len(line) && line[hi] == '.' && isAlphanumeric(line[hi+1]) {
+			continue
+		}
+		for i := range loc {
+			if loc[i] != -1 {
+				loc[i] += offset
+			}
+		}
+		return loc
+	}
+	return nil
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// replaceInLine replaces line[lo:hi] with replacement, unless doing so could
+// produce a syntactically invalid comment. If line is part of a boxed header,
+// the padding before its right border is adjusted to keep the border in
+// place, where possible. Every rewrite of a copyright statement goes through
+// here, which guarantees that:
+//   - the replacement never spans multiple lines, which would push the rest of
+//     a line comment out of the comment
+//   - the comment delimiters in the line are unchanged, so that a block comment
+//     is never terminated early, left unterminated, or merged with whatever
+//     surrounds the replacement
+//
+// If the replacement is unsafe, line is returned unchanged along with false.
+func replaceInLine(line []byte, lo int, hi int, replacement []byte) ([]byte, bool) {
+	if bytes.ContainsAny(replacement, "\r\n") {
+		return line, false
+	}
+
+	out := make([]byte, 0, len(line)-(hi-lo)+len(replacement))
+	out = append(out, line[:lo]...)
+	out = append(out, replacement...)
+	if m := boxEdge.FindSubmatchIndex(line); m != nil && hi <= m[2] {
+		growth := utf8.RuneCount(replacement) - utf8.RuneCount(line[lo:hi])
+		out = append(out, line[hi:m[2]]...)
+		out = append(out, bytes.Repeat([]byte(" "), max(m[3]-m[2]-growth, 1))...)
+		out = append(out, line[m[3]:]...)
+	} else {
+		out = append(out, line[hi:]...)
+	}
+
+	if !slices.Equal(delimitersIn(line), delimitersIn(out)) {
+		return line, false
+	}
+	return out, true
+}
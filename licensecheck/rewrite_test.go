@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceInLine(t *testing.T) {
+	cases := []struct {
+		description    string
+		line           string
+		old            string
+		replacement    string
+		expectedOutput string
+		expectedOK     bool
+	}{
+		{
+			description:    "Text within a block comment is replaced",
+			line:           "/* Copyright (c) Acme Inc. */",
+			old:            "Acme Inc.",
+			replacement:    "IBM Corp.",
+			expectedOutput: "/* Copyright (c) IBM Corp. */",
+			expectedOK:     true,
+		},
+		{
+			description:    "Replacements may not close a block comment",
+			line:           "/* Copyright (c) Acme Inc. */",
+			old:            "Acme Inc.",
+			replacement:    "Acme */ Inc.",
+			expectedOutput: "/* Copyright (c) Acme Inc. */",
+		},
+		{
+			description:    "Replacements may not open a block comment",
+			line:           "<!-- Copyright (c) Acme Inc. -->",
+			old:            "Acme Inc.",
+			replacement:    "<!-- Acme Inc.",
+			expectedOutput: "<!-- Copyright (c) Acme Inc. -->",
+		},
+		{
+			description:    "Replacements may not form a delimiter with surrounding text",
+			line:           "<!-- Copyright (c) Acme Inc. -->",
+			old:            "Acme Inc. ",
+			replacement:    "Acme Inc. <!",
+			expectedOutput: "<!-- Copyright (c) Acme Inc. -->",
+		},
+		{
+			description:    "Right borders of boxed headers stay in place",
+			line:           " * Copyright (c) 2019 Acme Inc.    =",
+			old:            "2019",
+			replacement:    "2019-2025",
+			expectedOutput: " * Copyright (c) 2019-2025 Acme Inc. =",
+			expectedOK:     true,
+		},
+		{
+			description:    "Right borders of boxed headers are pushed out if necessary",
+			line:           "# Copyright (c) Acme Inc. #",
+			old:            "Acme Inc.",
+			replacement:    "IBM Corp. (formerly Acme Inc.)",
+			expectedOutput: "# Copyright (c) IBM Corp. (formerly Acme Inc.) #",
+			expectedOK:     true,
+		},
+		{
+			description:    "Replacements may not span multiple lines",
+			line:           "// Copyright (c) Acme Inc.",
+			old:            "Acme Inc.",
+			replacement:    "Acme\nInc.",
+			expectedOutput: "// Copyright (c) Acme Inc.",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			lo := bytes.Index([]byte(tt.line), []byte(tt.old))
+			actualOutput, actualOK := replaceInLine([]byte(tt.line), lo, lo+len(tt.old), []byte(tt.replacement))
+			assert.Equal(t, tt.expectedOutput, string(actualOutput))
+			assert.Equal(t, tt.expectedOK, actualOK)
+		})
+	}
+}
+
+// Test that text trailing the years and holder of a statement is preserved
+// verbatim by every rewriter
+func TestRewriteTrailingText(t *testing.T) {
+	cases := []struct {
+		description      string
+		line             string
+		expectedBump     string
+		expectedTransfer string
+	}{
+		{
+			description:      "All rights reserved",
+			line:             "// Copyright (c) 2019 Acme Inc. All rights reserved.",
+			expectedBump:     "// Copyright (c) 2019-2026 Acme Inc. All rights reserved.",
+			expectedTransfer: "// Copyright (c) 2019-2026 IBM Corp. (formerly Acme Inc.) All rights reserved.",
+		},
+		{
+			description:      "Trailing punctuation",
+			line:             "// Copyright (c) 2019 Acme Inc.;!",
+			expectedBump:     "// Copyright (c) 2019-2026 Acme Inc.;!",
+			expectedTransfer: "// Copyright (c) 2019-2026 IBM Corp. (formerly Acme Inc.);!",
+		},
+		{
+			description:      "Year after the holder, followed by a URL",
+			line:             "// Copyright Acme Inc. 2019, see https://acme.example/2021/legal",
+			expectedBump:     "// Copyright Acme Inc. 2019-2026, see https://acme.example/2021/legal",
+			expectedTransfer: "// Copyright IBM Corp. (formerly Acme Inc.) 2019-2026, see https://acme.example/2021/legal",
+		},
+		{
+			description:      "Years in a URL are not the statement's years",
+			line:             "// Copyright (c) Acme Inc. - https://acme.example/2021/legal",
+			expectedBump:     "// Copyright (c) Acme Inc. - https://acme.example/2021/legal",
+			expectedTransfer: "// Copyright (c) IBM Corp. (formerly Acme Inc.) - https://acme.example/2021/legal",
+		},
+		{
+			description:      "Version numbers are not the statement's years",
+			line:             "// Copyright (c) Acme Inc. (format v2019.1)",
+			expectedBump:     "// Copyright (c) Acme Inc. (format v2019.1)",
+			expectedTransfer: "// Copyright (c) IBM Corp. (formerly Acme Inc.) (format v2019.1)",
+		},
+		{
+			description:      "Non-ASCII trailing text",
+			line:             "// Copyright (c) 2019, Acme Inc. — tous droits réservés, 日本語",
+			expectedBump:     "// Copyright (c) 2019-2026, Acme Inc. — tous droits réservés, 日本語",
+			expectedTransfer: "// Copyright (c) 2019-2026, IBM Corp. (formerly Acme Inc.) — tous droits réservés, 日本語",
+		},
+		{
+			description:      "Non-ASCII text before the statement",
+			line:             "// İİİ Copyright (c) 2019 Acme Inc. — ©",
+			expectedBump:     "// İİİ Copyright (c) 2019-2026 Acme Inc. — ©",
+			expectedTransfer: "// İİİ Copyright (c) 2019-2026 IBM Corp. (formerly Acme Inc.) — ©",
+		},
+		{
+			description:      "Holder mentioned before the statement",
+			line:             "// Acme Inc. SDK. Copyright (c) 2019 Acme Inc.",
+			expectedBump:     "// Acme Inc. SDK. Copyright (c) 2019-2026 Acme Inc.",
+			expectedTransfer: "// Acme Inc. SDK. Copyright (c) 2019-2026 IBM Corp. (formerly Acme Inc.)",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			bumped, _ := BumpCopyrightYear([]byte(tt.line+"\n"), "Acme Inc.", 2026, YearStrategyRange)
+			assert.Equal(t, tt.expectedBump+"\n", string(bumped))
+
+			transferred, _ := TransferCopyrightHolder([]byte(tt.line+"\n"), "Acme Inc.", "IBM Corp.", 2026, true)
+			assert.Equal(t, tt.expectedTransfer+"\n", string(transferred))
+		})
+	}
+}
+
+func TestTransferCopyrightHolder_UnsafeHolder(t *testing.T) {
+	in := "/*\n * Copyright (c) 2019 Acme Inc.\n */\npackage main\n"
+	out, transfers := TransferCopyrightHolder([]byte(in), "Acme Inc.", "IBM */ Corp.", 2026, false)
+	assert.Equal(t, in, string(out))
+	assert.Empty(t, transfers)
+}
+
+func TestRewritePreservesAttribution(t *testing.T) {
+	in := "// Copyright (c) 2019 Acme Inc.\n// Original author: Jane Doe <jane@example.com>\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+
+	bumped, changed := BumpCopyrightYear([]byte(in), "Acme Inc.", 2026, YearStrategyRange)
+	assert.True(t, changed)
+	assert.Equal(t, "// Copyright (c) 2019-2026 Acme Inc.\n// Original author: Jane Doe <jane@example.com>\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n", string(bumped))
+
+	transferred, transfers := TransferCopyrightHolder([]byte(in), "Acme Inc.", "IBM Corp.", 0, false)
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, "// Copyright (c) 2019 IBM Corp.\n// Original author: Jane Doe <jane@example.com>\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n", string(transferred))
+}
+
+// assertCommentsPreserved fails the test if out doesn't have exactly the same
+// lines and comment delimiters as in, which would mean a rewrite had broken
+// a comment
+func assertCommentsPreserved(t *testing.T, in []byte, out []byte) {
+	t.Helper()
+	if bytes.Count(in, []byte("\n")) != bytes.Count(out, []byte("\n")) {
+		t.Fatalf("number of lines changed:\n%q\n%q", in, out)
+	}
+	if !slices.Equal(delimitersIn(in), delimitersIn(out)) {
+		t.Fatalf("comment delimiters changed:\n%q\n%q", in, out)
+	}
+}
+
+// fuzzSeeds are headers in a variety of comment styles
+var fuzzSeeds = []string{
+	"// Copyright (c) 2019 HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\npackage main\n",
+	"/* Copyright (c) 2019-2023 HashiCorp, Inc. */\npackage main\n",
+	"/**\n * Copyright (c) 2019, 2023 HashiCorp, Inc.\n */\n",
+	"<!-- Copyright 2030-2009 HashiCorp, Inc. -->\n<html></html>\n",
+	"(**\n   Copyright 2019 HashiCorp, Inc.\n*)\n",
+	"<%/*\n  Copyright 2019 HashiCorp, Inc.*/%>\n",
+	"{{! Copyright 2019 HashiCorp, Inc.}}\n",
+	"# Copyright 2019 HashiCorp, Inc.\n",
+	"// Copyright 2019 HashiCorp, Inc.\n// Original author: Jane Doe <jane@example.com>\npackage main\n",
+}
+
+func FuzzBumpCopyrightYear(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s), 2025, false)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte, year int, current bool) {
+		strategy := YearStrategyRange
+		if current {
+			strategy = YearStrategyCurrent
+		}
+		out, _ := BumpCopyrightYear(b, "", year, strategy)
+		assertCommentsPreserved(t, b, out)
+	})
+}
+
+func FuzzTransferCopyrightHolder(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s), "HashiCorp, Inc.", "IBM Corp.", 2026, true)
+		f.Add([]byte(s), "HashiCorp, Inc.", "IBM */ Corp.", 2026, false)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte, from string, to string, year int, annotate bool) {
+		out, _ := TransferCopyrightHolder(b, from, to, year, annotate)
+		assertCommentsPreserved(t, b, out)
+	})
+}
+
+func FuzzFindYearAnomalies(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s), 2025, 2020)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte, year int, earliest int) {
+		for _, a := range FindYearAnomalies(b, year, earliest) {
+			assertCommentsPreserved(t, []byte(a.Original), []byte(a.Suggested))
+		}
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hashicorp/copywrite/addlicense"
+)
+
+// HolderTransfer records a single copyright statement rewritten by
+// TransferCopyrightHolder
+type HolderTransfer struct {
+	// Line number of the statement, starting at 1
+	Line int
+
+	// Original and Updated contents of the line
+	Original string
+	Updated  string
+}
+
+// TransferCopyrightHolder rewrites copyright statements in the header region of
+// b (see addlicense.HeaderScanWindow) that name the holder from, so that they name the holder to
+// instead. If year is non-zero, the end year of each statement is advanced to
+// it, marking when the transfer took effect. If annotate is true, the original
+// holder is kept alongside the new one, e.g. "IBM Corp. (formerly Acme Inc.)".
+//
+// Holders are matched exactly, as legal names should not be guessed at.
+// Statements that have already been transferred, lines following an
+// addlicense.IgnoreNextLineMarker, and statements in which the new holder would
+// break the surrounding comment are left alone. The updated content is
+// returned along with a record of every line that was changed.
+func TransferCopyrightHolder(b []byte, from string, to string, year int, annotate bool) ([]byte, []HolderTransfer) {
+	transfers := []HolderTransfer{}
+	if from == "" {
+		return b, transfers
+	}
+
+	replacement := []byte(to)
+	if annotate {
+		replacement = []byte(fmt.Sprintf("%s (formerly %s)", to, from))
+	}
+
+	var out bytes.Buffer
+	limit := len(addlicense.HeaderRegion(b))
+	start := 0
+	protected := false
+	for n := 1; start < limit; n++ {
+		end := bytes.IndexByte(b[start:], '\n')
+		if end == -1 {
+			end = len(b)
+		} else {
+			end += start
+		}
+
+		line := b[start:end]
+		skip := protected
+		protected = bytes.Contains(line, []byte(addlicense.IgnoreNextLineMarker))
+		transferred := bytes.Contains(line, []byte("(formerly "+from+")"))
+		// Only the holder named by the statement is transferred, rather than a
+		// mention of it before the word copyright
+		i := -1
+		if c := copyrightWord.FindIndex(line); c != nil {
+			if j := bytes.Index(line[c[0]:], []byte(from)); j != -1 {
+				i = c[0] + j
+			}
+		}
+		if !skip && !transferred && i != -1 {
+			// Holders that would break the comment are not transferred
+			if updated, ok := replaceInLine(line, i, i+len(from), replacement); ok {
+				if year != 0 {
+					updated, _ = bumpYearsInLine(updated, year, YearStrategyRange)
+				}
+				transfers = append(transfers, HolderTransfer{Line: n, Original: string(line), Updated: string(updated)})
+				line = updated
+			}
+		}
+
+		out.Write(line)
+		if end < len(b) {
+			out.WriteByte('\n')
+		}
+		start = end + 1
+	}
+
+	if len(transfers) == 0 {
+		return b, transfers
+	}
+	if start < len(b) {
+		out.Write(b[start:])
+	}
+	return out.Bytes(), transfers
+}
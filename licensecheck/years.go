@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/copywrite/addlicense"
+)
+
+// YearStrategy controls how the years in an existing copyright statement are
+// refreshed
+type YearStrategy string
+
+const (
+	// YearStrategyRange keeps the first year and advances the end year,
+	// e.g., "2019" or "2019-2023" becomes "2019-2025"
+	YearStrategyRange YearStrategy = "range"
+
+	// YearStrategyCurrent replaces the years with only the current year,
+	// e.g., "2019-2023" becomes "2025"
+	YearStrategyCurrent YearStrategy = "current"
+)
+
+// ParseYearStrategy validates a year strategy from config or flags. An empty
+// string results in the default strategy, YearStrategyRange.
+func ParseYearStrategy(s string) (YearStrategy, error) {
+	switch YearStrategy(s) {
+	case "", YearStrategyRange:
+		return YearStrategyRange, nil
+	case YearStrategyCurrent:
+		return YearStrategyCurrent, nil
+	}
+	return "", fmt.Errorf("invalid year strategy %q: must be one of %q or %q", s, YearStrategyRange, YearStrategyCurrent)
+}
+
+// yearExpr matches a single year or a range of years, such as "2019",
+// "2019-2023", or "2019, 2023"
+var yearExpr = regexp.MustCompile(`\b((?:19|20)\d{2})(?:(\s*[-–]\s*|,\s*)((?:19|20)\d{2}))?\b`)
+
+// BumpCopyrightYear refreshes the years in the first copyright statement for
+// the given holder found in the header region of b (see
+// addlicense.HeaderScanWindow). Only the years are modified: the holder and the
+// rest of the statement are left untouched.
+//
+// The updated content is returned along with whether any change was made.
+// Statements without a year, or whose end year is already current, are left
+// alone. An empty holder matches any copyright statement. Lines following an
+// addlicense.IgnoreNextLineMarker are never modified.
+func BumpCopyrightYear(b []byte, holder string, year int, strategy YearStrategy) ([]byte, bool) {
+	limit := len(addlicense.HeaderRegion(b))
+	start := 0
+	protected := false
+	for start < limit {
+		end := bytes.IndexByte(b[start:], '\n')
+		if end == -1 {
+			end = len(b)
+		} else {
+			end += start
+		}
+
+		line := b[start:end]
+		lower := bytes.ToLower(line)
+		skip := protected
+		protected = bytes.Contains(line, []byte(addlicense.IgnoreNextLineMarker))
+		if !skip && bytes.Contains(lower, []byte("copyright")) && bytes.Contains(lower, bytes.ToLower([]byte(holder))) {
+			updated, changed := bumpYearsInLine(line, year, strategy)
+			if !changed {
+				return b, false
+			}
+
+			out := make([]byte, 0, len(b)+len(updated)-len(line))
+			out = append(out, b[:start]...)
+			out = append(out, updated...)
+			out = append(out, b[end:]...)
+			return out, true
+		}
+
+		start = end + 1
+	}
+
+	return b, false
+}
+
+func bumpYearsInLine(line []byte, year int, strategy YearStrategy) ([]byte, bool) {
+	loc := copyrightYears(line)
+	if loc == nil {
+		return line, false
+	}
+
+	first, _ := strconv.Atoi(string(line[loc[2]:loc[3]]))
+	last := first
+	separator := "-"
+	if loc[4] != -1 {
+		separator = string(line[loc[4]:loc[5]])
+		last, _ = strconv.Atoi(string(line[loc[6]:loc[7]]))
+	}
+
+	if last >= year {
+		return line, false
+	}
+
+	replacement := fmt.Sprintf("%d%s%d", first, separator, year)
+	if strategy == YearStrategyCurrent {
+		replacement = strconv.Itoa(year)
+	}
+
+	return replaceInLine(line, loc[0], loc[1], []byte(replacement))
+}
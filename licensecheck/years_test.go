@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package licensecheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumpCopyrightYear(t *testing.T) {
+	cases := []struct {
+		description     string
+		fileContents    string
+		holder          string
+		strategy        YearStrategy
+		expectedOutput  string
+		expectedChanged bool
+	}{
+		{
+			description:     "Single year becomes a range",
+			fileContents:    "// Copyright (c) 2019 HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n",
+			holder:          "HashiCorp, Inc.",
+			strategy:        YearStrategyRange,
+			expectedOutput:  "// Copyright (c) 2019-2025 HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n",
+			expectedChanged: true,
+		},
+		{
+			description:     "End year of a range is advanced, keeping the separator",
+			fileContents:    "# Copyright IBM Corp. 2019, 2023\n",
+			holder:          "IBM Corp.",
+			strategy:        YearStrategyRange,
+			expectedOutput:  "# Copyright IBM Corp. 2019, 2025\n",
+			expectedChanged: true,
+		},
+		{
+			description:     "Current strategy collapses years",
+			fileContents:    "/* Copyright (c) 2019-2023 HashiCorp, Inc. */\npackage main\n",
+			holder:          "HashiCorp, Inc.",
+			strategy:        YearStrategyCurrent,
+			expectedOutput:  "/* Copyright (c) 2025 HashiCorp, Inc. */\npackage main\n",
+			expectedChanged: true,
+		},
+		{
+			description:     "Up-to-date year is left alone",
+			fileContents:    "// Copyright (c) 2019-2025 HashiCorp, Inc.\n",
+			holder:          "HashiCorp, Inc.",
+			strategy:        YearStrategyRange,
+			expectedOutput:  "// Copyright (c) 2019-2025 HashiCorp, Inc.\n",
+			expectedChanged: false,
+		},
+		{
+			description:     "Statement without a year is left alone",
+			fileContents:    "// Copyright (c) HashiCorp, Inc.\n",
+			holder:          "HashiCorp, Inc.",
+			strategy:        YearStrategyRange,
+			expectedOutput:  "// Copyright (c) HashiCorp, Inc.\n",
+			expectedChanged: false,
+		},
+		{
+			description:     "Other holders are never touched",
+			fileContents:    "// Copyright (c) 2015 Some Other Company\n",
+			holder:          "HashiCorp, Inc.",
+			strategy:        YearStrategyRange,
+			expectedOutput:  "// Copyright (c) 2015 Some Other Company\n",
+			expectedChanged: false,
+		},
+		{
+			description:     "Only the matching holder's statement is updated",
+			fileContents:    "// Copyright (c) 2015 Some Other Company\n// Copyright (c) 2020 HashiCorp, Inc.\n",
+			holder:          "HashiCorp, Inc.",
+			strategy:        YearStrategyRange,
+			expectedOutput:  "// Copyright (c) 2015 Some Other Company\n// Copyright (c) 2020-2025 HashiCorp, Inc.\n",
+			expectedChanged: true,
+		},
+		{
+			description:     "Lines after an ignore-next-line marker are protected",
+			fileContents:    "// copywrite:ignore-next-line\n// Copyright (c) 2015 HashiCorp, Inc. and Acme\n// Copyright (c) 2020 HashiCorp, Inc.\n",
+			holder:          "HashiCorp, Inc.",
+			strategy:        YearStrategyRange,
+			expectedOutput:  "// copywrite:ignore-next-line\n// Copyright (c) 2015 HashiCorp, Inc. and Acme\n// Copyright (c) 2020-2025 HashiCorp, Inc.\n",
+			expectedChanged: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, actualChanged := BumpCopyrightYear([]byte(tt.fileContents), tt.holder, 2025, tt.strategy)
+			assert.Equal(t, tt.expectedOutput, string(actualOutput), tt.description)
+			assert.Equal(t, tt.expectedChanged, actualChanged, tt.description)
+		})
+	}
+}
+
+func TestParseYearStrategy(t *testing.T) {
+	s, err := ParseYearStrategy("")
+	assert.Nil(t, err)
+	assert.Equal(t, YearStrategyRange, s, "Empty strategy defaults to range")
+
+	s, err = ParseYearStrategy("current")
+	assert.Nil(t, err)
+	assert.Equal(t, YearStrategyCurrent, s)
+
+	_, err = ParseYearStrategy("forever")
+	assert.NotNil(t, err, "Unknown strategies should error")
+}
+
+func TestTransferCopyrightHolder(t *testing.T) {
+	cases := []struct {
+		description       string
+		fileContents      string
+		year              int
+		annotate          bool
+		expectedOutput    string
+		expectedTransfers []HolderTransfer
+	}{
+		{
+			description:    "Holder is replaced and the end year advanced",
+			fileContents:   "// Copyright (c) 2019-2023 Acme Inc.\n// SPDX-License-Identifier: MPL-2.0\npackage main\n",
+			year:           2026,
+			expectedOutput: "// Copyright (c) 2019-2026 IBM Corp.\n// SPDX-License-Identifier: MPL-2.0\npackage main\n",
+			expectedTransfers: []HolderTransfer{
+				{Line: 1, Original: "// Copyright (c) 2019-2023 Acme Inc.", Updated: "// Copyright (c) 2019-2026 IBM Corp."},
+			},
+		},
+		{
+			description:    "Original holder is annotated",
+			fileContents:   "/* Copyright (c) Acme Inc. */\n",
+			annotate:       true,
+			expectedOutput: "/* Copyright (c) IBM Corp. (formerly Acme Inc.) */\n",
+			expectedTransfers: []HolderTransfer{
+				{Line: 1, Original: "/* Copyright (c) Acme Inc. */", Updated: "/* Copyright (c) IBM Corp. (formerly Acme Inc.) */"},
+			},
+		},
+		{
+			description:       "Annotated statements are not transferred twice",
+			fileContents:      "/* Copyright (c) IBM Corp. (formerly Acme Inc.) */\n",
+			annotate:          true,
+			expectedOutput:    "/* Copyright (c) IBM Corp. (formerly Acme Inc.) */\n",
+			expectedTransfers: []HolderTransfer{},
+		},
+		{
+			description:    "Every matching statement is transferred",
+			fileContents:   "# Copyright 2015 Acme Inc.\n# Copyright 2018 Jane Doe\n# Copyright 2020 Acme Inc.\n",
+			expectedOutput: "# Copyright 2015 IBM Corp.\n# Copyright 2018 Jane Doe\n# Copyright 2020 IBM Corp.\n",
+			expectedTransfers: []HolderTransfer{
+				{Line: 1, Original: "# Copyright 2015 Acme Inc.", Updated: "# Copyright 2015 IBM Corp."},
+				{Line: 3, Original: "# Copyright 2020 Acme Inc.", Updated: "# Copyright 2020 IBM Corp."},
+			},
+		},
+		{
+			description:       "Mentions outside of copyright statements are left alone",
+			fileContents:      "// Client for the Acme Inc. API\n",
+			expectedOutput:    "// Client for the Acme Inc. API\n",
+			expectedTransfers: []HolderTransfer{},
+		},
+		{
+			description:       "Lines after an ignore-next-line marker are protected",
+			fileContents:      "// copywrite:ignore-next-line\n// Copyright (c) 2019 Acme Inc.\n",
+			year:              2026,
+			expectedOutput:    "// copywrite:ignore-next-line\n// Copyright (c) 2019 Acme Inc.\n",
+			expectedTransfers: []HolderTransfer{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			actualOutput, actualTransfers := TransferCopyrightHolder([]byte(tt.fileContents), "Acme Inc.", "IBM Corp.", tt.year, tt.annotate)
+			assert.Equal(t, tt.expectedOutput, string(actualOutput))
+			assert.Equal(t, tt.expectedTransfers, actualTransfers)
+		})
+	}
+}
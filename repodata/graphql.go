@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repodata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	gh "github.com/hashicorp/copywrite/github"
+)
+
+// reposQuery fetches a page of an org's public repos, along with everything
+// needed to filter and audit them
+const reposQuery = `query($org: String!, $cursor: String) {
+  organization(login: $org) {
+    repositories(first: 100, after: $cursor, privacy: PUBLIC) {
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+      nodes {
+        name
+        nameWithOwner
+        url
+        visibility
+        isArchived
+        isFork
+        isTemplate
+        isEmpty
+        diskUsage
+        createdAt
+        primaryLanguage {
+          name
+        }
+        licenseInfo {
+          key
+          name
+          spdxId
+        }
+        defaultBranchRef {
+          name
+        }
+      }
+    }
+  }
+}`
+
+// graphQLRepo is a repository node returned by reposQuery
+type graphQLRepo struct {
+	Name            string    `json:"name"`
+	NameWithOwner   string    `json:"nameWithOwner"`
+	URL             string    `json:"url"`
+	Visibility      string    `json:"visibility"`
+	IsArchived      bool      `json:"isArchived"`
+	IsFork          bool      `json:"isFork"`
+	IsTemplate      bool      `json:"isTemplate"`
+	IsEmpty         bool      `json:"isEmpty"`
+	DiskUsage       *int      `json:"diskUsage"`
+	CreatedAt       time.Time `json:"createdAt"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	LicenseInfo *struct {
+		Key    string `json:"key"`
+		Name   string `json:"name"`
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+}
+
+// graphQLResponse is the response to reposQuery
+type graphQLResponse struct {
+	Data struct {
+		Organization *struct {
+			Repositories struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []graphQLRepo `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"organization"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// GetReposGraphQL retrieves the same repos as GetRepos, but with a single
+// GraphQL query per 100 repos that includes each repo's license and default
+// branch, which is much faster for orgs with thousands of repos. GitHub's
+// GraphQL API requires authentication, so GetRepos must be used without
+// credentials.
+func GetReposGraphQL(ctx context.Context, githubOrganization string) ([]*github.Repository, error) {
+	return fetchReposGraphQL(ctx, gh.NewGHClient().Raw(), githubOrganization)
+}
+
+// fetchReposGraphQL pages through reposQuery using client
+func fetchReposGraphQL(ctx context.Context, client *github.Client, org string) ([]*github.Repository, error) {
+	allRepos := []*github.Repository{}
+	variables := map[string]interface{}{"org": org, "cursor": nil}
+	for {
+		req, err := client.NewRequest("POST", "graphql", map[string]interface{}{
+			"query":     reposQuery,
+			"variables": variables,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var resp graphQLResponse
+		if _, err := client.Do(ctx, req, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			msgs := make([]string, 0, len(resp.Errors))
+			for _, e := range resp.Errors {
+				msgs = append(msgs, e.Message)
+			}
+			return nil, fmt.Errorf("GraphQL query failed: %s", strings.Join(msgs, "; "))
+		}
+		if resp.Data.Organization == nil {
+			return nil, errors.New("GraphQL query returned no organization")
+		}
+
+		repos := resp.Data.Organization.Repositories
+		for _, r := range repos.Nodes {
+			allRepos = append(allRepos, r.toRepository())
+		}
+
+		if !repos.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = repos.PageInfo.EndCursor
+	}
+	return allRepos, nil
+}
+
+// toRepository converts a GraphQL repository node into the REST API's
+// representation, so that it can be used interchangeably with GetRepos
+func (r graphQLRepo) toRepository() *github.Repository {
+	repo := &github.Repository{
+		Name:       github.String(r.Name),
+		FullName:   github.String(r.NameWithOwner),
+		HTMLURL:    github.String(r.URL),
+		Visibility: github.String(strings.ToLower(r.Visibility)),
+		Private:    github.Bool(r.Visibility == "PRIVATE"),
+		Archived:   github.Bool(r.IsArchived),
+		Fork:       github.Bool(r.IsFork),
+		IsTemplate: github.Bool(r.IsTemplate),
+		CreatedAt:  &github.Timestamp{Time: r.CreatedAt},
+	}
+	if r.IsEmpty {
+		repo.Size = github.Int(0)
+	} else if r.DiskUsage != nil {
+		repo.Size = github.Int(*r.DiskUsage)
+	}
+	if r.PrimaryLanguage != nil {
+		repo.Language = github.String(r.PrimaryLanguage.Name)
+	}
+	if r.LicenseInfo != nil {
+		repo.License = &github.License{
+			Key:    github.String(r.LicenseInfo.Key),
+			Name:   github.String(r.LicenseInfo.Name),
+			SPDXID: github.String(r.LicenseInfo.SPDXID),
+		}
+	}
+	if r.DefaultBranchRef != nil {
+		repo.DefaultBranch = github.String(r.DefaultBranchRef.Name)
+	}
+	return repo
+}
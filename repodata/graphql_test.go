@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repodata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchReposGraphQL(t *testing.T) {
+	pages := []string{
+		`{"data": {"organization": {"repositories": {
+			"pageInfo": {"hasNextPage": true, "endCursor": "abc"},
+			"nodes": [{"name": "copywrite", "nameWithOwner": "org/copywrite", "visibility": "PUBLIC",
+				"isArchived": false, "diskUsage": 1234, "createdAt": "2022-10-01T00:00:00Z",
+				"primaryLanguage": {"name": "Go"},
+				"licenseInfo": {"key": "mpl-2.0", "name": "Mozilla Public License 2.0", "spdxId": "MPL-2.0"},
+				"defaultBranchRef": {"name": "main"}}]
+		}}}}`,
+		`{"data": {"organization": {"repositories": {
+			"pageInfo": {"hasNextPage": false, "endCursor": "def"},
+			"nodes": [{"name": "empty", "nameWithOwner": "org/empty", "visibility": "PUBLIC",
+				"isArchived": true, "isEmpty": true, "createdAt": "2022-10-01T00:00:00Z"}]
+		}}}}`,
+	}
+
+	cursors := []interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "org", body.Variables["org"])
+		cursors = append(cursors, body.Variables["cursor"])
+		fmt.Fprint(w, pages[len(cursors)-1])
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	repos, err := fetchReposGraphQL(context.Background(), client, "org")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil, "abc"}, cursors)
+	assert.Len(t, repos, 2)
+
+	assert.Equal(t, "copywrite", repos[0].GetName())
+	assert.Equal(t, "main", repos[0].GetDefaultBranch())
+	assert.Equal(t, "MPL-2.0", repos[0].GetLicense().GetSPDXID())
+	assert.Equal(t, "Go", repos[0].GetLanguage())
+	assert.Equal(t, 1234, repos[0].GetSize())
+	assert.False(t, repos[0].GetArchived())
+
+	assert.True(t, repos[1].GetArchived())
+	assert.Equal(t, 0, repos[1].GetSize())
+	assert.Nil(t, repos[1].License)
+	assert.Equal(t, []*github.Repository{repos[0]}, FilterRepos(repos))
+}
+
+func TestFetchReposGraphQLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"organization": null}, "errors": [{"message": "Could not resolve to an Organization"}]}`)
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	_, err := fetchReposGraphQL(context.Background(), client, "missing")
+	assert.ErrorContains(t, err, "Could not resolve to an Organization")
+}
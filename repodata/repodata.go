@@ -16,8 +16,9 @@ import (
 	"github.com/samber/lo"
 )
 
-// GetRepos retrieves the repo data and places it into an array
-func GetRepos(githubOrganization string) ([]*github.Repository, error) {
+// GetRepos retrieves the repo data and places it into an array, stopping early
+// if ctx is done
+func GetRepos(ctx context.Context, githubOrganization string) ([]*github.Repository, error) {
 	client := gh.NewGHClient().Raw()
 
 	// list public repositories for org
@@ -29,7 +30,7 @@ func GetRepos(githubOrganization string) ([]*github.Repository, error) {
 	// pagination to always retrieve the exact number of repos and all metadata regarding them
 	var allRepos []*github.Repository
 	for {
-		repos, current, err := client.Repositories.ListByOrg(context.Background(), githubOrganization, opt)
+		repos, current, err := client.Repositories.ListByOrg(ctx, githubOrganization, opt)
 		if err != nil {
 			hclog.L().Error(err.Error())
 
@@ -50,12 +51,89 @@ func GetRepos(githubOrganization string) ([]*github.Repository, error) {
 
 // FilterRepos returns a new array of repo structs that only has non-archived repos
 func FilterRepos(repos []*github.Repository) []*github.Repository {
-	predicate := func(v *github.Repository, i int) bool {
+	return NewFilter().ExcludeArchived().Apply(repos)
+}
+
+// Predicate reports whether a repo should be kept by a Filter
+type Predicate func(r *github.Repository) bool
+
+// Filter is a composable set of predicates that a repo must all satisfy to be
+// kept, e.g.:
+//
+//	repos = repodata.NewFilter().ExcludeArchived().ExcludeForks().Apply(repos)
+type Filter struct {
+	predicates []Predicate
+}
+
+// NewFilter returns a Filter that keeps every repo
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+// Where adds a custom predicate to the filter
+func (f *Filter) Where(p Predicate) *Filter {
+	f.predicates = append(f.predicates, p)
+	return f
+}
+
+// ExcludeArchived removes archived repos
+func (f *Filter) ExcludeArchived() *Filter {
+	return f.Where(func(r *github.Repository) bool {
 		// Repo structs occasionally don't have the `Archived` key set. In these
 		// cases, default to including the repo as it is categorically not archived
-		return v.Archived == nil || !*v.Archived
+		return !r.GetArchived()
+	})
+}
+
+// ExcludeForks removes repos that are forks of another repo
+func (f *Filter) ExcludeForks() *Filter {
+	return f.Where(func(r *github.Repository) bool { return !r.GetFork() })
+}
+
+// ExcludeTemplates removes template repos
+func (f *Filter) ExcludeTemplates() *Filter {
+	return f.Where(func(r *github.Repository) bool { return !r.GetIsTemplate() })
+}
+
+// ExcludeEmpty removes repos that have no content
+func (f *Filter) ExcludeEmpty() *Filter {
+	return f.Where(func(r *github.Repository) bool {
+		// Size is omitted from some API responses, so only a known size of 0
+		// counts as empty
+		return r.Size == nil || *r.Size > 0
+	})
+}
+
+// Languages keeps only repos whose primary language is one of langs, compared
+// without case sensitivity. If langs is empty, no repos are removed.
+func (f *Filter) Languages(langs ...string) *Filter {
+	if len(langs) == 0 {
+		return f
+	}
+	return f.Where(func(r *github.Repository) bool {
+		return lo.ContainsBy(langs, func(l string) bool { return strings.EqualFold(l, r.GetLanguage()) })
+	})
+}
+
+// MaxSize removes repos larger than kb kilobytes. If kb is 0, no repos are
+// removed.
+func (f *Filter) MaxSize(kb int) *Filter {
+	if kb <= 0 {
+		return f
 	}
-	return lo.Filter(repos, predicate)
+	return f.Where(func(r *github.Repository) bool { return r.GetSize() <= kb })
+}
+
+// Apply returns a new array of the repos that satisfy every predicate
+func (f *Filter) Apply(repos []*github.Repository) []*github.Repository {
+	return lo.Filter(repos, func(r *github.Repository, i int) bool {
+		for _, p := range f.predicates {
+			if !p(r) {
+				return false
+			}
+		}
+		return true
+	})
 }
 
 // Transform takes in an array of repo structs and transforms it into an array of repo maps with attributes as strings
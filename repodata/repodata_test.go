@@ -60,6 +60,53 @@ func TestFilterRepos(t *testing.T) {
 
 }
 
+func TestFilter(t *testing.T) {
+	fork := &github.Repository{Name: github.String("fork"), Fork: github.Bool(true), Language: github.String("Go"), Size: github.Int(10)}
+	template := &github.Repository{Name: github.String("template"), IsTemplate: github.Bool(true), Language: github.String("Go"), Size: github.Int(10)}
+	empty := &github.Repository{Name: github.String("empty"), Size: github.Int(0)}
+	big := &github.Repository{Name: github.String("big"), Language: github.String("Go"), Size: github.Int(9000)}
+	hcl := &github.Repository{Name: github.String("hcl"), Language: github.String("HCL"), Size: github.Int(10)}
+	all := []*github.Repository{fork, template, empty, big, hcl}
+
+	cases := []struct {
+		description    string
+		filter         *Filter
+		expectedresult []*github.Repository
+	}{
+		{
+			description:    "empty filter keeps every repo",
+			filter:         NewFilter(),
+			expectedresult: all,
+		},
+		{
+			description:    "forks, templates, and empty repos can be excluded",
+			filter:         NewFilter().ExcludeForks().ExcludeTemplates().ExcludeEmpty(),
+			expectedresult: []*github.Repository{big, hcl},
+		},
+		{
+			description:    "languages are matched without case sensitivity",
+			filter:         NewFilter().Languages("hcl"),
+			expectedresult: []*github.Repository{hcl},
+		},
+		{
+			description:    "repos over the maximum size are excluded",
+			filter:         NewFilter().MaxSize(100),
+			expectedresult: []*github.Repository{fork, template, empty, hcl},
+		},
+		{
+			description:    "filters compose",
+			filter:         NewFilter().ExcludeForks().Languages("Go").MaxSize(100),
+			expectedresult: []*github.Repository{template},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.expectedresult, tt.filter.Apply(all), tt.description)
+		})
+	}
+}
+
 func TestValidateInputFields(t *testing.T) {
 	cases := []struct {
 		description    string
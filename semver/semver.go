@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package semver parses and compares the semantic versions used by copywrite
+// releases, such as "0.20.1" or "1.0.0-beta.1"
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Build metadata is discarded, as it has
+// no bearing on precedence.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Parse parses a version such as "1.2.3", "v1.2.3", or "1.2.3-rc.1". The minor
+// and patch numbers may be omitted (e.g., "0.20"), in which case they are 0.
+func Parse(s string) (Version, error) {
+	orig := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+
+	var v Version
+	s, v.Prerelease, _ = strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", orig)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", orig)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// String returns the version formatted as "major.minor.patch[-prerelease]"
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0, or 1 if v has lower, equal, or higher precedence than
+// o, following the rules of https://semver.org/#spec-item-11
+func (v Version) Compare(o Version) int {
+	for _, c := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if c[0] != c[1] {
+			return sign(c[0] - c[1])
+		}
+	}
+
+	// A prerelease has lower precedence than the associated normal version
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(o.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(a) - len(b))
+}
+
+// compareIdentifier compares a single dot-separated prerelease identifier,
+// where numeric identifiers compare numerically and below alphanumeric ones
+func compareIdentifier(a string, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return sign(na - nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Constraints is a set of version constraints that must all be satisfied, such
+// as ">= 0.20, < 1.0"
+type Constraints []constraint
+
+// constraint is a single operator and version, such as ">= 0.20"
+type constraint struct {
+	op      string
+	version Version
+}
+
+// constraintOps are the supported operators. Longer operators must be listed
+// before their prefixes.
+var constraintOps = []string{">=", "<=", "!=", "~>", ">", "<", "="}
+
+// ParseConstraints parses a comma-separated list of constraints, using the same
+// syntax as Terraform's required_version: each is an operator (=, !=, >, >=, <,
+// <=, or ~>) followed by a version. A version without an operator must match
+// exactly.
+//
+// The pessimistic operator ~> allows only the rightmost version component given
+// to increase, e.g. "~> 1.2" allows 1.3 but not 2.0, and "~> 1.2.3" allows
+// 1.2.9 but not 1.3.0.
+func ParseConstraints(s string) (Constraints, error) {
+	constraints := Constraints{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("invalid version constraint %q: empty constraint", s)
+		}
+
+		op := "="
+		for _, o := range constraintOps {
+			if rest, ok := strings.CutPrefix(part, o); ok {
+				op, part = o, strings.TrimSpace(rest)
+				break
+			}
+		}
+
+		v, err := Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c := constraint{op: op, version: v}
+		if op == "~>" {
+			c.version.Prerelease = ""
+			c.op = pessimisticOp(part)
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, nil
+}
+
+// pessimisticOp records how many components were given to a ~> constraint, as
+// "~>2" (major and minor) or "~>3" (major, minor, and patch)
+func pessimisticOp(version string) string {
+	version, _, _ = strings.Cut(version, "-")
+	if strings.Count(version, ".") >= 2 {
+		return "~>3"
+	}
+	return "~>2"
+}
+
+// Check reports whether v satisfies every constraint
+func (cs Constraints) Check(v Version) bool {
+	for _, c := range cs {
+		cmp := v.Compare(c.version)
+		ok := false
+		switch c.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case "~>2":
+			ok = cmp >= 0 && v.Major == c.version.Major
+		case "~>3":
+			ok = cmp >= 0 && v.Major == c.version.Major && v.Minor == c.version.Minor
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput Version
+		expectErr      bool
+	}{
+		{input: "1.2.3", expectedOutput: Version{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v0.20.1", expectedOutput: Version{Minor: 20, Patch: 1}},
+		{input: "0.20", expectedOutput: Version{Minor: 20}},
+		{input: "1.0.0-beta.1+abc123", expectedOutput: Version{Major: 1, Prerelease: "beta.1"}},
+		{input: "dev", expectErr: true},
+		{input: "1.2.3.4", expectErr: true},
+		{input: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actualOutput, err := Parse(tt.input)
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func Test_Compare(t *testing.T) {
+	// Each version has lower precedence than the next, per the example in
+	// https://semver.org/#spec-item-11
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := Parse(ordered[i])
+			b, _ := Parse(ordered[j])
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			assert.Equal(t, expected, a.Compare(b), "%s compared to %s", ordered[i], ordered[j])
+		}
+	}
+}
+
+func Test_String(t *testing.T) {
+	v, _ := Parse("v1.2")
+	assert.Equal(t, "1.2.0", v.String())
+
+	v, _ = Parse("1.0.0-rc.1")
+	assert.Equal(t, "1.0.0-rc.1", v.String())
+}
+
+func Test_Constraints(t *testing.T) {
+	tests := []struct {
+		constraints string
+		version     string
+		expected    bool
+	}{
+		{">= 0.20, < 1.0", "0.20.0", true},
+		{">= 0.20, < 1.0", "0.22.3", true},
+		{">= 0.20, < 1.0", "0.19.9", false},
+		{">= 0.20, < 1.0", "1.0.0", false},
+		{"0.20.1", "0.20.1", true},
+		{"= 0.20.1", "0.20.2", false},
+		{"!= 0.20.1", "0.20.2", true},
+		{"> 0.20", "0.20.0", false},
+		{"<= 0.20", "0.20.0", true},
+		{"~> 1.2", "1.9.0", true},
+		{"~> 1.2", "2.0.0", false},
+		{"~> 1.2", "1.1.0", false},
+		{"~> 1.2.3", "1.2.9", true},
+		{"~> 1.2.3", "1.3.0", false},
+		{">=0.20,<1.0", "0.21.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraints+" "+tt.version, func(t *testing.T) {
+			cs, err := ParseConstraints(tt.constraints)
+			assert.Nil(t, err)
+			v, err := Parse(tt.version)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, cs.Check(v))
+		})
+	}
+}
+
+func Test_ParseConstraints_Invalid(t *testing.T) {
+	for _, s := range []string{"", ">= 0.20,", ">= latest", "=> 1.0"} {
+		_, err := ParseConstraints(s)
+		assert.NotNil(t, err, "%q should not parse", s)
+	}
+}
@@ -0,0 +1,7 @@
+//SPDX-License-Identifier: MPL-2.0
+//
+// copyright hashicorp, inc.
+
+package main
+
+func helper() {}
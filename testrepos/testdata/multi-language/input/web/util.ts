@@ -0,0 +1 @@
+export const b: number = 2;
@@ -0,0 +1,6 @@
+schema_version = 1
+
+project {
+  license        = "MPL-2.0"
+  copyright_year = 2023
+}
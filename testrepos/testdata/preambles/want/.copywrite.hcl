@@ -0,0 +1,7 @@
+schema_version = 1
+
+project {
+  license          = "Apache-2.0"
+  copyright_holder = "Example Corp"
+  copyright_year   = 2021
+}
@@ -0,0 +1,5 @@
+# frozen_string_literal: true
+# Copyright (c) Example Corp
+# SPDX-License-Identifier: Apache-2.0
+
+puts "hi"
@@ -0,0 +1,6 @@
+// Copyright (c) Example Corp
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package main
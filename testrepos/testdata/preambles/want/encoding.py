@@ -0,0 +1,6 @@
+#!/usr/bin/env python3
+# -*- coding: utf-8 -*-
+# Copyright (c) Example Corp
+# SPDX-License-Identifier: Apache-2.0
+
+print("hi")
@@ -0,0 +1,5 @@
+<?php
+// Copyright (c) Example Corp
+// SPDX-License-Identifier: Apache-2.0
+
+echo "hi";
@@ -0,0 +1,6 @@
+#!/usr/bin/env bash
+# Copyright (c) Example Corp
+# SPDX-License-Identifier: Apache-2.0
+
+set -e
+echo hi
@@ -0,0 +1,4 @@
+// Copyright 2015 Acme Inc.
+// Licensed under the MIT License.
+
+package internal
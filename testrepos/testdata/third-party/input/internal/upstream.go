@@ -0,0 +1,2 @@
+// copywrite:ignore-file -- copied verbatim from upstream
+package internal
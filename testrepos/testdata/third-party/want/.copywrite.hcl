@@ -0,0 +1,11 @@
+schema_version = 1
+
+project {
+  license        = "MPL-2.0"
+  copyright_year = 2020
+
+  header_ignore = [
+    "vendor/**",
+    "ui/dist/**",
+  ]
+}
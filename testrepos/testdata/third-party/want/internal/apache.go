@@ -0,0 +1,6 @@
+// Copyright 2019 HashiCorp, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package internal
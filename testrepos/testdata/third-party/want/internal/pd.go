@@ -0,0 +1,3 @@
+// This is free and unencumbered software released into the public domain.
+
+package internal
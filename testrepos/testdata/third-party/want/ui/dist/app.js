@@ -0,0 +1 @@
+function a(){return 1}
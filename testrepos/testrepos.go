@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testrepos embeds golden repositories that exercise copywrite end to
+// end, for use by the selftest command. Each repository is a directory in
+// testdata with an input tree, including its .copywrite.hcl config, and the
+// tree that is expected once copywrite has processed it.
+//
+// To add or update a repository, run "copywrite selftest --keep <name>" and
+// copy the resulting tree, once reviewed, into the repository's want directory.
+package testrepos
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+//go:embed all:testdata
+var repos embed.FS
+
+// Names returns the name of every golden repository, sorted
+func Names() ([]string, error) {
+	entries, err := repos.ReadDir("testdata")
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Input returns the tree that copywrite is run against for the named
+// repository
+func Input(name string) (fs.FS, error) {
+	return sub(name, "input")
+}
+
+// Want returns the tree that the named repository is expected to have once
+// copywrite has processed it
+func Want(name string) (fs.FS, error) {
+	return sub(name, "want")
+}
+
+func sub(name string, tree string) (fs.FS, error) {
+	dir := path.Join("testdata", name, tree)
+	if _, err := fs.Stat(repos, dir); err != nil {
+		return nil, fmt.Errorf("unknown golden repository %q", name)
+	}
+	return fs.Sub(repos, dir)
+}
+
+// Extract writes the input tree of the named repository to dir
+func Extract(name string, dir string) error {
+	input, err := Input(name)
+	if err != nil {
+		return err
+	}
+	return writeTree(input, dir)
+}
+
+// writeTree writes every file in fsys to dir
+func writeTree(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, b, 0644)
+	})
+}
+
+// Mismatch is a file whose contents differ from what was expected. Want is nil
+// if the file wasn't expected to exist, and Got is nil if it's missing.
+type Mismatch struct {
+	Path string
+	Want []byte
+	Got  []byte
+}
+
+// Compare returns every file that differs between the want tree of the named
+// repository and dir, sorted by path
+func Compare(name string, dir string) ([]Mismatch, error) {
+	want, err := Want(name)
+	if err != nil {
+		return nil, err
+	}
+	wantFiles, err := readTree(want)
+	if err != nil {
+		return nil, err
+	}
+	gotFiles, err := readTree(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := []Mismatch{}
+	for p, w := range wantFiles {
+		if g, ok := gotFiles[p]; !ok || !bytes.Equal(w, g) {
+			mismatches = append(mismatches, Mismatch{Path: p, Want: w, Got: g})
+		}
+	}
+	for p, g := range gotFiles {
+		if _, ok := wantFiles[p]; !ok {
+			mismatches = append(mismatches, Mismatch{Path: p, Got: g})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Path < mismatches[j].Path })
+	return mismatches, nil
+}
+
+// readTree returns the contents of every file in fsys by path, other than those
+// in .git directories
+func readTree(fsys fs.FS) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		files[p] = b
+		return nil
+	})
+	return files, err
+}
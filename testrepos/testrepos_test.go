@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package testrepos
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Repos(t *testing.T) {
+	names, err := Names()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			for _, tree := range []func(string) (fs.FS, error){Input, Want} {
+				fsys, err := tree(name)
+				assert.Nil(t, err)
+				_, err = fs.Stat(fsys, ".copywrite.hcl")
+				assert.Nil(t, err, "every tree must include the repo's config")
+			}
+		})
+	}
+
+	_, err = Input("nonexistent")
+	assert.NotNil(t, err)
+}
+
+func Test_Compare(t *testing.T) {
+	names, err := Names()
+	assert.Nil(t, err)
+	name := names[0]
+
+	dir := t.TempDir()
+	want, err := Want(name)
+	assert.Nil(t, err)
+	assert.Nil(t, writeTree(want, dir))
+
+	mismatches, err := Compare(name, dir)
+	assert.Nil(t, err)
+	assert.Empty(t, mismatches)
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ".copywrite.hcl"), []byte("changed"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "extra.go"), []byte("package extra\n"), 0644))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+
+	mismatches, err = Compare(name, dir)
+	assert.Nil(t, err)
+	if assert.Len(t, mismatches, 2) {
+		assert.Equal(t, ".copywrite.hcl", mismatches[0].Path)
+		assert.Equal(t, []byte("changed"), mismatches[0].Got)
+		assert.Equal(t, "extra.go", mismatches[1].Path)
+		assert.Nil(t, mismatches[1].Want)
+	}
+}